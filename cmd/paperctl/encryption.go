@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"paper_ai/internal/infrastructure/persistence"
+)
+
+// defaultEncryptionBatchSize 存量数据加密时每批处理的记录数
+const defaultEncryptionBatchSize = 200
+
+// runEncryptionStatus 查看内容加密状态
+func runEncryptionStatus(ctx context.Context, a *app, args []string) error {
+	if _, err := parseFlags(newFlagSet("encryption status"), args, 0); err != nil {
+		return err
+	}
+
+	status, err := persistence.NewContentMigrator(a.db).Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	configured := "none"
+	if a.keyring != nil {
+		configured = strings.Join(a.keyring.MasterKeyIDs(), ", ")
+	}
+	fmt.Printf("encryption enabled:      %t\n", a.cfg.Encryption.Enabled)
+	fmt.Printf("active master key:       %s\n", a.cfg.Encryption.ActiveMasterKey)
+	fmt.Printf("configured master keys:  %s\n", configured)
+	fmt.Printf("master keys in use:      %s\n", strings.Join(status.MasterKeyIDsUsed, ", "))
+	fmt.Printf("data keys:               %d (%d active)\n", status.DataKeys, status.ActiveDataKeys)
+	fmt.Printf("polish records:          %d (%d pending)\n", status.Records, status.PendingRecords)
+	fmt.Printf("polish versions:         %d (%d pending)\n", status.Versions, status.PendingVersions)
+	fmt.Printf("polish revisions:        %d (%d pending)\n", status.Revisions, status.PendingRevisions)
+	return nil
+}
+
+// runEncryptionMigrate 加密存量明文记录，并用当前数据密钥重新加密使用已停用密钥的记录
+func runEncryptionMigrate(ctx context.Context, a *app, args []string) error {
+	flags := newFlagSet("encryption migrate")
+	batchSize := flags.Int("batch", defaultEncryptionBatchSize, "records per batch")
+	if _, err := parseFlags(flags, args, 0); err != nil {
+		return err
+	}
+	if *batchSize < 1 {
+		return errors.New("batch must be at least 1")
+	}
+
+	records, versions, revisions, err := persistence.NewContentMigrator(a.db).Run(ctx, *batchSize)
+	fmt.Printf("encrypted %d polish record(s), %d polish version(s) and %d polish revision(s)\n", records, versions, revisions)
+	return err
+}
+
+// runEncryptionRewrap 用当前主密钥重新包装所有数据密钥（主密钥轮换）
+func runEncryptionRewrap(ctx context.Context, a *app, args []string) error {
+	if _, err := parseFlags(newFlagSet("encryption rewrap"), args, 0); err != nil {
+		return err
+	}
+	if a.keyring == nil {
+		return errors.New("no master keys configured (encryption.master_keys)")
+	}
+
+	count, err := a.keyring.RewrapDataKeys(ctx)
+	fmt.Printf("rewrapped %d data key(s) with master key %s\n", count, a.cfg.Encryption.ActiveMasterKey)
+	return err
+}
+
+// runEncryptionRotateDataKeys 停用数据密钥，之后写入的内容使用新生成的数据密钥
+func runEncryptionRotateDataKeys(ctx context.Context, a *app, args []string) error {
+	flags := newFlagSet("encryption rotate-data-keys")
+	userRef := flags.String("user", "", "rotate the data key of this user (username or id)")
+	all := flags.Bool("all", false, "rotate the data keys of all users")
+	if _, err := parseFlags(flags, args, 0); err != nil {
+		return err
+	}
+	if (*userRef == "") == !*all {
+		return errors.New("exactly one of -user or -all is required")
+	}
+	if a.keyring == nil {
+		return errors.New("no master keys configured (encryption.master_keys)")
+	}
+
+	var userID *int64
+	if *userRef != "" {
+		user, err := findUser(ctx, a, *userRef)
+		if err != nil {
+			return err
+		}
+		userID = &user.ID
+	}
+
+	deactivated, err := a.keyring.RotateDataKeys(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("deactivated %d data key(s); run 'paperctl encryption migrate' to re-encrypt existing content\n", deactivated)
+	return nil
+}
@@ -0,0 +1,192 @@
+// paperctl 运维管理命令行工具
+// 直接复用服务端的配置和仓储实现，替代手工执行 SQL 完成用户、Prompt、迁移和数据维护操作
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"paper_ai/internal/config"
+	"paper_ai/internal/domain/repository"
+	"paper_ai/internal/infrastructure/database"
+	"paper_ai/internal/infrastructure/encryption"
+	"paper_ai/internal/infrastructure/persistence"
+	"paper_ai/pkg/idgen"
+	"paper_ai/pkg/logger"
+
+	"go.uber.org/zap/zapcore"
+	"gorm.io/gorm"
+)
+
+// usage 命令用法说明
+const usage = `Usage: paperctl [-config path] [-v] <command> [arguments]
+
+Commands:
+  user create -username NAME -email EMAIL (-password PASS | -password-stdin)
+              [-nickname NAME] [-multi-version] [-quota N]
+  user ban <username|id>                 封禁用户并撤销其所有刷新令牌
+  user unban <username|id>               解除封禁
+  user grant-multi-version <username|id> [-quota N]
+  user revoke-multi-version <username|id>
+  user set-quota <username|id> <N>       设置多版本配额（0=无限）
+
+  prompt export [-o file] [-version-type T] [-active-only]
+  prompt import -f file [-dry-run]       按 version_type/language/style/version 新增或更新
+
+  migrate up [-migrations dir]
+  migrate down [-steps N] [-migrations dir]
+  migrate version [-migrations dir]
+  migrate force <version> [-migrations dir]
+
+  tokens purge                           删除过期的刷新令牌
+  prompts recompute-stats                根据生成记录重算 Prompt 成功率
+
+  encryption status                      查看内容加密状态
+  encryption migrate [-batch N]          加密存量明文记录，重新加密使用已停用数据密钥的记录
+  encryption rewrap                      用当前主密钥重新包装所有数据密钥（主密钥轮换）
+  encryption rotate-data-keys (-user <username|id> | -all)
+                                         停用数据密钥，之后写入的内容使用新密钥
+
+Options:
+  -config string   配置文件路径（默认读取 CONFIG_PATH 环境变量或 ./config/config.yaml）
+  -v               输出详细日志（包括 SQL）
+`
+
+// app 命令执行所需的依赖
+type app struct {
+	cfg         *config.Config
+	userRepo    repository.UserRepository
+	tokenRepo   repository.RefreshTokenRepository
+	polishRepo  repository.PolishRepository
+	promptRepo  repository.PolishPromptRepository
+	versionRepo repository.PolishVersionRepository
+	db          *gorm.DB
+	keyring     *encryption.Keyring // 未配置主密钥时为 nil
+}
+
+// commandFunc 子命令处理函数
+type commandFunc func(ctx context.Context, a *app, args []string) error
+
+// commands 子命令表，键为 "<group> <action>"
+var commands = map[string]commandFunc{
+	"user create":               runUserCreate,
+	"user ban":                  runUserBan,
+	"user unban":                runUserUnban,
+	"user grant-multi-version":  runUserGrantMultiVersion,
+	"user revoke-multi-version": runUserRevokeMultiVersion,
+	"user set-quota":            runUserSetQuota,
+	"prompt export":             runPromptExport,
+	"prompt import":             runPromptImport,
+	"migrate up":                runMigrateUp,
+	"migrate down":              runMigrateDown,
+	"migrate version":           runMigrateVersion,
+	"migrate force":             runMigrateForce,
+	"tokens purge":              runTokensPurge,
+	"prompts recompute-stats":   runPromptsRecomputeStats,
+
+	"encryption status":           runEncryptionStatus,
+	"encryption migrate":          runEncryptionMigrate,
+	"encryption rewrap":           runEncryptionRewrap,
+	"encryption rotate-data-keys": runEncryptionRotateDataKeys,
+}
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+// run 解析参数并执行子命令，返回进程退出码
+func run(args []string) int {
+	flags := flag.NewFlagSet("paperctl", flag.ContinueOnError)
+	flags.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	configPath := flags.String("config", getConfigPath(), "config file path")
+	verbose := flags.Bool("v", false, "verbose output")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	rest := flags.Args()
+	if len(rest) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	}
+	command, ok := commands[rest[0]+" "+rest[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command: %s %s\n\n%s", rest[0], rest[1], usage)
+		return 2
+	}
+
+	level := zapcore.WarnLevel
+	if *verbose {
+		level = zapcore.DebugLevel
+	}
+	if err := logger.InitConsole(level); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to init logger: %v\n", err)
+		return 1
+	}
+	defer logger.Sync()
+
+	a, err := newApp(*configPath, *verbose)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+	defer database.Close()
+
+	if err := command(context.Background(), a, rest[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// newApp 加载配置、连接数据库（不自动执行迁移）并创建仓储
+func newApp(configPath string, verbose bool) (*app, error) {
+	if err := config.Load(configPath); err != nil {
+		return nil, fmt.Errorf("failed to load config %s: %w", configPath, err)
+	}
+	cfg := config.Get()
+
+	// 迁移由 migrate 子命令显式执行；非详细模式下不输出 SQL
+	dbConfig := cfg.Database
+	dbConfig.AutoMigrate = false
+	if !verbose {
+		dbConfig.LogMode = "silent"
+	}
+	if err := database.Init(&dbConfig); err != nil {
+		return nil, fmt.Errorf("failed to connect database: %w", err)
+	}
+
+	if err := idgen.Init(cfg.IDGen.WorkerID); err != nil {
+		return nil, fmt.Errorf("failed to init ID generator: %w", err)
+	}
+
+	db := database.GetDB().GetGormDB()
+
+	// 仓储读写润色内容时需要与服务端一致的加解密
+	keyring, err := encryption.NewKeyringFromConfig(&cfg.Encryption, persistence.NewDataKeyStore(db))
+	if err != nil {
+		return nil, fmt.Errorf("failed to init content encryption: %w", err)
+	}
+	persistence.SetContentKeyring(keyring)
+
+	return &app{
+		cfg:         cfg,
+		userRepo:    persistence.NewUserRepository(db),
+		tokenRepo:   persistence.NewRefreshTokenRepository(db),
+		polishRepo:  persistence.NewPolishRepository(db),
+		promptRepo:  persistence.NewPolishPromptRepository(db),
+		versionRepo: persistence.NewPolishVersionRepository(db),
+		db:          db,
+		keyring:     keyring,
+	}, nil
+}
+
+// getConfigPath 获取配置文件路径（与服务端一致）
+func getConfigPath() string {
+	if path := os.Getenv("CONFIG_PATH"); path != "" {
+		return path
+	}
+	return "./config/config.yaml"
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"paper_ai/internal/service"
+)
+
+// newMaintenanceService 创建运维维护服务
+func newMaintenanceService(a *app) *service.MaintenanceService {
+	return service.NewMaintenanceService(a.tokenRepo, a.polishRepo, a.promptRepo, a.versionRepo)
+}
+
+// runTokensPurge 删除过期的刷新令牌
+func runTokensPurge(ctx context.Context, a *app, args []string) error {
+	if _, err := parseFlags(newFlagSet("tokens purge"), args, 0); err != nil {
+		return err
+	}
+
+	deleted, err := newMaintenanceService(a).PurgeExpiredTokens(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("purged %d expired refresh token(s)\n", deleted)
+	return nil
+}
+
+// runPromptsRecomputeStats 根据生成记录重算 Prompt 成功率
+func runPromptsRecomputeStats(ctx context.Context, a *app, args []string) error {
+	if _, err := parseFlags(newFlagSet("prompts recompute-stats"), args, 0); err != nil {
+		return err
+	}
+
+	updated, err := newMaintenanceService(a).RecomputePromptStatistics(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("recomputed statistics for %d prompt(s)\n", updated)
+	return nil
+}
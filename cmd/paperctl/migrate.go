@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"paper_ai/internal/infrastructure/database"
+
+	"github.com/golang-migrate/migrate/v4"
+)
+
+// runMigrateUp 执行所有未应用的迁移
+func runMigrateUp(ctx context.Context, a *app, args []string) error {
+	flags := newFlagSet("migrate up")
+	path := flags.String("migrations", "migrations", "migrations directory")
+	if _, err := parseFlags(flags, args, 0); err != nil {
+		return err
+	}
+
+	m, err := newMigrator(*path)
+	if err != nil {
+		return err
+	}
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("failed to run migrations: %w", err)
+	}
+	return printMigrationVersion(m)
+}
+
+// runMigrateDown 回滚最近的 N 个迁移
+func runMigrateDown(ctx context.Context, a *app, args []string) error {
+	flags := newFlagSet("migrate down")
+	path := flags.String("migrations", "migrations", "migrations directory")
+	steps := flags.Int("steps", 1, "number of migrations to roll back")
+	if _, err := parseFlags(flags, args, 0); err != nil {
+		return err
+	}
+	if *steps < 1 {
+		return fmt.Errorf("migrate down: -steps must be at least 1")
+	}
+
+	m, err := newMigrator(*path)
+	if err != nil {
+		return err
+	}
+	if err := m.Steps(-*steps); err != nil {
+		return fmt.Errorf("failed to roll back migrations: %w", err)
+	}
+	return printMigrationVersion(m)
+}
+
+// runMigrateVersion 输出当前迁移版本
+func runMigrateVersion(ctx context.Context, a *app, args []string) error {
+	flags := newFlagSet("migrate version")
+	path := flags.String("migrations", "migrations", "migrations directory")
+	if _, err := parseFlags(flags, args, 0); err != nil {
+		return err
+	}
+
+	m, err := newMigrator(*path)
+	if err != nil {
+		return err
+	}
+	return printMigrationVersion(m)
+}
+
+// runMigrateForce 强制设置迁移版本（迁移失败处于 dirty 状态、手工修复后使用）
+func runMigrateForce(ctx context.Context, a *app, args []string) error {
+	flags := newFlagSet("migrate force")
+	path := flags.String("migrations", "migrations", "migrations directory")
+	positional, err := parseFlags(flags, args, 1)
+	if err != nil {
+		return err
+	}
+	version, err := strconv.Atoi(positional[0])
+	if err != nil {
+		return fmt.Errorf("invalid version: %s", positional[0])
+	}
+
+	m, err := newMigrator(*path)
+	if err != nil {
+		return err
+	}
+	if err := m.Force(version); err != nil {
+		return fmt.Errorf("failed to force migration version: %w", err)
+	}
+	return printMigrationVersion(m)
+}
+
+// newMigrator 基于已建立的数据库连接创建迁移执行器
+func newMigrator(path string) (*migrate.Migrate, error) {
+	sqlDB, err := database.GetDB().GetGormDB().DB()
+	if err != nil {
+		return nil, err
+	}
+	return database.NewMigrator(sqlDB, path)
+}
+
+// printMigrationVersion 输出当前迁移版本
+func printMigrationVersion(m *migrate.Migrate) error {
+	version, dirty, err := m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		fmt.Println("no migrations applied")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get migration version: %w", err)
+	}
+
+	fmt.Printf("migration version: %d (dirty=%t)\n", version, dirty)
+	return nil
+}
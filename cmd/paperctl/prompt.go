@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"paper_ai/internal/domain/entity"
+	"paper_ai/internal/domain/repository"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// promptFile Prompt 导入导出文件格式
+type promptFile struct {
+	Prompts []promptDocument `yaml:"prompts"`
+}
+
+// promptDocument 单个 Prompt（不含ID和统计信息，导入时按 version_type/language/style/version 匹配已有记录）
+type promptDocument struct {
+	Name               string   `yaml:"name"`
+	VersionType        string   `yaml:"version_type"`
+	Language           string   `yaml:"language"`
+	Style              string   `yaml:"style"`
+	Version            int      `yaml:"version"`
+	IsActive           bool     `yaml:"is_active"`
+	SystemPrompt       string   `yaml:"system_prompt"`
+	UserPromptTemplate string   `yaml:"user_prompt_template"`
+	Description        string   `yaml:"description,omitempty"`
+	Tags               []string `yaml:"tags,omitempty"`
+	ABTestGroup        string   `yaml:"ab_test_group,omitempty"`
+	Weight             int      `yaml:"weight"`
+	CreatedBy          string   `yaml:"created_by,omitempty"`
+}
+
+// key 匹配已有记录使用的键
+func (d *promptDocument) key() string {
+	return fmt.Sprintf("%s/%s/%s/v%d", d.VersionType, d.Language, d.Style, d.Version)
+}
+
+// validate 校验导入的 Prompt
+func (d *promptDocument) validate() error {
+	switch {
+	case d.Name == "":
+		return fmt.Errorf("%s: name must not be empty", d.key())
+	case !entity.IsValidVersionType(d.VersionType):
+		return fmt.Errorf("%s: invalid version_type %q", d.key(), d.VersionType)
+	case !entity.IsValidLanguage(d.Language):
+		return fmt.Errorf("%s: invalid language %q", d.key(), d.Language)
+	case !entity.IsValidStyle(d.Style):
+		return fmt.Errorf("%s: invalid style %q", d.key(), d.Style)
+	case d.Version < 1:
+		return fmt.Errorf("%s: version must be at least 1", d.key())
+	case d.SystemPrompt == "" || d.UserPromptTemplate == "":
+		return fmt.Errorf("%s: system_prompt and user_prompt_template must not be empty", d.key())
+	case d.Weight < 0:
+		return fmt.Errorf("%s: weight must not be negative", d.key())
+	}
+	return nil
+}
+
+// newPromptDocument 实体转换为导出格式
+func newPromptDocument(p *entity.PolishPrompt) promptDocument {
+	return promptDocument{
+		Name:               p.Name,
+		VersionType:        p.VersionType,
+		Language:           p.Language,
+		Style:              p.Style,
+		Version:            p.Version,
+		IsActive:           p.IsActive,
+		SystemPrompt:       p.SystemPrompt,
+		UserPromptTemplate: p.UserPromptTemplate,
+		Description:        p.Description,
+		Tags:               p.Tags,
+		ABTestGroup:        p.ABTestGroup,
+		Weight:             p.Weight,
+		CreatedBy:          p.CreatedBy,
+	}
+}
+
+// apply 把导入内容写入实体（保留ID、统计信息和创建时间）
+func (d *promptDocument) apply(p *entity.PolishPrompt) {
+	p.Name = d.Name
+	p.VersionType = d.VersionType
+	p.Language = d.Language
+	p.Style = d.Style
+	p.Version = d.Version
+	p.IsActive = d.IsActive
+	p.SystemPrompt = d.SystemPrompt
+	p.UserPromptTemplate = d.UserPromptTemplate
+	p.Description = d.Description
+	p.Tags = d.Tags
+	p.ABTestGroup = d.ABTestGroup
+	p.Weight = d.Weight
+	if d.CreatedBy != "" {
+		p.CreatedBy = d.CreatedBy
+	}
+}
+
+// runPromptExport 导出 Prompt 为 YAML
+func runPromptExport(ctx context.Context, a *app, args []string) error {
+	flags := newFlagSet("prompt export")
+	output := flags.String("o", "", "output file (default stdout)")
+	versionType := flags.String("version-type", "", "only export this version type")
+	activeOnly := flags.Bool("active-only", false, "only export active prompts")
+	if _, err := parseFlags(flags, args, 0); err != nil {
+		return err
+	}
+
+	filter := repository.PromptFilter{VersionType: *versionType}
+	if *activeOnly {
+		active := true
+		filter.IsActive = &active
+	}
+	prompts, err := a.promptRepo.List(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	file := promptFile{Prompts: make([]promptDocument, 0, len(prompts))}
+	for _, prompt := range prompts {
+		file.Prompts = append(file.Prompts, newPromptDocument(prompt))
+	}
+
+	var writer io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		writer = f
+	}
+
+	encoder := yaml.NewEncoder(writer)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&file); err != nil {
+		return fmt.Errorf("failed to write prompts: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+
+	if *output != "" {
+		fmt.Printf("exported %d prompt(s) to %s\n", len(file.Prompts), *output)
+	}
+	return nil
+}
+
+// runPromptImport 从 YAML 导入 Prompt：已存在（version_type/language/style/version 相同）则更新，否则新增
+func runPromptImport(ctx context.Context, a *app, args []string) error {
+	flags := newFlagSet("prompt import")
+	input := flags.String("f", "", "input file")
+	dryRun := flags.Bool("dry-run", false, "only print what would change")
+	if _, err := parseFlags(flags, args, 0); err != nil {
+		return err
+	}
+	if *input == "" {
+		return fmt.Errorf("prompt import: -f is required")
+	}
+
+	content, err := os.ReadFile(*input)
+	if err != nil {
+		return err
+	}
+	var file promptFile
+	if err := yaml.Unmarshal(content, &file); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", *input, err)
+	}
+
+	// 先全部校验，避免导入一半失败
+	seen := make(map[string]bool, len(file.Prompts))
+	for i := range file.Prompts {
+		doc := &file.Prompts[i]
+		if err := doc.validate(); err != nil {
+			return err
+		}
+		if seen[doc.key()] {
+			return fmt.Errorf("%s: duplicated in %s", doc.key(), *input)
+		}
+		seen[doc.key()] = true
+	}
+
+	existing, err := a.promptRepo.List(ctx, repository.PromptFilter{})
+	if err != nil {
+		return err
+	}
+	byKey := make(map[string]*entity.PolishPrompt, len(existing))
+	for _, prompt := range existing {
+		doc := newPromptDocument(prompt)
+		byKey[doc.key()] = prompt
+	}
+
+	created, updated := 0, 0
+	for i := range file.Prompts {
+		doc := &file.Prompts[i]
+		prompt, exists := byKey[doc.key()]
+		action := "create"
+		if exists {
+			action = "update"
+		}
+		fmt.Printf("%s %s (%s, active=%t)\n", action, doc.key(), doc.Name, doc.IsActive)
+		if *dryRun {
+			continue
+		}
+
+		if exists {
+			doc.apply(prompt)
+			err = a.promptRepo.Update(ctx, prompt)
+			updated++
+		} else {
+			prompt = &entity.PolishPrompt{}
+			doc.apply(prompt)
+			err = a.promptRepo.Create(ctx, prompt)
+			created++
+		}
+		if err != nil {
+			return err
+		}
+
+		// Update/Create 会忽略零值（is_active 默认 true），单独同步启用状态
+		if doc.IsActive {
+			err = a.promptRepo.Activate(ctx, prompt.ID)
+		} else {
+			err = a.promptRepo.Deactivate(ctx, prompt.ID)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if *dryRun {
+		fmt.Println("dry run, nothing changed")
+		return nil
+	}
+	fmt.Printf("imported %d prompt(s): %d created, %d updated\n", created+updated, created, updated)
+	return nil
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"paper_ai/internal/domain/entity"
+	"paper_ai/internal/infrastructure/security"
+)
+
+// runUserCreate 创建用户
+func runUserCreate(ctx context.Context, a *app, args []string) error {
+	flags := newFlagSet("user create")
+	username := flags.String("username", "", "username")
+	email := flags.String("email", "", "email")
+	password := flags.String("password", "", "password (prefer -password-stdin)")
+	passwordStdin := flags.Bool("password-stdin", false, "read password from stdin")
+	nickname := flags.String("nickname", "", "nickname (defaults to username)")
+	multiVersion := flags.Bool("multi-version", false, "enable multi-version polish")
+	quota := flags.Int("quota", 0, "multi-version quota (0 = unlimited)")
+	if _, err := parseFlags(flags, args, 0); err != nil {
+		return err
+	}
+
+	if *passwordStdin {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return fmt.Errorf("failed to read password from stdin: %w", err)
+		}
+		*password = strings.TrimRight(line, "\r\n")
+	}
+
+	if err := security.ValidateUsername(*username); err != nil {
+		return err
+	}
+	if err := security.ValidateEmail(*email); err != nil {
+		return err
+	}
+	if err := security.ValidatePasswordStrength(*password); err != nil {
+		return err
+	}
+	if *quota < 0 {
+		return errors.New("quota must not be negative")
+	}
+
+	if exists, err := a.userRepo.ExistsUsername(ctx, *username); err != nil {
+		return err
+	} else if exists {
+		return fmt.Errorf("username already exists: %s", *username)
+	}
+	if exists, err := a.userRepo.ExistsEmail(ctx, *email); err != nil {
+		return err
+	} else if exists {
+		return fmt.Errorf("email already registered: %s", *email)
+	}
+
+	passwordHash, err := security.HashPassword(*password)
+	if err != nil {
+		return err
+	}
+
+	user := &entity.User{
+		Username:           *username,
+		Email:              *email,
+		PasswordHash:       passwordHash,
+		Nickname:           *nickname,
+		Status:             "active",
+		EnableMultiVersion: *multiVersion,
+		MultiVersionQuota:  *quota,
+		CreatedAt:          time.Now(),
+		UpdatedAt:          time.Now(),
+	}
+	if user.Nickname == "" {
+		user.Nickname = user.Username
+	}
+
+	if err := a.userRepo.Create(ctx, user); err != nil {
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+
+	fmt.Printf("user created: id=%d username=%s multi_version=%t quota=%d\n",
+		user.ID, user.Username, user.EnableMultiVersion, user.MultiVersionQuota)
+	return nil
+}
+
+// runUserBan 封禁用户并撤销其所有刷新令牌（已签发的访问令牌在过期前仍有效）
+func runUserBan(ctx context.Context, a *app, args []string) error {
+	user, err := findUserArg(ctx, a, "user ban", args)
+	if err != nil {
+		return err
+	}
+
+	user.Status = "banned"
+	if err := saveUser(ctx, a, user); err != nil {
+		return err
+	}
+	if err := a.tokenRepo.RevokeAllByUserID(ctx, user.ID); err != nil {
+		return fmt.Errorf("user banned but failed to revoke refresh tokens: %w", err)
+	}
+
+	fmt.Printf("user banned: id=%d username=%s\n", user.ID, user.Username)
+	return nil
+}
+
+// runUserUnban 解除封禁
+func runUserUnban(ctx context.Context, a *app, args []string) error {
+	user, err := findUserArg(ctx, a, "user unban", args)
+	if err != nil {
+		return err
+	}
+
+	user.Status = "active"
+	if err := saveUser(ctx, a, user); err != nil {
+		return err
+	}
+
+	fmt.Printf("user unbanned: id=%d username=%s\n", user.ID, user.Username)
+	return nil
+}
+
+// runUserGrantMultiVersion 为用户开通多版本功能
+func runUserGrantMultiVersion(ctx context.Context, a *app, args []string) error {
+	flags := newFlagSet("user grant-multi-version")
+	quota := flags.Int("quota", 0, "multi-version quota (0 = unlimited)")
+	positional, err := parseFlags(flags, args, 1)
+	if err != nil {
+		return err
+	}
+	if *quota < 0 {
+		return errors.New("quota must not be negative")
+	}
+
+	user, err := findUser(ctx, a, positional[0])
+	if err != nil {
+		return err
+	}
+
+	user.EnableMultiVersion = true
+	user.MultiVersionQuota = *quota
+	if err := saveUser(ctx, a, user); err != nil {
+		return err
+	}
+
+	fmt.Printf("multi-version granted: id=%d username=%s quota=%d\n", user.ID, user.Username, user.MultiVersionQuota)
+	return nil
+}
+
+// runUserRevokeMultiVersion 关闭用户的多版本功能
+func runUserRevokeMultiVersion(ctx context.Context, a *app, args []string) error {
+	user, err := findUserArg(ctx, a, "user revoke-multi-version", args)
+	if err != nil {
+		return err
+	}
+
+	user.EnableMultiVersion = false
+	if err := saveUser(ctx, a, user); err != nil {
+		return err
+	}
+
+	fmt.Printf("multi-version revoked: id=%d username=%s\n", user.ID, user.Username)
+	return nil
+}
+
+// runUserSetQuota 设置多版本配额
+func runUserSetQuota(ctx context.Context, a *app, args []string) error {
+	positional, err := parseFlags(newFlagSet("user set-quota"), args, 2)
+	if err != nil {
+		return err
+	}
+	quota, err := strconv.Atoi(positional[1])
+	if err != nil || quota < 0 {
+		return fmt.Errorf("invalid quota: %s", positional[1])
+	}
+
+	user, err := findUser(ctx, a, positional[0])
+	if err != nil {
+		return err
+	}
+
+	user.MultiVersionQuota = quota
+	if err := saveUser(ctx, a, user); err != nil {
+		return err
+	}
+
+	fmt.Printf("quota updated: id=%d username=%s quota=%d\n", user.ID, user.Username, user.MultiVersionQuota)
+	return nil
+}
+
+// findUserArg 解析只有一个用户参数的子命令
+func findUserArg(ctx context.Context, a *app, name string, args []string) (*entity.User, error) {
+	positional, err := parseFlags(newFlagSet(name), args, 1)
+	if err != nil {
+		return nil, err
+	}
+	return findUser(ctx, a, positional[0])
+}
+
+// findUser 按ID（纯数字）或用户名查找用户
+func findUser(ctx context.Context, a *app, ref string) (*entity.User, error) {
+	var (
+		user *entity.User
+		err  error
+	)
+	if id, parseErr := strconv.ParseInt(ref, 10, 64); parseErr == nil {
+		user, err = a.userRepo.GetByID(ctx, id)
+	}
+	if user == nil && err == nil {
+		user, err = a.userRepo.GetByUsername(ctx, ref)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, fmt.Errorf("user not found: %s", ref)
+	}
+	return user, nil
+}
+
+// saveUser 保存用户
+func saveUser(ctx context.Context, a *app, user *entity.User) error {
+	user.UpdatedAt = time.Now()
+	if err := a.userRepo.Update(ctx, user); err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+	return nil
+}
+
+// newFlagSet 创建子命令参数解析器
+func newFlagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(os.Stderr)
+	return flags
+}
+
+// parseFlags 解析参数（允许选项出现在位置参数之后），并校验位置参数数量
+func parseFlags(flags *flag.FlagSet, args []string, positionalCount int) ([]string, error) {
+	var positional []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, err
+		}
+		args = flags.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+
+	if len(positional) != positionalCount {
+		return nil, fmt.Errorf("%s: expected %d argument(s), got %d", flags.Name(), positionalCount, len(positional))
+	}
+	return positional, nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"paper_ai/internal/config"
+	"paper_ai/internal/infrastructure/database"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// commandUsage 子命令用法说明
+const commandUsage = `Usage:
+  paper_ai                  启动服务
+  paper_ai config check     检查配置文件并输出生效的配置（密钥已脱敏）
+
+Options for "config check":
+  -config string   配置文件路径（默认读取 CONFIG_PATH 环境变量或 ./config/config.yaml）
+  -ping-db         同时测试数据库连接
+  -quiet           只输出检查结果，不输出配置
+`
+
+// runCommand 执行子命令，返回进程退出码
+func runCommand(args []string) int {
+	if len(args) >= 2 && args[0] == "config" && args[1] == "check" {
+		return runConfigCheck(args[2:], os.Stdout, os.Stderr)
+	}
+
+	fmt.Fprint(os.Stderr, commandUsage)
+	return 2
+}
+
+// runConfigCheck 加载并校验配置，可选测试数据库连接，并输出生效的配置
+func runConfigCheck(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("config check", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	configPath := flags.String("config", getConfigPath(), "config file path")
+	pingDB := flags.Bool("ping-db", false, "test database connection")
+	quiet := flags.Bool("quiet", false, "do not print effective config")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if err := config.Load(*configPath); err != nil {
+		fmt.Fprintf(stderr, "config check failed: %s\n%v\n", *configPath, err)
+		return 1
+	}
+	cfg := config.Get()
+
+	if !*quiet {
+		out, err := yaml.Marshal(cfg.Redacted())
+		if err != nil {
+			fmt.Fprintf(stderr, "failed to print config: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(stdout, "# effective config (%s)\n%s\n", *configPath, out)
+	}
+
+	if *pingDB {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := database.Ping(ctx, &cfg.Database); err != nil {
+			fmt.Fprintf(stderr, "database check failed: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(stdout, "database: ok (%s:%d/%s)\n", cfg.Database.Host, cfg.Database.Port, cfg.Database.DBName)
+	}
+
+	fmt.Fprintf(stdout, "config ok: %s\n", *configPath)
+	return 0
+}
@@ -14,15 +14,24 @@ import (
 	"paper_ai/internal/config"
 	"paper_ai/internal/infrastructure/ai"
 	"paper_ai/internal/infrastructure/database"
+	"paper_ai/internal/infrastructure/encryption"
+	"paper_ai/internal/infrastructure/metrics"
 	"paper_ai/internal/infrastructure/persistence"
 	"paper_ai/internal/infrastructure/security"
+	"paper_ai/internal/infrastructure/tracing"
 	"paper_ai/internal/service"
 	"paper_ai/pkg/idgen"
+	"paper_ai/pkg/language"
 	"paper_ai/pkg/logger"
 	"go.uber.org/zap"
 )
 
 func main() {
+	// 子命令（如 config check）
+	if len(os.Args) > 1 {
+		os.Exit(runCommand(os.Args[1:]))
+	}
+
 	// 初始化日志
 	if err := logger.Init(); err != nil {
 		fmt.Printf("failed to init logger: %v\n", err)
@@ -40,6 +49,13 @@ func main() {
 	cfg := config.Get()
 	logger.Info("config loaded successfully", zap.String("path", configPath))
 
+	// 初始化链路追踪（默认 no-op）
+	shutdownTracing, err := tracing.Init(context.Background(), &cfg.Tracing)
+	if err != nil {
+		logger.Fatal("failed to init tracing", zap.Error(err))
+	}
+	logger.Info("tracing initialized", zap.String("exporter", cfg.Tracing.Exporter))
+
 	// 初始化数据库（新增）
 	if err := database.Init(&cfg.Database); err != nil {
 		logger.Fatal("failed to init database", zap.Error(err))
@@ -53,6 +69,12 @@ func main() {
 	}
 	logger.Info("ID generator initialized", zap.Int64("worker_id", cfg.IDGen.WorkerID))
 
+	// 设置开放的论文语言
+	if err := language.Configure(cfg.Languages.Supported, cfg.Languages.Default); err != nil {
+		logger.Fatal("failed to configure languages", zap.Error(err))
+	}
+	logger.Info("languages configured", zap.Strings("supported", language.Supported()), zap.String("default", language.Default()))
+
 	// 初始化AI提供商工厂
 	factory := ai.GetFactory()
 	if err := factory.InitProviders(cfg); err != nil {
@@ -62,15 +84,34 @@ func main() {
 
 	// 创建仓储实现
 	db := database.GetDB().GetGormDB()
+	if sqlDB, err := db.DB(); err == nil {
+		metrics.RegisterDBStats(sqlDB)
+	}
 	polishRepo := persistence.NewPolishRepository(db)
 	versionRepo := persistence.NewPolishVersionRepository(db)
 	promptRepo := persistence.NewPolishPromptRepository(db)
 	userRepo := persistence.NewUserRepository(db)
 	tokenRepo := persistence.NewRefreshTokenRepository(db)
+	feedbackRepo := persistence.NewChangeFeedbackRepository(db)
+	actionRepo := persistence.NewComparisonActionRepository(db)
+	projectRepo := persistence.NewProjectRepository(db)
+	tagRepo := persistence.NewTagRepository(db)
+	revisionRepo := persistence.NewPolishRevisionRepository(db)
+	glossaryRepo := persistence.NewGlossaryRepository(db)
+
+	// 初始化内容加密（润色记录和版本内容在仓储层透明加解密，未配置主密钥时不加密）
+	keyring, err := encryption.NewKeyringFromConfig(&cfg.Encryption, persistence.NewDataKeyStore(db))
+	if err != nil {
+		logger.Fatal("failed to init content encryption", zap.Error(err))
+	}
+	persistence.SetContentKeyring(keyring)
+	logger.Info("content encryption initialized",
+		zap.Bool("enabled", cfg.Encryption.Enabled),
+		zap.String("active_master_key", cfg.Encryption.ActiveMasterKey))
 
 	// 初始化JWT管理器
 	jwtManager := security.NewJWTManager(
-		cfg.JWT.SecretKey,
+		cfg.JWT.SecretKey.Value(),
 		time.Duration(cfg.JWT.AccessTokenExpiry)*time.Second,
 		time.Duration(cfg.JWT.RefreshTokenExpiry)*time.Second,
 	)
@@ -79,42 +120,89 @@ func main() {
 	// 初始化服务层（注入仓储）
 	// 1. Prompt服务（包含LRU缓存）
 	promptService := service.NewPromptService(promptRepo)
+	metrics.RegisterPromptCache(promptService.CacheStats)
 	logger.Info("Prompt service initialized with LRU cache")
 
 	// 2. 功能开关服务
-	featureConfig := &service.FeatureConfig{
-		MultiVersionEnabled: cfg.Features.MultiVersionPolish.Enabled,
-		DefaultMode:         cfg.Features.MultiVersionPolish.DefaultMode,
-		MaxConcurrent:       cfg.Features.MultiVersionPolish.MaxConcurrent,
-	}
+	featureConfig := newFeatureConfig(cfg)
 	featureService := service.NewFeatureService(userRepo, featureConfig)
 	logger.Info("Feature service initialized",
 		zap.Bool("multi_version_enabled", featureConfig.MultiVersionEnabled),
 		zap.String("default_mode", featureConfig.DefaultMode))
 
-	// 3. 单版本润色服务（保留原有）
-	polishService := service.NewPolishService(factory, polishRepo)
+	// 3. 隐私服务（保留天数、不保存内容模式、删除历史）
+	privacyService := service.NewPrivacyService(userRepo, polishRepo)
 
-	// 4. 多版本润色服务（新增）
+	// 4. 整理服务（项目、标签、收藏）
+	organizeService := service.NewOrganizeService(projectRepo, tagRepo, polishRepo)
+
+	// 5. 单版本润色服务（保留原有）
+	polishService := service.NewPolishService(factory, polishRepo, privacyService, organizeService)
+
+	// 6. 多版本润色服务（新增）
 	multiVersionService := service.NewPolishMultiVersionService(
 		factory,
 		polishRepo,
 		versionRepo,
 		promptService,
 		featureService,
+		feedbackRepo,
+		actionRepo,
+		privacyService,
+		organizeService,
 	)
 	logger.Info("Multi-version polish service initialized")
 
-	// 5. 其他服务
-	comparisonService := service.NewComparisonService(polishRepo, versionRepo)
+	// 7. 重新润色服务（基于已有记录，复用单版本/多版本润色）
+	repolishService := service.NewRepolishService(polishRepo, polishService, multiVersionService)
+
+	// 8. 多轮修改服务（按指令继续修改已有记录的润色结果）
+	refineService := service.NewRefineService(factory, polishRepo, revisionRepo, feedbackRepo, actionRepo, privacyService)
+
+	// 9. 术语表与翻译润色服务
+	glossaryService := service.NewGlossaryService(glossaryRepo)
+	translateService := service.NewTranslateService(factory, polishRepo, glossaryService, privacyService, organizeService)
+
+	// 10. 其他服务
+	comparisonService := service.NewComparisonService(polishRepo, versionRepo, feedbackRepo, actionRepo)
 	authService := service.NewAuthService(userRepo, tokenRepo, jwtManager)
+	healthService := service.NewHealthService(factory, &cfg.Health)
+
+	// 11. 后台定时任务（多副本时只有 leader 执行）
+	maintenanceService := service.NewMaintenanceService(tokenRepo, polishRepo, promptRepo, versionRepo)
+	stopScheduler, err := startScheduler(&cfg.Scheduler, db, maintenanceService)
+	if err != nil {
+		logger.Fatal("failed to start scheduler", zap.Error(err))
+	}
+
+	// 配置热更新：提供商、功能开关（含并发上限）、健康检查和论文语言配置对之后的请求生效
+	config.OnChange(func(oldCfg, newCfg *config.Config) {
+		if err := factory.InitProviders(newCfg); err != nil {
+			logger.Error("failed to reload AI providers", zap.Error(err))
+		} else {
+			logger.Info("AI providers reloaded", zap.Strings("providers", factory.ListProviders()))
+		}
+		featureService.UpdateConfig(newFeatureConfig(newCfg))
+		healthService.UpdateConfig(&newCfg.Health)
+		if err := language.Configure(newCfg.Languages.Supported, newCfg.Languages.Default); err != nil {
+			logger.Error("failed to reload languages", zap.Error(err))
+		}
+	})
+	config.Watch()
+	logger.Info("watching config file for changes", zap.String("path", configPath))
 
 	// 初始化处理器
-	polishHandler := handler.NewPolishHandler(polishService)
+	polishHandler := handler.NewPolishHandler(polishService, repolishService)
 	multiVersionHandler := handler.NewPolishMultiVersionHandler(multiVersionService)
 	queryHandler := handler.NewPolishQueryHandler(polishService)
 	comparisonHandler := handler.NewComparisonHandler(comparisonService)
 	authHandler := handler.NewAuthHandler(authService)
+	healthHandler := handler.NewHealthHandler(healthService)
+	privacyHandler := handler.NewPrivacyHandler(privacyService)
+	organizeHandler := handler.NewOrganizeHandler(organizeService)
+	refineHandler := handler.NewRefineHandler(refineService)
+	glossaryHandler := handler.NewGlossaryHandler(glossaryService)
+	translateHandler := handler.NewTranslateHandler(translateService)
 
 	// TODO: 初始化管理处理器（需要添加管理员权限中间件和路由）
 	// 需要导入: adminhandler "paper_ai/internal/api/handler/admin"
@@ -132,6 +220,12 @@ func main() {
 		queryHandler,
 		comparisonHandler,
 		authHandler,
+		healthHandler,
+		privacyHandler,
+		organizeHandler,
+		refineHandler,
+		glossaryHandler,
+		translateHandler,
 		jwtManager,
 	)
 	logger.Info("Routes configured successfully")
@@ -159,6 +253,13 @@ func main() {
 
 	logger.Info("shutting down server...")
 
+	// 先让就绪检查失败，等待负载均衡摘除本实例后再停止接收请求
+	healthService.SetShuttingDown()
+	if cfg.Health.ShutdownDelay > 0 {
+		logger.Info("waiting before shutdown", zap.Duration("delay", cfg.Health.ShutdownDelay))
+		time.Sleep(cfg.Health.ShutdownDelay)
+	}
+
 	// 优雅关闭，最多等待30秒
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
@@ -167,9 +268,26 @@ func main() {
 		logger.Fatal("server forced to shutdown", zap.Error(err))
 	}
 
+	// 停止定时任务并释放 leader 锁
+	stopScheduler(ctx)
+
+	// 导出剩余的 span
+	if err := shutdownTracing(ctx); err != nil {
+		logger.Error("failed to shutdown tracing", zap.Error(err))
+	}
+
 	logger.Info("server exited")
 }
 
+// newFeatureConfig 从配置构建功能开关配置
+func newFeatureConfig(cfg *config.Config) *service.FeatureConfig {
+	return &service.FeatureConfig{
+		MultiVersionEnabled: cfg.Features.MultiVersionPolish.Enabled,
+		DefaultMode:         cfg.Features.MultiVersionPolish.DefaultMode,
+		MaxConcurrent:       cfg.Features.MultiVersionPolish.MaxConcurrent,
+	}
+}
+
 // getConfigPath 获取配置文件路径
 func getConfigPath() string {
 	if path := os.Getenv("CONFIG_PATH"); path != "" {
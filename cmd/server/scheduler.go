@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+
+	"paper_ai/internal/config"
+	"paper_ai/internal/infrastructure/scheduler"
+	"paper_ai/internal/service"
+	"paper_ai/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+// startScheduler 启动后台定时任务（清理过期令牌、处理中断的记录、重算 Prompt 统计、数据保留），返回停止函数
+// 多副本部署时通过 PostgreSQL advisory lock 选出一个 leader 执行任务
+func startScheduler(cfg *config.SchedulerConfig, db *gorm.DB, maintenance *service.MaintenanceService) (func(ctx context.Context), error) {
+	if !cfg.Enabled {
+		logger.Info("scheduler disabled")
+		return func(context.Context) {}, nil
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	elector := scheduler.NewLeaderElector(sqlDB, cfg.LockKey, cfg.LeaderCheckInterval)
+	sched := scheduler.New(elector)
+
+	jobs := []struct {
+		name string
+		spec string
+		run  scheduler.JobFunc
+	}{
+		{"purge_expired_tokens", cfg.Jobs.PurgeExpiredTokens, func(ctx context.Context) error {
+			_, err := maintenance.PurgeExpiredTokens(ctx)
+			return err
+		}},
+		{"fail_stale_processing", cfg.Jobs.FailStaleProcessing, func(ctx context.Context) error {
+			_, err := maintenance.FailStaleProcessing(ctx, cfg.StaleProcessingAfter)
+			return err
+		}},
+		{"recompute_prompt_stats", cfg.Jobs.RecomputePromptStats, func(ctx context.Context) error {
+			_, err := maintenance.RecomputePromptStatistics(ctx)
+			return err
+		}},
+		{"apply_retention", cfg.Jobs.ApplyRetention, func(ctx context.Context) error {
+			_, err := maintenance.ApplyRetention(ctx, cfg.Retention.PolishRecords)
+			return err
+		}},
+	}
+	for _, job := range jobs {
+		if err := sched.Register(job.name, job.spec, job.run); err != nil {
+			return nil, err
+		}
+	}
+
+	electCtx, stopElection := context.WithCancel(context.Background())
+	electionDone := make(chan struct{})
+	go func() {
+		defer close(electionDone)
+		elector.Run(electCtx)
+	}()
+	sched.Start()
+
+	return func(ctx context.Context) {
+		sched.Stop(ctx)
+		stopElection()
+		<-electionDone
+		logger.Info("scheduler stopped")
+	}, nil
+}
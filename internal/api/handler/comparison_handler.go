@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"strconv"
+
 	"github.com/gin-gonic/gin"
 	"paper_ai/internal/domain/model"
 	"paper_ai/internal/service"
@@ -28,7 +30,10 @@ func NewComparisonHandler(comparisonService *service.ComparisonService) *Compari
 // @Produce json
 // @Param trace_id path string true "润色记录的 trace_id"
 // @Param version query string false "版本类型：conservative/balanced/aggressive（仅多版本润色时使用）"
+// @Param min_confidence query number false "只返回置信度 >= 该值的修改（0-1）"
+// @Param auto_accept query number false "自动接受置信度 >= 该值的待处理修改（0-1）"
 // @Success 200 {object} model.ComparisonResult
+// @Failure 400 {object} response.ErrorResponse "参数错误"
 // @Failure 404 {object} response.ErrorResponse "记录不存在"
 // @Failure 403 {object} response.ErrorResponse "无权访问"
 // @Router /api/v1/polish/compare/{trace_id} [get]
@@ -43,7 +48,18 @@ func (h *ComparisonHandler) GetComparison(c *gin.Context) {
 		return
 	}
 
-	result, err := h.comparisonService.GetComparison(c.Request.Context(), traceID, userID.(int64), versionType)
+	opts := &model.ComparisonOptions{VersionType: versionType}
+	var err error
+	if opts.MinConfidence, err = parseConfidenceQuery(c, "min_confidence"); err != nil {
+		response.Error(c, err)
+		return
+	}
+	if opts.AutoAcceptThreshold, err = parseConfidenceQuery(c, "auto_accept"); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	result, err := h.comparisonService.GetComparison(c.Request.Context(), traceID, userID.(int64), opts)
 	if err != nil {
 		response.Error(c, err)
 		return
@@ -52,9 +68,23 @@ func (h *ComparisonHandler) GetComparison(c *gin.Context) {
 	response.Success(c, result)
 }
 
+// parseConfidenceQuery 解析置信度阈值查询参数（0-1），未提供时返回 0
+func parseConfidenceQuery(c *gin.Context, key string) (float64, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return 0, nil
+	}
+
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil || value < 0 || value > 1 {
+		return 0, apperrors.NewInvalidParameterError(key + " 必须是 0 到 1 之间的数字")
+	}
+	return value, nil
+}
+
 // ApplyAction 应用修改操作
-// @Summary 接受或拒绝修改
-// @Description 对单个修改执行接受或拒绝操作
+// @Summary 接受、拒绝或编辑修改
+// @Description 对单个修改执行接受、拒绝或编辑操作；edit 操作使用 edited_text 作为该修改的替换文本
 // @Tags 对比
 // @Accept json
 // @Produce json
@@ -94,7 +124,7 @@ func (h *ComparisonHandler) ApplyAction(c *gin.Context) {
 
 // BatchApplyAction 批量应用修改操作
 // @Summary 批量接受或拒绝修改
-// @Description 批量执行接受或拒绝操作
+// @Description 批量执行接受（accept_all）或拒绝（reject_all）操作。可通过 change_ids 指定修改，或按修改类型、置信度范围筛选；返回重建后的文本和各状态数量
 // @Tags 对比
 // @Accept json
 // @Produce json
@@ -122,13 +152,237 @@ func (h *ComparisonHandler) BatchApplyAction(c *gin.Context) {
 		return
 	}
 
-	// 目前只支持 accept_all
-	if req.Action != "accept_all" {
-		response.Error(c, apperrors.NewInvalidParameterError("目前只支持 accept_all 操作"))
+	result, err := h.comparisonService.BatchApply(c.Request.Context(), traceID, userID.(int64), versionType, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// GetConsensus 获取多版本一致性视图
+// @Summary 获取多版本一致性视图
+// @Description 将所有成功版本对齐到原文，标记所有版本一致（高置信度）、部分版本一致或仅单个版本做出的修改
+// @Tags 对比
+// @Accept json
+// @Produce json
+// @Param trace_id path string true "润色记录的 trace_id"
+// @Success 200 {object} model.ConsensusResult
+// @Failure 400 {object} response.ErrorResponse "不是多版本润色"
+// @Failure 404 {object} response.ErrorResponse "记录不存在"
+// @Failure 403 {object} response.ErrorResponse "无权访问"
+// @Router /api/v1/polish/compare/{trace_id}/consensus [get]
+func (h *ComparisonHandler) GetConsensus(c *gin.Context) {
+	traceID := c.Param("trace_id")
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, apperrors.NewUnauthorizedError("未登录"))
+		return
+	}
+
+	result, err := h.comparisonService.GetConsensus(c.Request.Context(), traceID, userID.(int64))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// ApplyConsensus 按区间选择版本写法
+// @Summary 按区间选择版本写法生成最终文本
+// @Description 为一致性视图中的每个区间选择采用哪个版本的写法（或保留原文），生成并保存最终文本
+// @Tags 对比
+// @Accept json
+// @Produce json
+// @Param trace_id path string true "润色记录的 trace_id"
+// @Param request body model.ConsensusApplyRequest true "区间选择"
+// @Success 200 {object} model.ConsensusApplyResponse
+// @Failure 400 {object} response.ErrorResponse "参数错误"
+// @Failure 404 {object} response.ErrorResponse "记录不存在"
+// @Failure 403 {object} response.ErrorResponse "无权访问"
+// @Router /api/v1/polish/compare/{trace_id}/consensus/apply [post]
+func (h *ComparisonHandler) ApplyConsensus(c *gin.Context) {
+	traceID := c.Param("trace_id")
+
+	var req model.ConsensusApplyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, apperrors.NewUnauthorizedError("未登录"))
+		return
+	}
+
+	result, err := h.comparisonService.ApplyConsensus(c.Request.Context(), traceID, userID.(int64), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// EditContent 自由编辑最终文本
+// @Summary 自由编辑最终文本
+// @Description 将当前最终文本的 [start, end) 区间（基于字符）替换为 text，编辑结果与原文重新对比并更新标注，用户编辑的修改状态为 edited
+// @Tags 对比
+// @Accept json
+// @Produce json
+// @Param trace_id path string true "润色记录的 trace_id"
+// @Param request body model.ContentEditRequest true "编辑请求"
+// @Success 200 {object} model.ChangeActionResponse
+// @Failure 400 {object} response.ErrorResponse "参数错误"
+// @Failure 404 {object} response.ErrorResponse "记录不存在"
+// @Failure 403 {object} response.ErrorResponse "无权访问"
+// @Router /api/v1/polish/compare/{trace_id}/edit [post]
+func (h *ComparisonHandler) EditContent(c *gin.Context) {
+	traceID := c.Param("trace_id")
+
+	var req model.ContentEditRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, apperrors.NewUnauthorizedError("未登录"))
+		return
+	}
+
+	result, err := h.comparisonService.EditContent(c.Request.Context(), traceID, userID.(int64), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// Undo 撤销最近一次操作
+// @Summary 撤销最近一次操作
+// @Description 撤销最近一次接受/拒绝操作（包括批量操作），撤销本身也会记录到操作历史
+// @Tags 对比
+// @Accept json
+// @Produce json
+// @Param trace_id path string true "润色记录的 trace_id"
+// @Success 200 {object} model.ChangeActionResponse
+// @Failure 400 {object} response.ErrorResponse "没有可撤销的操作"
+// @Failure 404 {object} response.ErrorResponse "记录不存在"
+// @Failure 403 {object} response.ErrorResponse "无权访问"
+// @Router /api/v1/polish/compare/{trace_id}/undo [post]
+func (h *ComparisonHandler) Undo(c *gin.Context) {
+	traceID := c.Param("trace_id")
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, apperrors.NewUnauthorizedError("未登录"))
+		return
+	}
+
+	result, err := h.comparisonService.Undo(c.Request.Context(), traceID, userID.(int64))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// Redo 重做最近一次撤销的操作
+// @Summary 重做最近一次撤销的操作
+// @Description 重做最近一次被撤销的操作；撤销后执行新的操作会清空可重做的操作
+// @Tags 对比
+// @Accept json
+// @Produce json
+// @Param trace_id path string true "润色记录的 trace_id"
+// @Success 200 {object} model.ChangeActionResponse
+// @Failure 400 {object} response.ErrorResponse "没有可重做的操作"
+// @Failure 404 {object} response.ErrorResponse "记录不存在"
+// @Failure 403 {object} response.ErrorResponse "无权访问"
+// @Router /api/v1/polish/compare/{trace_id}/redo [post]
+func (h *ComparisonHandler) Redo(c *gin.Context) {
+	traceID := c.Param("trace_id")
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, apperrors.NewUnauthorizedError("未登录"))
+		return
+	}
+
+	result, err := h.comparisonService.Redo(c.Request.Context(), traceID, userID.(int64))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// GetHistory 获取操作历史
+// @Summary 获取操作历史
+// @Description 按顺序返回对该记录执行的所有接受/拒绝/撤销/重做操作，以及当前是否可以撤销/重做
+// @Tags 对比
+// @Accept json
+// @Produce json
+// @Param trace_id path string true "润色记录的 trace_id"
+// @Success 200 {object} model.ActionHistoryResponse
+// @Failure 404 {object} response.ErrorResponse "记录不存在"
+// @Failure 403 {object} response.ErrorResponse "无权访问"
+// @Router /api/v1/polish/compare/{trace_id}/history [get]
+func (h *ComparisonHandler) GetHistory(c *gin.Context) {
+	traceID := c.Param("trace_id")
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, apperrors.NewUnauthorizedError("未登录"))
+		return
+	}
+
+	result, err := h.comparisonService.GetHistory(c.Request.Context(), traceID, userID.(int64))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// GetHistoryContent 获取历史某一步的最终文本
+// @Summary 获取历史某一步的最终文本
+// @Description 返回执行完第 seq 条操作后的最终文本和各修改状态，seq 为 0 表示执行任何操作之前
+// @Tags 对比
+// @Accept json
+// @Produce json
+// @Param trace_id path string true "润色记录的 trace_id"
+// @Param seq path int true "操作序号"
+// @Success 200 {object} model.HistoryContentResponse
+// @Failure 400 {object} response.ErrorResponse "参数错误"
+// @Failure 404 {object} response.ErrorResponse "记录不存在"
+// @Failure 403 {object} response.ErrorResponse "无权访问"
+// @Router /api/v1/polish/compare/{trace_id}/history/{seq}/content [get]
+func (h *ComparisonHandler) GetHistoryContent(c *gin.Context) {
+	traceID := c.Param("trace_id")
+
+	seq, err := strconv.Atoi(c.Param("seq"))
+	if err != nil {
+		response.Error(c, apperrors.NewInvalidParameterError("seq 必须是整数"))
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, apperrors.NewUnauthorizedError("未登录"))
 		return
 	}
 
-	result, err := h.comparisonService.BatchAcceptAll(c.Request.Context(), traceID, userID.(int64), versionType)
+	result, err := h.comparisonService.GetContentAt(c.Request.Context(), traceID, userID.(int64), seq)
 	if err != nil {
 		response.Error(c, err)
 		return
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"paper_ai/internal/domain/model"
+	"paper_ai/internal/service"
+	apperrors "paper_ai/pkg/errors"
+	"paper_ai/pkg/response"
+)
+
+// GlossaryHandler 术语表处理器
+type GlossaryHandler struct {
+	glossaryService *service.GlossaryService
+}
+
+// NewGlossaryHandler 创建术语表处理器
+func NewGlossaryHandler(glossaryService *service.GlossaryService) *GlossaryHandler {
+	return &GlossaryHandler{
+		glossaryService: glossaryService,
+	}
+}
+
+// ListTerms 获取术语表
+// @Summary 获取术语表
+// @Description 获取当前用户的所有术语（按源术语排序）
+// @Tags 术语表
+// @Produce json
+// @Success 200 {array} model.GlossaryTermInfo
+// @Router /api/v1/glossary [get]
+func (h *GlossaryHandler) ListTerms(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, apperrors.NewUnauthorizedError("未登录"))
+		return
+	}
+
+	terms, err := h.glossaryService.ListTerms(c.Request.Context(), userID.(int64))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, terms)
+}
+
+// CreateTerm 添加术语
+// @Summary 添加术语
+// @Tags 术语表
+// @Accept json
+// @Produce json
+// @Param request body model.GlossaryTermRequest true "术语及固定译法"
+// @Success 200 {object} model.GlossaryTermInfo
+// @Failure 400 {object} response.ErrorResponse "术语或译法为空、过长，术语已存在或术语表已满"
+// @Router /api/v1/glossary [post]
+func (h *GlossaryHandler) CreateTerm(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, apperrors.NewUnauthorizedError("未登录"))
+		return
+	}
+
+	var req model.GlossaryTermRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	term, err := h.glossaryService.CreateTerm(c.Request.Context(), userID.(int64), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, term)
+}
+
+// UpdateTerm 修改术语
+// @Summary 修改术语
+// @Description 修改术语、译法和备注（note 为空表示清空备注）
+// @Tags 术语表
+// @Accept json
+// @Produce json
+// @Param id path int true "术语ID"
+// @Param request body model.GlossaryTermRequest true "术语及固定译法"
+// @Success 200 {object} model.GlossaryTermInfo
+// @Failure 404 {object} response.ErrorResponse "术语不存在"
+// @Router /api/v1/glossary/{id} [put]
+func (h *GlossaryHandler) UpdateTerm(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, apperrors.NewUnauthorizedError("未登录"))
+		return
+	}
+
+	termID, err := parseIDParam(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	var req model.GlossaryTermRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	term, err := h.glossaryService.UpdateTerm(c.Request.Context(), userID.(int64), termID, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, term)
+}
+
+// DeleteTerm 删除术语
+// @Summary 删除术语
+// @Tags 术语表
+// @Produce json
+// @Param id path int true "术语ID"
+// @Success 200 {object} response.Response
+// @Failure 404 {object} response.ErrorResponse "术语不存在"
+// @Router /api/v1/glossary/{id} [delete]
+func (h *GlossaryHandler) DeleteTerm(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, apperrors.NewUnauthorizedError("未登录"))
+		return
+	}
+
+	termID, err := parseIDParam(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if err := h.glossaryService.DeleteTerm(c.Request.Context(), userID.(int64), termID); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"message": "删除成功"})
+}
@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"net/http"
+
+	"paper_ai/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HealthHandler 健康检查处理器
+type HealthHandler struct {
+	healthService *service.HealthService
+}
+
+// NewHealthHandler 创建健康检查处理器
+func NewHealthHandler(healthService *service.HealthService) *HealthHandler {
+	return &HealthHandler{healthService: healthService}
+}
+
+// Livez 存活检查：进程能处理请求即返回 200，不检查外部依赖（依赖故障不应导致重启）
+// GET /livez
+func (h *HealthHandler) Livez(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status": "ok",
+	})
+}
+
+// Readyz 就绪检查：返回各依赖组件的状态和耗时，任一组件不可用或正在关闭时返回 503
+// GET /readyz
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	report := h.healthService.Readiness(c.Request.Context())
+
+	status := http.StatusOK
+	if !report.IsReady() {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
@@ -0,0 +1,371 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"paper_ai/internal/domain/model"
+	"paper_ai/internal/service"
+	apperrors "paper_ai/pkg/errors"
+	"paper_ai/pkg/response"
+)
+
+// OrganizeHandler 润色记录整理处理器（项目、标签、收藏）
+type OrganizeHandler struct {
+	organizeService *service.OrganizeService
+}
+
+// NewOrganizeHandler 创建整理处理器
+func NewOrganizeHandler(organizeService *service.OrganizeService) *OrganizeHandler {
+	return &OrganizeHandler{
+		organizeService: organizeService,
+	}
+}
+
+// ListProjects 获取项目列表
+// @Summary 获取项目列表
+// @Description 获取当前用户的所有项目（按名称排序），包含每个项目的记录数
+// @Tags 整理
+// @Produce json
+// @Success 200 {array} model.ProjectInfo
+// @Router /api/v1/projects [get]
+func (h *OrganizeHandler) ListProjects(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, apperrors.NewUnauthorizedError("未登录"))
+		return
+	}
+
+	projects, err := h.organizeService.ListProjects(c.Request.Context(), userID.(int64))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, projects)
+}
+
+// CreateProject 创建项目
+// @Summary 创建项目
+// @Tags 整理
+// @Accept json
+// @Produce json
+// @Param request body model.ProjectRequest true "项目信息"
+// @Success 200 {object} model.ProjectInfo
+// @Failure 400 {object} response.ErrorResponse "名称为空、过长或已存在"
+// @Router /api/v1/projects [post]
+func (h *OrganizeHandler) CreateProject(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, apperrors.NewUnauthorizedError("未登录"))
+		return
+	}
+
+	var req model.ProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	project, err := h.organizeService.CreateProject(c.Request.Context(), userID.(int64), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, project)
+}
+
+// UpdateProject 修改项目
+// @Summary 修改项目
+// @Description 修改项目名称和描述（description 为空表示清空描述）
+// @Tags 整理
+// @Accept json
+// @Produce json
+// @Param id path int true "项目ID"
+// @Param request body model.ProjectRequest true "项目信息"
+// @Success 200 {object} model.ProjectInfo
+// @Failure 404 {object} response.ErrorResponse "项目不存在"
+// @Router /api/v1/projects/{id} [put]
+func (h *OrganizeHandler) UpdateProject(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, apperrors.NewUnauthorizedError("未登录"))
+		return
+	}
+
+	projectID, err := parseIDParam(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	var req model.ProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	project, err := h.organizeService.UpdateProject(c.Request.Context(), userID.(int64), projectID, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, project)
+}
+
+// DeleteProject 删除项目
+// @Summary 删除项目
+// @Description 删除项目，项目中的润色记录保留并移出项目
+// @Tags 整理
+// @Produce json
+// @Param id path int true "项目ID"
+// @Success 200 {object} response.Response
+// @Failure 404 {object} response.ErrorResponse "项目不存在"
+// @Router /api/v1/projects/{id} [delete]
+func (h *OrganizeHandler) DeleteProject(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, apperrors.NewUnauthorizedError("未登录"))
+		return
+	}
+
+	projectID, err := parseIDParam(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if err := h.organizeService.DeleteProject(c.Request.Context(), userID.(int64), projectID); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"message": "删除成功"})
+}
+
+// ListTags 获取标签列表
+// @Summary 获取标签列表
+// @Tags 整理
+// @Produce json
+// @Success 200 {array} model.TagInfo
+// @Router /api/v1/tags [get]
+func (h *OrganizeHandler) ListTags(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, apperrors.NewUnauthorizedError("未登录"))
+		return
+	}
+
+	tags, err := h.organizeService.ListTags(c.Request.Context(), userID.(int64))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, tags)
+}
+
+// CreateTag 创建标签
+// @Summary 创建标签
+// @Tags 整理
+// @Accept json
+// @Produce json
+// @Param request body model.TagRequest true "标签信息"
+// @Success 200 {object} model.TagInfo
+// @Failure 400 {object} response.ErrorResponse "名称为空、过长或已存在"
+// @Router /api/v1/tags [post]
+func (h *OrganizeHandler) CreateTag(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, apperrors.NewUnauthorizedError("未登录"))
+		return
+	}
+
+	var req model.TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	tag, err := h.organizeService.CreateTag(c.Request.Context(), userID.(int64), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, tag)
+}
+
+// UpdateTag 重命名标签
+// @Summary 重命名标签
+// @Tags 整理
+// @Accept json
+// @Produce json
+// @Param id path int true "标签ID"
+// @Param request body model.TagRequest true "标签信息"
+// @Success 200 {object} model.TagInfo
+// @Failure 404 {object} response.ErrorResponse "标签不存在"
+// @Router /api/v1/tags/{id} [put]
+func (h *OrganizeHandler) UpdateTag(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, apperrors.NewUnauthorizedError("未登录"))
+		return
+	}
+
+	tagID, err := parseIDParam(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	var req model.TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	tag, err := h.organizeService.UpdateTag(c.Request.Context(), userID.(int64), tagID, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, tag)
+}
+
+// DeleteTag 删除标签
+// @Summary 删除标签
+// @Description 删除标签并从所有润色记录上移除
+// @Tags 整理
+// @Produce json
+// @Param id path int true "标签ID"
+// @Success 200 {object} response.Response
+// @Failure 404 {object} response.ErrorResponse "标签不存在"
+// @Router /api/v1/tags/{id} [delete]
+func (h *OrganizeHandler) DeleteTag(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, apperrors.NewUnauthorizedError("未登录"))
+		return
+	}
+
+	tagID, err := parseIDParam(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if err := h.organizeService.DeleteTag(c.Request.Context(), userID.(int64), tagID); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"message": "删除成功"})
+}
+
+// SetRecordProject 设置记录所属项目
+// @Summary 设置记录所属项目
+// @Tags 整理
+// @Accept json
+// @Produce json
+// @Param trace_id path string true "润色记录的 trace_id"
+// @Param request body model.SetRecordProjectRequest true "project_id 为 null 表示移出项目"
+// @Success 200 {object} response.Response
+// @Failure 404 {object} response.ErrorResponse "记录或项目不存在"
+// @Router /api/v1/polish/records/{trace_id}/project [put]
+func (h *OrganizeHandler) SetRecordProject(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, apperrors.NewUnauthorizedError("未登录"))
+		return
+	}
+
+	var req model.SetRecordProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if err := h.organizeService.SetRecordProject(c.Request.Context(), userID.(int64), c.Param("trace_id"), req.ProjectID); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"project_id": req.ProjectID})
+}
+
+// SetRecordFavorite 收藏/取消收藏记录
+// @Summary 收藏/取消收藏记录
+// @Tags 整理
+// @Accept json
+// @Produce json
+// @Param trace_id path string true "润色记录的 trace_id"
+// @Param request body model.SetRecordFavoriteRequest true "是否收藏"
+// @Success 200 {object} response.Response
+// @Failure 404 {object} response.ErrorResponse "记录不存在"
+// @Router /api/v1/polish/records/{trace_id}/favorite [put]
+func (h *OrganizeHandler) SetRecordFavorite(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, apperrors.NewUnauthorizedError("未登录"))
+		return
+	}
+
+	var req model.SetRecordFavoriteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if err := h.organizeService.SetRecordFavorite(c.Request.Context(), userID.(int64), c.Param("trace_id"), *req.Favorite); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"favorite": *req.Favorite})
+}
+
+// SetRecordTags 设置记录标签
+// @Summary 设置记录标签
+// @Description 用 tag_ids 替换记录的全部标签（空数组表示清除）
+// @Tags 整理
+// @Accept json
+// @Produce json
+// @Param trace_id path string true "润色记录的 trace_id"
+// @Param request body model.SetRecordTagsRequest true "标签ID列表"
+// @Success 200 {array} model.TagInfo
+// @Failure 404 {object} response.ErrorResponse "记录或标签不存在"
+// @Router /api/v1/polish/records/{trace_id}/tags [put]
+func (h *OrganizeHandler) SetRecordTags(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, apperrors.NewUnauthorizedError("未登录"))
+		return
+	}
+
+	var req model.SetRecordTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	tags, err := h.organizeService.SetRecordTags(c.Request.Context(), userID.(int64), c.Param("trace_id"), req.TagIDs)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, tags)
+}
+
+// parseIDParam 解析路径参数 id
+func parseIDParam(c *gin.Context) (int64, error) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, apperrors.NewInvalidParameterError("无效的 id")
+	}
+	return id, nil
+}
@@ -1,21 +1,27 @@
 package handler
 
 import (
+	"errors"
+	"io"
+
 	"github.com/gin-gonic/gin"
 	"paper_ai/internal/domain/model"
 	"paper_ai/internal/service"
+	apperrors "paper_ai/pkg/errors"
 	"paper_ai/pkg/response"
 )
 
 // PolishHandler 润色处理器
 type PolishHandler struct {
-	polishService *service.PolishService
+	polishService   *service.PolishService
+	repolishService *service.RepolishService
 }
 
 // NewPolishHandler 创建润色处理器
-func NewPolishHandler(polishService *service.PolishService) *PolishHandler {
+func NewPolishHandler(polishService *service.PolishService, repolishService *service.RepolishService) *PolishHandler {
 	return &PolishHandler{
-		polishService: polishService,
+		polishService:   polishService,
+		repolishService: repolishService,
 	}
 }
 
@@ -41,3 +47,39 @@ func (h *PolishHandler) Polish(c *gin.Context) {
 
 	response.Success(c, resp)
 }
+
+// Repolish 基于已有记录重新润色
+// @Summary 重新润色
+// @Description 用记录的原文（source=original）或当前最终文本（source=final）重新执行单版本或多版本润色，未指定的参数沿用原记录；新记录的 parent_trace_id 指向原记录
+// @Tags polish
+// @Accept json
+// @Produce json
+// @Param trace_id path string true "来源记录的 trace_id"
+// @Param request body model.RepolishRequest false "覆盖的润色参数"
+// @Success 200 {object} response.Response{data=model.RepolishResponse}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /api/v1/polish/records/{trace_id}/repolish [post]
+func (h *PolishHandler) Repolish(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, apperrors.NewUnauthorizedError("请先登录"))
+		return
+	}
+
+	// 请求体可选：为空时全部沿用原记录的参数
+	var req model.RepolishRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.Error(c, apperrors.NewInvalidParameterError("请求体格式错误"))
+		return
+	}
+
+	resp, err := h.repolishService.Repolish(c.Request.Context(), c.Param("trace_id"), &req, userID.(int64))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
@@ -1,7 +1,11 @@
 package handler
 
 import (
+	"errors"
+	"io"
+
 	"github.com/gin-gonic/gin"
+	"paper_ai/internal/domain/entity"
 	"paper_ai/internal/domain/model"
 	"paper_ai/internal/service"
 	apperrors "paper_ai/pkg/errors"
@@ -56,15 +60,16 @@ func (h *PolishMultiVersionHandler) PolishMultiVersion(c *gin.Context) {
 	response.Success(c, resp)
 }
 
-// SelectVersion 选择一个版本
-// @Summary 选择多版本润色中的一个版本
-// @Description 用户从多版本润色结果中选择一个版本，系统会将该版本的内容复制到主记录
+// SelectVersion 选择一个版本，或按句组合多个版本
+// @Summary 选择多版本润色中的一个版本，或按句组合多个版本
+// @Description 不传请求体时，将 version 指定版本的内容复制到主记录；请求体中提供 sentences（句子序号 -> 版本类型）时，按句组合多个版本，未指定的句子使用 default 或 version 指定的版本
 // @Tags polish
 // @Accept json
 // @Produce json
 // @Param trace_id path string true "润色记录的 trace_id"
-// @Param version query string true "版本类型：conservative/balanced/aggressive"
-// @Success 200 {object} response.Response{data=map[string]string}
+// @Param version query string false "版本类型：conservative/balanced/aggressive（整体选择时必填）"
+// @Param request body model.SelectVersionRequest false "按句组合选择"
+// @Success 200 {object} response.Response{data=model.SelectVersionResponse}
 // @Failure 400 {object} response.Response
 // @Failure 403 {object} response.Response
 // @Failure 404 {object} response.Response
@@ -73,7 +78,14 @@ func (h *PolishMultiVersionHandler) SelectVersion(c *gin.Context) {
 	traceID := c.Param("trace_id")
 	versionType := c.Query("version")
 
-	if versionType == "" {
+	// 请求体可选：为空时按整体选择处理
+	var req model.SelectVersionRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.Error(c, apperrors.NewInvalidParameterError("请求体格式错误"))
+		return
+	}
+
+	if versionType == "" && len(req.Sentences) == 0 {
 		response.Error(c, apperrors.NewInvalidParameterError("version 参数不能为空"))
 		return
 	}
@@ -85,6 +97,27 @@ func (h *PolishMultiVersionHandler) SelectVersion(c *gin.Context) {
 		return
 	}
 
+	// 按句组合
+	if len(req.Sentences) > 0 {
+		if req.Default == "" {
+			req.Default = versionType
+		}
+
+		sources, err := h.multiVersionService.SelectSentences(c.Request.Context(), traceID, userID.(int64), &req)
+		if err != nil {
+			response.Error(c, err)
+			return
+		}
+
+		response.Success(c, &model.SelectVersionResponse{
+			Message:         "版本组合成功",
+			TraceID:         traceID,
+			SelectedVersion: entity.SelectedVersionComposite,
+			SentenceSources: sources,
+		})
+		return
+	}
+
 	// 调用服务
 	err := h.multiVersionService.SelectVersion(c.Request.Context(), traceID, userID.(int64), versionType)
 	if err != nil {
@@ -92,9 +125,39 @@ func (h *PolishMultiVersionHandler) SelectVersion(c *gin.Context) {
 		return
 	}
 
-	response.Success(c, map[string]string{
-		"message": "版本选择成功",
-		"trace_id": traceID,
-		"selected_version": versionType,
+	response.Success(c, &model.SelectVersionResponse{
+		Message:         "版本选择成功",
+		TraceID:         traceID,
+		SelectedVersion: versionType,
 	})
 }
+
+// GetAlignedSentences 获取按句对齐的多版本结果
+// @Summary 获取按句对齐的多版本结果
+// @Description 将原文和每个成功版本切分为对齐的句子，供用户逐句选择版本
+// @Tags polish
+// @Accept json
+// @Produce json
+// @Param trace_id path string true "润色记录的 trace_id"
+// @Success 200 {object} response.Response{data=model.SentenceAlignmentResponse}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /api/v1/polish/select-version/{trace_id}/sentences [get]
+func (h *PolishMultiVersionHandler) GetAlignedSentences(c *gin.Context) {
+	traceID := c.Param("trace_id")
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, apperrors.NewUnauthorizedError("请先登录"))
+		return
+	}
+
+	result, err := h.multiVersionService.GetAlignedSentences(c.Request.Context(), traceID, userID.(int64))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
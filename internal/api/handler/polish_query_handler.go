@@ -2,10 +2,12 @@ package handler
 
 import (
 	"strconv"
+	"strings"
 	"time"
 
 	"paper_ai/internal/domain/repository"
 	"paper_ai/internal/service"
+	apperrors "paper_ai/pkg/errors"
 	"paper_ai/pkg/response"
 
 	"github.com/gin-gonic/gin"
@@ -54,48 +56,163 @@ func (h *PolishQueryHandler) GetRecordByTraceID(c *gin.Context) {
 
 // ListRecords 查询记录列表
 // GET /api/v1/polish/records?page=1&page_size=20&provider=doubao&status=success&language=zh
+// 排序：sort=created_at|process_time_ms|original_length|changes_count，order=desc|asc（默认按创建时间降序）
+// 游标分页：cursor=（第一页）或 cursor=<上一页返回的 next_cursor>，此时忽略 page 且不返回 total
 func (h *PolishQueryHandler) ListRecords(c *gin.Context) {
 	// 解析分页参数
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
 
-	// 解析过滤参数
-	provider := c.Query("provider")
-	status := c.Query("status")
-	language := c.Query("language")
-	style := c.Query("style")
-
-	// 解析时间范围
-	startTimeStr := c.Query("start_time")
-	endTimeStr := c.Query("end_time")
-
 	// 解析是否排除大文本
 	excludeText := c.Query("exclude_text") == "true"
 
+	// 解析排序参数
+	sortField := c.DefaultQuery("sort", "created_at")
+	if !repository.IsSortableField(sortField) {
+		response.Error(c, apperrors.NewInvalidParameterError("不支持的排序字段: "+sortField))
+		return
+	}
+	order := c.DefaultQuery("order", "desc")
+	if order != "desc" && order != "asc" {
+		response.Error(c, apperrors.NewInvalidParameterError("order 只能为 desc 或 asc"))
+		return
+	}
+
 	// 构建查询选项
+	builder := repository.NewQueryOptions().
+		OrderBy(sortField, order == "desc")
+	applyRecordFilters(c, builder)
+
+	cursorParam, cursorMode := c.GetQuery("cursor")
+	if cursorMode {
+		cursor, err := parseCursorQuery(c, cursorParam, sortField, order == "desc")
+		if err != nil {
+			response.Error(c, err)
+			return
+		}
+		builder.CursorPage(cursor, pageSize)
+	} else {
+		builder.Page(page, pageSize)
+	}
+
+	if excludeText {
+		builder.ExcludeText()
+	}
+
+	opts := builder.Build()
+
+	// 查询记录
+	records, total, nextCursor, err := h.polishService.ListRecords(c.Request.Context(), opts)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if opts.CursorMode {
+		response.Success(c, gin.H{
+			"records":     records,
+			"page_size":   opts.PageSize,
+			"next_cursor": nextCursor,
+		})
+		return
+	}
+
+	response.Success(c, gin.H{
+		"records":     records,
+		"total":       total,
+		"page":        page,
+		"page_size":   pageSize,
+		"next_cursor": nextCursor,
+	})
+}
+
+// parseCursorQuery 解析游标参数（空字符串表示第一页）
+// 显式指定的排序方式必须与游标中记录的一致
+func parseCursorQuery(c *gin.Context, value, sortField string, desc bool) (*repository.Cursor, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	cursor, err := repository.DecodeCursor(value)
+	if err != nil {
+		return nil, apperrors.NewInvalidParameterError("无效的游标")
+	}
+
+	_, hasSort := c.GetQuery("sort")
+	_, hasOrder := c.GetQuery("order")
+	if (hasSort && cursor.OrderBy != sortField) || (hasOrder && cursor.OrderDesc != desc) {
+		return nil, apperrors.NewInvalidParameterError("游标与排序方式不一致")
+	}
+	return cursor, nil
+}
+
+// SearchRecords 全文检索润色历史
+// GET /api/v1/polish/records/search?q=neural+network&page=1&page_size=20&status=success
+// 支持与 ListRecords 相同的过滤参数；exclude_text=true 时只返回摘要
+func (h *PolishQueryHandler) SearchRecords(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	excludeText := c.Query("exclude_text") == "true"
+
 	builder := repository.NewQueryOptions().
 		Page(page, pageSize).
-		OrderBy("created_at", true) // 默认按创建时间降序
+		OrderBy("created_at", true). // 相关度相同时按创建时间降序
+		WithSearchText(strings.TrimSpace(c.Query("q")))
+	applyRecordFilters(c, builder)
 
+	opts := builder.Build()
+
+	hits, total, err := h.polishService.SearchRecords(c.Request.Context(), opts, excludeText)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{
+		"records":   hits,
+		"total":     total,
+		"page":      opts.Page,
+		"page_size": opts.PageSize,
+	})
+}
+
+// applyRecordFilters 解析记录列表的过滤参数（用户、提供商、状态、语言、风格、项目、标签、收藏、来源记录、时间范围）
+func applyRecordFilters(c *gin.Context, builder *repository.QueryOptionsBuilder) {
 	// 从JWT上下文获取用户ID，确保只能查询自己的记录
 	userID, exists := c.Get("user_id")
 	if exists {
 		builder.WithUserID(userID.(int64))
 	}
 
-	if provider != "" {
+	if provider := c.Query("provider"); provider != "" {
 		builder.WithProvider(provider)
 	}
-	if status != "" {
+	if status := c.Query("status"); status != "" {
 		builder.WithStatus(status)
 	}
-	if language != "" {
+	if language := c.Query("language"); language != "" {
 		builder.WithLanguage(language)
 	}
-	if style != "" {
+	if style := c.Query("style"); style != "" {
 		builder.WithStyle(style)
 	}
 
+	// 整理信息过滤（project_id=0 表示未归属项目的记录）
+	if projectID, err := strconv.ParseInt(c.Query("project_id"), 10, 64); err == nil && projectID >= 0 {
+		builder.WithProject(projectID)
+	}
+	if tagID, err := strconv.ParseInt(c.Query("tag_id"), 10, 64); err == nil && tagID > 0 {
+		builder.WithTag(tagID)
+	}
+	if favorite, err := strconv.ParseBool(c.Query("favorite")); err == nil {
+		builder.WithFavorite(favorite)
+	}
+	if parentTraceID := c.Query("parent_trace_id"); parentTraceID != "" {
+		builder.WithParentTraceID(parentTraceID)
+	}
+
+	startTimeStr := c.Query("start_time")
+	endTimeStr := c.Query("end_time")
 	if startTimeStr != "" && endTimeStr != "" {
 		startTime, err1 := time.Parse(time.RFC3339, startTimeStr)
 		endTime, err2 := time.Parse(time.RFC3339, endTimeStr)
@@ -103,26 +220,6 @@ func (h *PolishQueryHandler) ListRecords(c *gin.Context) {
 			builder.WithTimeRange(startTime, endTime)
 		}
 	}
-
-	if excludeText {
-		builder.ExcludeText()
-	}
-
-	opts := builder.Build()
-
-	// 查询记录
-	records, total, err := h.polishService.ListRecords(c.Request.Context(), opts)
-	if err != nil {
-		response.Error(c, err)
-		return
-	}
-
-	response.Success(c, gin.H{
-		"records":   records,
-		"total":     total,
-		"page":      page,
-		"page_size": pageSize,
-	})
 }
 
 // GetStatistics 获取统计信息
@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"paper_ai/internal/domain/model"
+	"paper_ai/internal/service"
+	apperrors "paper_ai/pkg/errors"
+	"paper_ai/pkg/response"
+)
+
+// PrivacyHandler 隐私处理器（隐私设置、删除润色历史）
+type PrivacyHandler struct {
+	privacyService *service.PrivacyService
+}
+
+// NewPrivacyHandler 创建隐私处理器
+func NewPrivacyHandler(privacyService *service.PrivacyService) *PrivacyHandler {
+	return &PrivacyHandler{
+		privacyService: privacyService,
+	}
+}
+
+// GetSettings 获取隐私设置
+// @Summary 获取隐私设置
+// @Description 获取当前用户的历史保留天数和不保存内容模式
+// @Tags 隐私
+// @Produce json
+// @Success 200 {object} model.PrivacySettings
+// @Router /api/v1/user/privacy [get]
+func (h *PrivacyHandler) GetSettings(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, apperrors.NewUnauthorizedError("未登录"))
+		return
+	}
+
+	settings, err := h.privacyService.GetSettings(c.Request.Context(), userID.(int64))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, settings)
+}
+
+// UpdateSettings 更新隐私设置
+// @Summary 更新隐私设置
+// @Description retention_days 大于 0 时，超过该天数的润色记录由后台任务自动删除；no_store_content 为 true 时之后的润色只保存元数据
+// @Tags 隐私
+// @Accept json
+// @Produce json
+// @Param request body model.UpdatePrivacySettingsRequest true "隐私设置（未提供的字段不修改）"
+// @Success 200 {object} model.PrivacySettings
+// @Failure 400 {object} response.ErrorResponse "参数错误"
+// @Router /api/v1/user/privacy [put]
+func (h *PrivacyHandler) UpdateSettings(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, apperrors.NewUnauthorizedError("未登录"))
+		return
+	}
+
+	var req model.UpdatePrivacySettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	settings, err := h.privacyService.UpdateSettings(c.Request.Context(), userID.(int64), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, settings)
+}
+
+// DeleteRecord 删除单条润色记录
+// @Summary 删除润色记录
+// @Description 永久删除一条润色记录及其所有版本和对比操作记录
+// @Tags 隐私
+// @Produce json
+// @Param trace_id path string true "润色记录的 trace_id"
+// @Success 200 {object} response.Response
+// @Failure 403 {object} response.ErrorResponse "无权删除"
+// @Failure 404 {object} response.ErrorResponse "记录不存在"
+// @Router /api/v1/polish/records/{trace_id} [delete]
+func (h *PrivacyHandler) DeleteRecord(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, apperrors.NewUnauthorizedError("未登录"))
+		return
+	}
+
+	if err := h.privacyService.DeleteRecord(c.Request.Context(), userID.(int64), c.Param("trace_id")); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"message": "删除成功"})
+}
+
+// DeleteRecords 按时间范围批量删除润色记录
+// @Summary 批量删除润色记录
+// @Description 永久删除 [start_time, end_time) 内创建的润色记录。两者都不提供时需要 all=true，删除全部历史
+// @Tags 隐私
+// @Produce json
+// @Param start_time query string false "开始时间（RFC3339，包含）"
+// @Param end_time query string false "结束时间（RFC3339，不包含）"
+// @Param all query bool false "未提供时间范围时必须为 true"
+// @Success 200 {object} model.DeleteRecordsResponse
+// @Failure 400 {object} response.ErrorResponse "参数错误"
+// @Router /api/v1/polish/records [delete]
+func (h *PrivacyHandler) DeleteRecords(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, apperrors.NewUnauthorizedError("未登录"))
+		return
+	}
+
+	start, err := parseTimeQuery(c, "start_time")
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	end, err := parseTimeQuery(c, "end_time")
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if start == nil && end == nil && c.Query("all") != "true" {
+		response.Error(c, apperrors.NewInvalidParameterError("请提供 start_time/end_time，或使用 all=true 删除全部历史"))
+		return
+	}
+
+	deleted, err := h.privacyService.DeleteRecords(c.Request.Context(), userID.(int64), start, end)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, &model.DeleteRecordsResponse{Deleted: deleted})
+}
+
+// parseTimeQuery 解析 RFC3339 时间查询参数，未提供时返回 nil
+func parseTimeQuery(c *gin.Context, key string) (*time.Time, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, apperrors.NewInvalidParameterError(key + " 必须是 RFC3339 格式，如 2024-01-01T00:00:00Z")
+	}
+	return &t, nil
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"paper_ai/internal/domain/model"
+	"paper_ai/internal/service"
+	apperrors "paper_ai/pkg/errors"
+	"paper_ai/pkg/response"
+)
+
+// RefineHandler 多轮修改处理器
+type RefineHandler struct {
+	refineService *service.RefineService
+}
+
+// NewRefineHandler 创建多轮修改处理器
+func NewRefineHandler(refineService *service.RefineService) *RefineHandler {
+	return &RefineHandler{
+		refineService: refineService,
+	}
+}
+
+// Refine 按修改指令继续修改润色结果
+// @Summary 多轮修改
+// @Description 用自然语言指令（如"更正式一些"、"缩短第二句"）继续修改记录当前的文本；原文、之前各轮的结果和指令作为对话发送给提供商。成功的修订成为记录当前的润色结果（对比数据重新生成，之前的接受/拒绝状态清空）
+// @Tags polish
+// @Accept json
+// @Produce json
+// @Param trace_id path string true "润色记录的 trace_id"
+// @Param request body model.RefineRequest true "修改指令"
+// @Success 200 {object} model.RevisionInfo
+// @Failure 400 {object} response.ErrorResponse "指令为空或过长、记录没有润色结果、修改轮数已达上限"
+// @Failure 403 {object} response.ErrorResponse "无权访问"
+// @Failure 404 {object} response.ErrorResponse "记录不存在"
+// @Router /api/v1/polish/records/{trace_id}/refine [post]
+func (h *RefineHandler) Refine(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, apperrors.NewUnauthorizedError("请先登录"))
+		return
+	}
+
+	var req model.RefineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	revision, err := h.refineService.Refine(c.Request.Context(), c.Param("trace_id"), &req, userID.(int64))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, revision)
+}
+
+// ListRevisions 获取记录的修订列表
+// @Summary 获取修订列表
+// @Description 按修订号升序返回记录的所有修改轮次（包括失败的），不含对比数据
+// @Tags polish
+// @Produce json
+// @Param trace_id path string true "润色记录的 trace_id"
+// @Success 200 {array} model.RevisionInfo
+// @Failure 403 {object} response.ErrorResponse "无权访问"
+// @Failure 404 {object} response.ErrorResponse "记录不存在"
+// @Router /api/v1/polish/records/{trace_id}/revisions [get]
+func (h *RefineHandler) ListRevisions(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, apperrors.NewUnauthorizedError("请先登录"))
+		return
+	}
+
+	revisions, err := h.refineService.ListRevisions(c.Request.Context(), c.Param("trace_id"), userID.(int64))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, revisions)
+}
+
+// GetRevision 获取指定修订
+// @Summary 获取修订详情
+// @Description 返回指定修订及其对比数据（修改前文本 → 本轮结果）
+// @Tags polish
+// @Produce json
+// @Param trace_id path string true "润色记录的 trace_id"
+// @Param revision path int true "修订号"
+// @Success 200 {object} model.RevisionInfo
+// @Failure 400 {object} response.ErrorResponse "参数错误"
+// @Failure 403 {object} response.ErrorResponse "无权访问"
+// @Failure 404 {object} response.ErrorResponse "记录或修订不存在"
+// @Router /api/v1/polish/records/{trace_id}/revisions/{revision} [get]
+func (h *RefineHandler) GetRevision(c *gin.Context) {
+	revisionNumber, err := strconv.Atoi(c.Param("revision"))
+	if err != nil || revisionNumber < 1 {
+		response.Error(c, apperrors.NewInvalidParameterError("revision 必须是正整数"))
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, apperrors.NewUnauthorizedError("请先登录"))
+		return
+	}
+
+	revision, err := h.refineService.GetRevision(c.Request.Context(), c.Param("trace_id"), revisionNumber, userID.(int64))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, revision)
+}
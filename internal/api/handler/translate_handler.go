@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"paper_ai/internal/domain/model"
+	"paper_ai/internal/service"
+	apperrors "paper_ai/pkg/errors"
+	"paper_ai/pkg/response"
+)
+
+// TranslateHandler 翻译润色处理器
+type TranslateHandler struct {
+	translateService *service.TranslateService
+}
+
+// NewTranslateHandler 创建翻译润色处理器
+func NewTranslateHandler(translateService *service.TranslateService) *TranslateHandler {
+	return &TranslateHandler{
+		translateService: translateService,
+	}
+}
+
+// Translate 翻译润色
+// @Summary 翻译润色
+// @Description 把源语言草稿翻译为目标语言的学术文本（源语言默认自动检测，目标语言默认英文）；原文中出现的术语表术语要求使用固定译法（use_glossary=false 时不使用术语表）。记录的 mode 为 translate
+// @Tags polish
+// @Accept json
+// @Produce json
+// @Param request body model.TranslateRequest true "翻译请求"
+// @Success 200 {object} model.TranslateResponse
+// @Failure 400 {object} response.ErrorResponse "参数错误（例如源语言和目标语言相同）"
+// @Router /api/v1/polish/translate [post]
+func (h *TranslateHandler) Translate(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, apperrors.NewUnauthorizedError("请先登录"))
+		return
+	}
+
+	var req model.TranslateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	resp, err := h.translateService.Translate(c.Request.Context(), &req, userID.(int64))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// GetBilingual 获取双语对照
+// @Summary 获取双语对照
+// @Description 原文和译文按句对齐（一句可能对应多句），并按当前术语表检查每组句子的术语译法是否一致
+// @Tags polish
+// @Produce json
+// @Param trace_id path string true "翻译记录的 trace_id"
+// @Success 200 {object} model.BilingualComparison
+// @Failure 400 {object} response.ErrorResponse "不是翻译记录或没有译文"
+// @Failure 403 {object} response.ErrorResponse "无权访问"
+// @Failure 404 {object} response.ErrorResponse "记录不存在"
+// @Router /api/v1/polish/translate/{trace_id}/bilingual [get]
+func (h *TranslateHandler) GetBilingual(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, apperrors.NewUnauthorizedError("请先登录"))
+		return
+	}
+
+	bilingual, err := h.translateService.GetBilingual(c.Request.Context(), c.Param("trace_id"), userID.(int64))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, bilingual)
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"paper_ai/internal/infrastructure/security"
 	apperrors "paper_ai/pkg/errors"
+	"paper_ai/pkg/reqctx"
 	"paper_ai/pkg/response"
 )
 
@@ -41,6 +42,7 @@ func AuthRequired(jwtManager *security.JWTManager) gin.HandlerFunc {
 		// 4. 保存用户信息到上下文
 		c.Set("user_id", claims.UserID)
 		c.Set("username", claims.Username)
+		c.Request = c.Request.WithContext(reqctx.WithUserID(c.Request.Context(), claims.UserID))
 
 		c.Next()
 	}
@@ -66,6 +68,7 @@ func OptionalAuth(jwtManager *security.JWTManager) gin.HandlerFunc {
 		if err == nil {
 			c.Set("user_id", claims.UserID)
 			c.Set("username", claims.Username)
+			c.Request = c.Request.WithContext(reqctx.WithUserID(c.Request.Context(), claims.UserID))
 		}
 
 		c.Next()
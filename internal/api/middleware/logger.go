@@ -6,6 +6,9 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"paper_ai/pkg/logger"
+	"paper_ai/pkg/reqctx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
@@ -16,17 +19,22 @@ func Logger() gin.HandlerFunc {
 		path := c.Request.URL.Path
 		query := c.Request.URL.RawQuery
 
-		// 生成RequestID用于日志追踪（不影响业务TraceID）
-		requestID := uuid.New().String()
+		// RequestID用于日志追踪（不影响业务TraceID）：优先沿用客户端/网关传入的 X-Request-ID
+		requestID := c.GetHeader(reqctx.HeaderRequestID)
+		if !reqctx.ValidRequestID(requestID) {
+			requestID = uuid.New().String()
+		}
 		c.Set("request_id", requestID)
+		c.Header(reqctx.HeaderRequestID, requestID)
+		c.Request = c.Request.WithContext(reqctx.WithRequestID(c.Request.Context(), requestID))
+		trace.SpanFromContext(c.Request.Context()).SetAttributes(attribute.String("request_id", requestID))
 
 		// 处理请求
 		c.Next()
 
-		// 记录日志
+		// 记录日志（request_id、user_id 由请求上下文提供）
 		latency := time.Since(start)
-		logger.Info("request completed",
-			zap.String("request_id", requestID),
+		logger.FromContext(c.Request.Context()).Info("request completed",
 			zap.String("method", c.Request.Method),
 			zap.String("path", path),
 			zap.String("query", query),
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+)
+
+// untracedPaths 不创建 span 的路径（探活和指标抓取）
+var untracedPaths = map[string]bool{
+	"/health":  true,
+	"/livez":   true,
+	"/readyz":  true,
+	"/metrics": true,
+}
+
+// Tracing 链路追踪中间件：为每个请求创建以路由模板命名的 span（未启用追踪时为 no-op）
+// 需注册在 Logger 之前，Logger 会把请求ID记录到该 span 上
+func Tracing() gin.HandlerFunc {
+	return otelgin.Middleware("paper_ai",
+		otelgin.WithFilter(func(r *http.Request) bool {
+			return !untracedPaths[r.URL.Path]
+		}),
+	)
+}
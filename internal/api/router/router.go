@@ -4,6 +4,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"paper_ai/internal/api/handler"
 	"paper_ai/internal/api/middleware"
+	"paper_ai/internal/infrastructure/metrics"
 	"paper_ai/internal/infrastructure/security"
 )
 
@@ -14,6 +15,12 @@ func Setup(
 	queryHandler *handler.PolishQueryHandler,
 	comparisonHandler *handler.ComparisonHandler,
 	authHandler *handler.AuthHandler,
+	healthHandler *handler.HealthHandler,
+	privacyHandler *handler.PrivacyHandler,
+	organizeHandler *handler.OrganizeHandler,
+	refineHandler *handler.RefineHandler,
+	glossaryHandler *handler.GlossaryHandler,
+	translateHandler *handler.TranslateHandler,
 	jwtManager *security.JWTManager,
 ) *gin.Engine {
 	// 设置Gin为发布模式
@@ -23,7 +30,9 @@ func Setup(
 
 	// 注册全局中间件
 	r.Use(middleware.Recovery())
+	r.Use(middleware.Tracing())
 	r.Use(middleware.Logger())
+	r.Use(middleware.Metrics())
 	r.Use(middleware.CORS())
 
 	// 根路径 - 欢迎页面
@@ -36,12 +45,13 @@ func Setup(
 		})
 	})
 
-	// 健康检查接口
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"status": "ok",
-		})
-	})
+	// 健康检查接口（/health 保留兼容，等同于 /livez）
+	r.GET("/health", healthHandler.Livez)
+	r.GET("/livez", healthHandler.Livez)
+	r.GET("/readyz", healthHandler.Readyz)
+
+	// Prometheus 指标
+	r.GET("/metrics", gin.WrapH(metrics.Handler()))
 
 	// API v1 路由组
 	v1 := r.Group("/api/v1")
@@ -62,21 +72,66 @@ func Setup(
 			authenticated.GET("/auth/me", authHandler.GetCurrentUser)
 			authenticated.POST("/auth/logout", authHandler.Logout)
 
+			// 隐私设置（需要认证）
+			authenticated.GET("/user/privacy", privacyHandler.GetSettings)
+			authenticated.PUT("/user/privacy", privacyHandler.UpdateSettings)
+
 			// 段落润色（需要认证）
 			authenticated.POST("/polish", polishHandler.Polish)
 			// 多版本润色（需要认证）
 			authenticated.POST("/polish/multi", multiVersionHandler.PolishMultiVersion)
 			// 选择版本（需要认证）
 			authenticated.POST("/polish/select-version/:trace_id", multiVersionHandler.SelectVersion)
+			authenticated.GET("/polish/select-version/:trace_id/sentences", multiVersionHandler.GetAlignedSentences)
+			// 翻译润色及双语对照（需要认证）
+			authenticated.POST("/polish/translate", translateHandler.Translate)
+			authenticated.GET("/polish/translate/:trace_id/bilingual", translateHandler.GetBilingual)
 
 			// 查询记录（需要认证）
 			authenticated.GET("/polish/records", queryHandler.ListRecords)
+			authenticated.GET("/polish/records/search", queryHandler.SearchRecords)
 			authenticated.GET("/polish/records/:trace_id", queryHandler.GetRecordByTraceID)
+			// 重新润色（需要认证）
+			authenticated.POST("/polish/records/:trace_id/repolish", polishHandler.Repolish)
+			// 多轮修改（需要认证）
+			authenticated.POST("/polish/records/:trace_id/refine", refineHandler.Refine)
+			authenticated.GET("/polish/records/:trace_id/revisions", refineHandler.ListRevisions)
+			authenticated.GET("/polish/records/:trace_id/revisions/:revision", refineHandler.GetRevision)
+
+			// 删除记录（需要认证，物理删除）
+			authenticated.DELETE("/polish/records", privacyHandler.DeleteRecords)
+			authenticated.DELETE("/polish/records/:trace_id", privacyHandler.DeleteRecord)
+
+			// 整理记录：项目、标签、收藏（需要认证）
+			authenticated.GET("/projects", organizeHandler.ListProjects)
+			authenticated.POST("/projects", organizeHandler.CreateProject)
+			authenticated.PUT("/projects/:id", organizeHandler.UpdateProject)
+			authenticated.DELETE("/projects/:id", organizeHandler.DeleteProject)
+			authenticated.GET("/tags", organizeHandler.ListTags)
+			authenticated.POST("/tags", organizeHandler.CreateTag)
+			authenticated.PUT("/tags/:id", organizeHandler.UpdateTag)
+			authenticated.DELETE("/tags/:id", organizeHandler.DeleteTag)
+			authenticated.PUT("/polish/records/:trace_id/project", organizeHandler.SetRecordProject)
+			authenticated.PUT("/polish/records/:trace_id/favorite", organizeHandler.SetRecordFavorite)
+			authenticated.PUT("/polish/records/:trace_id/tags", organizeHandler.SetRecordTags)
+
+			// 术语表（需要认证）
+			authenticated.GET("/glossary", glossaryHandler.ListTerms)
+			authenticated.POST("/glossary", glossaryHandler.CreateTerm)
+			authenticated.PUT("/glossary/:id", glossaryHandler.UpdateTerm)
+			authenticated.DELETE("/glossary/:id", glossaryHandler.DeleteTerm)
 
 			// 对比功能（需要认证）
 			authenticated.GET("/polish/compare/:trace_id", comparisonHandler.GetComparison)
 			authenticated.POST("/polish/compare/:trace_id/action", comparisonHandler.ApplyAction)
 			authenticated.POST("/polish/compare/:trace_id/batch-action", comparisonHandler.BatchApplyAction)
+			authenticated.POST("/polish/compare/:trace_id/edit", comparisonHandler.EditContent)
+			authenticated.GET("/polish/compare/:trace_id/consensus", comparisonHandler.GetConsensus)
+			authenticated.POST("/polish/compare/:trace_id/consensus/apply", comparisonHandler.ApplyConsensus)
+			authenticated.POST("/polish/compare/:trace_id/undo", comparisonHandler.Undo)
+			authenticated.POST("/polish/compare/:trace_id/redo", comparisonHandler.Redo)
+			authenticated.GET("/polish/compare/:trace_id/history", comparisonHandler.GetHistory)
+			authenticated.GET("/polish/compare/:trace_id/history/:seq/content", comparisonHandler.GetHistoryContent)
 
 			// 统计信息（需要认证）
 			authenticated.GET("/polish/statistics", queryHandler.GetStatistics)
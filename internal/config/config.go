@@ -1,22 +1,34 @@
 package config
 
 import (
+	"encoding/base64"
 	"fmt"
+	"strings"
+	"sync/atomic"
 	"time"
 
+	"paper_ai/pkg/language"
+
+	"github.com/go-viper/mapstructure/v2"
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	Server   ServerConfig   `mapstructure:"server"`
-	AI       AIConfig       `mapstructure:"ai"`
-	Database DatabaseConfig `mapstructure:"database"`
-	JWT      JWTConfig      `mapstructure:"jwt"`
-	IDGen    IDGenConfig    `mapstructure:"idgen"`
-	Features FeaturesConfig `mapstructure:"features"`
+	Server     ServerConfig     `mapstructure:"server"`
+	AI         AIConfig         `mapstructure:"ai"`
+	Database   DatabaseConfig   `mapstructure:"database"`
+	JWT        JWTConfig        `mapstructure:"jwt"`
+	IDGen      IDGenConfig      `mapstructure:"idgen"`
+	Features   FeaturesConfig   `mapstructure:"features"`
+	Tracing    TracingConfig    `mapstructure:"tracing"`
+	Health     HealthConfig     `mapstructure:"health"`
+	Scheduler  SchedulerConfig  `mapstructure:"scheduler"`
+	Encryption EncryptionConfig `mapstructure:"encryption"`
+	Languages  LanguagesConfig  `mapstructure:"languages"`
 }
 
 type ServerConfig struct {
+	Mode         string        `mapstructure:"mode"` // development / production
 	Port         int           `mapstructure:"port"`
 	ReadTimeout  time.Duration `mapstructure:"read_timeout"`
 	WriteTimeout time.Duration `mapstructure:"write_timeout"`
@@ -28,10 +40,13 @@ type AIConfig struct {
 }
 
 type ProviderConfig struct {
-	APIKey  string        `mapstructure:"api_key"`
-	BaseURL string        `mapstructure:"base_url"`
-	Model   string        `mapstructure:"model"`
-	Timeout time.Duration `mapstructure:"timeout"`
+	APIKey     Secret        `mapstructure:"api_key"`
+	APIKeyFile string        `mapstructure:"api_key_file"` // 从文件读取 api_key
+	BaseURL    string        `mapstructure:"base_url"`
+	Model      string        `mapstructure:"model"`
+	Timeout    time.Duration `mapstructure:"timeout"`
+	// Logprobs 是否请求 token 对数概率（用于计算修改置信度，需模型支持）
+	Logprobs bool `mapstructure:"logprobs"`
 }
 
 type DatabaseConfig struct {
@@ -39,7 +54,8 @@ type DatabaseConfig struct {
 	Host            string `mapstructure:"host"`
 	Port            int    `mapstructure:"port"`
 	User            string `mapstructure:"user"`
-	Password        string `mapstructure:"password"`
+	Password        Secret `mapstructure:"password"`
+	PasswordFile    string `mapstructure:"password_file"` // 从文件读取 password
 	DBName          string `mapstructure:"dbname"`
 	MaxIdleConns    int    `mapstructure:"max_idle_conns"`
 	MaxOpenConns    int    `mapstructure:"max_open_conns"`
@@ -49,7 +65,8 @@ type DatabaseConfig struct {
 }
 
 type JWTConfig struct {
-	SecretKey            string `mapstructure:"secret_key"`
+	SecretKey            Secret `mapstructure:"secret_key"`
+	SecretKeyFile        string `mapstructure:"secret_key_file"`        // 从文件读取 secret_key
 	AccessTokenExpiry    int    `mapstructure:"access_token_expiry"`    // 秒
 	RefreshTokenExpiry   int    `mapstructure:"refresh_token_expiry"`   // 秒
 }
@@ -68,12 +85,100 @@ type MultiVersionPolishConfig struct {
 	MaxConcurrent int    `mapstructure:"max_concurrent"` // 最大并发数
 }
 
-var globalConfig *Config
+type TracingConfig struct {
+	Exporter    string            `mapstructure:"exporter"`     // none / stdout / otlp
+	Endpoint    string            `mapstructure:"endpoint"`     // OTLP HTTP 端点，如 localhost:4318
+	Insecure    bool              `mapstructure:"insecure"`     // OTLP 是否使用 HTTP（不启用 TLS）
+	Headers     map[string]Secret `mapstructure:"headers"`      // OTLP 请求头（如鉴权）
+	ServiceName string            `mapstructure:"service_name"` // 服务名
+	SampleRatio float64           `mapstructure:"sample_ratio"` // 采样率 (0-1]
+}
+
+type HealthConfig struct {
+	CheckTimeout     time.Duration `mapstructure:"check_timeout"`      // 单个组件检查超时
+	ProviderProbe    bool          `mapstructure:"provider_probe"`     // 是否实际探测 AI 提供商（否则只检查是否已配置）
+	ProviderProbeTTL time.Duration `mapstructure:"provider_probe_ttl"` // 探测结果缓存时间
+	ShutdownDelay    time.Duration `mapstructure:"shutdown_delay"`     // 关闭时先让 /readyz 失败，等待该时长后再停止接收请求
+}
+
+type SchedulerConfig struct {
+	Enabled              bool                `mapstructure:"enabled"`
+	LockKey              int64               `mapstructure:"lock_key"`               // PostgreSQL advisory lock 键（所有副本必须一致）
+	LeaderCheckInterval  time.Duration       `mapstructure:"leader_check_interval"`  // 竞选/确认 leader 的间隔
+	Jobs                 SchedulerJobsConfig `mapstructure:"jobs"`                   // 各任务的 cron 表达式，为空表示不启用
+	StaleProcessingAfter time.Duration       `mapstructure:"stale_processing_after"` // processing 状态超过该时长视为中断
+	Retention            RetentionConfig     `mapstructure:"retention"`
+}
+
+type SchedulerJobsConfig struct {
+	PurgeExpiredTokens   string `mapstructure:"purge_expired_tokens"`
+	FailStaleProcessing  string `mapstructure:"fail_stale_processing"`
+	RecomputePromptStats string `mapstructure:"recompute_prompt_stats"`
+	ApplyRetention       string `mapstructure:"apply_retention"`
+}
+
+type RetentionConfig struct {
+	PolishRecords time.Duration `mapstructure:"polish_records"` // 润色记录保留时长，0 表示永久保留
+}
+
+type EncryptionConfig struct {
+	Enabled         bool                       `mapstructure:"enabled"`           // 是否加密新写入的润色内容
+	ActiveMasterKey string                     `mapstructure:"active_master_key"` // 包装新数据密钥使用的主密钥 ID
+	MasterKeys      map[string]MasterKeyConfig `mapstructure:"master_keys"`       // 主密钥（ID → 密钥），轮换期间旧主密钥需保留
+}
+
+type MasterKeyConfig struct {
+	Key     Secret `mapstructure:"key"`      // base64 编码的 32 字节 AES-256 密钥
+	KeyFile string `mapstructure:"key_file"` // 从文件读取 key
+}
+
+type LanguagesConfig struct {
+	Supported []string `mapstructure:"supported"` // 开放的论文语言（ISO 639-1 代码）
+	Default   string   `mapstructure:"default"`   // 未指定语言且无法检测时使用的语言
+}
+
+// MasterKeySize 主密钥长度（AES-256）
+const MasterKeySize = 32
+
+// Decode 解码主密钥
+func (k MasterKeyConfig) Decode() ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(k.Key.Value())
+	if err != nil {
+		return nil, fmt.Errorf("key must be base64 encoded: %w", err)
+	}
+	if len(key) != MasterKeySize {
+		return nil, fmt.Errorf("key must be %d bytes, got %d", MasterKeySize, len(key))
+	}
+	return key, nil
+}
+
+// ModeEnum 运行模式枚举
+const (
+	ModeDevelopment = "development"
+	ModeProduction  = "production" // 生产模式下拒绝默认 JWT 密钥和空的提供商密钥
+)
+
+// DefaultJWTSecret 默认 JWT 密钥（仅用于本地开发）
+const DefaultJWTSecret = "your-secret-key-change-in-production"
+
+// DefaultSchedulerLockKey 定时任务 leader 选举默认使用的 advisory lock 键
+const DefaultSchedulerLockKey int64 = 7_040_001
+
+// envPrefix 环境变量前缀：如 PAPER_AI_SERVER_PORT 覆盖 server.port
+// 只能覆盖配置文件或默认值中已有的键：如 PAPER_AI_AI_PROVIDERS_CLAUDE_API_KEY 只在配置文件中
+// 已配置 ai.providers.claude（含 api_key 键）时生效，不能用环境变量新增提供商
+const envPrefix = "PAPER_AI"
+
+// globalConfig 当前生效的配置（热更新时整体原子替换）
+var globalConfig atomic.Pointer[Config]
 
-// Load 加载配置文件
+// Load 加载配置文件（环境变量优先于配置文件）
 func Load(configPath string) error {
 	viper.SetConfigFile(configPath)
 	viper.SetConfigType("yaml")
+	viper.SetEnvPrefix(envPrefix)
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	viper.AutomaticEnv()
 
 	// 设置默认值
 	setDefaults()
@@ -82,22 +187,48 @@ func Load(configPath string) error {
 		return fmt.Errorf("failed to read config file: %w", err)
 	}
 
-	var cfg Config
-	if err := viper.Unmarshal(&cfg); err != nil {
-		return fmt.Errorf("failed to unmarshal config: %w", err)
+	cfg, err := unmarshal()
+	if err != nil {
+		return err
 	}
 
-	globalConfig = &cfg
+	globalConfig.Store(cfg)
 	return nil
 }
 
 // Get 获取全局配置
+// 配置可能被热更新替换，需要前后一致的多个字段时应只调用一次并复用返回值
 func Get() *Config {
-	return globalConfig
+	return globalConfig.Load()
+}
+
+// unmarshal 从 viper 解析并校验配置
+// 字符串值中的 ${VAR} 会替换为环境变量，*_file 字段指向的文件内容会填入对应的敏感字段
+func unmarshal() (*Config, error) {
+	var cfg Config
+	hooks := viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+		expandEnvHook(),
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+	))
+	if err := viper.Unmarshal(&cfg, hooks); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+
+	if err := cfg.resolveSecrets(); err != nil {
+		return nil, fmt.Errorf("failed to resolve secrets: %w", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	return &cfg, nil
 }
 
 // setDefaults 设置默认配置
 func setDefaults() {
+	viper.SetDefault("server.mode", ModeDevelopment)
 	viper.SetDefault("server.port", 8080)
 	viper.SetDefault("server.read_timeout", 30*time.Second)
 	viper.SetDefault("server.write_timeout", 30*time.Second)
@@ -117,7 +248,7 @@ func setDefaults() {
 	viper.SetDefault("database.log_mode", "info")
 
 	// JWT默认配置
-	viper.SetDefault("jwt.secret_key", "your-secret-key-change-in-production")
+	viper.SetDefault("jwt.secret_key", DefaultJWTSecret)
 	viper.SetDefault("jwt.access_token_expiry", 7200)    // 2小时
 	viper.SetDefault("jwt.refresh_token_expiry", 604800) // 7天
 
@@ -128,4 +259,33 @@ func setDefaults() {
 	viper.SetDefault("features.multi_version_polish.enabled", true)
 	viper.SetDefault("features.multi_version_polish.default_mode", "single")
 	viper.SetDefault("features.multi_version_polish.max_concurrent", 3)
+
+	// 链路追踪默认配置（默认不导出）
+	viper.SetDefault("tracing.exporter", "none")
+	viper.SetDefault("tracing.service_name", "paper_ai")
+	viper.SetDefault("tracing.sample_ratio", 1.0)
+
+	// 健康检查默认配置
+	viper.SetDefault("health.check_timeout", 2*time.Second)
+	viper.SetDefault("health.provider_probe", false)
+	viper.SetDefault("health.provider_probe_ttl", 60*time.Second)
+	viper.SetDefault("health.shutdown_delay", 0)
+
+	// 定时任务默认配置
+	viper.SetDefault("scheduler.enabled", true)
+	viper.SetDefault("scheduler.lock_key", DefaultSchedulerLockKey)
+	viper.SetDefault("scheduler.leader_check_interval", 15*time.Second)
+	viper.SetDefault("scheduler.jobs.purge_expired_tokens", "@every 1h")
+	viper.SetDefault("scheduler.jobs.fail_stale_processing", "@every 5m")
+	viper.SetDefault("scheduler.jobs.recompute_prompt_stats", "@every 1h")
+	viper.SetDefault("scheduler.jobs.apply_retention", "30 3 * * *")
+	viper.SetDefault("scheduler.stale_processing_after", 30*time.Minute)
+	viper.SetDefault("scheduler.retention.polish_records", 0)
+
+	// 内容加密默认配置（默认不加密）
+	viper.SetDefault("encryption.enabled", false)
+
+	// 论文语言默认配置
+	viper.SetDefault("languages.supported", language.DefaultSupported)
+	viper.SetDefault("languages.default", language.DefaultLanguage)
 }
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Redacted 返回用于展示的配置（键为配置文件中的名称，密钥已脱敏）
+func (c *Config) Redacted() map[string]interface{} {
+	return dumpValue(reflect.ValueOf(*c)).(map[string]interface{})
+}
+
+// dumpValue 按 mapstructure 标签把配置转换为通用结构，Secret 输出脱敏值，时长输出可读字符串
+func dumpValue(value reflect.Value) interface{} {
+	switch v := value.Interface().(type) {
+	case Secret:
+		return v.String()
+	case time.Duration:
+		return v.String()
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		result := make(map[string]interface{}, value.NumField())
+		for i := 0; i < value.NumField(); i++ {
+			field := value.Type().Field(i)
+			name := strings.Split(field.Tag.Get("mapstructure"), ",")[0]
+			if name == "" || name == "-" {
+				continue
+			}
+			result[name] = dumpValue(value.Field(i))
+		}
+		return result
+	case reflect.Map:
+		result := make(map[string]interface{}, value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			result[iter.Key().String()] = dumpValue(iter.Value())
+		}
+		return result
+	default:
+		return value.Interface()
+	}
+}
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRedacted(t *testing.T) {
+	cfg := validConfig()
+	cfg.Tracing.Headers = map[string]Secret{"authorization": "Bearer tracing-token"}
+	cfg.Encryption.MasterKeys = map[string]MasterKeyConfig{"k1": {Key: "bWFzdGVyLWtleQ=="}}
+	secrets := []string{"sk-claude", "db-password", "jwt-secret", "Bearer tracing-token", "bWFzdGVyLWtleQ=="}
+
+	dump := cfg.Redacted()
+	data, err := json.Marshal(dump)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	for _, secret := range secrets {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("Redacted() leaks %q: %s", secret, data)
+		}
+	}
+
+	paths := [][]string{
+		{"ai", "providers", "claude", "api_key"},
+		{"database", "password"},
+		{"jwt", "secret_key"},
+		{"tracing", "headers", "authorization"},
+		{"encryption", "master_keys", "k1", "key"},
+	}
+	for _, path := range paths {
+		var value interface{} = dump
+		for _, key := range path {
+			value = value.(map[string]interface{})[key]
+		}
+		if value != redactedValue {
+			t.Errorf("Redacted() %s = %v, want %s", strings.Join(path, "."), value, redactedValue)
+		}
+	}
+
+	// 非敏感字段原样输出，时长为可读字符串
+	if got := dump["server"].(map[string]interface{})["read_timeout"]; got != "30s" {
+		t.Errorf("Redacted() server.read_timeout = %v, want 30s", got)
+	}
+	if got := dump["ai"].(map[string]interface{})["default_provider"]; got != "claude" {
+		t.Errorf("Redacted() ai.default_provider = %v, want claude", got)
+	}
+}
@@ -0,0 +1,112 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/go-viper/mapstructure/v2"
+)
+
+// redactedValue 敏感信息脱敏后的显示值
+const redactedValue = "******"
+
+// Secret 敏感配置值（密钥、密码等）
+// 打印、记录日志或序列化时始终输出脱敏值，需要原值时调用 Value
+type Secret string
+
+// Value 返回原始值
+func (s Secret) Value() string {
+	return string(s)
+}
+
+// IsEmpty 判断是否未配置
+func (s Secret) IsEmpty() bool {
+	return s == ""
+}
+
+// String 实现 fmt.Stringer，返回脱敏值
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return redactedValue
+}
+
+// GoString 实现 fmt.GoStringer（%#v），返回脱敏值
+func (s Secret) GoString() string {
+	return fmt.Sprintf("%q", s.String())
+}
+
+// MarshalText 实现 encoding.TextMarshaler（JSON/YAML 序列化），返回脱敏值
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// envPattern ${VAR} 形式的环境变量引用（不处理 $VAR，避免误伤含 $ 的密码）
+var envPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// expandEnv 替换字符串中的 ${VAR} 引用，未设置的变量替换为空字符串
+func expandEnv(value string) string {
+	return envPattern.ReplaceAllStringFunc(value, func(match string) string {
+		return os.Getenv(match[2 : len(match)-1])
+	})
+}
+
+// expandEnvHook 解析配置时对所有字符串值（包括 Secret）做环境变量替换
+func expandEnvHook() mapstructure.DecodeHookFuncType {
+	return func(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+		if from.Kind() != reflect.String {
+			return data, nil
+		}
+		return expandEnv(reflect.ValueOf(data).String()), nil
+	}
+}
+
+// readSecretFile 从文件读取敏感值（去掉首尾空白，兼容 Docker/Kubernetes secret 文件末尾的换行）
+func readSecretFile(path string) (Secret, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret file: %w", err)
+	}
+	return Secret(strings.TrimSpace(string(content))), nil
+}
+
+// resolveSecret 处理 *_file 间接引用：配置了文件路径时从文件读取，优先于直接配置的值
+func resolveSecret(name string, value *Secret, file string) error {
+	if file == "" {
+		return nil
+	}
+
+	secret, err := readSecretFile(file)
+	if err != nil {
+		return fmt.Errorf("%s_file: %w", name, err)
+	}
+	*value = secret
+	return nil
+}
+
+// resolveSecrets 解析所有敏感字段的 *_file 引用
+func (c *Config) resolveSecrets() error {
+	if err := resolveSecret("jwt.secret_key", &c.JWT.SecretKey, c.JWT.SecretKeyFile); err != nil {
+		return err
+	}
+	if err := resolveSecret("database.password", &c.Database.Password, c.Database.PasswordFile); err != nil {
+		return err
+	}
+	for name, provider := range c.AI.Providers {
+		if err := resolveSecret(fmt.Sprintf("ai.providers.%s.api_key", name), &provider.APIKey, provider.APIKeyFile); err != nil {
+			return err
+		}
+		c.AI.Providers[name] = provider
+	}
+	for id, key := range c.Encryption.MasterKeys {
+		if err := resolveSecret(fmt.Sprintf("encryption.master_keys.%s.key", id), &key.Key, key.KeyFile); err != nil {
+			return err
+		}
+		c.Encryption.MasterKeys[id] = key
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-viper/mapstructure/v2"
+)
+
+// validConfig 返回一份能通过校验的开发模式配置
+func validConfig() *Config {
+	return &Config{
+		Server: ServerConfig{Mode: ModeDevelopment, Port: 8080, ReadTimeout: 30 * time.Second, WriteTimeout: 30 * time.Second},
+		AI: AIConfig{
+			DefaultProvider: "claude",
+			Providers: map[string]ProviderConfig{
+				"claude": {APIKey: "sk-claude", BaseURL: "https://api.anthropic.com", Model: "claude-model", Timeout: 60 * time.Second},
+			},
+		},
+		Database: DatabaseConfig{
+			Type: "postgres", Host: "localhost", Port: 5432, User: "postgres", Password: "db-password", DBName: "paper_ai",
+			MaxIdleConns: 10, MaxOpenConns: 100, ConnMaxLifetime: 3600, LogMode: "info",
+		},
+		JWT:      JWTConfig{SecretKey: "jwt-secret", AccessTokenExpiry: 7200, RefreshTokenExpiry: 604800},
+		IDGen:    IDGenConfig{WorkerID: 1},
+		Features: FeaturesConfig{MultiVersionPolish: MultiVersionPolishConfig{Enabled: true, DefaultMode: "single", MaxConcurrent: 3}},
+		Tracing:  TracingConfig{Exporter: "none", ServiceName: "paper_ai", SampleRatio: 1},
+		Health:   HealthConfig{CheckTimeout: 2 * time.Second},
+		Languages: LanguagesConfig{
+			Supported: []string{"en", "zh"},
+			Default:   "en",
+		},
+	}
+}
+
+// errorFields 返回校验错误涉及的配置路径
+func errorFields(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("error is not ValidationErrors: %v", err)
+	}
+	fields := make([]string, len(errs))
+	for i, fieldErr := range errs {
+		fields[i] = fieldErr.Field
+	}
+	return fields
+}
+
+func TestExpandEnvHook(t *testing.T) {
+	t.Setenv("PAPER_AI_TEST_KEY", "sk-from-env")
+	t.Setenv("PAPER_AI_TEST_HOST", "api.example.com")
+
+	tests := []struct {
+		name    string
+		input   map[string]interface{}
+		wantKey string
+		wantURL string
+	}{
+		{"secret field", map[string]interface{}{"api_key": "${PAPER_AI_TEST_KEY}"}, "sk-from-env", ""},
+		{"embedded in string", map[string]interface{}{"base_url": "https://${PAPER_AI_TEST_HOST}/v1"}, "", "https://api.example.com/v1"},
+		{"unset variable", map[string]interface{}{"api_key": "${PAPER_AI_TEST_UNSET}"}, "", ""},
+		{"bare $VAR kept", map[string]interface{}{"api_key": "pa$PAPER_AI_TEST_KEY"}, "pa$PAPER_AI_TEST_KEY", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var provider ProviderConfig
+			decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{DecodeHook: expandEnvHook(), Result: &provider})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := decoder.Decode(tt.input); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if provider.APIKey.Value() != tt.wantKey || provider.BaseURL != tt.wantURL {
+				t.Errorf("decoded api_key = %q, base_url = %q, want %q, %q", provider.APIKey.Value(), provider.BaseURL, tt.wantKey, tt.wantURL)
+			}
+		})
+	}
+}
+
+func TestResolveSecrets(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	jwtFile := writeFile("jwt", "jwt-from-file\n")
+	apiKeyFile := writeFile("api_key", "  sk-from-file\r\n")
+
+	t.Run("file overrides value and trims whitespace", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.JWT.SecretKeyFile = jwtFile
+		provider := cfg.AI.Providers["claude"]
+		provider.APIKeyFile = apiKeyFile
+		cfg.AI.Providers["claude"] = provider
+
+		if err := cfg.resolveSecrets(); err != nil {
+			t.Fatalf("resolveSecrets() error = %v", err)
+		}
+		if got := cfg.JWT.SecretKey.Value(); got != "jwt-from-file" {
+			t.Errorf("jwt.secret_key = %q, want jwt-from-file", got)
+		}
+		if got := cfg.AI.Providers["claude"].APIKey.Value(); got != "sk-from-file" {
+			t.Errorf("ai.providers.claude.api_key = %q, want sk-from-file", got)
+		}
+		if got := cfg.Database.Password.Value(); got != "db-password" {
+			t.Errorf("database.password without file = %q, want unchanged", got)
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.Database.PasswordFile = filepath.Join(dir, "missing")
+
+		err := cfg.resolveSecrets()
+		if err == nil || !strings.Contains(err.Error(), "database.password_file") {
+			t.Errorf("resolveSecrets() error = %v, want database.password_file error", err)
+		}
+	})
+}
+
+func TestSecretRedaction(t *testing.T) {
+	secret := Secret("sk-very-secret")
+	if secret.Value() != "sk-very-secret" {
+		t.Errorf("Value() = %q, want original value", secret.Value())
+	}
+
+	tests := []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{"String", secret.String(), redactedValue},
+		{"%v", fmt.Sprintf("%v", secret), redactedValue},
+		{"%#v", fmt.Sprintf("%#v", secret), `"******"`},
+		{"%+v in struct", fmt.Sprintf("%+v", JWTConfig{SecretKey: secret}), "{SecretKey:****** SecretKeyFile: AccessTokenExpiry:0 RefreshTokenExpiry:0}"},
+		{"empty", Secret("").String(), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.output != tt.want {
+				t.Errorf("output = %q, want %q", tt.output, tt.want)
+			}
+		})
+	}
+
+	data, err := json.Marshal(map[string]interface{}{"api_key": secret, "empty": Secret("")})
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if want := `{"api_key":"******","empty":""}`; string(data) != want {
+		t.Errorf("json.Marshal() = %s, want %s", data, want)
+	}
+}
+
+func TestValidateProductionSecrets(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *Config)
+		want   []string
+	}{
+		{"all secrets set", func(cfg *Config) {}, nil},
+		{"default jwt secret", func(cfg *Config) { cfg.JWT.SecretKey = DefaultJWTSecret }, []string{"jwt.secret_key"}},
+		{"empty jwt secret", func(cfg *Config) { cfg.JWT.SecretKey = "" }, []string{"jwt.secret_key"}},
+		{"empty provider key", func(cfg *Config) {
+			cfg.AI.Providers["doubao"] = ProviderConfig{BaseURL: "https://ark.example.com", Model: "doubao-model"}
+		}, []string{"ai.providers.doubao.api_key"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.Server.Mode = ModeProduction
+			tt.modify(cfg)
+
+			got := errorFields(t, cfg.Validate())
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Validate() fields = %q, want %q", got, tt.want)
+			}
+
+			// 开发模式下不检查密钥
+			cfg.Server.Mode = ModeDevelopment
+			if err := cfg.Validate(); err != nil {
+				t.Errorf("Validate() in development mode error = %v", err)
+			}
+		})
+	}
+}
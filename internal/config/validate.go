@@ -0,0 +1,297 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"paper_ai/pkg/language"
+
+	"github.com/robfig/cron/v3"
+)
+
+// supportedProviders 支持的 AI 提供商（与 ai.ProviderFactory 保持一致）
+var supportedProviders = map[string]bool{
+	"claude": true,
+	"doubao": true,
+}
+
+// maxWorkerID Snowflake 机器ID上限（10 位）
+const maxWorkerID = 1023
+
+// maxMasterKeyIDLength 主密钥 ID 最大长度（与 encryption_data_keys.master_key_id 列一致）
+const maxMasterKeyIDLength = 64
+
+// FieldError 单个配置项的校验错误
+type FieldError struct {
+	Field   string // 配置路径，如 ai.default_provider
+	Message string
+}
+
+// Error 实现 error 接口
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// ValidationErrors 配置校验错误汇总
+type ValidationErrors []*FieldError
+
+// Error 实现 error 接口，每行一个问题
+func (e ValidationErrors) Error() string {
+	lines := make([]string, 0, len(e)+1)
+	lines = append(lines, fmt.Sprintf("%d config error(s):", len(e)))
+	for _, fieldErr := range e {
+		lines = append(lines, "  - "+fieldErr.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// validator 收集校验错误
+type validator struct {
+	errs ValidationErrors
+}
+
+// addf 记录一个校验错误
+func (v *validator) addf(field, format string, args ...interface{}) {
+	v.errs = append(v.errs, &FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// positiveDuration 校验时长大于 0
+func (v *validator) positiveDuration(field string, value time.Duration) {
+	if value <= 0 {
+		v.addf(field, "must be positive, got %s", value)
+	}
+}
+
+// nonNegativeDuration 校验时长不为负
+func (v *validator) nonNegativeDuration(field string, value time.Duration) {
+	if value < 0 {
+		v.addf(field, "must not be negative, got %s", value)
+	}
+}
+
+// oneOf 校验取值在枚举范围内
+func (v *validator) oneOf(field, value string, allowed ...string) {
+	for _, candidate := range allowed {
+		if value == candidate {
+			return
+		}
+	}
+	v.addf(field, "must be one of %s, got %q", strings.Join(allowed, " / "), value)
+}
+
+// Validate 校验配置，返回所有问题的汇总（ValidationErrors），没有问题时返回 nil
+func (c *Config) Validate() error {
+	v := &validator{}
+
+	c.validateServer(v)
+	c.validateAI(v)
+	c.validateDatabase(v)
+	c.validateJWT(v)
+	c.validateOthers(v)
+	c.validateScheduler(v)
+	c.validateEncryption(v)
+	c.validateLanguages(v)
+	if c.Server.Mode == ModeProduction {
+		c.validateProductionSecrets(v)
+	}
+
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
+
+// validateServer 校验服务器配置
+func (c *Config) validateServer(v *validator) {
+	v.oneOf("server.mode", c.Server.Mode, ModeDevelopment, ModeProduction)
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		v.addf("server.port", "must be between 1 and 65535, got %d", c.Server.Port)
+	}
+	v.positiveDuration("server.read_timeout", c.Server.ReadTimeout)
+	v.positiveDuration("server.write_timeout", c.Server.WriteTimeout)
+}
+
+// validateAI 校验 AI 提供商配置
+func (c *Config) validateAI(v *validator) {
+	if len(c.AI.Providers) == 0 {
+		v.addf("ai.providers", "at least one provider must be configured")
+	}
+
+	names := make([]string, 0, len(c.AI.Providers))
+	for name := range c.AI.Providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		provider := c.AI.Providers[name]
+		field := "ai.providers." + name
+		if !supportedProviders[name] {
+			v.addf(field, "unsupported provider %q (supported: claude, doubao)", name)
+		}
+		if provider.BaseURL == "" {
+			v.addf(field+".base_url", "must not be empty")
+		}
+		if provider.Model == "" {
+			v.addf(field+".model", "must not be empty")
+		}
+		v.nonNegativeDuration(field+".timeout", provider.Timeout)
+	}
+
+	if _, ok := c.AI.Providers[c.AI.DefaultProvider]; !ok {
+		v.addf("ai.default_provider", "%q is not configured in ai.providers", c.AI.DefaultProvider)
+	}
+}
+
+// validateDatabase 校验数据库配置
+func (c *Config) validateDatabase(v *validator) {
+	v.oneOf("database.type", c.Database.Type, "postgres")
+	if c.Database.Host == "" {
+		v.addf("database.host", "must not be empty")
+	}
+	if c.Database.Port < 1 || c.Database.Port > 65535 {
+		v.addf("database.port", "must be between 1 and 65535, got %d", c.Database.Port)
+	}
+	if c.Database.DBName == "" {
+		v.addf("database.dbname", "must not be empty")
+	}
+	if c.Database.MaxOpenConns < 1 {
+		v.addf("database.max_open_conns", "must be at least 1, got %d", c.Database.MaxOpenConns)
+	}
+	if c.Database.MaxIdleConns < 0 || c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		v.addf("database.max_idle_conns", "must be between 0 and max_open_conns (%d), got %d", c.Database.MaxOpenConns, c.Database.MaxIdleConns)
+	}
+	if c.Database.ConnMaxLifetime < 0 {
+		v.addf("database.conn_max_lifetime", "must not be negative, got %d", c.Database.ConnMaxLifetime)
+	}
+	v.oneOf("database.log_mode", c.Database.LogMode, "silent", "error", "warn", "info")
+}
+
+// validateJWT 校验 JWT 配置
+func (c *Config) validateJWT(v *validator) {
+	if c.JWT.AccessTokenExpiry <= 0 {
+		v.addf("jwt.access_token_expiry", "must be positive, got %d", c.JWT.AccessTokenExpiry)
+	}
+	if c.JWT.RefreshTokenExpiry <= 0 {
+		v.addf("jwt.refresh_token_expiry", "must be positive, got %d", c.JWT.RefreshTokenExpiry)
+	}
+}
+
+// validateOthers 校验 ID 生成器、功能开关、链路追踪和健康检查配置
+func (c *Config) validateOthers(v *validator) {
+	if c.IDGen.WorkerID < 0 || c.IDGen.WorkerID > maxWorkerID {
+		v.addf("idgen.worker_id", "must be between 0 and %d, got %d", maxWorkerID, c.IDGen.WorkerID)
+	}
+
+	mv := c.Features.MultiVersionPolish
+	v.oneOf("features.multi_version_polish.default_mode", mv.DefaultMode, "single", "multi")
+	if mv.MaxConcurrent < 1 {
+		v.addf("features.multi_version_polish.max_concurrent", "must be at least 1, got %d", mv.MaxConcurrent)
+	}
+
+	v.oneOf("tracing.exporter", c.Tracing.Exporter, "none", "stdout", "otlp")
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		v.addf("tracing.sample_ratio", "must be between 0 and 1, got %g", c.Tracing.SampleRatio)
+	}
+
+	v.positiveDuration("health.check_timeout", c.Health.CheckTimeout)
+	v.nonNegativeDuration("health.provider_probe_ttl", c.Health.ProviderProbeTTL)
+	v.nonNegativeDuration("health.shutdown_delay", c.Health.ShutdownDelay)
+}
+
+// validateLanguages 校验论文语言配置
+func (c *Config) validateLanguages(v *validator) {
+	lc := c.Languages
+	if len(lc.Supported) == 0 {
+		v.addf("languages.supported", "at least one language must be supported")
+	}
+	supported := false
+	for _, code := range lc.Supported {
+		if !language.IsKnown(code) {
+			v.addf("languages.supported", "unknown language %q, must be one of %s", code, strings.Join(language.Known(), " / "))
+		}
+		if code == lc.Default {
+			supported = true
+		}
+	}
+	if !supported {
+		v.addf("languages.default", "must be one of languages.supported, got %q", lc.Default)
+	}
+}
+
+// validateScheduler 校验定时任务配置（未启用时不校验）
+func (c *Config) validateScheduler(v *validator) {
+	sc := c.Scheduler
+	if !sc.Enabled {
+		return
+	}
+
+	v.positiveDuration("scheduler.leader_check_interval", sc.LeaderCheckInterval)
+	v.positiveDuration("scheduler.stale_processing_after", sc.StaleProcessingAfter)
+	v.nonNegativeDuration("scheduler.retention.polish_records", sc.Retention.PolishRecords)
+
+	jobs := []struct {
+		name string
+		spec string
+	}{
+		{"purge_expired_tokens", sc.Jobs.PurgeExpiredTokens},
+		{"fail_stale_processing", sc.Jobs.FailStaleProcessing},
+		{"recompute_prompt_stats", sc.Jobs.RecomputePromptStats},
+		{"apply_retention", sc.Jobs.ApplyRetention},
+	}
+	for _, job := range jobs {
+		if job.spec == "" {
+			continue
+		}
+		if _, err := cron.ParseStandard(job.spec); err != nil {
+			v.addf("scheduler.jobs."+job.name, "invalid cron expression %q: %v", job.spec, err)
+		}
+	}
+}
+
+// validateEncryption 校验内容加密配置（未启用时仍校验已配置的主密钥，旧数据解密需要它们）
+func (c *Config) validateEncryption(v *validator) {
+	ec := c.Encryption
+
+	ids := make([]string, 0, len(ec.MasterKeys))
+	for id := range ec.MasterKeys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		field := "encryption.master_keys." + id
+		if len(id) > maxMasterKeyIDLength {
+			v.addf(field, "key id must be at most %d characters", maxMasterKeyIDLength)
+		}
+		if _, err := ec.MasterKeys[id].Decode(); err != nil {
+			v.addf(field+".key", "%v", err)
+		}
+	}
+
+	if !ec.Enabled && ec.ActiveMasterKey == "" {
+		return
+	}
+	if _, ok := ec.MasterKeys[ec.ActiveMasterKey]; !ok {
+		v.addf("encryption.active_master_key", "%q is not configured in encryption.master_keys", ec.ActiveMasterKey)
+	}
+}
+
+// validateProductionSecrets 生产模式下拒绝默认或空的密钥
+func (c *Config) validateProductionSecrets(v *validator) {
+	if c.JWT.SecretKey.IsEmpty() || c.JWT.SecretKey.Value() == DefaultJWTSecret {
+		v.addf("jwt.secret_key", "default or empty secret is not allowed in production mode")
+	}
+
+	names := make([]string, 0, len(c.AI.Providers))
+	for name := range c.AI.Providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if c.AI.Providers[name].APIKey.IsEmpty() {
+			v.addf("ai.providers."+name+".api_key", "empty api key is not allowed in production mode")
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *Config)
+		want   []string
+	}{
+		{"valid", func(cfg *Config) {}, nil},
+		{"server", func(cfg *Config) {
+			cfg.Server.Mode = "staging"
+			cfg.Server.Port = 70000
+			cfg.Server.ReadTimeout = 0
+		}, []string{"server.mode", "server.port", "server.read_timeout"}},
+		{"unknown default provider", func(cfg *Config) { cfg.AI.DefaultProvider = "doubao" }, []string{"ai.default_provider"}},
+		{"provider fields", func(cfg *Config) {
+			cfg.AI.Providers["openai"] = ProviderConfig{Timeout: -time.Second}
+		}, []string{"ai.providers.openai", "ai.providers.openai.base_url", "ai.providers.openai.model", "ai.providers.openai.timeout"}},
+		{"database", func(cfg *Config) {
+			cfg.Database.Host = ""
+			cfg.Database.MaxIdleConns = 200
+			cfg.Database.LogMode = "debug"
+		}, []string{"database.host", "database.max_idle_conns", "database.log_mode"}},
+		{"scheduler cron", func(cfg *Config) {
+			cfg.Scheduler = SchedulerConfig{
+				Enabled:              true,
+				LeaderCheckInterval:  time.Second,
+				StaleProcessingAfter: time.Minute,
+				Jobs:                 SchedulerJobsConfig{ApplyRetention: "every day"},
+			}
+		}, []string{"scheduler.jobs.apply_retention"}},
+		{"encryption", func(cfg *Config) {
+			cfg.Encryption = EncryptionConfig{
+				Enabled:         true,
+				ActiveMasterKey: "k2",
+				MasterKeys:      map[string]MasterKeyConfig{"k1": {Key: "not-base64"}},
+			}
+		}, []string{"encryption.master_keys.k1.key", "encryption.active_master_key"}},
+		{"languages", func(cfg *Config) {
+			cfg.Languages = LanguagesConfig{Supported: []string{"en", "xx"}, Default: "de"}
+		}, []string{"languages.supported", "languages.default"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.modify(cfg)
+
+			got := errorFields(t, cfg.Validate())
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Validate() fields = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad_ExampleConfig(t *testing.T) {
+	if err := Load("../../config/config.example.yaml"); err != nil {
+		t.Fatalf("Load(config.example.yaml) error = %v", err)
+	}
+	cfg := Get()
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	if cfg.AI.DefaultProvider == "" || len(cfg.AI.Providers) == 0 {
+		t.Errorf("example config has no providers: %+v", cfg.AI)
+	}
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"reflect"
+	"sync"
+
+	"paper_ai/pkg/logger"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// ChangeListener 配置变更回调，在新配置生效后调用
+type ChangeListener func(oldCfg, newCfg *Config)
+
+var (
+	listenersMu sync.Mutex
+	listeners   []ChangeListener
+)
+
+// OnChange 注册配置变更回调（按注册顺序调用）
+func OnChange(fn ChangeListener) {
+	listenersMu.Lock()
+	defer listenersMu.Unlock()
+	listeners = append(listeners, fn)
+}
+
+// Watch 监听配置文件变化并热更新
+// 新配置校验通过后才原子替换，校验失败时保留旧配置；已在处理的请求继续使用旧配置
+func Watch() {
+	viper.OnConfigChange(func(e fsnotify.Event) {
+		reload(e.Name)
+	})
+	viper.WatchConfig()
+}
+
+// reload 重新解析配置并通知订阅者
+func reload(path string) {
+	newCfg, err := unmarshal()
+	if err != nil {
+		logger.Error("config reload rejected, keeping previous config",
+			zap.String("path", path),
+			zap.Error(err))
+		return
+	}
+
+	oldCfg := globalConfig.Swap(newCfg)
+	if fields := restartRequired(oldCfg, newCfg); len(fields) > 0 {
+		logger.Warn("config changes require restart to take effect", zap.Strings("sections", fields))
+	}
+	logger.Info("config reloaded", zap.String("path", path))
+
+	listenersMu.Lock()
+	current := make([]ChangeListener, len(listeners))
+	copy(current, listeners)
+	listenersMu.Unlock()
+
+	for _, fn := range current {
+		fn(oldCfg, newCfg)
+	}
+}
+
+// restartRequired 返回发生变化但不支持热更新的配置段
+func restartRequired(oldCfg, newCfg *Config) []string {
+	if oldCfg == nil {
+		return nil
+	}
+
+	sections := []struct {
+		name       string
+		oldV, newV interface{}
+	}{
+		{"server", oldCfg.Server, newCfg.Server},
+		{"database", oldCfg.Database, newCfg.Database},
+		{"jwt", oldCfg.JWT, newCfg.JWT},
+		{"idgen", oldCfg.IDGen, newCfg.IDGen},
+		{"tracing", oldCfg.Tracing, newCfg.Tracing},
+		{"scheduler", oldCfg.Scheduler, newCfg.Scheduler},
+		{"encryption", oldCfg.Encryption, newCfg.Encryption},
+	}
+
+	changed := make([]string, 0)
+	for _, section := range sections {
+		if !reflect.DeepEqual(section.oldV, section.newV) {
+			changed = append(changed, section.name)
+		}
+	}
+	return changed
+}
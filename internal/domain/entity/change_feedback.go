@@ -0,0 +1,26 @@
+package entity
+
+import "time"
+
+// ChangeFeedback 用户对修改的反馈记录
+// 用于统计相似修改的历史接受率，只保存修改签名，不保存原文
+type ChangeFeedback struct {
+	ID         int64
+	UserID     int64
+	TraceID    string
+	ChangeType string // vocabulary / grammar / structure
+	Signature  string // 修改签名（类型 + 规范化原文/修改文本的哈希）
+	Action     string // accepted / rejected
+	CreatedAt  time.Time
+}
+
+// FeedbackActionEnum 反馈动作枚举
+const (
+	FeedbackActionAccepted = "accepted"
+	FeedbackActionRejected = "rejected"
+)
+
+// IsAccepted 判断是否为接受
+func (f *ChangeFeedback) IsAccepted() bool {
+	return f.Action == FeedbackActionAccepted
+}
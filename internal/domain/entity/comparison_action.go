@@ -0,0 +1,53 @@
+package entity
+
+import "time"
+
+// ComparisonAction 对比操作日志（只追加，不修改）
+// 每次改变修改状态的操作（包括撤销/重做）都会追加一条记录
+type ComparisonAction struct {
+	ID        int64
+	RecordID  int64
+	TraceID   string
+	UserID    int64
+	Seq       int                  // 记录内的操作序号（从 1 开始递增）
+	Action    string               // 操作类型，见 ComparisonActionEnum
+	TargetSeq int                  // 撤销/重做操作对应的原操作序号
+	Changes   []ActionChangeRecord // 本次操作改变的修改状态
+	CreatedAt time.Time
+}
+
+// ActionChangeRecord 单个修改的状态变化
+type ActionChangeRecord struct {
+	ChangeID   string `json:"change_id"`
+	Before     string `json:"before"`
+	After      string `json:"after"`
+	BeforeText string `json:"before_text,omitempty"` // 操作前的编辑文本（edited 状态）
+	AfterText  string `json:"after_text,omitempty"`  // 操作后的编辑文本（edited 状态）
+}
+
+// IsNoop 判断状态和编辑文本是否都没有变化
+func (c ActionChangeRecord) IsNoop() bool {
+	return c.Before == c.After && c.BeforeText == c.AfterText
+}
+
+// ComparisonActionEnum 对比操作类型枚举
+const (
+	ComparisonActionAccept     = "accept"      // 接受单个修改
+	ComparisonActionReject     = "reject"      // 拒绝单个修改
+	ComparisonActionEdit       = "edit"        // 手动编辑单个修改
+	ComparisonActionFreeEdit   = "free_edit"   // 自由编辑最终文本
+	ComparisonActionAcceptAll  = "accept_all"  // 批量接受
+	ComparisonActionRejectAll  = "reject_all"  // 批量拒绝
+	ComparisonActionAutoAccept = "auto_accept" // 按置信度自动接受
+	ComparisonActionConsensus  = "consensus"   // 按区间选择多版本写法
+	ComparisonActionUndo       = "undo"        // 撤销
+	ComparisonActionRedo       = "redo"        // 重做
+	ComparisonActionReset      = "reset"       // 对比数据重新生成（选择版本），之前的操作不可再撤销
+)
+
+// IsStatusAction 判断是否为直接改变修改状态的普通操作（非撤销/重做/重置）
+func (a *ComparisonAction) IsStatusAction() bool {
+	return a.Action != ComparisonActionUndo &&
+		a.Action != ComparisonActionRedo &&
+		a.Action != ComparisonActionReset
+}
@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+// GlossaryTerm 术语表条目：翻译时源语言术语固定使用的译法，源术语在用户的同一语言对内唯一
+type GlossaryTerm struct {
+	ID             int64
+	UserID         int64
+	SourceLanguage string // 源语言
+	TargetLanguage string // 目标语言（只在该语言对的翻译中使用）
+	SourceTerm     string
+	TargetTerm     string
+	Note           string // 备注（如使用场景）
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
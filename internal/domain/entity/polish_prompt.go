@@ -1,6 +1,10 @@
 package entity
 
-import "time"
+import (
+	"time"
+
+	"paper_ai/pkg/language"
+)
 
 // PolishPrompt Prompt模板实体
 // 用于数据库化管理Prompt模板，支持版本管理和A/B测试
@@ -11,7 +15,7 @@ type PolishPrompt struct {
 	// 基本信息
 	Name        string
 	VersionType string // conservative / balanced / aggressive
-	Language    string // en / zh / all
+	Language    string // 语言代码（en / zh / de 等）或 all（通用）
 	Style       string // academic / formal / concise / all
 
 	// Prompt内容
@@ -115,11 +119,9 @@ const (
 	PromptStyleAll      = "all" // 通用
 )
 
-// IsValidLanguage 验证语言是否有效
-func IsValidLanguage(language string) bool {
-	return language == PromptLanguageEnglish ||
-		language == PromptLanguageChinese ||
-		language == PromptLanguageAll
+// IsValidLanguage 验证语言是否有效（已知语言或通用；未开放的语言也可以预先配置 Prompt）
+func IsValidLanguage(lang string) bool {
+	return lang == PromptLanguageAll || language.IsKnown(lang)
 }
 
 // IsValidStyle 验证风格是否有效
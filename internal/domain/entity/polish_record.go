@@ -13,7 +13,8 @@ type PolishRecord struct {
 	// 输入信息
 	OriginalContent string
 	Style           string
-	Language        string
+	Language        string // 润色语言（翻译记录为目标语言）
+	SourceLanguage  string // 翻译记录的源语言（润色记录为空）
 
 	// 输出信息
 	PolishedContent string
@@ -25,7 +26,7 @@ type PolishRecord struct {
 	Model    string
 
 	// 模式信息
-	Mode            string // single / multi
+	Mode            string // single / multi / translate
 	SelectedVersion string // 用户选择的版本类型（多版本模式下使用）
 
 	// 性能指标
@@ -42,11 +43,63 @@ type PolishRecord struct {
 	RejectedChanges []string // 用户拒绝的修改ID列表
 	FinalContent    string   // 用户最终确认的文本（原文+接受的修改）
 
+	// 置信度信号
+	TokenLogProbs []TokenLogProb `json:"-"` // 模型返回的 token 对数概率（提供商支持时才有，不对外返回）
+
+	// 按句组合多版本时每句的来源（SelectedVersion 为 composite 时有效）
+	SentenceSources []SentenceSource
+
+	// 不保存内容模式下创建的记录只有元数据（长度、耗时、状态等），原文和润色结果为空
+	ContentOmitted bool
+
+	// 选区润色时润色的原文片段（为 nil 表示润色整段），对比标注只限于该片段
+	Selection *TextSelection
+
+	// 重新润色的来源记录 TraceID（为空表示不是重新润色产生的），创建后不再修改
+	ParentTraceID string
+
+	// 整理信息（只通过专门的仓储方法修改，Update 不写入）
+	ProjectID  *int64 // 所属项目，为 nil 表示未归属项目
+	IsFavorite bool   // 是否收藏
+	Tags       []*Tag // 标签（单独加载）
+
 	// 时间戳
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
 
+// TextSelection 原文中的选区（基于 rune，End 不包含）
+type TextSelection struct {
+	Start int
+	End   int
+}
+
+// TokenLogProb 单个 token 的对数概率
+type TokenLogProb struct {
+	Token   string  `json:"t"`
+	LogProb float64 `json:"l"`
+}
+
+// SentenceSource 组合文本中单个句子的来源
+type SentenceSource struct {
+	Index         int    `json:"index"`          // 句子序号
+	Version       string `json:"version"`        // 来源版本类型，original 表示保留原文
+	OriginalStart int    `json:"original_start"` // 在原文中的位置（基于 rune）
+	OriginalEnd   int    `json:"original_end"`
+	Start         int    `json:"start"` // 在组合文本中的位置（基于 rune）
+	End           int    `json:"end"`
+}
+
+// OmitContent 清空原文、润色结果等内容字段，只保留元数据（不保存内容模式）
+func (r *PolishRecord) OmitContent() {
+	r.OriginalContent = ""
+	r.PolishedContent = ""
+	r.FinalContent = ""
+	r.ComparisonData = ""
+	r.TokenLogProbs = nil
+	r.ContentOmitted = true
+}
+
 // IsSuccess 判断是否成功
 func (r *PolishRecord) IsSuccess() bool {
 	return r.Status == "success"
@@ -75,6 +128,11 @@ func (r *PolishRecord) IsMultiVersionMode() bool {
 	return r.Mode == "multi"
 }
 
+// IsTranslateMode 判断是否为翻译模式
+func (r *PolishRecord) IsTranslateMode() bool {
+	return r.Mode == ModeTranslate
+}
+
 // IsSingleVersionMode 判断是否为单版本模式
 func (r *PolishRecord) IsSingleVersionMode() bool {
 	return r.Mode == "single" || r.Mode == ""
@@ -84,9 +142,17 @@ func (r *PolishRecord) IsSingleVersionMode() bool {
 const (
 	ModeSingle = "single" // 单版本模式
 	ModeMulti  = "multi"  // 多版本模式
+
+	ModeTranslate = "translate" // 翻译模式（不属于润色模式，单独的接口创建）
 )
 
-// IsValidMode 验证模式是否有效
+// IsValidMode 验证润色模式是否有效
 func IsValidMode(mode string) bool {
 	return mode == ModeSingle || mode == ModeMulti
 }
+
+// 按句组合选择相关取值
+const (
+	SelectedVersionComposite = "composite" // 按句组合多个版本
+	SentenceSourceOriginal   = "original"  // 该句保留原文
+)
@@ -0,0 +1,38 @@
+package entity
+
+import "time"
+
+// PolishRevision 多轮修改的一轮修订
+// 用户针对当时看到的文本（BaseContent）提出修改指令，模型返回修改结果；
+// 成功的修订会成为主记录当前的润色结果
+type PolishRevision struct {
+	ID       int64
+	RecordID int64
+	Revision int // 记录内的修订号（从 1 开始递增）
+
+	// 本轮输入
+	Instruction string // 修改指令
+	BaseContent string // 修改前的文本
+
+	// 本轮输出
+	PolishedContent string
+	PolishedLength  int
+	ComparisonData  string // BaseContent 与 PolishedContent 的对比数据JSON
+	ChangesCount    int
+
+	// AI信息
+	Provider      string
+	Model         string
+	ProcessTimeMs int
+
+	// 状态
+	Status       string // success / failed
+	ErrorMessage string
+
+	CreatedAt time.Time
+}
+
+// IsSuccess 判断是否成功
+func (r *PolishRevision) IsSuccess() bool {
+	return r.Status == "success"
+}
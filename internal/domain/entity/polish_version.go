@@ -15,7 +15,8 @@ type PolishVersion struct {
 	// 输出内容
 	PolishedContent string
 	PolishedLength  int
-	Suggestions     []string // 改进建议
+	Suggestions     []string       // 改进建议
+	TokenLogProbs   []TokenLogProb `json:"-"` // 模型返回的 token 对数概率（提供商支持时才有，不对外返回）
 
 	// AI信息
 	ModelUsed string
@@ -32,6 +33,13 @@ type PolishVersion struct {
 	CreatedAt time.Time
 }
 
+// OmitContent 清空润色结果和建议，只保留元数据（不保存内容模式）
+func (v *PolishVersion) OmitContent() {
+	v.PolishedContent = ""
+	v.Suggestions = nil
+	v.TokenLogProbs = nil
+}
+
 // IsSuccess 判断是否成功
 func (v *PolishVersion) IsSuccess() bool {
 	return v.Status == "success"
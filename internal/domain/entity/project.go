@@ -0,0 +1,22 @@
+package entity
+
+import "time"
+
+// Project 项目（润色记录文件夹），名称在用户内唯一
+type Project struct {
+	ID          int64
+	UserID      int64
+	Name        string
+	Description string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// Tag 用户自定义标签，名称在用户内唯一
+type Tag struct {
+	ID        int64
+	UserID    int64
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -21,6 +21,10 @@ type User struct {
 	EnableMultiVersion  bool // 是否启用多版本功能
 	MultiVersionQuota   int  // 多版本配额（0=无限）
 
+	// 隐私设置
+	RetentionDays  int  // 润色历史保留天数（0=不按用户设置删除）
+	NoStoreContent bool // 不保存内容模式：润色记录只保存元数据
+
 	CreatedAt        time.Time
 	UpdatedAt        time.Time
 }
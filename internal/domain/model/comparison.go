@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 // ComparisonResult 对比结果
 type ComparisonResult struct {
 	TraceID         string      `json:"trace_id"`
@@ -22,16 +24,20 @@ type Change struct {
 
 	// 原文信息（悬浮时展示）
 	OriginalText     string        `json:"original_text"`     // 原始文本
+	OriginalPosition Position      `json:"original_position"` // 原文中的位置（用于跨版本对齐）
 
 	// 详情信息（右侧面板展示）
 	Reason           string        `json:"reason"`            // 修改理由
 	Alternatives     []Alternative `json:"alternatives"`      // 替代方案
 	Confidence       float64       `json:"confidence"`        // 置信度 0-1
+	ConfidenceFactors *ConfidenceFactors `json:"confidence_factors,omitempty"` // 置信度计算依据
 	Impact           string        `json:"impact"`            // 影响维度
 	HighlightColor   string        `json:"highlight_color"`   // 建议的高亮颜色
 
 	// 用户操作状态
-	Status           ActionStatus  `json:"status"`            // pending/accepted/rejected
+	Status           ActionStatus  `json:"status"`                // pending/accepted/rejected/edited
+	EditedText       string        `json:"edited_text,omitempty"` // 用户编辑后的文本（status 为 edited 时替代 polished_text）
+	Manual           bool          `json:"manual,omitempty"`      // 用户在最终文本上自由编辑产生的修改
 }
 
 // Position 位置信息
@@ -53,6 +59,25 @@ type Metadata struct {
 	PolishedWordCount        int     `json:"polished_word_count"`
 	TotalChanges             int     `json:"total_changes"`
 	AcademicScoreImprovement float64 `json:"academic_score_improvement"` // 百分比
+	HiddenChanges            int     `json:"hidden_changes,omitempty"`   // 因置信度过滤而未返回的修改数
+}
+
+// ConfidenceFactors 置信度计算因子
+// 可选信号为 nil 表示该信号不可用
+type ConfidenceFactors struct {
+	Prior                float64  `json:"prior"`                            // 类型先验（含编辑幅度调整）
+	EditDistanceRatio    float64  `json:"edit_distance_ratio"`              // 归一化编辑距离 0-1
+	VersionAgreement     *float64 `json:"version_agreement,omitempty"`      // 多版本一致率
+	TokenProbability     *float64 `json:"token_probability,omitempty"`      // 模型 token 平均概率
+	HistoricalAcceptRate *float64 `json:"historical_accept_rate,omitempty"` // 相似修改历史接受率
+	HistoricalSamples    int64    `json:"historical_samples,omitempty"`     // 历史样本数
+}
+
+// ComparisonOptions 获取对比数据的可选参数
+type ComparisonOptions struct {
+	VersionType         string  // 多版本润色中的版本类型
+	MinConfidence       float64 // 仅返回置信度 >= 该值的修改（0 表示不过滤）
+	AutoAcceptThreshold float64 // 自动接受置信度 >= 该值的待处理修改（0 表示不自动接受）
 }
 
 // Statistics 统计信息
@@ -78,13 +103,28 @@ const (
 	ActionStatusPending  ActionStatus = "pending"  // 待处理
 	ActionStatusAccepted ActionStatus = "accepted" // 已接受
 	ActionStatusRejected ActionStatus = "rejected" // 已拒绝
+	ActionStatusEdited   ActionStatus = "edited"   // 用户手动编辑
 )
 
+// IsApplied 判断该状态的修改是否体现在最终文本中
+func (s ActionStatus) IsApplied() bool {
+	return s == ActionStatusAccepted || s == ActionStatusEdited
+}
+
 // ChangeActionRequest 修改操作请求
 type ChangeActionRequest struct {
 	ChangeID         string `json:"change_id" binding:"required"`
-	Action           string `json:"action" binding:"required,oneof=accept reject"`
-	AlternativeIndex *int   `json:"alternative_index"` // 可选：选择替代方案
+	Action           string  `json:"action" binding:"required,oneof=accept reject edit"`
+	AlternativeIndex *int    `json:"alternative_index"` // 可选：选择替代方案
+	EditedText       *string `json:"edited_text"`       // edit 操作必填：用户自定义的替换文本
+}
+
+// ContentEditRequest 最终文本自由编辑请求
+// start/end 为当前最终文本中的位置（基于 rune），[start, end) 区间替换为 text
+type ContentEditRequest struct {
+	Start int    `json:"start" binding:"min=0"`
+	End   int    `json:"end" binding:"min=0"`
+	Text  string `json:"text"`
 }
 
 // ChangeActionResponse 修改操作响应
@@ -96,14 +136,162 @@ type ChangeActionResponse struct {
 }
 
 // BatchActionRequest 批量操作请求
+// 指定 change_ids 时只处理这些修改（不论当前状态）；否则处理所有待处理的修改
+// types / min_confidence / max_confidence 进一步筛选要处理的修改
 type BatchActionRequest struct {
-	Action    string   `json:"action" binding:"required,oneof=accept_all reject_all"`
-	ChangeIDs []string `json:"change_ids"` // 可选：指定特定的修改
+	Action        string       `json:"action" binding:"required,oneof=accept_all reject_all"`
+	ChangeIDs     []string     `json:"change_ids"`                                                        // 可选：指定特定的修改
+	Types         []ChangeType `json:"types" binding:"omitempty,dive,oneof=vocabulary grammar structure"` // 可选：只处理这些类型的修改
+	MinConfidence *float64     `json:"min_confidence" binding:"omitempty,min=0,max=1"`                    // 可选：只处理置信度 >= 该值的修改
+	MaxConfidence *float64     `json:"max_confidence" binding:"omitempty,min=0,max=1"`                    // 可选：只处理置信度 < 该值的修改
+}
+
+// Matches 判断修改是否符合筛选条件（不含 change_ids）
+func (r *BatchActionRequest) Matches(change *Change) bool {
+	if len(r.Types) > 0 {
+		matched := false
+		for _, t := range r.Types {
+			if change.Type == t {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if r.MinConfidence != nil && change.Confidence < *r.MinConfidence {
+		return false
+	}
+	if r.MaxConfidence != nil && change.Confidence >= *r.MaxConfidence {
+		return false
+	}
+	return true
 }
 
 // BatchActionResponse 批量操作响应
 type BatchActionResponse struct {
+	Success        bool         `json:"success"`
+	UpdatedContent string       `json:"updated_content"`
+	AppliedCount   int          `json:"applied_count"`
+	AppliedChanges []string     `json:"applied_changes"` // 本次状态发生变化的修改ID
+	StatusCounts   StatusCounts `json:"status_counts"`   // 操作后各状态的修改数量
+}
+
+// StatusCounts 各状态的修改数量
+type StatusCounts struct {
+	Pending  int `json:"pending"`
+	Accepted int `json:"accepted"`
+	Rejected int `json:"rejected"`
+	Edited   int `json:"edited"`
+}
+
+// CountStatuses 统计各状态的修改数量
+func CountStatuses(annotations []Change) StatusCounts {
+	var counts StatusCounts
+	for _, ann := range annotations {
+		switch ann.Status {
+		case ActionStatusPending:
+			counts.Pending++
+		case ActionStatusAccepted:
+			counts.Accepted++
+		case ActionStatusRejected:
+			counts.Rejected++
+		case ActionStatusEdited:
+			counts.Edited++
+		}
+	}
+	return counts
+}
+
+// ActionHistoryResponse 操作历史
+type ActionHistoryResponse struct {
+	TraceID string           `json:"trace_id"`
+	Entries []ActionLogEntry `json:"entries"` // 按序号升序
+	CanUndo bool             `json:"can_undo"`
+	CanRedo bool             `json:"can_redo"`
+	UndoSeq int              `json:"undo_seq,omitempty"` // 撤销时将被撤销的操作序号
+	RedoSeq int              `json:"redo_seq,omitempty"` // 重做时将被重做的操作序号
+}
+
+// ActionLogEntry 操作日志条目
+type ActionLogEntry struct {
+	Seq       int            `json:"seq"`
+	Action    string         `json:"action"`               // accept/reject/edit/free_edit/accept_all/reject_all/auto_accept/consensus/undo/redo/reset
+	TargetSeq int            `json:"target_seq,omitempty"` // 撤销/重做对应的原操作序号
+	Changes   []ActionChange `json:"changes"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+// ActionChange 单个修改的状态变化
+type ActionChange struct {
+	ChangeID   string       `json:"change_id"`
+	Before     ActionStatus `json:"before"`
+	After      ActionStatus `json:"after"`
+	BeforeText string       `json:"before_text,omitempty"` // 操作前的编辑文本（edited 状态）
+	AfterText  string       `json:"after_text,omitempty"`  // 操作后的编辑文本（edited 状态）
+}
+
+// HistoryContentResponse 历史某一时刻的最终文本
+type HistoryContentResponse struct {
+	TraceID      string                  `json:"trace_id"`
+	Seq          int                     `json:"seq"` // 0 表示执行任何操作之前
+	FinalContent string                  `json:"final_content"`
+	Statuses     map[string]ActionStatus `json:"statuses"` // change_id -> 当时的状态
+}
+
+// ConsensusResult 多版本一致性视图
+type ConsensusResult struct {
+	TraceID          string           `json:"trace_id"`
+	OriginalContent  string           `json:"original_content"`
+	ConsensusContent string           `json:"consensus_content"` // 仅应用所有版本一致修改后的文本
+	FinalContent     string           `json:"final_content"`     // 用户当前的最终文本
+	Versions         []string         `json:"versions"`          // 参与对齐的版本
+	Spans            []ConsensusSpan  `json:"spans"`
+	Summary          ConsensusSummary `json:"summary"`
+}
+
+// ConsensusSpan 对齐后的修改区间
+type ConsensusSpan struct {
+	ID                string            `json:"id"`
+	OriginalPosition  Position          `json:"original_position"`
+	OriginalText      string            `json:"original_text"`
+	Options           []ConsensusOption `json:"options"`            // 候选写法（按支持版本数降序）
+	UnchangedVersions []string          `json:"unchanged_versions"` // 未修改该区间的版本
+	Agreement         string            `json:"agreement"`          // all/some/one
+	Confidence        float64           `json:"confidence"`         // 最多版本采用的写法所占比例
+	Recommended       string            `json:"recommended"`        // 推荐采用的版本
+}
+
+// ConsensusOption 区间的一种候选写法
+type ConsensusOption struct {
+	Text     string   `json:"text"`
+	Versions []string `json:"versions"`
+}
+
+// ConsensusSummary 一致性统计
+type ConsensusSummary struct {
+	TotalSpans int `json:"total_spans"`
+	AllAgree   int `json:"all_agree"`
+	SomeAgree  int `json:"some_agree"`
+	OneVersion int `json:"one_version"`
+}
+
+// 区间选择的特殊取值
+const (
+	ConsensusSelectOriginal    = "original"    // 保留原文
+	ConsensusSelectRecommended = "recommended" // 采用推荐写法
+)
+
+// ConsensusApplyRequest 按区间选择版本写法生成最终文本
+type ConsensusApplyRequest struct {
+	Selections map[string]string `json:"selections"` // span_id -> 版本类型或 "original"
+	Default    string            `json:"default"`    // 未选择区间的处理：original（默认）/ recommended / 版本类型
+}
+
+// ConsensusApplyResponse 按区间选择版本写法的响应
+type ConsensusApplyResponse struct {
 	Success      bool   `json:"success"`
-	UpdatedContent string `json:"updated_content"`
-	AppliedCount int    `json:"applied_count"`
+	FinalContent string `json:"final_content"`
+	AppliedSpans int    `json:"applied_spans"` // 采用了版本写法的区间数
 }
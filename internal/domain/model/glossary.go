@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+const (
+	MaxGlossaryTermLength = 200 // 术语和译法最大长度（字符数）
+	MaxGlossaryNoteLength = 500 // 备注最大长度（字符数）
+	MaxGlossaryTerms      = 500 // 每个用户最多的术语数
+)
+
+// GlossaryTermRequest 创建/更新术语请求
+type GlossaryTermRequest struct {
+	SourceLanguage string `json:"source_language" binding:"required"` // 源语言
+	TargetLanguage string `json:"target_language" binding:"required"` // 目标语言（与源语言不同）
+	SourceTerm     string `json:"source_term" binding:"required"`     // 源语言术语（用户的同一语言对内唯一）
+	TargetTerm     string `json:"target_term" binding:"required"`     // 固定译法
+	Note           string `json:"note"`                               // 备注
+}
+
+// GlossaryTermInfo 术语信息
+type GlossaryTermInfo struct {
+	ID             int64     `json:"id"`
+	SourceLanguage string    `json:"source_language"`
+	TargetLanguage string    `json:"target_language"`
+	SourceTerm     string    `json:"source_term"`
+	TargetTerm     string    `json:"target_term"`
+	Note           string    `json:"note"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// ComponentStatusEnum 组件状态枚举
+const (
+	ComponentStatusUp   = "up"   // 正常
+	ComponentStatusDown = "down" // 不可用
+)
+
+// ReadinessStatusEnum 就绪状态枚举
+const (
+	ReadinessStatusReady        = "ready"         // 可以接收流量
+	ReadinessStatusNotReady     = "not_ready"     // 有依赖组件不可用
+	ReadinessStatusShuttingDown = "shutting_down" // 正在优雅关闭
+)
+
+// ComponentHealth 单个依赖组件的检查结果
+type ComponentHealth struct {
+	Name      string                 `json:"name"`
+	Status    string                 `json:"status"`     // up / down
+	LatencyMs int64                  `json:"latency_ms"` // 检查耗时（毫秒）
+	Error     string                 `json:"error,omitempty"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+}
+
+// ReadinessReport 就绪检查结果
+type ReadinessReport struct {
+	Status     string            `json:"status"` // ready / not_ready / shutting_down
+	Components []ComponentHealth `json:"components,omitempty"`
+	CheckedAt  time.Time         `json:"checked_at"`
+}
+
+// IsReady 判断是否可以接收流量
+func (r *ReadinessReport) IsReady() bool {
+	return r.Status == ReadinessStatusReady
+}
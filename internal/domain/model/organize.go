@@ -0,0 +1,52 @@
+package model
+
+import "time"
+
+const (
+	MaxProjectNameLength = 100 // 项目名称最大长度（字符数）
+	MaxTagNameLength     = 50  // 标签名称最大长度（字符数）
+	MaxTagsPerRecord     = 20  // 每条记录最多的标签数
+)
+
+// ProjectRequest 创建/更新项目请求
+type ProjectRequest struct {
+	Name        string `json:"name" binding:"required"` // 项目名称（用户内唯一）
+	Description string `json:"description"`             // 项目描述
+}
+
+// ProjectInfo 项目信息
+type ProjectInfo struct {
+	ID          int64     `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	RecordCount int64     `json:"record_count"` // 项目中的润色记录数
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TagRequest 创建/重命名标签请求
+type TagRequest struct {
+	Name string `json:"name" binding:"required"` // 标签名称（用户内唯一）
+}
+
+// TagInfo 标签信息
+type TagInfo struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SetRecordProjectRequest 设置记录所属项目请求
+type SetRecordProjectRequest struct {
+	ProjectID *int64 `json:"project_id"` // 为 null 表示移出项目
+}
+
+// SetRecordFavoriteRequest 收藏/取消收藏请求
+type SetRecordFavoriteRequest struct {
+	Favorite *bool `json:"favorite" binding:"required"`
+}
+
+// SetRecordTagsRequest 设置记录标签请求（替换全部标签）
+type SetRecordTagsRequest struct {
+	TagIDs []int64 `json:"tag_ids" binding:"required"` // 空数组表示清除所有标签
+}
@@ -1,11 +1,26 @@
 package model
 
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"paper_ai/pkg/language"
+)
+
 // PolishRequest 段落润色请求模型
 type PolishRequest struct {
 	Content  string `json:"content" binding:"required"`
 	Provider string `json:"provider"`
 	Style    string `json:"style"`
-	Language string `json:"language"`
+	Language string `json:"language"` // 为空时根据内容自动检测
+
+	ProjectID *int64 `json:"project_id"` // 保存到指定项目（可选）
+
+	// Selection 只润色 content 中的这一段，其余文本作为上下文（可选）
+	Selection *TextSelection `json:"selection"`
+
+	ParentTraceID string `json:"-"` // 重新润色的来源记录（由重新润色接口设置）
 }
 
 // Validate 验证请求参数
@@ -25,19 +40,43 @@ func (r *PolishRequest) Validate() error {
 
 	// 验证language
 	if r.Language != "" && !isValidLanguage(r.Language) {
-		return &ValidationError{Field: "language", Message: "invalid language, must be one of: en, zh"}
+		return invalidLanguageError("language")
+	}
+
+	// 验证selection
+	if r.Selection != nil {
+		if err := r.Selection.validate(r.Content); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
+// TextSelection 文本选区（基于 rune 的偏移，End 不包含）
+type TextSelection struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// validate 验证选区在文本范围内且不只包含空白
+func (s *TextSelection) validate(content string) error {
+	if s.Start < 0 || s.End <= s.Start || s.End > utf8.RuneCountInString(content) {
+		return &ValidationError{Field: "selection", Message: "invalid selection, must satisfy 0 <= start < end <= length of content"}
+	}
+	if strings.TrimSpace(string([]rune(content)[s.Start:s.End])) == "" {
+		return &ValidationError{Field: "selection", Message: "selection cannot be blank"}
+	}
+	return nil
+}
+
 // SetDefaults 设置默认值
 func (r *PolishRequest) SetDefaults() {
 	if r.Style == "" {
 		r.Style = "academic"
 	}
 	if r.Language == "" {
-		r.Language = "en"
+		r.Language = language.DetectOrDefault(r.Content)
 	}
 }
 
@@ -61,11 +100,15 @@ func isValidStyle(style string) bool {
 	return validStyles[style]
 }
 
-// isValidLanguage 验证语言是否有效
+// isValidLanguage 验证语言是否已开放（languages.supported 配置）
 func isValidLanguage(lang string) bool {
-	validLanguages := map[string]bool{
-		"en": true,
-		"zh": true,
+	return language.IsSupported(lang)
+}
+
+// invalidLanguageError 语言未开放的验证错误
+func invalidLanguageError(field string) *ValidationError {
+	return &ValidationError{
+		Field:   field,
+		Message: fmt.Sprintf("invalid %s, must be one of: %s", field, strings.Join(language.Supported(), ", ")),
 	}
-	return validLanguages[lang]
 }
@@ -1,12 +1,18 @@
 package model
 
+import "paper_ai/internal/domain/entity"
+
 // PolishMultiVersionRequest 多版本润色请求
 type PolishMultiVersionRequest struct {
 	Content  string   `json:"content"`  // 原始内容
 	Style    string   `json:"style"`    // 润色风格: academic/formal/concise
-	Language string   `json:"language"` // 语言: en/zh
+	Language string   `json:"language"` // 语言（languages.supported 中的代码），为空时根据内容自动检测
 	Provider string   `json:"provider"` // AI提供商: claude/doubao等
 	Versions []string `json:"versions"` // 指定需要的版本类型，不指定则生成全部3个版本
+
+	ProjectID *int64 `json:"project_id"` // 保存到指定项目（可选）
+
+	ParentTraceID string `json:"-"` // 重新润色的来源记录（由重新润色接口设置）
 }
 
 // PolishMultiVersionResponse 多版本润色响应
@@ -28,3 +34,32 @@ type VersionResult struct {
 	Status          string   `json:"status"`           // 状态: success / failed
 	ErrorMessage    string   `json:"error_message"`    // 错误信息(如果失败)
 }
+
+// SelectVersionRequest 按句组合多版本请求
+type SelectVersionRequest struct {
+	Sentences map[int]string `json:"sentences"` // 句子序号 -> 版本类型（或 original 保留原文）
+	Default   string         `json:"default"`   // 未指定句子的来源，默认使用查询参数 version，均未指定时保留原文
+}
+
+// SentenceAlignmentResponse 多版本句子对齐结果
+type SentenceAlignmentResponse struct {
+	TraceID   string            `json:"trace_id"`
+	Versions  []string          `json:"versions"` // 参与对齐的成功版本
+	Sentences []AlignedSentence `json:"sentences"`
+}
+
+// AlignedSentence 对齐后的句子
+type AlignedSentence struct {
+	Index     int               `json:"index"`
+	Original  string            `json:"original"`
+	Versions  map[string]string `json:"versions"`  // version_type -> 该版本对应的句子
+	Identical bool              `json:"identical"` // 所有版本该句相同
+}
+
+// SelectVersionResponse 选择版本响应
+type SelectVersionResponse struct {
+	Message         string                  `json:"message"`
+	TraceID         string                  `json:"trace_id"`
+	SelectedVersion string                  `json:"selected_version"`
+	SentenceSources []entity.SentenceSource `json:"sentence_sources,omitempty"` // 按句组合时每句的来源
+}
@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+const (
+	MaxRefineInstructionLength = 500 // 修改指令最大长度（字符数）
+	MaxRevisionsPerRecord      = 20  // 每条记录最多的修订数（包括失败的）
+)
+
+// RefineRequest 多轮修改请求
+type RefineRequest struct {
+	Instruction string `json:"instruction" binding:"required"` // 自然语言修改指令，如"更简短一些"、"保留我的第二句"
+	Provider    string `json:"provider"`                       // AI提供商，默认与记录相同
+}
+
+// RevisionInfo 修订信息
+type RevisionInfo struct {
+	Revision        int               `json:"revision"`
+	Instruction     string            `json:"instruction"`
+	BaseContent     string            `json:"base_content"` // 修改前的文本
+	PolishedContent string            `json:"polished_content"`
+	PolishedLength  int               `json:"polished_length"`
+	ChangesCount    int               `json:"changes_count"`
+	Provider        string            `json:"provider"`
+	Model           string            `json:"model"`
+	ProcessTimeMs   int               `json:"process_time_ms"`
+	Status          string            `json:"status"`
+	ErrorMessage    string            `json:"error_message,omitempty"`
+	CreatedAt       time.Time         `json:"created_at"`
+	Comparison      *ComparisonResult `json:"comparison,omitempty"` // 修改前文本与修改结果的对比（列表中不返回）
+}
@@ -0,0 +1,26 @@
+package model
+
+// 重新润色的文本来源
+const (
+	RepolishSourceOriginal = "original" // 来源记录的原文
+	RepolishSourceFinal    = "final"    // 来源记录当前的最终文本（应用修改或选择版本后的结果）
+)
+
+// RepolishRequest 基于已有记录重新润色请求（未指定的参数沿用来源记录）
+type RepolishRequest struct {
+	Mode     string   `json:"mode"`     // single / multi，默认与来源记录相同
+	Source   string   `json:"source"`   // original（默认）/ final
+	Style    string   `json:"style"`    // 润色风格
+	Language string   `json:"language"` // 语言
+	Provider string   `json:"provider"` // AI提供商
+	Versions []string `json:"versions"` // 多版本模式下的版本类型，不指定则生成全部版本
+}
+
+// RepolishResponse 重新润色响应
+type RepolishResponse struct {
+	TraceID       string      `json:"trace_id"`        // 新记录的追踪ID
+	ParentTraceID string      `json:"parent_trace_id"` // 来源记录的追踪ID
+	Mode          string      `json:"mode"`
+	Source        string      `json:"source"`
+	Result        interface{} `json:"result"` // 单版本为润色结果，多版本为多版本润色结果
+}
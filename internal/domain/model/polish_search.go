@@ -0,0 +1,13 @@
+package model
+
+import "paper_ai/internal/domain/entity"
+
+// MaxSearchTextLength 检索词最大长度（字符数）
+const MaxSearchTextLength = 200
+
+// RecordSearchHit 润色历史检索结果
+type RecordSearchHit struct {
+	Record          *entity.PolishRecord `json:"record"`
+	OriginalSnippet string               `json:"original_snippet,omitempty"` // 原文中匹配位置附近的片段（HTML 转义，匹配词以 <mark> 标记）
+	PolishedSnippet string               `json:"polished_snippet,omitempty"` // 最终文本中匹配位置附近的片段
+}
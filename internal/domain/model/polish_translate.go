@@ -0,0 +1,125 @@
+package model
+
+import (
+	"fmt"
+
+	"paper_ai/pkg/language"
+)
+
+// TranslateRequest 翻译润色请求（例如中文草稿 → 英文学术文本）
+type TranslateRequest struct {
+	Content        string `json:"content" binding:"required"`
+	SourceLanguage string `json:"source_language"` // 源语言（为空时根据内容自动检测）
+	TargetLanguage string `json:"target_language"` // 目标语言（为空时使用默认语言，与源语言相同时取第一个不同的开放语言）
+	Style          string `json:"style"`
+	Provider       string `json:"provider"`
+
+	UseGlossary *bool  `json:"use_glossary"` // 是否使用术语表（默认使用）
+	ProjectID   *int64 `json:"project_id"`   // 保存到指定项目（可选）
+}
+
+// Validate 验证请求参数
+func (r *TranslateRequest) Validate() error {
+	if r.Content == "" {
+		return &ValidationError{Field: "content", Message: "content cannot be empty"}
+	}
+
+	if len(r.Content) > 10000 {
+		return &ValidationError{Field: "content", Message: "content too long, maximum 10000 characters"}
+	}
+
+	if r.Style != "" && !isValidStyle(r.Style) {
+		return &ValidationError{Field: "style", Message: "invalid style, must be one of: academic, formal, concise"}
+	}
+
+	if r.SourceLanguage != "" && !isValidLanguage(r.SourceLanguage) {
+		return invalidLanguageError("source_language")
+	}
+
+	if r.TargetLanguage != "" && !isValidLanguage(r.TargetLanguage) {
+		return invalidLanguageError("target_language")
+	}
+
+	return nil
+}
+
+// SetDefaults 设置默认值，并检查源语言和目标语言不同
+func (r *TranslateRequest) SetDefaults() error {
+	if r.Style == "" {
+		r.Style = "academic"
+	}
+	if r.SourceLanguage == "" {
+		code, ok := language.Detect(r.Content)
+		switch {
+		case !ok:
+			r.SourceLanguage = language.Default()
+		case !language.IsSupported(code):
+			return &ValidationError{Field: "source_language", Message: fmt.Sprintf("unsupported source language: %s", code)}
+		default:
+			r.SourceLanguage = code
+		}
+	}
+	if r.TargetLanguage == "" {
+		r.TargetLanguage = defaultTargetLanguage(r.SourceLanguage)
+	}
+	if r.TargetLanguage == "" || r.SourceLanguage == r.TargetLanguage {
+		return &ValidationError{Field: "target_language", Message: "target_language must differ from source_language, use polish instead"}
+	}
+	return nil
+}
+
+// defaultTargetLanguage 默认目标语言：默认语言，与源语言相同时取第一个不同的开放语言
+func defaultTargetLanguage(source string) string {
+	if def := language.Default(); def != source {
+		return def
+	}
+	for _, code := range language.Supported() {
+		if code != source {
+			return code
+		}
+	}
+	return ""
+}
+
+// TranslateResponse 翻译润色响应
+type TranslateResponse struct {
+	TraceID           string              `json:"trace_id"`
+	TranslatedContent string              `json:"translated_content"`
+	SourceLanguage    string              `json:"source_language"`
+	TargetLanguage    string              `json:"target_language"`
+	OriginalLength    int                 `json:"original_length"`
+	TranslatedLength  int                 `json:"translated_length"`
+	GlossaryTerms     []*GlossaryTermInfo `json:"glossary_terms"` // 原文中出现、要求提供商使用的术语
+	ProviderUsed      string              `json:"provider_used"`
+	ModelUsed         string              `json:"model_used"`
+}
+
+// BilingualComparison 双语对照（翻译记录按句对齐，代替逐字对比）
+type BilingualComparison struct {
+	TraceID           string           `json:"trace_id"`
+	SourceLanguage    string           `json:"source_language"`
+	TargetLanguage    string           `json:"target_language"`
+	SourceContent     string           `json:"source_content"`
+	TranslatedContent string           `json:"translated_content"`
+	Pairs             []*BilingualPair `json:"pairs"`
+	InconsistentTerms int              `json:"inconsistent_terms"` // 译文没有使用术语表译法的次数
+}
+
+// BilingualPair 互相对应的一组原文和译文句子
+type BilingualPair struct {
+	Index       int          `json:"index"`
+	SourceText  string       `json:"source_text"`
+	TargetText  string       `json:"target_text"`
+	SourceStart int          `json:"source_start"` // 在原文中的位置（基于 rune）
+	SourceEnd   int          `json:"source_end"`
+	TargetStart int          `json:"target_start"` // 在译文中的位置（基于 rune）
+	TargetEnd   int          `json:"target_end"`
+	Terms       []*TermCheck `json:"terms,omitempty"` // 原文句子中出现的术语及译文是否使用了术语表译法
+}
+
+// TermCheck 术语一致性检查结果
+type TermCheck struct {
+	SourceTerm string `json:"source_term"`
+	TargetTerm string `json:"target_term"`
+	Consistent bool   `json:"consistent"`
+}
@@ -0,0 +1,21 @@
+package model
+
+// MaxRetentionDays 用户可设置的最大保留天数
+const MaxRetentionDays = 3650
+
+// PrivacySettings 用户隐私设置
+type PrivacySettings struct {
+	RetentionDays  int  `json:"retention_days"`   // 润色历史保留天数，超过后自动删除（0=不自动删除）
+	NoStoreContent bool `json:"no_store_content"` // 不保存内容模式：只保存元数据，不保存原文和润色结果
+}
+
+// UpdatePrivacySettingsRequest 更新隐私设置请求（字段为空表示不修改）
+type UpdatePrivacySettingsRequest struct {
+	RetentionDays  *int  `json:"retention_days" binding:"omitempty,min=0,max=3650"`
+	NoStoreContent *bool `json:"no_store_content"`
+}
+
+// DeleteRecordsResponse 批量删除润色记录响应
+type DeleteRecordsResponse struct {
+	Deleted int64 `json:"deleted"` // 删除的记录数
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"paper_ai/internal/domain/entity"
+)
+
+// ChangeFeedbackRepository 修改反馈仓储接口
+type ChangeFeedbackRepository interface {
+	// BatchCreate 批量记录反馈
+	BatchCreate(ctx context.Context, feedbacks []*entity.ChangeFeedback) error
+
+	// GetAcceptStatsBySignatures 按修改签名统计接受情况
+	GetAcceptStatsBySignatures(ctx context.Context, signatures []string) (map[string]*AcceptStats, error)
+
+	// GetAcceptStatsByType 按修改类型统计接受情况
+	GetAcceptStatsByType(ctx context.Context) (map[string]*AcceptStats, error)
+}
+
+// AcceptStats 接受情况统计
+type AcceptStats struct {
+	TotalCount    int64
+	AcceptedCount int64
+}
+
+// AcceptRate 接受率 0-1
+func (s *AcceptStats) AcceptRate() float64 {
+	if s.TotalCount == 0 {
+		return 0
+	}
+	return float64(s.AcceptedCount) / float64(s.TotalCount)
+}
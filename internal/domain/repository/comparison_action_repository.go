@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"paper_ai/internal/domain/entity"
+)
+
+// ComparisonActionRepository 对比操作日志仓储接口
+type ComparisonActionRepository interface {
+	// Append 追加操作日志（自动分配记录内的序号）
+	Append(ctx context.Context, action *entity.ComparisonAction) error
+
+	// ListByRecordID 按序号升序获取某条记录的全部操作日志
+	ListByRecordID(ctx context.Context, recordID int64) ([]*entity.ComparisonAction, error)
+}
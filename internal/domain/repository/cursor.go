@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"paper_ai/internal/domain/entity"
+)
+
+// sortableFields 润色记录列表允许排序的字段
+var sortableFields = map[string]bool{
+	"created_at":      true,
+	"process_time_ms": true,
+	"original_length": true,
+	"changes_count":   true,
+}
+
+// IsSortableField 是否为允许排序的字段
+func IsSortableField(field string) bool {
+	return sortableFields[field]
+}
+
+// Cursor 游标分页位置：上一页最后一条记录的排序字段值和 ID（排序字段值相同时按 ID 区分）
+// 游标中记录了排序方式，使用游标翻页时排序方式以游标为准
+type Cursor struct {
+	OrderBy   string `json:"o"`
+	OrderDesc bool   `json:"d"`
+	Value     string `json:"v"` // created_at 为 RFC3339Nano 时间，其他字段为整数
+	ID        int64  `json:"i"`
+}
+
+// NewCursor 生成指向 record 之后的游标
+func NewCursor(orderBy string, orderDesc bool, record *entity.PolishRecord) *Cursor {
+	var value string
+	switch orderBy {
+	case "process_time_ms":
+		value = strconv.Itoa(record.ProcessTimeMs)
+	case "original_length":
+		value = strconv.Itoa(record.OriginalLength)
+	case "changes_count":
+		value = strconv.Itoa(record.ChangesCount)
+	default:
+		orderBy = "created_at"
+		value = record.CreatedAt.Format(time.RFC3339Nano)
+	}
+
+	return &Cursor{OrderBy: orderBy, OrderDesc: orderDesc, Value: value, ID: record.ID}
+}
+
+// DecodeCursor 解析 Encode 生成的游标字符串
+func DecodeCursor(s string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+	if !IsSortableField(cursor.OrderBy) {
+		return nil, fmt.Errorf("invalid cursor: unsupported sort field %q", cursor.OrderBy)
+	}
+	if _, err := cursor.SortValue(); err != nil {
+		return nil, err
+	}
+	return &cursor, nil
+}
+
+// Encode 编码为不透明的游标字符串（URL 安全）
+func (c *Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// SortValue 排序字段值（created_at 为 time.Time，其他字段为 int64）
+func (c *Cursor) SortValue() (interface{}, error) {
+	if c.OrderBy == "created_at" {
+		t, err := time.Parse(time.RFC3339Nano, c.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor: %w", err)
+		}
+		return t, nil
+	}
+
+	n, err := strconv.ParseInt(c.Value, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+	return n, nil
+}
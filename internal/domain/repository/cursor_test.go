@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"paper_ai/internal/domain/entity"
+)
+
+func TestCursor_RoundTrip(t *testing.T) {
+	createdAt := time.Date(2025, 3, 1, 8, 30, 15, 123456000, time.UTC)
+	record := &entity.PolishRecord{ID: 42, CreatedAt: createdAt, ProcessTimeMs: 1500, OriginalLength: 320, ChangesCount: 7}
+
+	tests := []struct {
+		orderBy string
+		want    interface{}
+	}{
+		{"created_at", createdAt},
+		{"process_time_ms", int64(1500)},
+		{"original_length", int64(320)},
+		{"changes_count", int64(7)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.orderBy, func(t *testing.T) {
+			cursor, err := DecodeCursor(NewCursor(tt.orderBy, true, record).Encode())
+			if err != nil {
+				t.Fatalf("DecodeCursor() error = %v", err)
+			}
+			if cursor.OrderBy != tt.orderBy || !cursor.OrderDesc || cursor.ID != 42 {
+				t.Errorf("cursor = %+v", cursor)
+			}
+
+			value, err := cursor.SortValue()
+			if err != nil {
+				t.Fatalf("SortValue() error = %v", err)
+			}
+			if got, ok := value.(time.Time); ok {
+				if !got.Equal(tt.want.(time.Time)) {
+					t.Errorf("SortValue() = %v, want %v", got, tt.want)
+				}
+			} else if value != tt.want {
+				t.Errorf("SortValue() = %v, want %v", value, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeCursor_Invalid(t *testing.T) {
+	invalid := []string{
+		"not base64!",
+		"bm90IGpzb24", // "not json"
+		(&Cursor{OrderBy: "trace_id", Value: "1"}).Encode(), // 不允许排序的字段
+		(&Cursor{OrderBy: "created_at", Value: "yesterday"}).Encode(),
+		(&Cursor{OrderBy: "changes_count", Value: "1.5"}).Encode(),
+	}
+
+	for _, s := range invalid {
+		if _, err := DecodeCursor(s); err == nil {
+			t.Errorf("DecodeCursor(%q) 应返回错误", s)
+		}
+	}
+}
+
+func TestQueryOptionsBuilder_OrderByWhitelist(t *testing.T) {
+	opts := NewQueryOptions().OrderBy("id; DROP TABLE users", false).Build()
+	if opts.OrderBy != "created_at" || !opts.OrderDesc {
+		t.Errorf("不允许排序的字段应被忽略, got %q desc=%v", opts.OrderBy, opts.OrderDesc)
+	}
+
+	cursor := &Cursor{OrderBy: "changes_count", OrderDesc: false, Value: "3", ID: 9}
+	opts = NewQueryOptions().OrderBy("created_at", true).CursorPage(cursor, 500).Build()
+	if !opts.CursorMode || opts.Limit != 100 || opts.Offset != 0 {
+		t.Errorf("CursorPage() opts = %+v", opts)
+	}
+	if opts.OrderBy != "changes_count" || opts.OrderDesc {
+		t.Errorf("使用游标时应按游标的排序方式, got %q desc=%v", opts.OrderBy, opts.OrderDesc)
+	}
+}
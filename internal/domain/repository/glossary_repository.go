@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+
+	"paper_ai/internal/domain/entity"
+)
+
+// GlossaryRepository 术语表仓储接口
+type GlossaryRepository interface {
+	// Create 创建术语
+	Create(ctx context.Context, term *entity.GlossaryTerm) error
+
+	// GetByID 根据ID获取术语，不存在时返回 nil, nil
+	GetByID(ctx context.Context, id int64) (*entity.GlossaryTerm, error)
+
+	// GetBySourceTerm 获取用户在指定语言对下的同名源术语，不存在时返回 nil, nil
+	GetBySourceTerm(ctx context.Context, userID int64, sourceLanguage, targetLanguage, sourceTerm string) (*entity.GlossaryTerm, error)
+
+	// ListByUser 列出用户的所有术语（按语言对和源术语排序）
+	ListByUser(ctx context.Context, userID int64) ([]*entity.GlossaryTerm, error)
+
+	// ListByLanguagePair 列出用户在指定语言对下的术语（按源术语排序）
+	ListByLanguagePair(ctx context.Context, userID int64, sourceLanguage, targetLanguage string) ([]*entity.GlossaryTerm, error)
+
+	// CountByUser 统计用户的术语数
+	CountByUser(ctx context.Context, userID int64) (int64, error)
+
+	// Update 更新术语的语言对、源术语、译法和备注
+	Update(ctx context.Context, term *entity.GlossaryTerm) error
+
+	// Delete 删除术语
+	Delete(ctx context.Context, id int64) error
+}
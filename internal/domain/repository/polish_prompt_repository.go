@@ -17,7 +17,8 @@ type PolishPromptRepository interface {
 	// 查询策略：
 	// 1. 精确匹配：versionType + language + style
 	// 2. 降级匹配：versionType + language + style='all'
-	// 3. 再降级：versionType + language='all' + style='all'
+	// 3. 再降级：versionType + language='all' + style
+	// 4. 最后：versionType + language='all' + style='all'
 	GetActive(ctx context.Context, versionType, language, style string) (*entity.PolishPrompt, error)
 
 	// List 列出Prompts（支持过滤）
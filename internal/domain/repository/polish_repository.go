@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"time"
+
 	"paper_ai/internal/domain/entity"
 )
 
@@ -22,6 +24,26 @@ type PolishRepository interface {
 	// 统计操作
 	GetStatistics(ctx context.Context, opts StatisticsOptions) (*Statistics, error)
 
+	// 整理操作（项目、收藏只通过这两个方法修改，Update 不写入）
+	// SetProject 设置记录所属项目（projectID 为 nil 表示移出项目）
+	SetProject(ctx context.Context, id int64, projectID *int64) error
+	// SetFavorite 设置记录是否收藏
+	SetFavorite(ctx context.Context, id int64, favorite bool) error
+
 	// 批量操作（可选，用于未来扩展）
 	BatchCreate(ctx context.Context, records []*entity.PolishRecord) error
+
+	// 维护操作（定时任务使用）
+	// MarkStaleProcessingFailed 将 before 之前创建、仍处于 processing 状态的记录标记为失败，返回更新数量
+	MarkStaleProcessingFailed(ctx context.Context, before time.Time, errorMessage string) (int64, error)
+	// PurgeCreatedBefore 物理删除 before 之前创建的记录（包括已软删除的，版本记录级联删除），按批次执行，返回删除数量
+	PurgeCreatedBefore(ctx context.Context, before time.Time, batchSize int) (int64, error)
+	// PurgeByUserRetention 按用户设置的保留天数物理删除过期记录，按批次执行，返回删除数量
+	PurgeByUserRetention(ctx context.Context, batchSize int) (int64, error)
+
+	// 用户删除（物理删除，版本记录级联删除）
+	// PurgeByID 删除单条记录
+	PurgeByID(ctx context.Context, id int64) error
+	// PurgeByUser 删除用户在时间范围内创建的记录（start/end 为空表示不限），返回删除数量
+	PurgeByUser(ctx context.Context, userID int64, start, end *time.Time) (int64, error)
 }
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"paper_ai/internal/domain/entity"
+)
+
+// PolishRevisionRepository 多轮修改记录仓储接口
+type PolishRevisionRepository interface {
+	// Create 创建修订，修订号自动分配为该记录当前最大修订号 + 1
+	Create(ctx context.Context, revision *entity.PolishRevision) error
+
+	// ListByRecordID 按修订号升序获取记录的所有修订
+	ListByRecordID(ctx context.Context, recordID int64) ([]*entity.PolishRevision, error)
+
+	// GetByRecordIDAndRevision 获取记录的指定修订，不存在时返回 nil, nil
+	GetByRecordIDAndRevision(ctx context.Context, recordID int64, revision int) (*entity.PolishRevision, error)
+}
@@ -36,6 +36,9 @@ type PolishVersionRepository interface {
 
 	// GetStatsByVersionType 按版本类型统计
 	GetStatsByVersionType(ctx context.Context) (map[string]*VersionTypeStats, error)
+
+	// GetStatsByPromptID 按使用的Prompt统计（忽略未关联Prompt的版本）
+	GetStatsByPromptID(ctx context.Context) (map[int64]*PromptVersionStats, error)
 }
 
 // VersionFilter 版本查询过滤器
@@ -53,3 +56,11 @@ type VersionTypeStats struct {
 	FailedCount      int64
 	AvgProcessTimeMs float64
 }
+
+// PromptVersionStats 单个Prompt生成版本的统计
+type PromptVersionStats struct {
+	PromptID     int64
+	TotalCount   int64
+	SuccessCount int64
+	FailedCount  int64
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+
+	"paper_ai/internal/domain/entity"
+)
+
+// ProjectRepository 项目仓储接口
+type ProjectRepository interface {
+	// Create 创建项目
+	Create(ctx context.Context, project *entity.Project) error
+
+	// GetByID 根据ID获取项目，不存在时返回 nil, nil
+	GetByID(ctx context.Context, id int64) (*entity.Project, error)
+
+	// GetByName 获取用户的同名项目，不存在时返回 nil, nil
+	GetByName(ctx context.Context, userID int64, name string) (*entity.Project, error)
+
+	// ListByUser 列出用户的所有项目（按名称排序）
+	ListByUser(ctx context.Context, userID int64) ([]*entity.Project, error)
+
+	// CountRecords 统计用户各项目中的润色记录数（project_id → 数量）
+	CountRecords(ctx context.Context, userID int64) (map[int64]int64, error)
+
+	// Update 更新项目名称和描述
+	Update(ctx context.Context, project *entity.Project) error
+
+	// Delete 删除项目（项目中的记录保留，移出项目）
+	Delete(ctx context.Context, id int64) error
+}
+
+// TagRepository 标签仓储接口
+type TagRepository interface {
+	// Create 创建标签
+	Create(ctx context.Context, tag *entity.Tag) error
+
+	// GetByID 根据ID获取标签，不存在时返回 nil, nil
+	GetByID(ctx context.Context, id int64) (*entity.Tag, error)
+
+	// GetByName 获取用户的同名标签，不存在时返回 nil, nil
+	GetByName(ctx context.Context, userID int64, name string) (*entity.Tag, error)
+
+	// ListByUser 列出用户的所有标签（按名称排序）
+	ListByUser(ctx context.Context, userID int64) ([]*entity.Tag, error)
+
+	// Update 更新标签名称
+	Update(ctx context.Context, tag *entity.Tag) error
+
+	// Delete 删除标签（同时删除与记录的关联）
+	Delete(ctx context.Context, id int64) error
+
+	// ListByRecordIDs 批量获取记录的标签（record_id → 标签，按名称排序）
+	ListByRecordIDs(ctx context.Context, recordIDs []int64) (map[int64][]*entity.Tag, error)
+
+	// SetRecordTags 替换记录的全部标签
+	SetRecordTags(ctx context.Context, recordID int64, tagIDs []int64) error
+}
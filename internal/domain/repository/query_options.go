@@ -10,6 +10,10 @@ type QueryOptions struct {
 	Offset   int
 	Limit    int
 
+	// 游标分页（keyset），设置后忽略 Offset，不统计总数
+	CursorMode bool
+	Cursor     *Cursor // 上一页最后一条记录的位置，为 nil 表示第一页
+
 	// 过滤条件
 	UserID   *int64  // 按用户ID过滤 ⭐ 新增
 	Provider *string // 按提供商过滤
@@ -17,12 +21,23 @@ type QueryOptions struct {
 	Language *string // 按语言过滤
 	Style    *string // 按风格过滤
 
+	// 整理
+	ProjectID *int64 // 按项目过滤（0 表示未归属项目的记录）
+	TagID     *int64 // 按标签过滤
+	Favorite  *bool  // 按是否收藏过滤
+
+	// 重新润色谱系
+	ParentTraceID *string // 只查询由该记录重新润色产生的记录
+
+	// 全文检索（匹配原文和最终文本，已加密的记录不参与检索）
+	SearchText *string
+
 	// 时间范围
 	StartTime *time.Time
 	EndTime   *time.Time
 
-	// 排序
-	OrderBy   string // 排序字段
+	// 排序（排序字段值相同时按 ID 排序）
+	OrderBy   string // 排序字段，见 IsSortableField
 	OrderDesc bool   // 是否降序
 
 	// 字段选择（性能优化）
@@ -50,12 +65,7 @@ func (b *QueryOptionsBuilder) Page(page, pageSize int) *QueryOptionsBuilder {
 	if page < 1 {
 		page = 1
 	}
-	if pageSize < 1 {
-		pageSize = 20
-	}
-	if pageSize > 100 {
-		pageSize = 100 // 限制最大页大小
-	}
+	pageSize = normalizePageSize(pageSize)
 
 	b.opts.Page = page
 	b.opts.PageSize = pageSize
@@ -64,6 +74,35 @@ func (b *QueryOptionsBuilder) Page(page, pageSize int) *QueryOptionsBuilder {
 	return b
 }
 
+// CursorPage 设置游标分页（cursor 为 nil 表示第一页），与 Page 互斥
+// cursor 不为 nil 时排序方式使用游标中记录的排序方式
+func (b *QueryOptionsBuilder) CursorPage(cursor *Cursor, pageSize int) *QueryOptionsBuilder {
+	pageSize = normalizePageSize(pageSize)
+
+	b.opts.CursorMode = true
+	b.opts.Cursor = cursor
+	b.opts.Page = 0
+	b.opts.PageSize = pageSize
+	b.opts.Offset = 0
+	b.opts.Limit = pageSize
+	if cursor != nil {
+		b.opts.OrderBy = cursor.OrderBy
+		b.opts.OrderDesc = cursor.OrderDesc
+	}
+	return b
+}
+
+// normalizePageSize 规范页大小（默认 20，最大 100）
+func normalizePageSize(pageSize int) int {
+	if pageSize < 1 {
+		return 20
+	}
+	if pageSize > 100 {
+		return 100 // 限制最大页大小
+	}
+	return pageSize
+}
+
 // WithUserID 按用户ID过滤 ⭐ 新增
 func (b *QueryOptionsBuilder) WithUserID(userID int64) *QueryOptionsBuilder {
 	b.opts.UserID = &userID
@@ -94,6 +133,36 @@ func (b *QueryOptionsBuilder) WithStyle(style string) *QueryOptionsBuilder {
 	return b
 }
 
+// WithProject 按项目过滤（0 表示未归属项目的记录）
+func (b *QueryOptionsBuilder) WithProject(projectID int64) *QueryOptionsBuilder {
+	b.opts.ProjectID = &projectID
+	return b
+}
+
+// WithTag 按标签过滤
+func (b *QueryOptionsBuilder) WithTag(tagID int64) *QueryOptionsBuilder {
+	b.opts.TagID = &tagID
+	return b
+}
+
+// WithFavorite 按是否收藏过滤
+func (b *QueryOptionsBuilder) WithFavorite(favorite bool) *QueryOptionsBuilder {
+	b.opts.Favorite = &favorite
+	return b
+}
+
+// WithParentTraceID 只查询由 parentTraceID 重新润色产生的记录
+func (b *QueryOptionsBuilder) WithParentTraceID(parentTraceID string) *QueryOptionsBuilder {
+	b.opts.ParentTraceID = &parentTraceID
+	return b
+}
+
+// WithSearchText 全文检索（设置后 List 按相关度排序）
+func (b *QueryOptionsBuilder) WithSearchText(text string) *QueryOptionsBuilder {
+	b.opts.SearchText = &text
+	return b
+}
+
 // WithTimeRange 时间范围过滤
 func (b *QueryOptionsBuilder) WithTimeRange(start, end time.Time) *QueryOptionsBuilder {
 	b.opts.StartTime = &start
@@ -101,8 +170,11 @@ func (b *QueryOptionsBuilder) WithTimeRange(start, end time.Time) *QueryOptionsB
 	return b
 }
 
-// OrderBy 排序
+// OrderBy 排序（不允许排序的字段忽略，保持原排序）
 func (b *QueryOptionsBuilder) OrderBy(field string, desc bool) *QueryOptionsBuilder {
+	if !IsSortableField(field) {
+		return b
+	}
 	b.opts.OrderBy = field
 	b.opts.OrderDesc = desc
 	return b
@@ -19,8 +19,8 @@ type RefreshTokenRepository interface {
 	// RevokeAllByUserID 撤销用户的所有令牌（用户登出所有设备）
 	RevokeAllByUserID(ctx context.Context, userID int64) error
 
-	// DeleteExpired 删除过期的令牌（定期清理）
-	DeleteExpired(ctx context.Context) error
+	// DeleteExpired 删除过期的令牌（定期清理），返回删除数量
+	DeleteExpired(ctx context.Context) (int64, error)
 
 	// GetValidTokensByUserID 获取用户的有效令牌列表
 	GetValidTokensByUserID(ctx context.Context, userID int64) ([]*entity.RefreshToken, error)
@@ -11,8 +11,11 @@ import (
 	"time"
 
 	"paper_ai/internal/infrastructure/ai/types"
+	"paper_ai/internal/infrastructure/tracing"
 	apperrors "paper_ai/pkg/errors"
+	"paper_ai/pkg/language"
 	"paper_ai/pkg/logger"
+	"paper_ai/pkg/reqctx"
 	"go.uber.org/zap"
 )
 
@@ -33,7 +36,8 @@ func NewClient(apiKey, baseURL, model string, timeout time.Duration) *Client {
 		model:   model,
 		timeout: timeout,
 		client: &http.Client{
-			Timeout: timeout,
+			Timeout:   timeout,
+			Transport: tracing.NewHTTPTransport("claude"),
 		},
 	}
 }
@@ -44,21 +48,79 @@ func (c *Client) Polish(ctx context.Context, req *types.PolishRequest) (*types.P
 	prompt := c.buildPolishPrompt(req)
 
 	// 调用Claude API
-	claudeResp, err := c.callClaudeAPI(ctx, prompt)
+	claudeResp, err := c.callClaudeAPI(ctx, []ClaudeMessage{{Role: "user", Content: prompt}})
 	if err != nil {
-		logger.Error("failed to call claude api", zap.Error(err))
+		logger.FromContext(ctx).Error("failed to call claude api", zap.Error(err))
 		return nil, apperrors.NewAIServiceError("failed to call claude api", err)
 	}
 
 	// 构建响应
+	return c.buildResponse(claudeResp, len(req.Content)), nil
+}
+
+// Refine 实现多轮修改
+func (c *Client) Refine(ctx context.Context, req *types.RefineRequest) (*types.PolishResponse, error) {
+	claudeResp, err := c.callClaudeAPI(ctx, c.buildRefineMessages(req))
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to call claude api for refinement", zap.Error(err))
+		return nil, apperrors.NewAIServiceError("failed to call claude api", err)
+	}
+
+	return c.buildResponse(claudeResp, len(req.Current)), nil
+}
+
+// Translate 实现翻译润色
+func (c *Client) Translate(ctx context.Context, req *types.TranslateRequest) (*types.PolishResponse, error) {
+	claudeResp, err := c.callClaudeAPI(ctx, []ClaudeMessage{{Role: "user", Content: c.buildTranslatePrompt(req)}})
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to call claude api for translation", zap.Error(err))
+		return nil, apperrors.NewAIServiceError("failed to call claude api", err)
+	}
+
+	return c.buildResponse(claudeResp, len(req.Content)), nil
+}
+
+// buildResponse 构建润色响应
+func (c *Client) buildResponse(claudeResp *ClaudeAPIResponse, originalLength int) *types.PolishResponse {
 	return &types.PolishResponse{
 		PolishedContent: claudeResp.Content[0].Text,
-		OriginalLength:  len(req.Content),
+		OriginalLength:  originalLength,
 		PolishedLength:  len(claudeResp.Content[0].Text),
 		Suggestions:     c.extractSuggestions(claudeResp.Content[0].Text),
 		ProviderUsed:    "claude",
 		ModelUsed:       c.model,
-	}, nil
+		Usage: types.TokenUsage{
+			InputTokens:  claudeResp.Usage.InputTokens,
+			OutputTokens: claudeResp.Usage.OutputTokens,
+		},
+	}
+}
+
+// Ping 探测Claude API 连通性（请求模型列表，不消耗 token）
+// 只关心可达性和凭证：鉴权失败或服务端错误视为不可用，其他状态码视为可达
+func (c *Client) Ping(ctx context.Context) error {
+	url := strings.TrimRight(c.baseURL, "/") + "/v1/models"
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("x-api-key", c.apiKey)
+	httpReq.Header.Set("anthropic-version", "2023-06-01")
+	reqctx.InjectHeaders(ctx, httpReq.Header)
+
+	httpResp, err := c.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer httpResp.Body.Close()
+	_, _ = io.Copy(io.Discard, httpResp.Body)
+
+	if httpResp.StatusCode == http.StatusUnauthorized ||
+		httpResp.StatusCode == http.StatusForbidden ||
+		httpResp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("claude api unavailable: status %d", httpResp.StatusCode)
+	}
+	return nil
 }
 
 // buildPolishPrompt 构建润色prompt
@@ -75,11 +137,22 @@ func (c *Client) buildPolishPrompt(req *types.PolishRequest) string {
 		stylePrompt = "Please polish the following text to improve its clarity, coherence, and readability."
 	}
 
-	languagePrompt := ""
-	if req.Language == "zh" {
-		languagePrompt = "Please ensure the polished text is in Chinese."
-	} else {
-		languagePrompt = "Please ensure the polished text is in English."
+	languagePrompt := fmt.Sprintf("Please ensure the polished text is in %s.", languageName(req.Language))
+
+	if req.HasContext() {
+		return fmt.Sprintf(`%s %s
+Only polish the selected passage. The text before and after it is context only: do not repeat or modify it, and make sure the polished passage still reads naturally between them.
+
+Text before the passage:
+%s
+
+Selected passage:
+%s
+
+Text after the passage:
+%s
+
+Please return only the polished passage without any explanations or metadata.`, stylePrompt, languagePrompt, req.ContextBefore, req.Content, req.ContextAfter)
 	}
 
 	return fmt.Sprintf(`%s %s
@@ -90,6 +163,80 @@ Original text:
 Please return only the polished text without any explanations or metadata.`, stylePrompt, languagePrompt, req.Content)
 }
 
+// buildTranslatePrompt 构建翻译润色prompt
+func (c *Client) buildTranslatePrompt(req *types.TranslateRequest) string {
+	stylePrompt := ""
+	switch req.Style {
+	case "formal":
+		stylePrompt = "Use a formal, professional register."
+	case "concise":
+		stylePrompt = "Keep the translation concise, avoiding redundant wording."
+	default:
+		stylePrompt = "Use the precise, formal register expected in academic papers."
+	}
+
+	glossaryPrompt := ""
+	if len(req.Glossary) > 0 {
+		var b strings.Builder
+		b.WriteString("\nAlways translate the following terms exactly as given:\n")
+		for _, entry := range req.Glossary {
+			fmt.Fprintf(&b, "- %s => %s\n", entry.Source, entry.Target)
+		}
+		glossaryPrompt = b.String()
+	}
+
+	return fmt.Sprintf(`Please translate the following %s text into %s for an academic paper. %s
+Requirements:
+- Translate faithfully and completely; do not add, omit or summarize content.
+- Follow the academic writing conventions of the target language instead of translating word for word.
+- Keep citations, formulas, numbers, units and abbreviations unchanged.
+- Keep the sentence order of the source text, so that each translated sentence corresponds to a source sentence.
+%s
+Source text:
+%s
+
+Please return only the translated text without any explanations or metadata.`,
+		languageName(req.SourceLanguage), languageName(req.TargetLanguage), stylePrompt, glossaryPrompt, req.Content)
+}
+
+// languageName 语言代码对应的英文名称（未指定时为默认语言）
+func languageName(code string) string {
+	if code == "" {
+		code = language.Default()
+	}
+	return language.Name(code)
+}
+
+// buildRefineMessages 构建多轮修改对话：初始润色请求，之后每轮是润色结果（assistant）和修改指令（user）
+func (c *Client) buildRefineMessages(req *types.RefineRequest) []ClaudeMessage {
+	messages := []ClaudeMessage{{
+		Role: "user",
+		Content: c.buildPolishPrompt(&types.PolishRequest{
+			Content:  req.Original,
+			Style:    req.Style,
+			Language: req.Language,
+		}),
+	}}
+	for _, turn := range req.History {
+		messages = append(messages,
+			ClaudeMessage{Role: "assistant", Content: turn.Polished},
+			ClaudeMessage{Role: "user", Content: c.buildRefinePrompt(turn.Instruction)})
+	}
+	return append(messages,
+		ClaudeMessage{Role: "assistant", Content: req.Current},
+		ClaudeMessage{Role: "user", Content: c.buildRefinePrompt(req.Instruction)})
+}
+
+// buildRefinePrompt 构建修改指令prompt
+func (c *Client) buildRefinePrompt(instruction string) string {
+	return fmt.Sprintf(`Please revise your polished text according to the following instruction. Keep everything else unchanged unless the instruction requires otherwise.
+
+Instruction:
+%s
+
+Please return only the revised text without any explanations or metadata.`, instruction)
+}
+
 // extractSuggestions 提取改进建议（简化版实现）
 func (c *Client) extractSuggestions(polishedText string) []string {
 	// 这里可以根据实际需求实现更复杂的建议提取逻辑
@@ -117,6 +264,13 @@ type ClaudeAPIResponse struct {
 	Role    string               `json:"role"`
 	Content []ClaudeContentBlock `json:"content"`
 	Model   string               `json:"model"`
+	Usage   ClaudeUsage          `json:"usage"`
+}
+
+// ClaudeUsage Claude使用统计
+type ClaudeUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
 }
 
 // ClaudeContentBlock Claude内容块
@@ -135,17 +289,12 @@ type ClaudeErrorResponse struct {
 }
 
 // callClaudeAPI 调用Claude API
-func (c *Client) callClaudeAPI(ctx context.Context, prompt string) (*ClaudeAPIResponse, error) {
+func (c *Client) callClaudeAPI(ctx context.Context, messages []ClaudeMessage) (*ClaudeAPIResponse, error) {
 	// 构建请求体
 	reqBody := ClaudeAPIRequest{
 		Model:     c.model,
 		MaxTokens: 4096,
-		Messages: []ClaudeMessage{
-			{
-				Role:    "user",
-				Content: prompt,
-			},
-		},
+		Messages:  messages,
 	}
 
 	jsonData, err := json.Marshal(reqBody)
@@ -164,6 +313,7 @@ func (c *Client) callClaudeAPI(ctx context.Context, prompt string) (*ClaudeAPIRe
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("x-api-key", c.apiKey)
 	httpReq.Header.Set("anthropic-version", "2023-06-01")
+	reqctx.InjectHeaders(ctx, httpReq.Header) // 传递请求ID，便于与提供商侧日志关联
 
 	// 发送请求
 	httpResp, err := c.client.Do(httpReq)
@@ -11,29 +11,35 @@ import (
 	"time"
 
 	"paper_ai/internal/infrastructure/ai/types"
+	"paper_ai/internal/infrastructure/tracing"
 	apperrors "paper_ai/pkg/errors"
+	"paper_ai/pkg/language"
 	"paper_ai/pkg/logger"
+	"paper_ai/pkg/reqctx"
 	"go.uber.org/zap"
 )
 
 // Client 豆包客户端
 type Client struct {
-	apiKey  string
-	baseURL string
-	model   string
-	timeout time.Duration
-	client  *http.Client
+	apiKey   string
+	baseURL  string
+	model    string
+	timeout  time.Duration
+	logprobs bool // 是否请求 token 对数概率
+	client   *http.Client
 }
 
 // NewClient 创建豆包客户端
-func NewClient(apiKey, baseURL, model string, timeout time.Duration) *Client {
+func NewClient(apiKey, baseURL, model string, timeout time.Duration, logprobs bool) *Client {
 	return &Client{
-		apiKey:  apiKey,
-		baseURL: baseURL,
-		model:   model,
-		timeout: timeout,
+		apiKey:   apiKey,
+		baseURL:  baseURL,
+		model:    model,
+		timeout:  timeout,
+		logprobs: logprobs,
 		client: &http.Client{
-			Timeout: timeout,
+			Timeout:   timeout,
+			Transport: tracing.NewHTTPTransport("doubao"),
 		},
 	}
 }
@@ -44,21 +50,95 @@ func (c *Client) Polish(ctx context.Context, req *types.PolishRequest) (*types.P
 	prompt := c.buildPolishPrompt(req)
 
 	// 调用豆包API
-	doubaoResp, err := c.callDoubaoAPI(ctx, prompt)
+	doubaoResp, err := c.callDoubaoAPI(ctx, []DoubaoMessage{{Role: "user", Content: prompt}})
 	if err != nil {
-		logger.Error("failed to call doubao api", zap.Error(err))
+		logger.FromContext(ctx).Error("failed to call doubao api", zap.Error(err))
 		return nil, apperrors.NewAIServiceError("failed to call doubao api", err)
 	}
 
 	// 构建响应
+	return c.buildResponse(doubaoResp, len(req.Content)), nil
+}
+
+// Refine 实现多轮修改
+func (c *Client) Refine(ctx context.Context, req *types.RefineRequest) (*types.PolishResponse, error) {
+	doubaoResp, err := c.callDoubaoAPI(ctx, c.buildRefineMessages(req))
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to call doubao api for refinement", zap.Error(err))
+		return nil, apperrors.NewAIServiceError("failed to call doubao api", err)
+	}
+
+	return c.buildResponse(doubaoResp, len(req.Current)), nil
+}
+
+// Translate 实现翻译润色
+func (c *Client) Translate(ctx context.Context, req *types.TranslateRequest) (*types.PolishResponse, error) {
+	doubaoResp, err := c.callDoubaoAPI(ctx, []DoubaoMessage{{Role: "user", Content: c.buildTranslatePrompt(req)}})
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to call doubao api for translation", zap.Error(err))
+		return nil, apperrors.NewAIServiceError("failed to call doubao api", err)
+	}
+
+	return c.buildResponse(doubaoResp, len(req.Content)), nil
+}
+
+// buildResponse 构建润色响应
+func (c *Client) buildResponse(doubaoResp *DoubaoAPIResponse, originalLength int) *types.PolishResponse {
 	return &types.PolishResponse{
 		PolishedContent: doubaoResp.Choices[0].Message.Content,
-		OriginalLength:  len(req.Content),
+		OriginalLength:  originalLength,
 		PolishedLength:  len(doubaoResp.Choices[0].Message.Content),
 		Suggestions:     c.extractSuggestions(doubaoResp.Choices[0].Message.Content),
 		ProviderUsed:    "doubao",
 		ModelUsed:       c.model,
-	}, nil
+		TokenLogProbs:   c.extractTokenLogProbs(doubaoResp.Choices[0].Logprobs),
+		Usage: types.TokenUsage{
+			InputTokens:  doubaoResp.Usage.PromptTokens,
+			OutputTokens: doubaoResp.Usage.CompletionTokens,
+		},
+	}
+}
+
+// Ping 探测豆包 API 连通性（请求模型列表，不消耗 token）
+// 只关心可达性和凭证：鉴权失败或服务端错误视为不可用，其他状态码视为可达
+func (c *Client) Ping(ctx context.Context) error {
+	url := strings.TrimRight(c.baseURL, "/") + "/models"
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
+	reqctx.InjectHeaders(ctx, httpReq.Header)
+
+	httpResp, err := c.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer httpResp.Body.Close()
+	_, _ = io.Copy(io.Discard, httpResp.Body)
+
+	if httpResp.StatusCode == http.StatusUnauthorized ||
+		httpResp.StatusCode == http.StatusForbidden ||
+		httpResp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("doubao api unavailable: status %d", httpResp.StatusCode)
+	}
+	return nil
+}
+
+// extractTokenLogProbs 提取 token 对数概率
+func (c *Client) extractTokenLogProbs(logprobs *DoubaoLogprobs) []types.TokenLogProb {
+	if logprobs == nil || len(logprobs.Content) == 0 {
+		return nil
+	}
+
+	tokens := make([]types.TokenLogProb, 0, len(logprobs.Content))
+	for _, item := range logprobs.Content {
+		tokens = append(tokens, types.TokenLogProb{
+			Token:   item.Token,
+			LogProb: item.Logprob,
+		})
+	}
+	return tokens
 }
 
 // buildPolishPrompt 构建润色prompt
@@ -75,11 +155,22 @@ func (c *Client) buildPolishPrompt(req *types.PolishRequest) string {
 		stylePrompt = "请润色以下文本以提高其清晰度、连贯性和可读性。"
 	}
 
-	languagePrompt := ""
-	if req.Language == "zh" {
-		languagePrompt = "请确保润色后的文本为中文。"
-	} else {
-		languagePrompt = "请确保润色后的文本为英文。"
+	languagePrompt := fmt.Sprintf("请确保润色后的文本为%s。", languageName(req.Language))
+
+	if req.HasContext() {
+		return fmt.Sprintf(`%s %s
+只润色选中的片段。片段前后的文本仅作为上下文：不要重复或修改它们，并确保润色后的片段与前后文衔接自然。
+
+片段之前的文本：
+%s
+
+选中的片段：
+%s
+
+片段之后的文本：
+%s
+
+请只返回润色后的片段，不需要任何解释或元数据。`, stylePrompt, languagePrompt, req.ContextBefore, req.Content, req.ContextAfter)
 	}
 
 	return fmt.Sprintf(`%s %s
@@ -90,6 +181,80 @@ func (c *Client) buildPolishPrompt(req *types.PolishRequest) string {
 请只返回润色后的文本，不需要任何解释或元数据。`, stylePrompt, languagePrompt, req.Content)
 }
 
+// buildTranslatePrompt 构建翻译润色prompt
+func (c *Client) buildTranslatePrompt(req *types.TranslateRequest) string {
+	stylePrompt := ""
+	switch req.Style {
+	case "formal":
+		stylePrompt = "译文使用正式、专业的语体。"
+	case "concise":
+		stylePrompt = "译文保持简洁，避免冗余的表达。"
+	default:
+		stylePrompt = "译文使用学术论文要求的准确、正式的语体。"
+	}
+
+	glossaryPrompt := ""
+	if len(req.Glossary) > 0 {
+		var b strings.Builder
+		b.WriteString("\n以下术语必须严格使用给定的译法：\n")
+		for _, entry := range req.Glossary {
+			fmt.Fprintf(&b, "- %s => %s\n", entry.Source, entry.Target)
+		}
+		glossaryPrompt = b.String()
+	}
+
+	return fmt.Sprintf(`请将以下%s文本翻译为用于学术论文的%s。%s
+要求：
+- 忠实、完整地翻译，不要增加、删减或概括内容。
+- 遵循目标语言的学术写作习惯，不要逐字硬译。
+- 引用、公式、数字、单位和缩写保持不变。
+- 保持原文的句子顺序，使每个译文句子对应一个原文句子。
+%s
+原始文本：
+%s
+
+请只返回译文，不需要任何解释或元数据。`,
+		languageName(req.SourceLanguage), languageName(req.TargetLanguage), stylePrompt, glossaryPrompt, req.Content)
+}
+
+// languageName 语言代码对应的中文名称（未指定时为默认语言）
+func languageName(code string) string {
+	if code == "" {
+		code = language.Default()
+	}
+	return language.ChineseName(code)
+}
+
+// buildRefineMessages 构建多轮修改对话：初始润色请求，之后每轮是润色结果（assistant）和修改指令（user）
+func (c *Client) buildRefineMessages(req *types.RefineRequest) []DoubaoMessage {
+	messages := []DoubaoMessage{{
+		Role: "user",
+		Content: c.buildPolishPrompt(&types.PolishRequest{
+			Content:  req.Original,
+			Style:    req.Style,
+			Language: req.Language,
+		}),
+	}}
+	for _, turn := range req.History {
+		messages = append(messages,
+			DoubaoMessage{Role: "assistant", Content: turn.Polished},
+			DoubaoMessage{Role: "user", Content: c.buildRefinePrompt(turn.Instruction)})
+	}
+	return append(messages,
+		DoubaoMessage{Role: "assistant", Content: req.Current},
+		DoubaoMessage{Role: "user", Content: c.buildRefinePrompt(req.Instruction)})
+}
+
+// buildRefinePrompt 构建修改指令prompt
+func (c *Client) buildRefinePrompt(instruction string) string {
+	return fmt.Sprintf(`请按照以下要求修改你润色后的文本，除要求涉及的部分外保持其余内容不变。
+
+修改要求：
+%s
+
+请只返回修改后的文本，不需要任何解释或元数据。`, instruction)
+}
+
 // extractSuggestions 提取改进建议（简化版实现）
 func (c *Client) extractSuggestions(polishedText string) []string {
 	// 这里可以根据实际需求实现更复杂的建议提取逻辑
@@ -101,6 +266,7 @@ func (c *Client) extractSuggestions(polishedText string) []string {
 type DoubaoAPIRequest struct {
 	Model    string           `json:"model"`
 	Messages []DoubaoMessage  `json:"messages"`
+	Logprobs bool             `json:"logprobs,omitempty"` // 是否返回 token 对数概率
 }
 
 // DoubaoMessage 豆包消息结构
@@ -122,8 +288,20 @@ type DoubaoAPIResponse struct {
 // DoubaoChoice 豆包选择结构
 type DoubaoChoice struct {
 	Index        int           `json:"index"`
-	Message      DoubaoMessage `json:"message"`
-	FinishReason string        `json:"finish_reason"`
+	Message      DoubaoMessage   `json:"message"`
+	FinishReason string          `json:"finish_reason"`
+	Logprobs     *DoubaoLogprobs `json:"logprobs,omitempty"`
+}
+
+// DoubaoLogprobs 豆包 token 对数概率
+type DoubaoLogprobs struct {
+	Content []DoubaoTokenLogprob `json:"content"`
+}
+
+// DoubaoTokenLogprob 单个 token 的对数概率
+type DoubaoTokenLogprob struct {
+	Token   string  `json:"token"`
+	Logprob float64 `json:"logprob"`
 }
 
 // DoubaoUsage 豆包使用统计
@@ -143,16 +321,12 @@ type DoubaoErrorResponse struct {
 }
 
 // callDoubaoAPI 调用豆包API
-func (c *Client) callDoubaoAPI(ctx context.Context, prompt string) (*DoubaoAPIResponse, error) {
+func (c *Client) callDoubaoAPI(ctx context.Context, messages []DoubaoMessage) (*DoubaoAPIResponse, error) {
 	// 构建请求体
 	reqBody := DoubaoAPIRequest{
-		Model: c.model,
-		Messages: []DoubaoMessage{
-			{
-				Role:    "user",
-				Content: prompt,
-			},
-		},
+		Model:    c.model,
+		Messages: messages,
+		Logprobs: c.logprobs,
 	}
 
 	jsonData, err := json.Marshal(reqBody)
@@ -170,6 +344,7 @@ func (c *Client) callDoubaoAPI(ctx context.Context, prompt string) (*DoubaoAPIRe
 	// 设置请求头
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
+	reqctx.InjectHeaders(ctx, httpReq.Header) // 传递请求ID，便于与提供商侧日志关联
 
 	// 发送请求
 	httpResp, err := c.client.Do(httpReq)
@@ -32,38 +32,44 @@ func GetFactory() *ProviderFactory {
 }
 
 // InitProviders 初始化所有配置的提供商
+// 配置热更新时再次调用：先构建完整的新提供商集合再整体替换，
+// 已取得旧客户端的请求不受影响；构建失败时保留原有提供商
 func (f *ProviderFactory) InitProviders(cfg *config.Config) error {
-	f.mu.Lock()
-	defer f.mu.Unlock()
+	providers := make(map[string]AIProvider, len(cfg.AI.Providers))
 
 	// 初始化所有配置的提供商
 	for name, providerCfg := range cfg.AI.Providers {
 		switch name {
 		case "claude":
 			client := claude.NewClient(
-				providerCfg.APIKey,
+				providerCfg.APIKey.Value(),
 				providerCfg.BaseURL,
 				providerCfg.Model,
 				providerCfg.Timeout,
 			)
-			f.providers[name] = client
+			providers[name] = client
 		case "doubao":
 			client := doubao.NewClient(
-				providerCfg.APIKey,
+				providerCfg.APIKey.Value(),
 				providerCfg.BaseURL,
 				providerCfg.Model,
 				providerCfg.Timeout,
+				providerCfg.Logprobs,
 			)
-			f.providers[name] = client
+			providers[name] = client
 		// 未来可以在这里添加其他提供商
 		// case "openai":
 		//     client := openai.NewClient(...)
-		//     f.providers[name] = client
+		//     providers[name] = client
 		default:
 			return fmt.Errorf("unsupported AI provider: %s", name)
 		}
 	}
 
+	f.mu.Lock()
+	f.providers = providers
+	f.mu.Unlock()
+
 	return nil
 }
 
@@ -11,7 +11,19 @@ type AIProvider interface {
 	// Polish 段落润色
 	Polish(ctx context.Context, req *types.PolishRequest) (*types.PolishResponse, error)
 
+	// Refine 多轮修改：把原文、之前的润色结果和修改指令作为多轮对话发送，返回修改后的文本
+	Refine(ctx context.Context, req *types.RefineRequest) (*types.PolishResponse, error)
+
+	// Translate 翻译润色：把源语言文本翻译为目标语言的学术文本，遵循给定的术语译法
+	Translate(ctx context.Context, req *types.TranslateRequest) (*types.PolishResponse, error)
+
 	// 预留未来扩展的接口
 	// GenerateCode(ctx context.Context, req *CodeGenRequest) (*CodeGenResponse, error)
 	// AnalyzeData(ctx context.Context, req *DataAnalysisRequest) (*DataAnalysisResponse, error)
 }
+
+// HealthChecker 可选接口：支持轻量连通性探测的提供商（不消耗 token）
+type HealthChecker interface {
+	// Ping 探测提供商 API 是否可达且凭证有效
+	Ping(ctx context.Context) error
+}
@@ -4,7 +4,47 @@ package types
 type PolishRequest struct {
 	Content  string `json:"content"`  // 原始文本
 	Style    string `json:"style"`    // 风格: academic/formal/concise
-	Language string `json:"language"` // 语言: en/zh
+	Language string `json:"language"` // 语言代码（en / zh / de 等）
+
+	// 选区润色：Content 为选中的片段，前后文只作为上下文，不修改也不返回
+	ContextBefore string `json:"context_before,omitempty"`
+	ContextAfter  string `json:"context_after,omitempty"`
+}
+
+// HasContext 是否为选区润色（带前后文）
+func (r *PolishRequest) HasContext() bool {
+	return r.ContextBefore != "" || r.ContextAfter != ""
+}
+
+// TranslateRequest 翻译润色请求：把源语言文本翻译为目标语言的学术文本
+type TranslateRequest struct {
+	Content        string          // 源语言文本
+	SourceLanguage string          // 源语言代码
+	TargetLanguage string          // 目标语言代码
+	Style          string          // 风格: academic/formal/concise
+	Glossary       []GlossaryEntry // 必须使用的术语译法（只包含原文中出现的术语）
+}
+
+// GlossaryEntry 术语译法
+type GlossaryEntry struct {
+	Source string
+	Target string
+}
+
+// RefineRequest 多轮修改请求：在初始润色的对话基础上，按用户的后续指令修改当前润色文本
+type RefineRequest struct {
+	Original    string       // 原始文本（对话第一轮的润色请求）
+	Style       string       // 风格: academic/formal/concise
+	Language    string       // 语言代码（en / zh / de 等）
+	History     []RefineTurn // 之前的修改轮次（按时间顺序）
+	Current     string       // 当前润色文本（用户看到的最新结果）
+	Instruction string       // 本轮修改指令
+}
+
+// RefineTurn 一轮修改：当时的润色文本和用户针对它提出的指令
+type RefineTurn struct {
+	Polished    string
+	Instruction string
 }
 
 // PolishResponse 润色响应
@@ -16,4 +56,31 @@ type PolishResponse struct {
 	Suggestions     []string `json:"suggestions"`      // 改进建议
 	ProviderUsed    string   `json:"provider_used"`    // 使用的提供商
 	ModelUsed       string   `json:"model_used"`       // 使用的模型
+
+	// Selection 选区润色时，替换后的片段在 PolishedContent 中的位置（基于 rune）
+	Selection *TextSpan `json:"selection,omitempty"`
+
+	// TokenLogProbs 模型返回的 token 对数概率（仅部分提供商支持，不返回给前端）
+	TokenLogProbs []TokenLogProb `json:"-"`
+
+	// Usage token 用量（用于监控统计，不返回给前端）
+	Usage TokenUsage `json:"-"`
+}
+
+// TextSpan 文本片段位置（基于 rune，End 不包含）
+type TextSpan struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// TokenUsage token 用量
+type TokenUsage struct {
+	InputTokens  int
+	OutputTokens int
+}
+
+// TokenLogProb 单个 token 的对数概率
+type TokenLogProb struct {
+	Token   string
+	LogProb float64
 }
@@ -0,0 +1,162 @@
+package comparison
+
+import (
+	"math"
+	"unicode"
+)
+
+// BilingualPair 双语对照中互相对应的一组句子（基于 rune，End 不包含）
+// 一个原文句子可能被译为多句，或多个原文句子合译为一句；某一侧为空区间表示该句没有对应
+type BilingualPair struct {
+	SourceStart int
+	SourceEnd   int
+	TargetStart int
+	TargetEnd   int
+}
+
+// bead 一次对齐可以消耗的句子数及其附加代价（越偏离一一对应代价越高）
+type bead struct {
+	source, target int
+	penalty        float64
+}
+
+var beads = []bead{
+	{1, 1, 0},
+	{1, 2, 2},
+	{2, 1, 2},
+	{2, 2, 4},
+	{1, 0, 6},
+	{0, 1, 6},
+}
+
+// AlignBilingual 按句对齐原文和译文
+// 基于句子长度的动态规划（Gale-Church 思路）：句子长度按两段文本的总长度之比换算后比较，
+// 不依赖词典，中英文之间也适用；译文保持原文句序时绝大多数句子一一对应
+func AlignBilingual(source, target string) []BilingualPair {
+	sourceSentences := nonBlankSentences(source)
+	targetSentences := nonBlankSentences(target)
+	if len(sourceSentences) == 0 && len(targetSentences) == 0 {
+		return nil
+	}
+
+	sourceLengths := sentenceLengths(source, sourceSentences)
+	targetLengths := sentenceLengths(target, targetSentences)
+	ratio := 1.0
+	if total := sum(sourceLengths); total > 0 {
+		ratio = float64(sum(targetLengths)) / float64(total)
+	}
+
+	n, m := len(sourceSentences), len(targetSentences)
+	cost := make([][]float64, n+1)
+	choice := make([][]int, n+1)
+	for i := range cost {
+		cost[i] = make([]float64, m+1)
+		choice[i] = make([]int, m+1)
+		for j := range cost[i] {
+			cost[i][j] = math.Inf(1)
+		}
+	}
+	cost[0][0] = 0
+
+	for i := 0; i <= n; i++ {
+		for j := 0; j <= m; j++ {
+			if i == 0 && j == 0 {
+				continue
+			}
+			for k, b := range beads {
+				pi, pj := i-b.source, j-b.target
+				if pi < 0 || pj < 0 || math.IsInf(cost[pi][pj], 1) {
+					continue
+				}
+				c := cost[pi][pj] + b.penalty + lengthCost(sum(sourceLengths[pi:i]), sum(targetLengths[pj:j]), ratio)
+				if c < cost[i][j] {
+					cost[i][j] = c
+					choice[i][j] = k
+				}
+			}
+		}
+	}
+
+	// 回溯得到对齐结果
+	pairs := make([]BilingualPair, 0, n)
+	for i, j := n, m; i > 0 || j > 0; {
+		b := beads[choice[i][j]]
+		pi, pj := i-b.source, j-b.target
+		pairs = append(pairs, BilingualPair{
+			SourceStart: spanStart(sourceSentences, pi, i, source),
+			SourceEnd:   spanEnd(sourceSentences, pi, i, source),
+			TargetStart: spanStart(targetSentences, pj, j, target),
+			TargetEnd:   spanEnd(targetSentences, pj, j, target),
+		})
+		i, j = pi, pj
+	}
+	for l, r := 0, len(pairs)-1; l < r; l, r = l+1, r-1 {
+		pairs[l], pairs[r] = pairs[r], pairs[l]
+	}
+	return pairs
+}
+
+// lengthCost 一组对应句子的长度差异代价：实际译文长度偏离按比例换算的期望长度越多代价越高
+func lengthCost(sourceLength, targetLength int, ratio float64) float64 {
+	expected := float64(sourceLength) * ratio
+	return math.Abs(float64(targetLength)-expected) / math.Sqrt((expected+float64(targetLength))/2+1)
+}
+
+// nonBlankSentences 切分句子，去掉只包含空白的句子（例如空行）
+func nonBlankSentences(text string) []interval {
+	runes := []rune(text)
+	sentences := make([]interval, 0)
+	for _, sentence := range SplitSentences(text) {
+		for i := sentence.start; i < sentence.end; i++ {
+			if !unicode.IsSpace(runes[i]) {
+				sentences = append(sentences, sentence)
+				break
+			}
+		}
+	}
+	return sentences
+}
+
+// sentenceLengths 每个句子的非空白字符数
+func sentenceLengths(text string, sentences []interval) []int {
+	runes := []rune(text)
+	lengths := make([]int, len(sentences))
+	for i, sentence := range sentences {
+		for _, r := range runes[sentence.start:sentence.end] {
+			if !unicode.IsSpace(r) {
+				lengths[i]++
+			}
+		}
+	}
+	return lengths
+}
+
+// spanStart 第 from 到 to 个句子组成的区间起点；没有句子时为前一句的终点（空区间）
+func spanStart(sentences []interval, from, to int, text string) int {
+	if from < to {
+		return sentences[from].start
+	}
+	return spanEnd(sentences, from, to, text)
+}
+
+// spanEnd 第 from 到 to 个句子组成的区间终点
+func spanEnd(sentences []interval, from, to int, text string) int {
+	if from < to {
+		return sentences[to-1].end
+	}
+	if to > 0 {
+		return sentences[to-1].end
+	}
+	if len(sentences) > 0 {
+		return sentences[0].start
+	}
+	return len([]rune(text))
+}
+
+func sum(values []int) int {
+	total := 0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
@@ -0,0 +1,85 @@
+package comparison
+
+import (
+	"strings"
+	"testing"
+)
+
+// pairTexts 把对齐结果还原为（原文, 译文）文本对
+func pairTexts(source, target string, pairs []BilingualPair) [][2]string {
+	sourceRunes, targetRunes := []rune(source), []rune(target)
+	texts := make([][2]string, len(pairs))
+	for i, pair := range pairs {
+		texts[i] = [2]string{
+			strings.TrimSpace(string(sourceRunes[pair.SourceStart:pair.SourceEnd])),
+			strings.TrimSpace(string(targetRunes[pair.TargetStart:pair.TargetEnd])),
+		}
+	}
+	return texts
+}
+
+func TestAlignBilingual(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		target string
+		want   [][2]string
+	}{
+		{
+			name:   "一一对应",
+			source: "本文提出了一种新的方法。实验结果表明该方法有效。",
+			target: "This paper proposes a novel method. Experimental results demonstrate that the method is effective.",
+			want: [][2]string{
+				{"本文提出了一种新的方法。", "This paper proposes a novel method."},
+				{"实验结果表明该方法有效。", "Experimental results demonstrate that the method is effective."},
+			},
+		},
+		{
+			name:   "一句译为两句",
+			source: "我们收集了三个公开数据集，并在所有数据集上与五种基线方法进行了比较，结果显示我们的方法在各项指标上均优于基线。未来工作将扩展到更多领域。",
+			target: "We collected three public datasets and compared our approach with five baseline methods on all of them. The results show that our method outperforms the baselines on every metric. Future work will extend it to more domains.",
+			want: [][2]string{
+				{"我们收集了三个公开数据集，并在所有数据集上与五种基线方法进行了比较，结果显示我们的方法在各项指标上均优于基线。", "We collected three public datasets and compared our approach with five baseline methods on all of them. The results show that our method outperforms the baselines on every metric."},
+				{"未来工作将扩展到更多领域。", "Future work will extend it to more domains."},
+			},
+		},
+		{
+			name:   "译文为空",
+			source: "只有原文。",
+			target: "",
+			want:   [][2]string{{"只有原文。", ""}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pairTexts(tt.source, tt.target, AlignBilingual(tt.source, tt.target))
+			if len(got) != len(tt.want) {
+				t.Fatalf("AlignBilingual() returned %d pairs %q, want %d", len(got), got, len(tt.want))
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("pair %d = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAlignBilingual_CoversBothTexts(t *testing.T) {
+	source := "第一句。\n\n第二句很长，包含了许多细节和解释。第三句。"
+	target := "First sentence.\n\nThe second sentence is long and contains many details and explanations. Third."
+
+	pairs := AlignBilingual(source, target)
+	if len(pairs) == 0 {
+		t.Fatal("AlignBilingual() returned no pairs")
+	}
+	for i := 1; i < len(pairs); i++ {
+		if pairs[i].SourceStart < pairs[i-1].SourceEnd || pairs[i].TargetStart < pairs[i-1].TargetEnd {
+			t.Errorf("pairs %d and %d overlap: %+v %+v", i-1, i, pairs[i-1], pairs[i])
+		}
+	}
+	if last := pairs[len(pairs)-1]; last.SourceEnd != len([]rune(source)) || last.TargetEnd != len([]rune(target)) {
+		t.Errorf("last pair %+v does not reach the end of both texts", last)
+	}
+}
@@ -37,11 +37,11 @@ func (c *ChangeClassifier) Classify(originalText, polishedText string) model.Cha
 
 // isSynonymReplacement 检测是否为同义词替换
 func (c *ChangeClassifier) isSynonymReplacement(orig, pol string) bool {
-	origWords := strings.Fields(orig)
-	polWords := strings.Fields(pol)
+	origWords := CountWords(orig)
+	polWords := CountWords(pol)
 
 	// 词数相同，且都是短语（1-3个词）
-	if len(origWords) == len(polWords) && len(origWords) <= 3 {
+	if origWords == polWords && origWords <= 3 {
 		return true
 	}
 	return false
@@ -79,8 +79,8 @@ func (c *ChangeClassifier) isGrammarFix(orig, pol string) bool {
 
 // isStructuralChange 检测是否为结构调整
 func (c *ChangeClassifier) isStructuralChange(orig, pol string) bool {
-	origWords := len(strings.Fields(orig))
-	polWords := len(strings.Fields(pol))
+	origWords := CountWords(orig)
+	polWords := CountWords(pol)
 
 	// 避免除以零
 	if origWords == 0 {
@@ -0,0 +1,253 @@
+package comparison
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"paper_ai/internal/domain/entity"
+	"paper_ai/internal/domain/model"
+)
+
+// 各信号在加权平均中的权重
+const (
+	priorWeight       = 1.0 // 类型先验 + 编辑距离
+	agreementWeight   = 1.5 // 多版本一致性
+	probabilityWeight = 1.0 // 模型 token 概率
+	historyWeight     = 1.5 // 历史接受率（按样本量折算）
+
+	// 历史样本达到该数量时，历史接受率获得全部权重
+	historyFullWeightSamples = 20
+	// 历史样本少于该数量时，不参与计算
+	historyMinSamples = 3
+
+	minConfidence = 0.05
+	maxConfidence = 0.99
+)
+
+// ConfidenceSignals 计算置信度可用的外部信号
+// 指针为 nil 表示该信号不可用（例如单版本润色没有一致性信号）
+type ConfidenceSignals struct {
+	VersionAgreement  *float64 // 多版本中做出相同修改的比例 0-1
+	TokenProbability  *float64 // 模型生成该片段的平均 token 概率 0-1
+	HistoricalAccept  *float64 // 相似修改的历史接受率 0-1
+	HistoricalSamples int64    // 历史接受率的样本数
+}
+
+// ConfidenceCalculator 置信度计算器
+type ConfidenceCalculator struct{}
+
+// NewConfidenceCalculator 创建置信度计算器
+func NewConfidenceCalculator() *ConfidenceCalculator {
+	return &ConfidenceCalculator{}
+}
+
+// Prior 根据修改类型和编辑幅度计算基础置信度
+// 改动幅度越小，越可能是确定性的修正
+func (c *ConfidenceCalculator) Prior(changeType model.ChangeType, original, polished string) float64 {
+	var base float64
+	switch changeType {
+	case model.ChangeTypeVocabulary:
+		base = 0.90
+	case model.ChangeTypeGrammar:
+		base = 0.85
+	case model.ChangeTypeStructure:
+		base = 0.75
+	default:
+		base = 0.80
+	}
+
+	// 编辑距离调整：[-0.05, +0.05]
+	adjust := 0.05 - 0.1*EditDistanceRatio(original, polished)
+	return clampConfidence(base + adjust)
+}
+
+// Calculate 综合所有可用信号计算置信度，并返回各因子明细
+func (c *ConfidenceCalculator) Calculate(changeType model.ChangeType, original, polished string, signals ConfidenceSignals) (float64, *model.ConfidenceFactors) {
+	prior := c.Prior(changeType, original, polished)
+	factors := &model.ConfidenceFactors{
+		Prior:             round2(prior),
+		EditDistanceRatio: round2(EditDistanceRatio(original, polished)),
+	}
+
+	weightedSum := prior * priorWeight
+	totalWeight := priorWeight
+
+	if signals.VersionAgreement != nil {
+		agreement := *signals.VersionAgreement
+		factors.VersionAgreement = floatPtr(round2(agreement))
+		weightedSum += agreement * agreementWeight
+		totalWeight += agreementWeight
+	}
+
+	if signals.TokenProbability != nil {
+		probability := *signals.TokenProbability
+		factors.TokenProbability = floatPtr(round2(probability))
+		weightedSum += probability * probabilityWeight
+		totalWeight += probabilityWeight
+	}
+
+	if signals.HistoricalAccept != nil && signals.HistoricalSamples >= historyMinSamples {
+		rate := *signals.HistoricalAccept
+		factors.HistoricalAcceptRate = floatPtr(round2(rate))
+		factors.HistoricalSamples = signals.HistoricalSamples
+
+		weight := historyWeight * math.Min(float64(signals.HistoricalSamples)/historyFullWeightSamples, 1)
+		weightedSum += rate * weight
+		totalWeight += weight
+	}
+
+	return round2(clampConfidence(weightedSum / totalWeight)), factors
+}
+
+// EditDistanceRatio 计算归一化编辑距离（基于 rune 的 Levenshtein 距离 / 较长文本长度）
+// 返回 0 表示完全相同，1 表示完全不同
+func EditDistanceRatio(a, b string) float64 {
+	ra := []rune(a)
+	rb := []rune(b)
+
+	maxLen := len(ra)
+	if len(rb) > maxLen {
+		maxLen = len(rb)
+	}
+	if maxLen == 0 {
+		return 0
+	}
+
+	return float64(levenshtein(ra, rb)) / float64(maxLen)
+}
+
+// levenshtein 计算两个 rune 序列的编辑距离（滚动数组实现）
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
+
+// SpanProbability 计算润色后文本中 [start, end) 区间所覆盖 token 的平均概率
+// tokens 拼接后必须与润色文本一致，否则无法对齐，返回 false
+func SpanProbability(tokens []entity.TokenLogProb, polishedText string, start, end int) (float64, bool) {
+	if len(tokens) == 0 || start >= end {
+		return 0, false
+	}
+
+	var builder strings.Builder
+	for _, token := range tokens {
+		builder.WriteString(token.Token)
+	}
+	if builder.String() != polishedText {
+		return 0, false
+	}
+
+	pos := 0
+	sum := 0.0
+	count := 0
+	for _, token := range tokens {
+		tokenStart := pos
+		pos += utf8.RuneCountInString(token.Token)
+
+		// token 与目标区间有重叠
+		if pos > start && tokenStart < end {
+			sum += token.LogProb
+			count++
+		}
+		if pos >= end {
+			break
+		}
+	}
+
+	if count == 0 {
+		return 0, false
+	}
+
+	return math.Exp(sum / float64(count)), true
+}
+
+// ChangeKey 修改在原文中的对齐键（原文区间 + 替换文本）
+// 不同版本对同一原文区间做出相同替换时，键相同
+func ChangeKey(originalStart, originalEnd int, polishedText string) string {
+	return fmt.Sprintf("%d:%d:%s", originalStart, originalEnd, polishedText)
+}
+
+// VersionAgreement 计算每个修改在多个版本中出现的比例
+// target 为待评估的修改列表，versions 为参与比较的所有版本的修改列表
+// 版本数少于 2 时没有比较意义，返回 nil
+func VersionAgreement(target []PositionInfo, versions [][]ChangeInfo) []*float64 {
+	if len(versions) < 2 {
+		return nil
+	}
+
+	counts := make(map[string]int)
+	for _, changes := range versions {
+		seen := make(map[string]bool)
+		for _, change := range changes {
+			key := ChangeKey(change.OriginalStart, change.OriginalEnd, change.PolishedText)
+			if !seen[key] {
+				seen[key] = true
+				counts[key]++
+			}
+		}
+	}
+
+	result := make([]*float64, len(target))
+	for i, pos := range target {
+		count := counts[ChangeKey(pos.OriginalStart, pos.OriginalEnd, pos.PolishedText)]
+		ratio := float64(count) / float64(len(versions))
+		result[i] = &ratio
+	}
+
+	return result
+}
+
+// ChangeSignature 生成修改的相似性签名（用于统计历史接受率）
+// 忽略大小写和首尾空白，使用哈希避免在统计表中存储原文
+func ChangeSignature(changeType model.ChangeType, original, polished string) string {
+	normalized := string(changeType) + "\x00" +
+		strings.ToLower(strings.TrimSpace(original)) + "\x00" +
+		strings.ToLower(strings.TrimSpace(polished))
+	sum := sha1.Sum([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// clampConfidence 将置信度限制在合理范围内
+func clampConfidence(v float64) float64 {
+	return math.Max(minConfidence, math.Min(maxConfidence, v))
+}
+
+// round2 保留两位小数
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
@@ -0,0 +1,123 @@
+package comparison
+
+import (
+	"math"
+	"testing"
+
+	"paper_ai/internal/domain/entity"
+	"paper_ai/internal/domain/model"
+)
+
+func TestEditDistanceRatio(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want float64
+	}{
+		{name: "完全相同", a: "method", b: "method", want: 0},
+		{name: "完全不同", a: "abc", b: "xyz", want: 1},
+		{name: "单字符替换", a: "a apple", b: "an apple", want: 1.0 / 8},
+		{name: "中文", a: "方法", b: "方法论", want: 1.0 / 3},
+		{name: "空字符串", a: "", b: "", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EditDistanceRatio(tt.a, tt.b)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("EditDistanceRatio(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSpanProbability(t *testing.T) {
+	tokens := []entity.TokenLogProb{
+		{Token: "a ", LogProb: 0},
+		{Token: "novel", LogProb: math.Log(0.5)},
+		{Token: " method", LogProb: 0},
+	}
+	text := "a novel method"
+
+	t.Run("覆盖单个 token", func(t *testing.T) {
+		got, ok := SpanProbability(tokens, text, 2, 7)
+		if !ok {
+			t.Fatal("SpanProbability() 应该可以对齐")
+		}
+		if math.Abs(got-0.5) > 1e-9 {
+			t.Errorf("SpanProbability() = %v, want 0.5", got)
+		}
+	})
+
+	t.Run("文本不一致时无法对齐", func(t *testing.T) {
+		if _, ok := SpanProbability(tokens, "another text", 2, 7); ok {
+			t.Error("文本不一致时应该返回 false")
+		}
+	})
+
+	t.Run("空区间", func(t *testing.T) {
+		if _, ok := SpanProbability(tokens, text, 3, 3); ok {
+			t.Error("空区间应该返回 false")
+		}
+	})
+}
+
+func TestVersionAgreement(t *testing.T) {
+	target := []PositionInfo{
+		{OriginalStart: 2, OriginalEnd: 5, PolishedText: "novel"},
+		{OriginalStart: 10, OriginalEnd: 14, PolishedText: "issue"},
+	}
+	versions := [][]ChangeInfo{
+		{{OriginalStart: 2, OriginalEnd: 5, PolishedText: "novel"}, {OriginalStart: 10, OriginalEnd: 14, PolishedText: "issue"}},
+		{{OriginalStart: 2, OriginalEnd: 5, PolishedText: "novel"}},
+		{{OriginalStart: 2, OriginalEnd: 5, PolishedText: "new"}},
+	}
+
+	got := VersionAgreement(target, versions)
+	if len(got) != 2 {
+		t.Fatalf("VersionAgreement() 返回 %d 项, want 2", len(got))
+	}
+	if math.Abs(*got[0]-2.0/3) > 1e-9 {
+		t.Errorf("第一个修改一致率 = %v, want %v", *got[0], 2.0/3)
+	}
+	if math.Abs(*got[1]-1.0/3) > 1e-9 {
+		t.Errorf("第二个修改一致率 = %v, want %v", *got[1], 1.0/3)
+	}
+
+	if VersionAgreement(target, versions[:1]) != nil {
+		t.Error("单个版本时应该返回 nil")
+	}
+}
+
+func TestConfidenceCalculator_Calculate(t *testing.T) {
+	calc := NewConfidenceCalculator()
+	high := 1.0
+	low := 0.1
+
+	base, factors := calc.Calculate(model.ChangeTypeVocabulary, "method", "methodology", ConfidenceSignals{})
+	if factors == nil || factors.VersionAgreement != nil {
+		t.Fatal("没有信号时不应包含一致性因子")
+	}
+
+	agreed, _ := calc.Calculate(model.ChangeTypeVocabulary, "method", "methodology", ConfidenceSignals{VersionAgreement: &high})
+	disagreed, _ := calc.Calculate(model.ChangeTypeVocabulary, "method", "methodology", ConfidenceSignals{VersionAgreement: &low})
+	if !(agreed > base && base > disagreed) {
+		t.Errorf("一致性信号应影响置信度: agreed=%v base=%v disagreed=%v", agreed, base, disagreed)
+	}
+
+	// 历史样本不足时忽略历史接受率
+	few, factors := calc.Calculate(model.ChangeTypeVocabulary, "method", "methodology", ConfidenceSignals{HistoricalAccept: &low, HistoricalSamples: 2})
+	if few != base || factors.HistoricalAcceptRate != nil {
+		t.Errorf("样本不足时不应使用历史接受率: got %v, want %v", few, base)
+	}
+
+	rejected, _ := calc.Calculate(model.ChangeTypeVocabulary, "method", "methodology", ConfidenceSignals{HistoricalAccept: &low, HistoricalSamples: 50})
+	if rejected >= base {
+		t.Errorf("历史接受率低时置信度应下降: got %v, base %v", rejected, base)
+	}
+
+	if agreed < minConfidence || agreed > maxConfidence {
+		t.Errorf("置信度超出范围: %v", agreed)
+	}
+}
@@ -0,0 +1,178 @@
+package comparison
+
+import (
+	"sort"
+	"strings"
+)
+
+// 跨版本一致性级别
+const (
+	AgreementAll  = "all"  // 所有版本做出相同修改
+	AgreementSome = "some" // 多个（但不是全部）版本做出相同修改
+	AgreementOne  = "one"  // 仅一个版本做出该修改
+)
+
+// VersionChanges 单个版本相对原文的修改
+type VersionChanges struct {
+	VersionType string
+	Changes     []ChangeInfo
+}
+
+// SpanOption 某个原文区间的一种候选写法
+type SpanOption struct {
+	Text     string   // 替换后的文本
+	Versions []string // 采用该写法的版本
+}
+
+// AlignedSpan 对齐后的原文区间
+type AlignedSpan struct {
+	OriginalStart     int          // 原文中的起始位置（rune）
+	OriginalEnd       int          // 原文中的结束位置（rune）
+	OriginalText      string       // 原文片段
+	Options           []SpanOption // 候选写法（按支持版本数降序）
+	UnchangedVersions []string     // 未修改该区间的版本
+	Agreement         string       // 一致性级别 all/some/one
+	Support           int          // 最多版本采用的写法的版本数
+}
+
+// ConsensusAligner 多版本对齐器
+type ConsensusAligner struct{}
+
+// NewConsensusAligner 创建多版本对齐器
+func NewConsensusAligner() *ConsensusAligner {
+	return &ConsensusAligner{}
+}
+
+// Align 将所有版本的修改对齐到原文区间
+// 任意版本的修改区间相互重叠时合并为同一个区间，再分别还原各版本在该区间内的写法
+func (a *ConsensusAligner) Align(original string, versions []VersionChanges) []AlignedSpan {
+	origRunes := []rune(original)
+
+	clusters := mergeIntervals(versions)
+	assigned := make([][][]ChangeInfo, len(versions))
+	for i, version := range versions {
+		assigned[i] = assignChanges(clusters, version.Changes)
+	}
+
+	spans := make([]AlignedSpan, 0, len(clusters))
+
+	for ci, cluster := range clusters {
+		span := AlignedSpan{
+			OriginalStart: cluster.start,
+			OriginalEnd:   cluster.end,
+			OriginalText:  string(origRunes[cluster.start:cluster.end]),
+		}
+
+		optionIndex := make(map[string]int)
+		for vi, version := range versions {
+			text := renderSpan(origRunes, cluster.start, cluster.end, assigned[vi][ci])
+			if text == span.OriginalText {
+				span.UnchangedVersions = append(span.UnchangedVersions, version.VersionType)
+				continue
+			}
+
+			if idx, ok := optionIndex[text]; ok {
+				span.Options[idx].Versions = append(span.Options[idx].Versions, version.VersionType)
+				continue
+			}
+			optionIndex[text] = len(span.Options)
+			span.Options = append(span.Options, SpanOption{Text: text, Versions: []string{version.VersionType}})
+		}
+
+		// 不同版本的修改合并后可能互相抵消
+		if len(span.Options) == 0 {
+			continue
+		}
+
+		sort.SliceStable(span.Options, func(i, j int) bool {
+			return len(span.Options[i].Versions) > len(span.Options[j].Versions)
+		})
+
+		span.Support = len(span.Options[0].Versions)
+		switch {
+		case span.Support == len(versions):
+			span.Agreement = AgreementAll
+		case span.Support > 1:
+			span.Agreement = AgreementSome
+		default:
+			span.Agreement = AgreementOne
+		}
+
+		spans = append(spans, span)
+	}
+
+	return spans
+}
+
+// interval 原文区间 [start, end)
+type interval struct {
+	start int
+	end   int
+}
+
+// mergeIntervals 合并所有版本中相互重叠的修改区间
+// 纯插入（start == end）与相邻区间接触时也视为重叠，避免同一位置的插入被拆成多个区间
+func mergeIntervals(versions []VersionChanges) []interval {
+	all := make([]interval, 0)
+	for _, version := range versions {
+		for _, change := range version.Changes {
+			all = append(all, interval{start: change.OriginalStart, end: change.OriginalEnd})
+		}
+	}
+	if len(all) == 0 {
+		return nil
+	}
+
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].start != all[j].start {
+			return all[i].start < all[j].start
+		}
+		return all[i].end < all[j].end
+	})
+
+	merged := []interval{all[0]}
+	for _, next := range all[1:] {
+		cur := &merged[len(merged)-1]
+		touching := next.start == cur.end && (next.start == next.end || cur.start == cur.end)
+		if next.start < cur.end || touching {
+			if next.end > cur.end {
+				cur.end = next.end
+			}
+			continue
+		}
+		merged = append(merged, next)
+	}
+
+	return merged
+}
+
+// assignChanges 将版本的修改分配到所属区间（按顺序分配给第一个包含该修改的区间）
+func assignChanges(clusters []interval, changes []ChangeInfo) [][]ChangeInfo {
+	assigned := make([][]ChangeInfo, len(clusters))
+	ci := 0
+	for _, change := range changes {
+		for ci < len(clusters) && !(clusters[ci].start <= change.OriginalStart && change.OriginalEnd <= clusters[ci].end) {
+			ci++
+		}
+		if ci == len(clusters) {
+			break
+		}
+		assigned[ci] = append(assigned[ci], change)
+	}
+	return assigned
+}
+
+// renderSpan 还原某个版本在原文区间 [start, end) 内的写法
+func renderSpan(origRunes []rune, start, end int, changes []ChangeInfo) string {
+	var builder strings.Builder
+	pos := start
+
+	for _, change := range changes {
+		builder.WriteString(string(origRunes[pos:change.OriginalStart]))
+		builder.WriteString(change.PolishedText)
+		pos = change.OriginalEnd
+	}
+	builder.WriteString(string(origRunes[pos:end]))
+
+	return builder.String()
+}
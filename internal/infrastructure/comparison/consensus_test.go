@@ -0,0 +1,99 @@
+package comparison
+
+import (
+	"testing"
+)
+
+func TestConsensusAligner_Align(t *testing.T) {
+	engine := NewDiffEngine()
+	aligner := NewConsensusAligner()
+
+	original := "We use a new method to solve the problem."
+	polished := map[string]string{
+		"conservative": "We use a novel method to solve the problem.",
+		"balanced":     "We use a novel method to address the problem.",
+		"aggressive":   "We use a novel approach to address the issue.",
+	}
+
+	versions := make([]VersionChanges, 0, len(polished))
+	for _, vt := range []string{"conservative", "balanced", "aggressive"} {
+		diffs := engine.GenerateWordDiff(original, polished[vt])
+		versions = append(versions, VersionChanges{VersionType: vt, Changes: engine.GetChanges(diffs)})
+	}
+
+	spans := aligner.Align(original, versions)
+	if len(spans) == 0 {
+		t.Fatal("Align() 应该返回对齐区间")
+	}
+
+	agreements := make(map[string]string)
+	for _, span := range spans {
+		if span.OriginalText == "" && span.OriginalStart != span.OriginalEnd {
+			t.Errorf("区间 [%d, %d) 的原文为空", span.OriginalStart, span.OriginalEnd)
+		}
+		if len(span.Options) == 0 {
+			t.Errorf("区间 %q 没有候选写法", span.OriginalText)
+			continue
+		}
+		agreements[span.Options[0].Text] = span.Agreement
+		t.Logf("区间 %q -> %q (%s)", span.OriginalText, span.Options[0].Text, span.Agreement)
+	}
+
+	if got := agreements["novel"]; got != AgreementAll {
+		t.Errorf("所有版本一致的修改应为 %s，得到 %q", AgreementAll, got)
+	}
+	if got := agreements["address"]; got != AgreementSome {
+		t.Errorf("两个版本一致的修改应为 %s，得到 %q", AgreementSome, got)
+	}
+	if got := agreements["issue"]; got != AgreementOne {
+		t.Errorf("单个版本的修改应为 %s，得到 %q", AgreementOne, got)
+	}
+}
+
+func TestConsensusAligner_OverlappingChanges(t *testing.T) {
+	aligner := NewConsensusAligner()
+	original := "abcdef"
+
+	versions := []VersionChanges{
+		{VersionType: "v1", Changes: []ChangeInfo{{OriginalStart: 1, OriginalEnd: 3, PolishedText: "X"}}},
+		{VersionType: "v2", Changes: []ChangeInfo{{OriginalStart: 2, OriginalEnd: 4, PolishedText: "Y"}}},
+		{VersionType: "v3", Changes: nil},
+	}
+
+	spans := aligner.Align(original, versions)
+	if len(spans) != 1 {
+		t.Fatalf("重叠修改应合并为 1 个区间，得到 %d", len(spans))
+	}
+
+	span := spans[0]
+	if span.OriginalStart != 1 || span.OriginalEnd != 4 || span.OriginalText != "bcd" {
+		t.Errorf("合并区间错误: [%d, %d) %q", span.OriginalStart, span.OriginalEnd, span.OriginalText)
+	}
+	if len(span.Options) != 2 || span.Options[0].Text != "Xd" || span.Options[1].Text != "bY" {
+		t.Errorf("候选写法错误: %+v", span.Options)
+	}
+	if len(span.UnchangedVersions) != 1 || span.UnchangedVersions[0] != "v3" {
+		t.Errorf("未修改版本错误: %v", span.UnchangedVersions)
+	}
+	if span.Agreement != AgreementOne {
+		t.Errorf("Agreement = %s, want %s", span.Agreement, AgreementOne)
+	}
+}
+
+func TestConsensusAligner_Insertion(t *testing.T) {
+	aligner := NewConsensusAligner()
+	original := "ab"
+
+	versions := []VersionChanges{
+		{VersionType: "v1", Changes: []ChangeInfo{{OriginalStart: 1, OriginalEnd: 1, PolishedText: "-"}}},
+		{VersionType: "v2", Changes: []ChangeInfo{{OriginalStart: 1, OriginalEnd: 1, PolishedText: "-"}}},
+	}
+
+	spans := aligner.Align(original, versions)
+	if len(spans) != 1 {
+		t.Fatalf("应该返回 1 个区间，得到 %d", len(spans))
+	}
+	if spans[0].Agreement != AgreementAll || spans[0].Options[0].Text != "-" || spans[0].OriginalText != "" {
+		t.Errorf("插入对齐错误: %+v", spans[0])
+	}
+}
@@ -1,6 +1,10 @@
 package comparison
 
 import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
 	"github.com/sergi/go-diff/diffmatchpatch"
 )
 
@@ -42,37 +46,146 @@ func (e *DiffEngine) GenerateDiff(original, polished string) []DiffItem {
 	return items
 }
 
+// maxWordTokens 词级 diff 支持的最大不同词数（每个词编码为一个 rune，需避开代理区）
+const maxWordTokens = 0xD000
+
+// GenerateWordDiff 生成词级文本差异
+// 以词为最小单位比较，修改边界总是落在词边界上，便于跨版本对齐
+// 不同词数超过上限时退化为字符级 diff
+func (e *DiffEngine) GenerateWordDiff(original, polished string) []DiffItem {
+	tokenIndex := make(map[string]rune)
+	tokens := make([]string, 0)
+
+	encode := func(text string) []rune {
+		words := SplitWords(text)
+		encoded := make([]rune, len(words))
+		for i, word := range words {
+			idx, ok := tokenIndex[word]
+			if !ok {
+				idx = rune(len(tokens))
+				tokenIndex[word] = idx
+				tokens = append(tokens, word)
+			}
+			encoded[i] = idx
+		}
+		return encoded
+	}
+
+	originalRunes := encode(original)
+	polishedRunes := encode(polished)
+	if len(tokens) > maxWordTokens {
+		return e.GenerateDiff(original, polished)
+	}
+
+	diffs := e.dmp.DiffMainRunes(originalRunes, polishedRunes, false)
+
+	items := make([]DiffItem, 0, len(diffs))
+	for _, diff := range diffs {
+		var builder strings.Builder
+		for _, idx := range diff.Text {
+			builder.WriteString(tokens[idx])
+		}
+		items = append(items, DiffItem{
+			Type: diff.Type,
+			Text: builder.String(),
+		})
+	}
+
+	return items
+}
+
+// SplitWords 将文本切分为词
+// 连续的字母数字（含附加的组合符号，如德文分解形式的变音符、天城文元音符号）为一个词，连续空白为一个词；
+// 不用空格分词的文字（汉字、假名、泰文等）和标点各自为一个词；韩文按空格分词，连续的谚文为一个词
+func SplitWords(text string) []string {
+	words := make([]string, 0)
+	runes := []rune(text)
+
+	for i := 0; i < len(runes); {
+		j := i + 1
+		switch {
+		case isUnspacedScript(runes[i]):
+			// 单字成词
+		case isWordRune(runes[i]):
+			for j < len(runes) && isWordRune(runes[j]) && !isUnspacedScript(runes[j]) {
+				j++
+			}
+		case unicode.IsSpace(runes[i]):
+			for j < len(runes) && unicode.IsSpace(runes[j]) {
+				j++
+			}
+		}
+		// 组合符号归属前一个字符
+		if !unicode.IsSpace(runes[i]) {
+			for j < len(runes) && unicode.IsMark(runes[j]) {
+				j++
+			}
+		}
+		words = append(words, string(runes[i:j]))
+		i = j
+	}
+
+	return words
+}
+
+// isUnspacedScript 判断字符是否属于不用空格分词的文字（每个字符单独成词）
+func isUnspacedScript(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana,
+		unicode.Thai, unicode.Lao, unicode.Khmer, unicode.Myanmar) ||
+		r == 'ー' // 日文长音符（不属于假名区）
+}
+
+// isWordRune 判断字符是否可以组成词（字母、数字和组合符号）
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r)
+}
+
 // GetChanges 从 diff 结果中提取修改对
+// 同时记录每个修改在原文中的字符级位置（基于 rune），便于跨版本对齐
 func (e *DiffEngine) GetChanges(diffs []DiffItem) []ChangeInfo {
 	changes := make([]ChangeInfo, 0)
+	originalPos := 0 // 当前在原文中的位置
 
 	i := 0
 	for i < len(diffs) {
 		// 查找删除-插入对（表示替换）
 		if i < len(diffs)-1 {
 			if diffs[i].Type == diffmatchpatch.DiffDelete && diffs[i+1].Type == diffmatchpatch.DiffInsert {
+				deleted := utf8.RuneCountInString(diffs[i].Text)
 				changes = append(changes, ChangeInfo{
-					OriginalText: diffs[i].Text,
-					PolishedText: diffs[i+1].Text,
+					OriginalText:  diffs[i].Text,
+					PolishedText:  diffs[i+1].Text,
+					OriginalStart: originalPos,
+					OriginalEnd:   originalPos + deleted,
 				})
+				originalPos += deleted
 				i += 2
 				continue
 			}
 		}
 
-		// 单独的删除
-		if diffs[i].Type == diffmatchpatch.DiffDelete {
+		switch diffs[i].Type {
+		case diffmatchpatch.DiffEqual:
+			originalPos += utf8.RuneCountInString(diffs[i].Text)
+
+		case diffmatchpatch.DiffDelete:
+			// 单独的删除
+			deleted := utf8.RuneCountInString(diffs[i].Text)
 			changes = append(changes, ChangeInfo{
-				OriginalText: diffs[i].Text,
-				PolishedText: "",
+				OriginalText:  diffs[i].Text,
+				PolishedText:  "",
+				OriginalStart: originalPos,
+				OriginalEnd:   originalPos + deleted,
 			})
-		}
+			originalPos += deleted
 
-		// 单独的插入
-		if diffs[i].Type == diffmatchpatch.DiffInsert {
+		case diffmatchpatch.DiffInsert:
+			// 单独的插入（原文中为空区间）
 			changes = append(changes, ChangeInfo{
-				OriginalText: "",
-				PolishedText: diffs[i].Text,
+				OriginalText:  "",
+				PolishedText:  diffs[i].Text,
+				OriginalStart: originalPos,
+				OriginalEnd:   originalPos,
 			})
 		}
 
@@ -84,6 +197,8 @@ func (e *DiffEngine) GetChanges(diffs []DiffItem) []ChangeInfo {
 
 // ChangeInfo 修改信息
 type ChangeInfo struct {
-	OriginalText string
-	PolishedText string
+	OriginalText  string
+	PolishedText  string
+	OriginalStart int // 在原文中的起始位置（基于 rune）
+	OriginalEnd   int // 在原文中的结束位置
 }
@@ -167,3 +167,45 @@ func TestDiffItem_Types(t *testing.T) {
 		t.Error("应该包含插入部分 (Insert)")
 	}
 }
+
+func TestSplitWords(t *testing.T) {
+	words := SplitWords("We use  a 新方法, ok")
+	want := []string{"We", " ", "use", "  ", "a", " ", "新", "方", "法", ",", " ", "ok"}
+	if len(words) != len(want) {
+		t.Fatalf("SplitWords() = %q, want %q", words, want)
+	}
+	for i := range want {
+		if words[i] != want[i] {
+			t.Errorf("words[%d] = %q, want %q", i, words[i], want[i])
+		}
+	}
+}
+
+func TestSplitWords_OtherScripts(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"german decomposed umlaut", "Gro\u0308\u00dfe Modelle", []string{"Gro\u0308\u00dfe", " ", "Modelle"}},
+		{"japanese", "データを解析。", []string{"デ", "ー", "タ", "を", "解", "析", "。"}},
+		{"korean", "새로운 방법을", []string{"새로운", " ", "방법을"}},
+		{"spanish", "¿Método nuevo?", []string{"¿", "Método", " ", "nuevo", "?"}},
+		{"thai with vowel marks", "ที่นี่", []string{"ที่", "นี่"}},
+		{"hindi", "नई विधि", []string{"नई", " ", "विधि"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			words := SplitWords(tt.text)
+			if len(words) != len(tt.want) {
+				t.Fatalf("SplitWords() = %q, want %q", words, tt.want)
+			}
+			for i := range tt.want {
+				if words[i] != tt.want[i] {
+					t.Errorf("words[%d] = %q, want %q", i, words[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -1,7 +1,7 @@
 package comparison
 
 import (
-	"strings"
+	"unicode"
 )
 
 // PositionCalculator 位置计算器
@@ -30,11 +30,13 @@ func (c *PositionCalculator) CalculatePositions(polishedText string, changes []C
 
 		if pos.Start >= 0 {
 			positions = append(positions, PositionInfo{
-				Start:        pos.Start,
-				End:          pos.End,
-				Line:         pos.Line,
-				OriginalText: change.OriginalText,
-				PolishedText: change.PolishedText,
+				Start:         pos.Start,
+				End:           pos.End,
+				Line:          pos.Line,
+				OriginalText:  change.OriginalText,
+				PolishedText:  change.PolishedText,
+				OriginalStart: change.OriginalStart,
+				OriginalEnd:   change.OriginalEnd,
 			})
 			currentPos = pos.End // 更新搜索起始位置
 		}
@@ -90,16 +92,26 @@ type Position struct {
 
 // PositionInfo 带文本的位置信息
 type PositionInfo struct {
-	Start        int
-	End          int
-	Line         int
-	OriginalText string
-	PolishedText string
+	Start         int
+	End           int
+	Line          int
+	OriginalText  string
+	PolishedText  string
+	OriginalStart int // 在原文中的起始位置（基于 rune）
+	OriginalEnd   int // 在原文中的结束位置
 }
 
 // CountWords 统计文本中的词数
+// 按 SplitWords 切分后统计包含字母或数字的词：中文、日文等每个字计为一个词，标点和空白不计
 func CountWords(text string) int {
-	// 简单实现：按空格分割
-	words := strings.Fields(text)
-	return len(words)
+	count := 0
+	for _, word := range SplitWords(text) {
+		for _, r := range word {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				count++
+				break
+			}
+		}
+	}
+	return count
 }
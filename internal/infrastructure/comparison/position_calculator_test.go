@@ -150,6 +150,26 @@ func TestCountWords(t *testing.T) {
 			text:      "Hello, World! How are you?",
 			wantCount: 5,
 		},
+		{
+			name:      "中文按字计数",
+			text:      "本文提出新方法。",
+			wantCount: 7,
+		},
+		{
+			name:      "中英混排",
+			text:      "使用BERT模型",
+			wantCount: 5,
+		},
+		{
+			name:      "德文",
+			text:      "Die Ergebnisse zeigen große Verbesserungen.",
+			wantCount: 5,
+		},
+		{
+			name:      "韩文按空格计数",
+			text:      "새로운 방법을 제안한다.",
+			wantCount: 3,
+		},
 	}
 
 	for _, tt := range tests {
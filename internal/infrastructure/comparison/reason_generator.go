@@ -107,25 +107,10 @@ func (g *ReasonGenerator) getVocabularyAlternatives(original string) []model.Alt
 	}
 }
 
-// CalculateConfidence 计算修改的置信度
+// CalculateConfidence 计算修改的基础置信度（修改类型 + 编辑幅度）
+// 需要结合多版本一致性、模型概率、历史接受率时，使用 ConfidenceCalculator.Calculate
 func (g *ReasonGenerator) CalculateConfidence(changeType model.ChangeType, original, polished string) float64 {
-	// 简单实现：根据修改类型返回不同的置信度
-	switch changeType {
-	case model.ChangeTypeVocabulary:
-		// 词汇替换置信度较高
-		return 0.90
-
-	case model.ChangeTypeGrammar:
-		// 语法修正置信度中等
-		return 0.85
-
-	case model.ChangeTypeStructure:
-		// 结构调整置信度相对较低
-		return 0.75
-
-	default:
-		return 0.80
-	}
+	return NewConfidenceCalculator().Prior(changeType, original, polished)
 }
 
 // GetImpact 获取修改的影响维度
@@ -0,0 +1,177 @@
+package comparison
+
+import (
+	"unicode"
+)
+
+// AlignedSentence 对齐后的句子
+type AlignedSentence struct {
+	Index         int               // 句子序号（从 0 开始）
+	OriginalStart int               // 原文中的起始位置（rune）
+	OriginalEnd   int               // 原文中的结束位置（rune）
+	OriginalText  string            // 原文句子（含句末空白）
+	Texts         map[string]string // 各版本对应的句子：version_type -> text
+}
+
+// SentenceAligner 句子对齐器
+type SentenceAligner struct{}
+
+// NewSentenceAligner 创建句子对齐器
+func NewSentenceAligner() *SentenceAligner {
+	return &SentenceAligner{}
+}
+
+// Align 将原文切分为句子，并还原每个版本对应的句子
+// 某个版本的修改跨越句子边界（例如合并两句）时，相关原文句子合并为一句，保证拼接结果完整
+func (a *SentenceAligner) Align(original string, versions []VersionChanges) []AlignedSentence {
+	origRunes := []rune(original)
+	sentences := SplitSentences(original)
+	if len(sentences) == 0 {
+		return nil
+	}
+
+	// 1. 跨越句子边界的修改：删除被跨越的边界
+	boundaries := make(map[int]bool, len(sentences))
+	for _, sentence := range sentences[1:] {
+		boundaries[sentence.start] = true
+	}
+	for _, version := range versions {
+		for _, change := range version.Changes {
+			for b := range boundaries {
+				if change.OriginalStart < b && b < change.OriginalEnd {
+					delete(boundaries, b)
+				}
+			}
+		}
+	}
+
+	groups := make([]interval, 0, len(sentences))
+	current := sentences[0]
+	for _, sentence := range sentences[1:] {
+		if boundaries[sentence.start] {
+			groups = append(groups, current)
+			current = sentence
+			continue
+		}
+		current.end = sentence.end
+	}
+	groups = append(groups, current)
+
+	// 2. 某个版本的句子不再以句末标点结束（例如把句号改为逗号连接下一句）时，与下一句合并
+	assigned := make([][][]ChangeInfo, len(versions))
+	for i := 0; i < len(groups)-1; {
+		merge := false
+		for vi, version := range versions {
+			assigned[vi] = assignChanges(groups, version.Changes)
+			if !endsSentence(renderSpan(origRunes, groups[i].start, groups[i].end, assigned[vi][i])) {
+				merge = true
+				break
+			}
+		}
+		if !merge {
+			i++
+			continue
+		}
+		groups[i].end = groups[i+1].end
+		groups = append(groups[:i+1], groups[i+2:]...)
+	}
+
+	// 3. 还原每个版本的句子
+	aligned := make([]AlignedSentence, len(groups))
+	for i, group := range groups {
+		aligned[i] = AlignedSentence{
+			Index:         i,
+			OriginalStart: group.start,
+			OriginalEnd:   group.end,
+			OriginalText:  string(origRunes[group.start:group.end]),
+			Texts:         make(map[string]string, len(versions)),
+		}
+	}
+	for vi, version := range versions {
+		assigned[vi] = assignChanges(groups, version.Changes)
+		for i, group := range groups {
+			aligned[i].Texts[version.VersionType] = renderSpan(origRunes, group.start, group.end, assigned[vi][i])
+		}
+	}
+
+	return aligned
+}
+
+// SplitSentences 将文本切分为句子区间（基于 rune）
+// 句末标点（含其后的引号、括号和空白）归属当前句子，换行同样结束一个句子；
+// 英文句点后必须跟空白或位于文本末尾，避免切开小数和缩写
+func SplitSentences(text string) []interval {
+	runes := []rune(text)
+	sentences := make([]interval, 0)
+	start := 0
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		end := -1
+		switch {
+		case r == '\n':
+			end = i + 1
+		case isCJKTerminator(r):
+			end = i + 1
+		case r == '.' || r == '!' || r == '?':
+			j := i + 1
+			for j < len(runes) && isSentenceCloser(runes[j]) {
+				j++
+			}
+			if j == len(runes) || unicode.IsSpace(runes[j]) {
+				end = j
+			}
+		}
+		if end == -1 {
+			continue
+		}
+
+		// 句末的引号、括号和空白归属当前句子
+		for end < len(runes) && (isSentenceCloser(runes[end]) || unicode.IsSpace(runes[end])) {
+			end++
+		}
+		sentences = append(sentences, interval{start: start, end: end})
+		start = end
+		i = end - 1
+	}
+
+	if start < len(runes) {
+		sentences = append(sentences, interval{start: start, end: len(runes)})
+	}
+
+	return sentences
+}
+
+// endsSentence 判断文本是否以句末标点（或换行）结束
+func endsSentence(text string) bool {
+	runes := []rune(text)
+	i := len(runes) - 1
+	for i >= 0 && unicode.IsSpace(runes[i]) {
+		if runes[i] == '\n' {
+			return true
+		}
+		i--
+	}
+	for i >= 0 && isSentenceCloser(runes[i]) {
+		i--
+	}
+	if i < 0 {
+		return false
+	}
+	return runes[i] == '.' || runes[i] == '!' || runes[i] == '?' || isCJKTerminator(runes[i])
+}
+
+// isCJKTerminator 判断是否为中文句末标点
+func isCJKTerminator(r rune) bool {
+	return r == '。' || r == '！' || r == '？'
+}
+
+// isSentenceCloser 判断是否为句末的闭合引号或括号
+func isSentenceCloser(r rune) bool {
+	switch r {
+	case '"', '\'', ')', ']', '”', '’', '）', '」', '』':
+		return true
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package comparison
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplitSentences(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{
+			name: "英文句子",
+			text: "First sentence. Second one! Third?",
+			want: []string{"First sentence. ", "Second one! ", "Third?"},
+		},
+		{
+			name: "小数不切分",
+			text: "The value is 3.14 here. Done.",
+			want: []string{"The value is 3.14 here. ", "Done."},
+		},
+		{
+			name: "中文句子",
+			text: "第一句。第二句！第三句",
+			want: []string{"第一句。", "第二句！", "第三句"},
+		},
+		{
+			name: "引号和换行",
+			text: "He said \"yes.\" Then\nleft.",
+			want: []string{"He said \"yes.\" ", "Then\n", "left."},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runes := []rune(tt.text)
+			spans := SplitSentences(tt.text)
+
+			got := make([]string, len(spans))
+			for i, span := range spans {
+				got[i] = string(runes[span.start:span.end])
+			}
+
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("SplitSentences() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSentenceAligner_Align(t *testing.T) {
+	engine := NewDiffEngine()
+	aligner := NewSentenceAligner()
+
+	original := "We use a new method. It solves the problem. Results are good."
+	polished := map[string]string{
+		"conservative": "We use a novel method. It solves the problem. Results are good.",
+		"aggressive":   "We use a novel approach, which solves the issue. Results are promising.",
+	}
+
+	versions := []VersionChanges{}
+	for _, vt := range []string{"conservative", "aggressive"} {
+		diffs := engine.GenerateWordDiff(original, polished[vt])
+		versions = append(versions, VersionChanges{VersionType: vt, Changes: engine.GetChanges(diffs)})
+	}
+
+	sentences := aligner.Align(original, versions)
+
+	// aggressive 版本合并了前两句，因此对齐结果只有 2 句
+	if len(sentences) != 2 {
+		for _, s := range sentences {
+			t.Logf("%d: %q %v", s.Index, s.OriginalText, s.Texts)
+		}
+		t.Fatalf("Align() 返回 %d 句, want 2", len(sentences))
+	}
+
+	for vt, text := range polished {
+		var builder strings.Builder
+		for _, sentence := range sentences {
+			builder.WriteString(sentence.Texts[vt])
+		}
+		if builder.String() != text {
+			t.Errorf("版本 %s 拼接结果 = %q, want %q", vt, builder.String(), text)
+		}
+	}
+
+	var original2 strings.Builder
+	for _, sentence := range sentences {
+		original2.WriteString(sentence.OriginalText)
+	}
+	if original2.String() != original {
+		t.Errorf("原文拼接结果 = %q, want %q", original2.String(), original)
+	}
+
+	if sentences[1].Texts["conservative"] != "Results are good." || sentences[1].Texts["aggressive"] != "Results are promising." {
+		t.Errorf("第二句对齐错误: %v", sentences[1].Texts)
+	}
+}
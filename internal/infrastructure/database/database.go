@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"paper_ai/internal/config"
+	"paper_ai/internal/infrastructure/tracing"
 	"paper_ai/pkg/logger"
 
 	"github.com/golang-migrate/migrate/v4"
@@ -29,10 +30,7 @@ var instance *DB
 // Init 初始化数据库连接
 func Init(cfg *config.DatabaseConfig) error {
 	// 构建DSN
-	dsn := fmt.Sprintf(
-		"host=%s user=%s password=%s dbname=%s port=%d sslmode=disable TimeZone=Asia/Shanghai",
-		cfg.Host, cfg.User, cfg.Password, cfg.DBName, cfg.Port,
-	)
+	dsn := buildDSN(cfg)
 
 	// GORM日志配置
 	var logLevel gormLogger.LogLevel
@@ -63,6 +61,11 @@ func Init(cfg *config.DatabaseConfig) error {
 		return fmt.Errorf("failed to connect database: %w", err)
 	}
 
+	// 注册链路追踪插件（未启用追踪时为 no-op）
+	if err := db.Use(tracing.NewGormPlugin()); err != nil {
+		return fmt.Errorf("failed to register tracing plugin: %w", err)
+	}
+
 	// 获取底层sqlDB
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -93,6 +96,35 @@ func Init(cfg *config.DatabaseConfig) error {
 	return nil
 }
 
+// Ping 使用给定配置建立一次性连接并 ping（不执行迁移，不影响全局实例），用于配置检查
+func Ping(ctx context.Context, cfg *config.DatabaseConfig) error {
+	db, err := gorm.Open(postgres.Open(buildDSN(cfg)), &gorm.Config{
+		Logger: gormLogger.Default.LogMode(gormLogger.Silent),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to connect database: %w", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get sql.DB: %w", err)
+	}
+	defer sqlDB.Close()
+
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return fmt.Errorf("database ping failed: %w", err)
+	}
+	return nil
+}
+
+// buildDSN 构建 PostgreSQL 连接串
+func buildDSN(cfg *config.DatabaseConfig) string {
+	return fmt.Sprintf(
+		"host=%s user=%s password=%s dbname=%s port=%d sslmode=disable TimeZone=Asia/Shanghai",
+		cfg.Host, cfg.User, cfg.Password.Value(), cfg.DBName, cfg.Port,
+	)
+}
+
 // GetDB 获取数据库实例
 func GetDB() *DB {
 	return instance
@@ -135,28 +167,62 @@ func Health(ctx context.Context) error {
 	return nil
 }
 
-// runMigrations 运行数据库迁移
-func runMigrations(sqlDB *sql.DB) error {
-	// 获取项目根目录的 migrations 文件夹路径
-	migrationsPath, err := filepath.Abs("migrations")
+// MigrationStatus 查询当前迁移版本和是否处于 dirty 状态（迁移中途失败）
+// 尚未执行任何迁移时返回版本 0
+func MigrationStatus(ctx context.Context) (uint, bool, error) {
+	if instance == nil {
+		return 0, false, fmt.Errorf("database not initialized")
+	}
+
+	var version int64
+	var dirty bool
+	row := instance.DB.WithContext(ctx).Raw("SELECT version, dirty FROM schema_migrations LIMIT 1").Row()
+	if err := row.Scan(&version, &dirty); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, false, nil
+		}
+		return 0, false, fmt.Errorf("failed to query migration version: %w", err)
+	}
+
+	return uint(version), dirty, nil
+}
+
+// NewMigrator 创建迁移执行器，migrationsPath 为空时使用当前目录下的 migrations
+func NewMigrator(sqlDB *sql.DB, migrationsPath string) (*migrate.Migrate, error) {
+	if migrationsPath == "" {
+		migrationsPath = "migrations"
+	}
+
+	// 获取 migrations 文件夹的绝对路径
+	absPath, err := filepath.Abs(migrationsPath)
 	if err != nil {
-		return fmt.Errorf("failed to get migrations path: %w", err)
+		return nil, fmt.Errorf("failed to get migrations path: %w", err)
 	}
 
 	// 创建 postgres 驱动实例
 	driver, err := migratePostgres.WithInstance(sqlDB, &migratePostgres.Config{})
 	if err != nil {
-		return fmt.Errorf("failed to create migrate driver: %w", err)
+		return nil, fmt.Errorf("failed to create migrate driver: %w", err)
 	}
 
 	// 创建迁移实例
 	m, err := migrate.NewWithDatabaseInstance(
-		fmt.Sprintf("file://%s", migrationsPath),
+		fmt.Sprintf("file://%s", absPath),
 		"postgres",
 		driver,
 	)
 	if err != nil {
-		return fmt.Errorf("failed to create migrate instance: %w", err)
+		return nil, fmt.Errorf("failed to create migrate instance: %w", err)
+	}
+
+	return m, nil
+}
+
+// runMigrations 运行数据库迁移
+func runMigrations(sqlDB *sql.DB) error {
+	m, err := NewMigrator(sqlDB, "")
+	if err != nil {
+		return err
 	}
 
 	// 执行迁移
@@ -0,0 +1,96 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ciphertextPrefix 密文前缀（格式版本），密文为 prefix + base64(nonce || ciphertext || tag)
+const ciphertextPrefix = "enc:v1:"
+
+// keySize 数据密钥长度（AES-256）
+const keySize = 32
+
+// ErrNotEncrypted 值不是本包生成的密文
+var ErrNotEncrypted = errors.New("value is not encrypted")
+
+// DataKey 解包后的数据密钥（每个用户一个有效密钥，加密该用户的润色内容）
+type DataKey struct {
+	id   int64
+	aead cipher.AEAD
+}
+
+// newDataKey 由密钥明文创建数据密钥
+func newDataKey(id int64, key []byte) (*DataKey, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	return &DataKey{id: id, aead: aead}, nil
+}
+
+// ID 数据密钥 ID（存储在每行记录的 encryption_key_id 列）
+func (k *DataKey) ID() int64 {
+	return k.id
+}
+
+// EncryptString 加密字符串，field 作为附加认证数据（密文不能被挪到其他字段解密）
+func (k *DataKey) EncryptString(field, plaintext string) (string, error) {
+	sealed, err := seal(k.aead, []byte(plaintext), []byte(field))
+	if err != nil {
+		return "", err
+	}
+	return ciphertextPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptString 解密 EncryptString 生成的密文，field 必须与加密时一致
+func (k *DataKey) DecryptString(field, ciphertext string) (string, error) {
+	if !strings.HasPrefix(ciphertext, ciphertextPrefix) {
+		return "", ErrNotEncrypted
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(ciphertext, ciphertextPrefix))
+	if err != nil {
+		return "", fmt.Errorf("invalid ciphertext encoding: %w", err)
+	}
+
+	plaintext, err := open(k.aead, sealed, []byte(field))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt %s with data key %d: %w", field, k.id, err)
+	}
+	return string(plaintext), nil
+}
+
+// newAEAD 创建 AES-GCM
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != keySize {
+		return nil, fmt.Errorf("key must be %d bytes, got %d", keySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal 加密，返回 nonce || ciphertext || tag（每次使用随机 nonce）
+func seal(aead cipher.AEAD, plaintext, additionalData []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// open 解密 seal 的输出
+func open(aead cipher.AEAD, sealed, additionalData []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize()+aead.Overhead() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, additionalData)
+}
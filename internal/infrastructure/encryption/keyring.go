@@ -0,0 +1,289 @@
+package encryption
+
+import (
+	"context"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"paper_ai/internal/config"
+	"paper_ai/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// activeKeyTTL 用户有效数据密钥的缓存时间（paperctl 轮换数据密钥后，运行中的服务最多在该时间后改用新密钥）
+const activeKeyTTL = 5 * time.Minute
+
+// WrappedKey 被主密钥包装的数据密钥（持久化形式）
+type WrappedKey struct {
+	ID          int64
+	UserID      int64
+	MasterKeyID string // 包装该数据密钥的主密钥 ID
+	Wrapped     []byte // 主密钥加密后的数据密钥
+	Active      bool   // 是否为用户当前用于加密新内容的密钥
+	CreatedAt   time.Time
+}
+
+// KeyStore 数据密钥存储
+type KeyStore interface {
+	// GetActive 获取用户当前有效的数据密钥，不存在时返回 nil, nil
+	GetActive(ctx context.Context, userID int64) (*WrappedKey, error)
+	// Get 根据 ID 获取数据密钥（包括已停用的）
+	Get(ctx context.Context, id int64) (*WrappedKey, error)
+	// CreateActive 保存用户的新有效密钥；用户已有有效密钥时（并发创建）不保存，返回已有的密钥
+	CreateActive(ctx context.Context, key *WrappedKey) (*WrappedKey, error)
+	// ListNotWrappedBy 列出不是由 masterKeyID 包装的数据密钥（主密钥轮换）
+	ListNotWrappedBy(ctx context.Context, masterKeyID string) ([]*WrappedKey, error)
+	// UpdateWrapped 更新数据密钥的包装结果
+	UpdateWrapped(ctx context.Context, id int64, masterKeyID string, wrapped []byte) error
+	// Deactivate 停用数据密钥（userID 为 nil 时停用所有用户的），返回停用数量
+	Deactivate(ctx context.Context, userID *int64) (int64, error)
+}
+
+// Keyring 信封加密密钥环
+// 每个用户一个有效数据密钥（AES-256-GCM），数据密钥由配置中的主密钥包装后存储在数据库；
+// 解包后的数据密钥缓存在内存中
+type Keyring struct {
+	store           KeyStore
+	masterKeys      map[string]cipher.AEAD
+	activeMasterKey string
+	enabled         bool
+
+	mu     sync.RWMutex
+	byID   map[int64]*DataKey   // 数据密钥 ID → 数据密钥（不会变化，永久缓存）
+	active map[int64]*cachedKey // 用户 ID → 有效数据密钥
+}
+
+// cachedKey 带过期时间的缓存项
+type cachedKey struct {
+	key       *DataKey
+	expiresAt time.Time
+}
+
+// NewKeyring 创建密钥环
+// enabled 为 false 时不加密新内容，但仍可解密已加密的数据（masterKeys 中需保留对应主密钥）
+func NewKeyring(store KeyStore, masterKeys map[string][]byte, activeMasterKey string, enabled bool) (*Keyring, error) {
+	aeads := make(map[string]cipher.AEAD, len(masterKeys))
+	for id, key := range masterKeys {
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid master key %s: %w", id, err)
+		}
+		aeads[id] = aead
+	}
+	if enabled {
+		if _, ok := aeads[activeMasterKey]; !ok {
+			return nil, fmt.Errorf("active master key %q is not configured", activeMasterKey)
+		}
+	}
+
+	return &Keyring{
+		store:           store,
+		masterKeys:      aeads,
+		activeMasterKey: activeMasterKey,
+		enabled:         enabled,
+		byID:            make(map[int64]*DataKey),
+		active:          make(map[int64]*cachedKey),
+	}, nil
+}
+
+// NewKeyringFromConfig 根据配置创建密钥环，未配置主密钥时返回 nil（不加密，也无法读取已加密的数据）
+func NewKeyringFromConfig(cfg *config.EncryptionConfig, store KeyStore) (*Keyring, error) {
+	if len(cfg.MasterKeys) == 0 {
+		if cfg.Enabled {
+			return nil, fmt.Errorf("encryption is enabled but no master keys are configured")
+		}
+		return nil, nil
+	}
+
+	masterKeys := make(map[string][]byte, len(cfg.MasterKeys))
+	for id, keyConfig := range cfg.MasterKeys {
+		key, err := keyConfig.Decode()
+		if err != nil {
+			return nil, fmt.Errorf("encryption.master_keys.%s: %w", id, err)
+		}
+		masterKeys[id] = key
+	}
+	return NewKeyring(store, masterKeys, cfg.ActiveMasterKey, cfg.Enabled)
+}
+
+// Enabled 是否加密新写入的内容
+func (k *Keyring) Enabled() bool {
+	return k.enabled
+}
+
+// ActiveKey 获取用户当前用于加密的数据密钥，没有时生成并保存
+func (k *Keyring) ActiveKey(ctx context.Context, userID int64) (*DataKey, error) {
+	if !k.enabled {
+		return nil, fmt.Errorf("encryption is not enabled")
+	}
+
+	k.mu.RLock()
+	cached, ok := k.active[userID]
+	k.mu.RUnlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.key, nil
+	}
+
+	wrapped, err := k.store.GetActive(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load data key of user %d: %w", userID, err)
+	}
+	if wrapped == nil {
+		if wrapped, err = k.createActive(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	key, err := k.unwrapAndCache(wrapped)
+	if err != nil {
+		return nil, err
+	}
+
+	k.mu.Lock()
+	k.active[userID] = &cachedKey{key: key, expiresAt: time.Now().Add(activeKeyTTL)}
+	k.mu.Unlock()
+	return key, nil
+}
+
+// Key 根据 ID 获取数据密钥（解密使用）
+func (k *Keyring) Key(ctx context.Context, id int64) (*DataKey, error) {
+	k.mu.RLock()
+	key, ok := k.byID[id]
+	k.mu.RUnlock()
+	if ok {
+		return key, nil
+	}
+
+	wrapped, err := k.store.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load data key %d: %w", id, err)
+	}
+	return k.unwrapAndCache(wrapped)
+}
+
+// RewrapDataKeys 用当前主密钥重新包装所有由其他主密钥包装的数据密钥（主密钥轮换），返回处理数量
+// 完成后即可从配置中移除旧主密钥；数据本身不需要重新加密
+func (k *Keyring) RewrapDataKeys(ctx context.Context) (int, error) {
+	active, ok := k.masterKeys[k.activeMasterKey]
+	if !ok {
+		return 0, fmt.Errorf("active master key %q is not configured", k.activeMasterKey)
+	}
+
+	keys, err := k.store.ListNotWrappedBy(ctx, k.activeMasterKey)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list data keys: %w", err)
+	}
+
+	for i, wrapped := range keys {
+		plaintext, err := k.unwrap(wrapped)
+		if err != nil {
+			return i, err
+		}
+		rewrapped, err := seal(active, plaintext, wrapAdditionalData(wrapped.UserID))
+		if err != nil {
+			return i, err
+		}
+		if err := k.store.UpdateWrapped(ctx, wrapped.ID, k.activeMasterKey, rewrapped); err != nil {
+			return i, fmt.Errorf("failed to save rewrapped data key %d: %w", wrapped.ID, err)
+		}
+	}
+
+	logger.FromContext(ctx).Info("data keys rewrapped",
+		zap.Int("count", len(keys)),
+		zap.String("master_key_id", k.activeMasterKey))
+	return len(keys), nil
+}
+
+// RotateDataKeys 停用数据密钥（userID 为 nil 时停用所有用户的），之后写入的内容使用新生成的数据密钥，返回停用数量
+// 已有数据仍可用旧密钥解密，需要再执行存量数据重新加密才能让旧密钥不再被使用
+func (k *Keyring) RotateDataKeys(ctx context.Context, userID *int64) (int64, error) {
+	deactivated, err := k.store.Deactivate(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to deactivate data keys: %w", err)
+	}
+
+	k.mu.Lock()
+	if userID != nil {
+		delete(k.active, *userID)
+	} else {
+		k.active = make(map[int64]*cachedKey)
+	}
+	k.mu.Unlock()
+
+	logger.FromContext(ctx).Info("data keys rotated", zap.Int64("deactivated", deactivated))
+	return deactivated, nil
+}
+
+// MasterKeyIDs 已配置的主密钥 ID（排序后）
+func (k *Keyring) MasterKeyIDs() []string {
+	ids := make([]string, 0, len(k.masterKeys))
+	for id := range k.masterKeys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// createActive 为用户生成新数据密钥并用当前主密钥包装后保存
+func (k *Keyring) createActive(ctx context.Context, userID int64) (*WrappedKey, error) {
+	plaintext := make([]byte, keySize)
+	if _, err := rand.Read(plaintext); err != nil {
+		return nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+
+	wrapped, err := seal(k.masterKeys[k.activeMasterKey], plaintext, wrapAdditionalData(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	saved, err := k.store.CreateActive(ctx, &WrappedKey{
+		UserID:      userID,
+		MasterKeyID: k.activeMasterKey,
+		Wrapped:     wrapped,
+		Active:      true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save data key of user %d: %w", userID, err)
+	}
+	return saved, nil
+}
+
+// unwrapAndCache 解包数据密钥并缓存
+func (k *Keyring) unwrapAndCache(wrapped *WrappedKey) (*DataKey, error) {
+	plaintext, err := k.unwrap(wrapped)
+	if err != nil {
+		return nil, err
+	}
+	key, err := newDataKey(wrapped.ID, plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	k.mu.Lock()
+	k.byID[wrapped.ID] = key
+	k.mu.Unlock()
+	return key, nil
+}
+
+// unwrap 用包装时的主密钥解包数据密钥
+func (k *Keyring) unwrap(wrapped *WrappedKey) ([]byte, error) {
+	master, ok := k.masterKeys[wrapped.MasterKeyID]
+	if !ok {
+		return nil, fmt.Errorf("master key %q of data key %d is not configured", wrapped.MasterKeyID, wrapped.ID)
+	}
+	plaintext, err := open(master, wrapped.Wrapped, wrapAdditionalData(wrapped.UserID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key %d with master key %q: %w", wrapped.ID, wrapped.MasterKeyID, err)
+	}
+	return plaintext, nil
+}
+
+// wrapAdditionalData 包装数据密钥时的附加认证数据（数据密钥与用户绑定）
+func wrapAdditionalData(userID int64) []byte {
+	return []byte(fmt.Sprintf("paper_ai:data-key:user:%d", userID))
+}
@@ -0,0 +1,227 @@
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// memoryStore 内存数据密钥存储（测试用）
+type memoryStore struct {
+	mu     sync.Mutex
+	nextID int64
+	keys   map[int64]*WrappedKey
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{keys: make(map[int64]*WrappedKey)}
+}
+
+func (s *memoryStore) GetActive(ctx context.Context, userID int64) (*WrappedKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, key := range s.keys {
+		if key.UserID == userID && key.Active {
+			copied := *key
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *memoryStore) Get(ctx context.Context, id int64) (*WrappedKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key, ok := s.keys[id]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	copied := *key
+	return &copied, nil
+}
+
+func (s *memoryStore) CreateActive(ctx context.Context, key *WrappedKey) (*WrappedKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	copied := *key
+	copied.ID = s.nextID
+	s.keys[copied.ID] = &copied
+	result := copied
+	return &result, nil
+}
+
+func (s *memoryStore) ListNotWrappedBy(ctx context.Context, masterKeyID string) ([]*WrappedKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var result []*WrappedKey
+	for _, key := range s.keys {
+		if key.MasterKeyID != masterKeyID {
+			copied := *key
+			result = append(result, &copied)
+		}
+	}
+	return result, nil
+}
+
+func (s *memoryStore) UpdateWrapped(ctx context.Context, id int64, masterKeyID string, wrapped []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys[id].MasterKeyID = masterKeyID
+	s.keys[id].Wrapped = wrapped
+	return nil
+}
+
+func (s *memoryStore) Deactivate(ctx context.Context, userID *int64) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var count int64
+	for _, key := range s.keys {
+		if key.Active && (userID == nil || key.UserID == *userID) {
+			key.Active = false
+			count++
+		}
+	}
+	return count, nil
+}
+
+func masterKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, keySize)
+}
+
+func TestKeyringEncryptDecrypt(t *testing.T) {
+	ctx := context.Background()
+	keyring, err := NewKeyring(newMemoryStore(), map[string][]byte{"k1": masterKey(1)}, "k1", true)
+	if err != nil {
+		t.Fatalf("NewKeyring: %v", err)
+	}
+
+	key, err := keyring.ActiveKey(ctx, 42)
+	if err != nil {
+		t.Fatalf("ActiveKey: %v", err)
+	}
+	ciphertext, err := key.EncryptString("original_content", "未发表的研究内容")
+	if err != nil {
+		t.Fatalf("EncryptString: %v", err)
+	}
+	if !strings.HasPrefix(ciphertext, ciphertextPrefix) || strings.Contains(ciphertext, "研究") {
+		t.Fatalf("unexpected ciphertext %q", ciphertext)
+	}
+
+	again, err := keyring.ActiveKey(ctx, 42)
+	if err != nil || again.ID() != key.ID() {
+		t.Fatalf("expected the same active key, got %v (err %v)", again, err)
+	}
+	other, err := keyring.ActiveKey(ctx, 43)
+	if err != nil || other.ID() == key.ID() {
+		t.Fatalf("expected a separate key per user, got %v (err %v)", other, err)
+	}
+
+	loaded, err := keyring.Key(ctx, key.ID())
+	if err != nil {
+		t.Fatalf("Key: %v", err)
+	}
+	plaintext, err := loaded.DecryptString("original_content", ciphertext)
+	if err != nil || plaintext != "未发表的研究内容" {
+		t.Fatalf("DecryptString = %q, %v", plaintext, err)
+	}
+
+	if _, err := loaded.DecryptString("final_content", ciphertext); err == nil {
+		t.Fatal("expected decryption with a different field to fail")
+	}
+	if _, err := other.DecryptString("original_content", ciphertext); err == nil {
+		t.Fatal("expected decryption with another user's key to fail")
+	}
+	if _, err := loaded.DecryptString("original_content", "plain text"); !errors.Is(err, ErrNotEncrypted) {
+		t.Fatalf("expected ErrNotEncrypted, got %v", err)
+	}
+}
+
+func TestKeyringRewrapDataKeys(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStore()
+
+	oldKeyring, err := NewKeyring(store, map[string][]byte{"k1": masterKey(1)}, "k1", true)
+	if err != nil {
+		t.Fatalf("NewKeyring: %v", err)
+	}
+	key, err := oldKeyring.ActiveKey(ctx, 42)
+	if err != nil {
+		t.Fatalf("ActiveKey: %v", err)
+	}
+	ciphertext, err := key.EncryptString("polished_content", "hello")
+	if err != nil {
+		t.Fatalf("EncryptString: %v", err)
+	}
+
+	rotating, err := NewKeyring(store, map[string][]byte{"k1": masterKey(1), "k2": masterKey(2)}, "k2", true)
+	if err != nil {
+		t.Fatalf("NewKeyring: %v", err)
+	}
+	count, err := rotating.RewrapDataKeys(ctx)
+	if err != nil || count != 1 {
+		t.Fatalf("RewrapDataKeys = %d, %v", count, err)
+	}
+
+	// 旧主密钥移除后仍能解密
+	rotated, err := NewKeyring(store, map[string][]byte{"k2": masterKey(2)}, "k2", true)
+	if err != nil {
+		t.Fatalf("NewKeyring: %v", err)
+	}
+	loaded, err := rotated.Key(ctx, key.ID())
+	if err != nil {
+		t.Fatalf("Key: %v", err)
+	}
+	if plaintext, err := loaded.DecryptString("polished_content", ciphertext); err != nil || plaintext != "hello" {
+		t.Fatalf("DecryptString = %q, %v", plaintext, err)
+	}
+}
+
+func TestKeyringRotateDataKeys(t *testing.T) {
+	ctx := context.Background()
+	keyring, err := NewKeyring(newMemoryStore(), map[string][]byte{"k1": masterKey(1)}, "k1", true)
+	if err != nil {
+		t.Fatalf("NewKeyring: %v", err)
+	}
+
+	oldKey, err := keyring.ActiveKey(ctx, 42)
+	if err != nil {
+		t.Fatalf("ActiveKey: %v", err)
+	}
+
+	userID := int64(42)
+	if deactivated, err := keyring.RotateDataKeys(ctx, &userID); err != nil || deactivated != 1 {
+		t.Fatalf("RotateDataKeys = %d, %v", deactivated, err)
+	}
+
+	newKey, err := keyring.ActiveKey(ctx, 42)
+	if err != nil {
+		t.Fatalf("ActiveKey: %v", err)
+	}
+	if newKey.ID() == oldKey.ID() {
+		t.Fatal("expected a new data key after rotation")
+	}
+	if _, err := keyring.Key(ctx, oldKey.ID()); err != nil {
+		t.Fatalf("retired key should still decrypt: %v", err)
+	}
+}
+
+func TestKeyringDisabled(t *testing.T) {
+	keyring, err := NewKeyring(newMemoryStore(), map[string][]byte{"k1": masterKey(1)}, "", false)
+	if err != nil {
+		t.Fatalf("NewKeyring: %v", err)
+	}
+	if keyring.Enabled() {
+		t.Fatal("expected keyring to be disabled")
+	}
+	if _, err := keyring.ActiveKey(context.Background(), 42); err == nil {
+		t.Fatal("expected ActiveKey to fail when encryption is disabled")
+	}
+
+	if _, err := NewKeyring(newMemoryStore(), map[string][]byte{"k1": masterKey(1)}, "missing", true); err == nil {
+		t.Fatal("expected error for unknown active master key")
+	}
+}
@@ -0,0 +1,229 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace 指标名前缀
+const namespace = "paper_ai"
+
+// registry 独立的指标注册表（不使用全局默认注册表，避免第三方库的指标混入）
+var registry = prometheus.NewRegistry()
+
+var (
+	// httpRequestsTotal HTTP 请求数（按路由模板、方法和状态码）
+	httpRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "HTTP 请求总数",
+	}, []string{"method", "route", "status"})
+
+	// httpRequestDuration HTTP 请求耗时
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP 请求耗时（秒）",
+		Buckets:   []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"method", "route", "status"})
+
+	// providerRequestsTotal AI 提供商调用次数（result: success/error）
+	providerRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "provider",
+		Name:      "requests_total",
+		Help:      "AI 提供商调用总数",
+	}, []string{"provider", "model", "version_type", "result"})
+
+	// providerRequestDuration AI 提供商调用耗时
+	providerRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "provider",
+		Name:      "request_duration_seconds",
+		Help:      "AI 提供商调用耗时（秒）",
+		Buckets:   []float64{0.5, 1, 2, 5, 10, 20, 30, 60, 120},
+	}, []string{"provider", "model", "version_type"})
+
+	// providerTokensTotal AI 提供商 token 用量（direction: input/output）
+	providerTokensTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "provider",
+		Name:      "tokens_total",
+		Help:      "AI 提供商 token 用量",
+	}, []string{"provider", "model", "direction"})
+
+	// multiVersionInFlight 正在处理的多版本润色请求数
+	multiVersionInFlight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "polish",
+		Name:      "multi_version_in_flight",
+		Help:      "正在处理的多版本润色请求数",
+	})
+
+	// schedulerJobRunsTotal 定时任务执行次数（result: success/error）
+	schedulerJobRunsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "scheduler",
+		Name:      "job_runs_total",
+		Help:      "定时任务执行总数",
+	}, []string{"job", "result"})
+
+	// schedulerJobDuration 定时任务执行耗时
+	schedulerJobDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "scheduler",
+		Name:      "job_duration_seconds",
+		Help:      "定时任务执行耗时（秒）",
+		Buckets:   []float64{0.01, 0.05, 0.1, 0.5, 1, 5, 10, 30, 60, 300},
+	}, []string{"job"})
+
+	// schedulerLeader 本实例是否为定时任务 leader（1=是）
+	schedulerLeader = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "scheduler",
+		Name:      "leader",
+		Help:      "本实例是否持有定时任务 leader 锁（1=是，0=否）",
+	})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequestsTotal,
+		httpRequestDuration,
+		providerRequestsTotal,
+		providerRequestDuration,
+		providerTokensTotal,
+		multiVersionInFlight,
+		schedulerJobRunsTotal,
+		schedulerJobDuration,
+		schedulerLeader,
+	)
+}
+
+// Handler 返回 /metrics 的 HTTP 处理器
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{Registry: registry})
+}
+
+// ObserveHTTPRequest 记录一次 HTTP 请求
+func ObserveHTTPRequest(method, route, status string, duration time.Duration) {
+	httpRequestsTotal.WithLabelValues(method, route, status).Inc()
+	httpRequestDuration.WithLabelValues(method, route, status).Observe(duration.Seconds())
+}
+
+// ProviderCall 一次 AI 提供商调用的观测数据
+type ProviderCall struct {
+	Provider     string
+	Model        string // 为空时记为 unknown
+	VersionType  string // 单版本润色为 single
+	Duration     time.Duration
+	Err          error
+	InputTokens  int
+	OutputTokens int
+}
+
+// ObserveProviderCall 记录一次 AI 提供商调用
+func ObserveProviderCall(call ProviderCall) {
+	model := call.Model
+	if model == "" {
+		model = "unknown"
+	}
+
+	result := "success"
+	if call.Err != nil {
+		result = "error"
+	}
+
+	providerRequestsTotal.WithLabelValues(call.Provider, model, call.VersionType, result).Inc()
+	providerRequestDuration.WithLabelValues(call.Provider, model, call.VersionType).Observe(call.Duration.Seconds())
+
+	if call.InputTokens > 0 {
+		providerTokensTotal.WithLabelValues(call.Provider, model, "input").Add(float64(call.InputTokens))
+	}
+	if call.OutputTokens > 0 {
+		providerTokensTotal.WithLabelValues(call.Provider, model, "output").Add(float64(call.OutputTokens))
+	}
+}
+
+// MultiVersionStarted 多版本润色开始处理，返回结束时调用的函数
+func MultiVersionStarted() func() {
+	multiVersionInFlight.Inc()
+	return multiVersionInFlight.Dec
+}
+
+// ObserveSchedulerJob 记录一次定时任务执行
+func ObserveSchedulerJob(job string, duration time.Duration, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+
+	schedulerJobRunsTotal.WithLabelValues(job, result).Inc()
+	schedulerJobDuration.WithLabelValues(job).Observe(duration.Seconds())
+}
+
+// SetSchedulerLeader 记录本实例的 leader 状态
+func SetSchedulerLeader(isLeader bool) {
+	if isLeader {
+		schedulerLeader.Set(1)
+	} else {
+		schedulerLeader.Set(0)
+	}
+}
+
+// CacheStats 缓存统计
+type CacheStats struct {
+	Hits    uint64
+	Misses  uint64
+	Entries int
+}
+
+// RegisterPromptCache 注册 Prompt 缓存指标（命中数、未命中数、命中率、条目数）
+func RegisterPromptCache(stats func() CacheStats) {
+	registry.MustRegister(
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "prompt_cache",
+			Name:      "hits_total",
+			Help:      "Prompt 缓存命中次数",
+		}, func() float64 { return float64(stats().Hits) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "prompt_cache",
+			Name:      "misses_total",
+			Help:      "Prompt 缓存未命中次数",
+		}, func() float64 { return float64(stats().Misses) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "prompt_cache",
+			Name:      "hit_ratio",
+			Help:      "Prompt 缓存命中率（启动以来）",
+		}, func() float64 {
+			s := stats()
+			if s.Hits+s.Misses == 0 {
+				return 0
+			}
+			return float64(s.Hits) / float64(s.Hits+s.Misses)
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "prompt_cache",
+			Name:      "entries",
+			Help:      "Prompt 缓存条目数",
+		}, func() float64 { return float64(stats().Entries) }),
+	)
+}
+
+// RegisterDBStats 注册数据库连接池指标
+func RegisterDBStats(db *sql.DB) {
+	registry.MustRegister(collectors.NewDBStatsCollector(db, "postgres"))
+}
@@ -0,0 +1,118 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+
+	"paper_ai/internal/domain/entity"
+	"paper_ai/internal/domain/repository"
+	"paper_ai/pkg/logger"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// changeFeedbackRepositoryImpl 修改反馈仓储实现
+type changeFeedbackRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewChangeFeedbackRepository 创建修改反馈仓储实现
+func NewChangeFeedbackRepository(db *gorm.DB) repository.ChangeFeedbackRepository {
+	return &changeFeedbackRepositoryImpl{db: db}
+}
+
+// BatchCreate 批量记录反馈
+func (r *changeFeedbackRepositoryImpl) BatchCreate(ctx context.Context, feedbacks []*entity.ChangeFeedback) error {
+	if len(feedbacks) == 0 {
+		return nil
+	}
+
+	pos := make([]*ChangeFeedbackPO, len(feedbacks))
+	for i, f := range feedbacks {
+		po := &ChangeFeedbackPO{}
+		po.FromEntity(f)
+		pos[i] = po
+	}
+
+	if err := r.db.WithContext(ctx).Create(&pos).Error; err != nil {
+		logger.Error("failed to batch create change feedbacks", zap.Error(err))
+		return fmt.Errorf("failed to batch create change feedbacks: %w", err)
+	}
+
+	// 回写ID和时间戳
+	for i, po := range pos {
+		feedbacks[i].ID = po.ID
+		feedbacks[i].CreatedAt = po.CreatedAt
+	}
+
+	return nil
+}
+
+// acceptStatsResult 接受情况统计查询结果
+type acceptStatsResult struct {
+	GroupKey      string
+	TotalCount    int64
+	AcceptedCount int64
+}
+
+// GetAcceptStatsBySignatures 按修改签名统计接受情况
+func (r *changeFeedbackRepositoryImpl) GetAcceptStatsBySignatures(ctx context.Context, signatures []string) (map[string]*repository.AcceptStats, error) {
+	statsMap := make(map[string]*repository.AcceptStats)
+	if len(signatures) == 0 {
+		return statsMap, nil
+	}
+
+	var results []acceptStatsResult
+	err := r.db.WithContext(ctx).Model(&ChangeFeedbackPO{}).
+		Select(`
+			signature as group_key,
+			COUNT(*) as total_count,
+			SUM(CASE WHEN action = 'accepted' THEN 1 ELSE 0 END) as accepted_count
+		`).
+		Where("signature IN ?", signatures).
+		Group("signature").
+		Find(&results).Error
+
+	if err != nil {
+		logger.Error("failed to get accept stats by signatures", zap.Error(err))
+		return nil, fmt.Errorf("failed to get accept stats by signatures: %w", err)
+	}
+
+	for _, res := range results {
+		statsMap[res.GroupKey] = &repository.AcceptStats{
+			TotalCount:    res.TotalCount,
+			AcceptedCount: res.AcceptedCount,
+		}
+	}
+
+	return statsMap, nil
+}
+
+// GetAcceptStatsByType 按修改类型统计接受情况
+func (r *changeFeedbackRepositoryImpl) GetAcceptStatsByType(ctx context.Context) (map[string]*repository.AcceptStats, error) {
+	var results []acceptStatsResult
+	err := r.db.WithContext(ctx).Model(&ChangeFeedbackPO{}).
+		Select(`
+			change_type as group_key,
+			COUNT(*) as total_count,
+			SUM(CASE WHEN action = 'accepted' THEN 1 ELSE 0 END) as accepted_count
+		`).
+		Group("change_type").
+		Find(&results).Error
+
+	if err != nil {
+		logger.Error("failed to get accept stats by type", zap.Error(err))
+		return nil, fmt.Errorf("failed to get accept stats by type: %w", err)
+	}
+
+	statsMap := make(map[string]*repository.AcceptStats)
+	for _, res := range results {
+		statsMap[res.GroupKey] = &repository.AcceptStats{
+			TotalCount:    res.TotalCount,
+			AcceptedCount: res.AcceptedCount,
+		}
+	}
+
+	return statsMap, nil
+}
@@ -0,0 +1,76 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+
+	"paper_ai/internal/domain/entity"
+	"paper_ai/internal/domain/repository"
+	"paper_ai/pkg/logger"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// comparisonActionRepositoryImpl 对比操作日志仓储实现
+type comparisonActionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewComparisonActionRepository 创建对比操作日志仓储实现
+func NewComparisonActionRepository(db *gorm.DB) repository.ComparisonActionRepository {
+	return &comparisonActionRepositoryImpl{db: db}
+}
+
+// Append 追加操作日志
+// 在事务中分配序号，(record_id, seq) 唯一索引保证并发写入时不会产生重复序号
+func (r *comparisonActionRepositoryImpl) Append(ctx context.Context, action *entity.ComparisonAction) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var maxSeq int
+		if err := tx.Model(&ComparisonActionPO{}).
+			Where("record_id = ?", action.RecordID).
+			Select("COALESCE(MAX(seq), 0)").
+			Scan(&maxSeq).Error; err != nil {
+			return err
+		}
+
+		action.Seq = maxSeq + 1
+
+		po := &ComparisonActionPO{}
+		po.FromEntity(action)
+		if err := tx.Create(po).Error; err != nil {
+			return err
+		}
+
+		action.ID = po.ID
+		action.CreatedAt = po.CreatedAt
+		return nil
+	})
+	if err != nil {
+		logger.Error("failed to append comparison action",
+			zap.Int64("record_id", action.RecordID),
+			zap.String("action", action.Action),
+			zap.Error(err))
+		return fmt.Errorf("failed to append comparison action: %w", err)
+	}
+
+	return nil
+}
+
+// ListByRecordID 按序号升序获取操作日志
+func (r *comparisonActionRepositoryImpl) ListByRecordID(ctx context.Context, recordID int64) ([]*entity.ComparisonAction, error) {
+	var pos []*ComparisonActionPO
+	if err := r.db.WithContext(ctx).
+		Where("record_id = ?", recordID).
+		Order("seq ASC").
+		Find(&pos).Error; err != nil {
+		logger.Error("failed to list comparison actions", zap.Int64("record_id", recordID), zap.Error(err))
+		return nil, fmt.Errorf("failed to list comparison actions: %w", err)
+	}
+
+	actions := make([]*entity.ComparisonAction, len(pos))
+	for i, po := range pos {
+		actions[i] = po.ToEntity()
+	}
+	return actions, nil
+}
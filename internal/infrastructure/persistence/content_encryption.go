@@ -0,0 +1,153 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+
+	"paper_ai/internal/infrastructure/encryption"
+)
+
+// contentKeyring 内容加密密钥环（为 nil 时不加密，也无法读取已加密的记录）
+var contentKeyring atomic.Pointer[encryption.Keyring]
+
+// SetContentKeyring 设置内容加密密钥环，润色记录和版本记录的内容字段在 FromEntity/ToEntity 中透明加解密
+func SetContentKeyring(keyring *encryption.Keyring) {
+	contentKeyring.Store(keyring)
+}
+
+// contentEncryptionEnabled 新写入的内容是否需要加密
+func contentEncryptionEnabled() bool {
+	keyring := contentKeyring.Load()
+	return keyring != nil && keyring.Enabled()
+}
+
+// recordContentColumns 润色记录中随数据密钥变化的列（更新时必须一起写入，保证同一行使用同一个密钥）
+var recordContentColumns = []string{
+	"original_content", "polished_content", "final_content", "comparison_data", "token_log_probs", "encryption_key_id",
+}
+
+// versionContentColumns 版本记录中随数据密钥变化的列
+var versionContentColumns = []string{
+	"polished_content", "suggestions", "token_log_probs", "encryption_key_id",
+}
+
+// revisionContentColumns 修订记录中随数据密钥变化的列
+var revisionContentColumns = []string{
+	"instruction", "base_content", "polished_content", "comparison_data", "encryption_key_id",
+}
+
+// rowCipher 一行记录内容字段的加解密器，同一行的字段使用同一个数据密钥
+// 出错后后续字段不再处理，调用方最后检查 err
+type rowCipher struct {
+	ctx     context.Context
+	userID  int64
+	keyring *encryption.Keyring // 加密时使用，为 nil 表示不加密
+	key     *encryption.DataKey // 加密时首次遇到非空字段才获取（没有内容的记录不会为用户生成密钥）
+	err     error
+}
+
+// sealerFor 返回写入 userID 的记录时使用的加密器，未启用加密时原样写入
+func sealerFor(ctx context.Context, userID int64) *rowCipher {
+	c := &rowCipher{ctx: ctx, userID: userID}
+	if contentEncryptionEnabled() {
+		c.keyring = contentKeyring.Load()
+	}
+	return c
+}
+
+// openerFor 返回读取用 keyID 加密的记录时使用的解密器，keyID 为 0 表示明文
+func openerFor(ctx context.Context, keyID int64) (*rowCipher, error) {
+	if keyID == 0 {
+		return &rowCipher{ctx: ctx}, nil
+	}
+
+	keyring := contentKeyring.Load()
+	if keyring == nil {
+		return nil, fmt.Errorf("content is encrypted with data key %d but encryption is not configured", keyID)
+	}
+	key, err := keyring.Key(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+	return &rowCipher{ctx: ctx, key: key}, nil
+}
+
+// keyID 本行使用的数据密钥 ID（没有加密任何字段时为 0）
+func (c *rowCipher) keyID() int64 {
+	if c.key == nil {
+		return 0
+	}
+	return c.key.ID()
+}
+
+// sealText 加密文本字段，空值原样保留
+func (c *rowCipher) sealText(field, value string) string {
+	if c.err != nil || c.keyring == nil || value == "" {
+		return value
+	}
+	if c.key == nil {
+		if c.key, c.err = c.keyring.ActiveKey(c.ctx, c.userID); c.err != nil {
+			return ""
+		}
+	}
+
+	ciphertext, err := c.key.EncryptString(field, value)
+	if err != nil {
+		c.err = err
+		return ""
+	}
+	return ciphertext
+}
+
+// openText 解密文本字段
+func (c *rowCipher) openText(field, value string) string {
+	if c.err != nil || c.key == nil || value == "" {
+		return value
+	}
+
+	plaintext, err := c.key.DecryptString(field, value)
+	if err != nil {
+		c.err = err
+		return ""
+	}
+	return plaintext
+}
+
+// sealJSON 加密 jsonb 字段：密文以 JSON 字符串形式存储，列类型保持不变
+func (c *rowCipher) sealJSON(field string, value *string) *string {
+	if value == nil || c.keyring == nil {
+		return value
+	}
+
+	ciphertext := c.sealText(field, *value)
+	if c.err != nil {
+		return nil
+	}
+	encoded, err := json.Marshal(ciphertext)
+	if err != nil {
+		c.err = err
+		return nil
+	}
+	result := string(encoded)
+	return &result
+}
+
+// openJSON 解密 sealJSON 写入的 jsonb 字段
+func (c *rowCipher) openJSON(field string, value *string) *string {
+	if value == nil || c.key == nil || c.err != nil {
+		return value
+	}
+
+	var ciphertext string
+	if err := json.Unmarshal([]byte(*value), &ciphertext); err != nil {
+		c.err = fmt.Errorf("invalid encrypted %s: %w", field, err)
+		return nil
+	}
+	plaintext := c.openText(field, ciphertext)
+	if c.err != nil {
+		return nil
+	}
+	return &plaintext
+}
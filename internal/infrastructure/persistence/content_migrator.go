@@ -0,0 +1,259 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+
+	"paper_ai/pkg/logger"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// pendingKeyCondition 明文（encryption_key_id = 0）或使用已停用数据密钥加密的行
+const pendingKeyCondition = "encryption_key_id NOT IN (SELECT id FROM encryption_data_keys WHERE active)"
+
+// pendingRecordCondition 需要加密的润色记录（不保存内容模式的记录没有内容，跳过）
+const pendingRecordCondition = pendingKeyCondition +
+	" AND content_omitted = FALSE AND (original_content <> '' OR polished_content <> '' OR final_content <> '')"
+
+// pendingVersionCondition 需要加密的版本记录（失败的版本没有内容，跳过）
+const pendingVersionCondition = pendingKeyCondition +
+	" AND (polished_content <> '' OR suggestions IS NOT NULL OR token_log_probs IS NOT NULL)"
+
+// pendingRevisionCondition 需要加密的修订记录
+const pendingRevisionCondition = pendingKeyCondition +
+	" AND (instruction <> '' OR base_content <> '' OR polished_content <> '' OR comparison_data IS NOT NULL)"
+
+// EncryptionStatus 内容加密状态
+type EncryptionStatus struct {
+	DataKeys         int64 // 数据密钥总数
+	ActiveDataKeys   int64 // 有效数据密钥数
+	Records          int64 // 润色记录总数（包括已软删除的）
+	PendingRecords   int64 // 明文或使用已停用密钥的润色记录数
+	Versions         int64 // 版本记录总数
+	PendingVersions  int64 // 明文或使用已停用密钥的版本记录数
+	Revisions        int64 // 修订记录总数
+	PendingRevisions int64 // 明文或使用已停用密钥的修订记录数
+	MasterKeyIDsUsed []string
+}
+
+// ContentMigrator 存量内容加密迁移
+// 加密明文的记录，并用用户当前数据密钥重新加密使用已停用密钥的记录（数据密钥轮换后执行）
+type ContentMigrator struct {
+	db *gorm.DB
+}
+
+// NewContentMigrator 创建存量内容加密迁移工具
+func NewContentMigrator(db *gorm.DB) *ContentMigrator {
+	return &ContentMigrator{db: db}
+}
+
+// Status 统计内容加密状态
+func (m *ContentMigrator) Status(ctx context.Context) (*EncryptionStatus, error) {
+	status := &EncryptionStatus{}
+	db := m.db.WithContext(ctx)
+
+	counts := []struct {
+		target *int64
+		query  *gorm.DB
+	}{
+		{&status.DataKeys, db.Model(&DataKeyPO{})},
+		{&status.ActiveDataKeys, db.Model(&DataKeyPO{}).Where("active")},
+		{&status.Records, db.Unscoped().Model(&PolishRecordPO{})},
+		{&status.PendingRecords, db.Unscoped().Model(&PolishRecordPO{}).Where(pendingRecordCondition)},
+		{&status.Versions, db.Model(&PolishVersionPO{})},
+		{&status.PendingVersions, db.Model(&PolishVersionPO{}).Where(pendingVersionCondition)},
+		{&status.Revisions, db.Model(&PolishRevisionPO{})},
+		{&status.PendingRevisions, db.Model(&PolishRevisionPO{}).Where(pendingRevisionCondition)},
+	}
+	for _, count := range counts {
+		if err := count.query.Count(count.target).Error; err != nil {
+			return nil, fmt.Errorf("failed to get encryption status: %w", err)
+		}
+	}
+
+	if err := db.Model(&DataKeyPO{}).Distinct().Order("master_key_id").Pluck("master_key_id", &status.MasterKeyIDsUsed).Error; err != nil {
+		return nil, fmt.Errorf("failed to list master keys in use: %w", err)
+	}
+	return status, nil
+}
+
+// Run 分批加密/重新加密所有待处理的记录，返回处理的润色记录数、版本记录数和修订记录数
+// 与服务同时运行是安全的：某行在读取后被服务更新（密钥 ID 已变化）时跳过该行
+func (m *ContentMigrator) Run(ctx context.Context, batchSize int) (int64, int64, int64, error) {
+	if !contentEncryptionEnabled() {
+		return 0, 0, 0, fmt.Errorf("content encryption is not enabled (encryption.enabled)")
+	}
+
+	records, err := m.migrateRecords(ctx, batchSize)
+	if err != nil {
+		return records, 0, 0, err
+	}
+	versions, err := m.migrateVersions(ctx, batchSize)
+	if err != nil {
+		return records, versions, 0, err
+	}
+	revisions, err := m.migrateRevisions(ctx, batchSize)
+	return records, versions, revisions, err
+}
+
+// migrateRecords 处理润色记录
+func (m *ContentMigrator) migrateRecords(ctx context.Context, batchSize int) (int64, error) {
+	var migrated int64
+	var lastID int64
+	for {
+		var pos []*PolishRecordPO
+		err := m.db.WithContext(ctx).Unscoped().
+			Where("id > ? AND "+pendingRecordCondition, lastID).
+			Order("id").Limit(batchSize).
+			Find(&pos).Error
+		if err != nil {
+			return migrated, fmt.Errorf("failed to load polish records: %w", err)
+		}
+		if len(pos) == 0 {
+			return migrated, nil
+		}
+
+		for _, po := range pos {
+			lastID = po.ID
+			record, err := po.ToEntity(ctx)
+			if err != nil {
+				return migrated, err
+			}
+
+			updated := &PolishRecordPO{}
+			if err := updated.FromEntity(ctx, record); err != nil {
+				return migrated, err
+			}
+			result := m.db.WithContext(ctx).Unscoped().Model(&PolishRecordPO{}).
+				Where("id = ? AND encryption_key_id = ?", po.ID, po.EncryptionKeyID).
+				Select(recordContentColumns).UpdateColumns(updated)
+			if result.Error != nil {
+				return migrated, fmt.Errorf("failed to save encrypted polish record %d: %w", po.ID, result.Error)
+			}
+			migrated += result.RowsAffected
+		}
+
+		logger.FromContext(ctx).Info("polish records encrypted", zap.Int64("migrated", migrated), zap.Int64("last_id", lastID))
+	}
+}
+
+// migrateVersions 处理版本记录
+func (m *ContentMigrator) migrateVersions(ctx context.Context, batchSize int) (int64, error) {
+	var migrated int64
+	var lastID int64
+	for {
+		var pos []*PolishVersionPO
+		err := m.db.WithContext(ctx).
+			Where("id > ? AND "+pendingVersionCondition, lastID).
+			Order("id").Limit(batchSize).
+			Find(&pos).Error
+		if err != nil {
+			return migrated, fmt.Errorf("failed to load polish versions: %w", err)
+		}
+		if len(pos) == 0 {
+			return migrated, nil
+		}
+
+		recordIDs := make([]int64, len(pos))
+		for i, po := range pos {
+			recordIDs[i] = po.RecordID
+		}
+		owners, err := m.owners(ctx, recordIDs)
+		if err != nil {
+			return migrated, err
+		}
+
+		for _, po := range pos {
+			lastID = po.ID
+			version, err := po.ToEntity(ctx)
+			if err != nil {
+				return migrated, err
+			}
+
+			updated := &PolishVersionPO{}
+			if err := updated.FromEntity(ctx, version, owners[po.RecordID]); err != nil {
+				return migrated, err
+			}
+			result := m.db.WithContext(ctx).Model(&PolishVersionPO{}).
+				Where("id = ? AND encryption_key_id = ?", po.ID, po.EncryptionKeyID).
+				Select(versionContentColumns).UpdateColumns(updated)
+			if result.Error != nil {
+				return migrated, fmt.Errorf("failed to save encrypted polish version %d: %w", po.ID, result.Error)
+			}
+			migrated += result.RowsAffected
+		}
+
+		logger.FromContext(ctx).Info("polish versions encrypted", zap.Int64("migrated", migrated), zap.Int64("last_id", lastID))
+	}
+}
+
+// migrateRevisions 处理修订记录
+func (m *ContentMigrator) migrateRevisions(ctx context.Context, batchSize int) (int64, error) {
+	var migrated int64
+	var lastID int64
+	for {
+		var pos []*PolishRevisionPO
+		err := m.db.WithContext(ctx).
+			Where("id > ? AND "+pendingRevisionCondition, lastID).
+			Order("id").Limit(batchSize).
+			Find(&pos).Error
+		if err != nil {
+			return migrated, fmt.Errorf("failed to load polish revisions: %w", err)
+		}
+		if len(pos) == 0 {
+			return migrated, nil
+		}
+
+		recordIDs := make([]int64, len(pos))
+		for i, po := range pos {
+			recordIDs[i] = po.RecordID
+		}
+		owners, err := m.owners(ctx, recordIDs)
+		if err != nil {
+			return migrated, err
+		}
+
+		for _, po := range pos {
+			lastID = po.ID
+			revision, err := po.ToEntity(ctx)
+			if err != nil {
+				return migrated, err
+			}
+
+			updated := &PolishRevisionPO{}
+			if err := updated.FromEntity(ctx, revision, owners[po.RecordID]); err != nil {
+				return migrated, err
+			}
+			result := m.db.WithContext(ctx).Model(&PolishRevisionPO{}).
+				Where("id = ? AND encryption_key_id = ?", po.ID, po.EncryptionKeyID).
+				Select(revisionContentColumns).UpdateColumns(updated)
+			if result.Error != nil {
+				return migrated, fmt.Errorf("failed to save encrypted polish revision %d: %w", po.ID, result.Error)
+			}
+			migrated += result.RowsAffected
+		}
+
+		logger.FromContext(ctx).Info("polish revisions encrypted", zap.Int64("migrated", migrated), zap.Int64("last_id", lastID))
+	}
+}
+
+// owners 查询一批主记录的用户ID
+func (m *ContentMigrator) owners(ctx context.Context, recordIDs []int64) (map[int64]int64, error) {
+	var rows []struct {
+		ID     int64
+		UserID int64
+	}
+	if err := m.db.WithContext(ctx).Unscoped().Model(&PolishRecordPO{}).
+		Select("id, user_id").Where("id IN ?", recordIDs).
+		Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to get owners of polish records: %w", err)
+	}
+
+	owners := make(map[int64]int64, len(rows))
+	for _, row := range rows {
+		owners[row.ID] = row.UserID
+	}
+	return owners, nil
+}
@@ -0,0 +1,129 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+
+	"paper_ai/internal/infrastructure/encryption"
+	"paper_ai/pkg/logger"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// dataKeyStoreImpl 内容加密数据密钥存储实现
+type dataKeyStoreImpl struct {
+	db *gorm.DB
+}
+
+// NewDataKeyStore 创建数据密钥存储实现
+func NewDataKeyStore(db *gorm.DB) encryption.KeyStore {
+	return &dataKeyStoreImpl{db: db}
+}
+
+// GetActive 获取用户当前有效的数据密钥
+func (s *dataKeyStoreImpl) GetActive(ctx context.Context, userID int64) (*encryption.WrappedKey, error) {
+	var pos []*DataKeyPO
+	if err := s.db.WithContext(ctx).Where("user_id = ? AND active", userID).Limit(1).Find(&pos).Error; err != nil {
+		logger.Error("failed to get active data key", zap.Int64("user_id", userID), zap.Error(err))
+		return nil, fmt.Errorf("failed to get active data key: %w", err)
+	}
+
+	if len(pos) == 0 {
+		return nil, nil
+	}
+	return pos[0].ToWrappedKey(), nil
+}
+
+// Get 根据 ID 获取数据密钥
+func (s *dataKeyStoreImpl) Get(ctx context.Context, id int64) (*encryption.WrappedKey, error) {
+	var po DataKeyPO
+	err := s.db.WithContext(ctx).First(&po, id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("data key not found: id=%d", id)
+		}
+		logger.Error("failed to get data key", zap.Int64("id", id), zap.Error(err))
+		return nil, fmt.Errorf("failed to get data key: %w", err)
+	}
+
+	return po.ToWrappedKey(), nil
+}
+
+// CreateActive 保存用户的新有效密钥，并发创建时以先保存的为准（每个用户最多一个有效密钥由唯一索引保证）
+func (s *dataKeyStoreImpl) CreateActive(ctx context.Context, key *encryption.WrappedKey) (*encryption.WrappedKey, error) {
+	var pos []*DataKeyPO
+	err := s.db.WithContext(ctx).Raw(`
+		INSERT INTO encryption_data_keys (user_id, master_key_id, wrapped_key, active)
+		VALUES (?, ?, ?, TRUE)
+		ON CONFLICT (user_id) WHERE active DO NOTHING
+		RETURNING *`,
+		key.UserID, key.MasterKeyID, key.Wrapped,
+	).Scan(&pos).Error
+	if err != nil {
+		logger.Error("failed to create data key", zap.Int64("user_id", key.UserID), zap.Error(err))
+		return nil, fmt.Errorf("failed to create data key: %w", err)
+	}
+
+	if len(pos) > 0 {
+		return pos[0].ToWrappedKey(), nil
+	}
+
+	existing, err := s.GetActive(ctx, key.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, fmt.Errorf("data key of user %d was neither created nor found", key.UserID)
+	}
+	return existing, nil
+}
+
+// ListNotWrappedBy 列出不是由 masterKeyID 包装的数据密钥
+func (s *dataKeyStoreImpl) ListNotWrappedBy(ctx context.Context, masterKeyID string) ([]*encryption.WrappedKey, error) {
+	var pos []*DataKeyPO
+	if err := s.db.WithContext(ctx).Where("master_key_id <> ?", masterKeyID).Order("id").Find(&pos).Error; err != nil {
+		logger.Error("failed to list data keys", zap.Error(err))
+		return nil, fmt.Errorf("failed to list data keys: %w", err)
+	}
+
+	keys := make([]*encryption.WrappedKey, len(pos))
+	for i, po := range pos {
+		keys[i] = po.ToWrappedKey()
+	}
+	return keys, nil
+}
+
+// UpdateWrapped 更新数据密钥的包装结果
+func (s *dataKeyStoreImpl) UpdateWrapped(ctx context.Context, id int64, masterKeyID string, wrapped []byte) error {
+	result := s.db.WithContext(ctx).Model(&DataKeyPO{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"master_key_id": masterKeyID,
+		"wrapped_key":   wrapped,
+	})
+	if result.Error != nil {
+		logger.Error("failed to update data key", zap.Int64("id", id), zap.Error(result.Error))
+		return fmt.Errorf("failed to update data key: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("data key not found: id=%d", id)
+	}
+
+	return nil
+}
+
+// Deactivate 停用数据密钥（userID 为 nil 时停用所有用户的）
+func (s *dataKeyStoreImpl) Deactivate(ctx context.Context, userID *int64) (int64, error) {
+	query := s.db.WithContext(ctx).Model(&DataKeyPO{}).Where("active")
+	if userID != nil {
+		query = query.Where("user_id = ?", *userID)
+	}
+
+	result := query.Update("active", false)
+	if result.Error != nil {
+		logger.Error("failed to deactivate data keys", zap.Error(result.Error))
+		return 0, fmt.Errorf("failed to deactivate data keys: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,156 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"paper_ai/internal/domain/entity"
+	"paper_ai/internal/domain/repository"
+	"paper_ai/pkg/logger"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// glossaryRepositoryImpl 术语表仓储实现
+type glossaryRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewGlossaryRepository 创建术语表仓储实现
+func NewGlossaryRepository(db *gorm.DB) repository.GlossaryRepository {
+	return &glossaryRepositoryImpl{db: db}
+}
+
+// Create 创建术语
+func (r *glossaryRepositoryImpl) Create(ctx context.Context, term *entity.GlossaryTerm) error {
+	po := &GlossaryTermPO{}
+	po.FromEntity(term)
+
+	if err := r.db.WithContext(ctx).Create(po).Error; err != nil {
+		logger.Error("failed to create glossary term", zap.Error(err))
+		return fmt.Errorf("failed to create glossary term: %w", err)
+	}
+
+	// 回写ID和时间戳
+	term.ID = po.ID
+	term.CreatedAt = po.CreatedAt
+	term.UpdatedAt = po.UpdatedAt
+
+	return nil
+}
+
+// GetByID 根据ID获取术语，不存在时返回 nil, nil
+func (r *glossaryRepositoryImpl) GetByID(ctx context.Context, id int64) (*entity.GlossaryTerm, error) {
+	var po GlossaryTermPO
+	if err := r.db.WithContext(ctx).First(&po, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		logger.Error("failed to get glossary term", zap.Int64("id", id), zap.Error(err))
+		return nil, fmt.Errorf("failed to get glossary term: %w", err)
+	}
+
+	return po.ToEntity(), nil
+}
+
+// GetBySourceTerm 获取用户在指定语言对下的同名源术语，不存在时返回 nil, nil
+func (r *glossaryRepositoryImpl) GetBySourceTerm(ctx context.Context, userID int64, sourceLanguage, targetLanguage, sourceTerm string) (*entity.GlossaryTerm, error) {
+	var po GlossaryTermPO
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ? AND source_language = ? AND target_language = ? AND source_term = ?", userID, sourceLanguage, targetLanguage, sourceTerm).
+		First(&po).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		logger.Error("failed to get glossary term by source term", zap.Int64("user_id", userID), zap.Error(err))
+		return nil, fmt.Errorf("failed to get glossary term: %w", err)
+	}
+
+	return po.ToEntity(), nil
+}
+
+// ListByUser 列出用户的所有术语（按语言对和源术语排序）
+func (r *glossaryRepositoryImpl) ListByUser(ctx context.Context, userID int64) ([]*entity.GlossaryTerm, error) {
+	var pos []*GlossaryTermPO
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).
+		Order("source_language, target_language, source_term").Find(&pos).Error; err != nil {
+		logger.Error("failed to list glossary terms", zap.Int64("user_id", userID), zap.Error(err))
+		return nil, fmt.Errorf("failed to list glossary terms: %w", err)
+	}
+
+	return glossaryTermsToEntities(pos), nil
+}
+
+// ListByLanguagePair 列出用户在指定语言对下的术语（按源术语排序）
+func (r *glossaryRepositoryImpl) ListByLanguagePair(ctx context.Context, userID int64, sourceLanguage, targetLanguage string) ([]*entity.GlossaryTerm, error) {
+	var pos []*GlossaryTermPO
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ? AND source_language = ? AND target_language = ?", userID, sourceLanguage, targetLanguage).
+		Order("source_term").Find(&pos).Error; err != nil {
+		logger.Error("failed to list glossary terms by language pair",
+			zap.Int64("user_id", userID),
+			zap.String("source_language", sourceLanguage),
+			zap.String("target_language", targetLanguage),
+			zap.Error(err))
+		return nil, fmt.Errorf("failed to list glossary terms: %w", err)
+	}
+
+	return glossaryTermsToEntities(pos), nil
+}
+
+// CountByUser 统计用户的术语数
+func (r *glossaryRepositoryImpl) CountByUser(ctx context.Context, userID int64) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&GlossaryTermPO{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		logger.Error("failed to count glossary terms", zap.Int64("user_id", userID), zap.Error(err))
+		return 0, fmt.Errorf("failed to count glossary terms: %w", err)
+	}
+	return count, nil
+}
+
+// Update 更新术语的语言对、源术语、译法和备注
+func (r *glossaryRepositoryImpl) Update(ctx context.Context, term *entity.GlossaryTerm) error {
+	result := r.db.WithContext(ctx).Model(&GlossaryTermPO{}).Where("id = ?", term.ID).Updates(map[string]interface{}{
+		"source_language": term.SourceLanguage,
+		"target_language": term.TargetLanguage,
+		"source_term":     term.SourceTerm,
+		"target_term":     term.TargetTerm,
+		"note":            term.Note,
+	})
+	if result.Error != nil {
+		logger.Error("failed to update glossary term", zap.Int64("id", term.ID), zap.Error(result.Error))
+		return fmt.Errorf("failed to update glossary term: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("glossary term not found: id=%d", term.ID)
+	}
+
+	return nil
+}
+
+// Delete 删除术语
+func (r *glossaryRepositoryImpl) Delete(ctx context.Context, id int64) error {
+	result := r.db.WithContext(ctx).Delete(&GlossaryTermPO{}, id)
+	if result.Error != nil {
+		logger.Error("failed to delete glossary term", zap.Int64("id", id), zap.Error(result.Error))
+		return fmt.Errorf("failed to delete glossary term: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("glossary term not found: id=%d", id)
+	}
+
+	return nil
+}
+
+// glossaryTermsToEntities 批量转换为领域实体
+func glossaryTermsToEntities(pos []*GlossaryTermPO) []*entity.GlossaryTerm {
+	terms := make([]*entity.GlossaryTerm, len(pos))
+	for i, po := range pos {
+		terms[i] = po.ToEntity()
+	}
+	return terms
+}
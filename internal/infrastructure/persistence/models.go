@@ -1,10 +1,13 @@
 package persistence
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"paper_ai/internal/domain/entity"
+	"paper_ai/internal/infrastructure/encryption"
 	"gorm.io/gorm"
 )
 
@@ -18,6 +21,7 @@ type PolishRecordPO struct {
 	OriginalContent string         `gorm:"type:text;not null"`
 	Style           string         `gorm:"type:varchar(20);not null;index:idx_style"`
 	Language        string         `gorm:"type:varchar(10);not null;index:idx_language"`
+	SourceLanguage  string         `gorm:"type:varchar(10);not null;default:''"` // 翻译记录的源语言（润色记录为空）
 
 	PolishedContent string         `gorm:"type:text;not null"`
 	OriginalLength  int            `gorm:"not null"`
@@ -26,7 +30,7 @@ type PolishRecordPO struct {
 	Provider        string         `gorm:"type:varchar(50);not null;index:idx_provider"`
 	Model           string         `gorm:"type:varchar(100);not null"`
 
-	Mode            string         `gorm:"type:varchar(20);not null;default:'single';index:idx_mode;comment:'润色模式: single(单版本) / multi(多版本)'"`
+	Mode            string         `gorm:"type:varchar(20);not null;default:'single';index:idx_mode;comment:'润色模式: single(单版本) / multi(多版本) / translate(翻译)'"`
 	SelectedVersion string         `gorm:"type:varchar(20);comment:'用户选择的版本类型(多版本模式下使用)'"`
 
 	ProcessTimeMs   int            `gorm:"default:0;index:idx_process_time"`
@@ -41,6 +45,29 @@ type PolishRecordPO struct {
 	RejectedChanges *string `gorm:"type:jsonb"` // 用户拒绝的修改ID列表（指针类型，允许NULL）
 	FinalContent    string  `gorm:"type:text"`  // 用户最终确认的文本（原文+接受的修改）
 
+	// 置信度信号
+	TokenLogProbs *string `gorm:"type:jsonb"` // token 对数概率JSON（指针类型，允许NULL）
+
+	// 按句组合多版本的句子来源
+	SentenceSources *string `gorm:"type:jsonb"` // 句子来源JSON（指针类型，允许NULL）
+
+	// 不保存内容模式
+	ContentOmitted bool `gorm:"not null;default:false"` // 是否未保存内容
+
+	// 内容加密（原文、润色结果、最终文本、对比数据、token 对数概率）
+	EncryptionKeyID int64 `gorm:"not null;default:0"` // 数据密钥ID（0=明文）
+
+	// 选区润色
+	SelectionStart *int // 润色的原文片段起始位置（基于 rune，NULL=润色整段）
+	SelectionEnd   *int // 润色的原文片段结束位置（不包含）
+
+	// 重新润色的来源记录
+	ParentTraceID *string `gorm:"type:varchar(20)"` // 来源记录TraceID（NULL=不是重新润色产生的记录）
+
+	// 整理信息
+	ProjectID  *int64 `gorm:"index:idx_project_id_record"` // 所属项目ID（NULL=未归属项目）
+	IsFavorite bool   `gorm:"not null;default:false"`      // 是否收藏
+
 	CreatedAt       time.Time      `gorm:"autoCreateTime;index:idx_created_at"`
 	UpdatedAt       time.Time      `gorm:"autoUpdateTime"`
 	DeletedAt       gorm.DeletedAt `gorm:"index"` // 软删除支持
@@ -51,16 +78,23 @@ func (PolishRecordPO) TableName() string {
 	return "polish_records"
 }
 
-// ToEntity 转换为领域实体
-func (po *PolishRecordPO) ToEntity() *entity.PolishRecord {
+// ToEntity 转换为领域实体（加密的内容字段解密后返回）
+func (po *PolishRecordPO) ToEntity(ctx context.Context) (*entity.PolishRecord, error) {
+	c, err := openerFor(ctx, po.EncryptionKeyID)
+	if err != nil {
+		return nil, err
+	}
+	comparisonData := c.openJSON("comparison_data", po.ComparisonData)
+
 	record := &entity.PolishRecord{
 		ID:              po.ID,
 		TraceID:         po.TraceID,
 		UserID:          po.UserID,
-		OriginalContent: po.OriginalContent,
+		OriginalContent: c.openText("original_content", po.OriginalContent),
 		Style:           po.Style,
 		Language:        po.Language,
-		PolishedContent: po.PolishedContent,
+		SourceLanguage:  po.SourceLanguage,
+		PolishedContent: c.openText("polished_content", po.PolishedContent),
 		OriginalLength:  po.OriginalLength,
 		PolishedLength:  po.PolishedLength,
 		Provider:        po.Provider,
@@ -71,13 +105,30 @@ func (po *PolishRecordPO) ToEntity() *entity.PolishRecord {
 		Status:          po.Status,
 		ErrorMessage:    po.ErrorMessage,
 		ComparisonData:  func() string {
-			if po.ComparisonData != nil {
-				return *po.ComparisonData
+			if comparisonData != nil {
+				return *comparisonData
 			}
 			return ""
 		}(),
 		ChangesCount:    po.ChangesCount,
-		FinalContent:    po.FinalContent,
+		FinalContent:    c.openText("final_content", po.FinalContent),
+		TokenLogProbs:   unmarshalTokenLogProbs(c.openJSON("token_log_probs", po.TokenLogProbs)),
+		SentenceSources: unmarshalSentenceSources(po.SentenceSources),
+		ContentOmitted:  po.ContentOmitted,
+		Selection:       func() *entity.TextSelection {
+			if po.SelectionStart != nil && po.SelectionEnd != nil {
+				return &entity.TextSelection{Start: *po.SelectionStart, End: *po.SelectionEnd}
+			}
+			return nil
+		}(),
+		ParentTraceID:   func() string {
+			if po.ParentTraceID != nil {
+				return *po.ParentTraceID
+			}
+			return ""
+		}(),
+		ProjectID:       po.ProjectID,
+		IsFavorite:      po.IsFavorite,
 		CreatedAt:       po.CreatedAt,
 		UpdatedAt:       po.UpdatedAt,
 	}
@@ -105,18 +156,24 @@ func (po *PolishRecordPO) ToEntity() *entity.PolishRecord {
 		record.RejectedChanges = []string{}
 	}
 
-	return record
+	if c.err != nil {
+		return nil, fmt.Errorf("failed to decrypt polish record %d: %w", po.ID, c.err)
+	}
+	return record, nil
 }
 
-// FromEntity 从领域实体创建PO
-func (po *PolishRecordPO) FromEntity(e *entity.PolishRecord) {
+// FromEntity 从领域实体创建PO（启用内容加密时用记录所属用户的数据密钥加密内容字段）
+func (po *PolishRecordPO) FromEntity(ctx context.Context, e *entity.PolishRecord) error {
+	c := sealerFor(ctx, e.UserID)
+
 	po.ID = e.ID
 	po.TraceID = e.TraceID
 	po.UserID = e.UserID
-	po.OriginalContent = e.OriginalContent
+	po.OriginalContent = c.sealText("original_content", e.OriginalContent)
 	po.Style = e.Style
 	po.Language = e.Language
-	po.PolishedContent = e.PolishedContent
+	po.SourceLanguage = e.SourceLanguage
+	po.PolishedContent = c.sealText("polished_content", e.PolishedContent)
 	po.OriginalLength = e.OriginalLength
 	po.PolishedLength = e.PolishedLength
 	po.Provider = e.Provider
@@ -129,13 +186,30 @@ func (po *PolishRecordPO) FromEntity(e *entity.PolishRecord) {
 
 	// 处理 ComparisonData JSONB 字段（空字符串设为 nil）
 	if e.ComparisonData != "" {
-		po.ComparisonData = &e.ComparisonData
+		po.ComparisonData = c.sealJSON("comparison_data", &e.ComparisonData)
 	} else {
 		po.ComparisonData = nil
 	}
 
 	po.ChangesCount = e.ChangesCount
-	po.FinalContent = e.FinalContent
+	po.FinalContent = c.sealText("final_content", e.FinalContent)
+	po.TokenLogProbs = c.sealJSON("token_log_probs", marshalTokenLogProbs(e.TokenLogProbs))
+	po.SentenceSources = marshalSentenceSources(e.SentenceSources)
+	po.ContentOmitted = e.ContentOmitted
+	if e.Selection != nil {
+		start, end := e.Selection.Start, e.Selection.End
+		po.SelectionStart, po.SelectionEnd = &start, &end
+	} else {
+		po.SelectionStart, po.SelectionEnd = nil, nil
+	}
+	if e.ParentTraceID != "" {
+		parentTraceID := e.ParentTraceID
+		po.ParentTraceID = &parentTraceID
+	} else {
+		po.ParentTraceID = nil
+	}
+	po.ProjectID = e.ProjectID
+	po.IsFavorite = e.IsFavorite
 	po.CreatedAt = e.CreatedAt
 	po.UpdatedAt = e.UpdatedAt
 
@@ -163,15 +237,25 @@ func (po *PolishRecordPO) FromEntity(e *entity.PolishRecord) {
 	} else {
 		po.RejectedChanges = nil
 	}
+
+	po.EncryptionKeyID = c.keyID()
+	if c.err != nil {
+		return fmt.Errorf("failed to encrypt polish record: %w", c.err)
+	}
+	return nil
 }
 
 // ToEntityList 批量转换为实体列表
-func ToEntityList(pos []*PolishRecordPO) []*entity.PolishRecord {
+func ToEntityList(ctx context.Context, pos []*PolishRecordPO) ([]*entity.PolishRecord, error) {
 	entities := make([]*entity.PolishRecord, len(pos))
 	for i, po := range pos {
-		entities[i] = po.ToEntity()
+		record, err := po.ToEntity(ctx)
+		if err != nil {
+			return nil, err
+		}
+		entities[i] = record
 	}
-	return entities
+	return entities, nil
 }
 
 // UserPO 用户持久化对象
@@ -193,6 +277,10 @@ type UserPO struct {
 	EnableMultiVersion bool `gorm:"default:false;index:idx_enable_multi_version;comment:'是否启用多版本功能'"`
 	MultiVersionQuota  int  `gorm:"default:0;comment:'多版本配额(0=无限)'"`
 
+	// 隐私设置
+	RetentionDays  int  `gorm:"not null;default:0"`     // 润色历史保留天数（0=不按用户设置删除）
+	NoStoreContent bool `gorm:"not null;default:false"` // 不保存内容模式
+
 	CreatedAt        time.Time      `gorm:"autoCreateTime;index:idx_created_at"`
 	UpdatedAt        time.Time      `gorm:"autoUpdateTime"`
 	DeletedAt        gorm.DeletedAt `gorm:"index"`
@@ -220,6 +308,8 @@ func (po *UserPO) ToEntity() *entity.User {
 		FailedLoginCount: po.FailedLoginCount,
 		EnableMultiVersion: po.EnableMultiVersion,
 		MultiVersionQuota: po.MultiVersionQuota,
+		RetentionDays:    po.RetentionDays,
+		NoStoreContent:   po.NoStoreContent,
 		CreatedAt:        po.CreatedAt,
 		UpdatedAt:        po.UpdatedAt,
 	}
@@ -241,6 +331,8 @@ func (po *UserPO) FromEntity(e *entity.User) {
 	po.FailedLoginCount = e.FailedLoginCount
 	po.EnableMultiVersion = e.EnableMultiVersion
 	po.MultiVersionQuota = e.MultiVersionQuota
+	po.RetentionDays = e.RetentionDays
+	po.NoStoreContent = e.NoStoreContent
 	po.CreatedAt = e.CreatedAt
 	po.UpdatedAt = e.UpdatedAt
 }
@@ -310,6 +402,7 @@ type PolishVersionPO struct {
 	PolishedContent string  `gorm:"type:text;not null"`
 	PolishedLength  int     `gorm:"not null"`
 	Suggestions     *string `gorm:"type:jsonb"` // JSON数组
+	TokenLogProbs   *string `gorm:"type:jsonb"` // token 对数概率JSON
 
 	// AI信息
 	ModelUsed string `gorm:"type:varchar(64);not null"`
@@ -322,6 +415,9 @@ type PolishVersionPO struct {
 	Status       string `gorm:"type:varchar(20);not null;default:'success'"`
 	ErrorMessage string `gorm:"type:text"`
 
+	// 内容加密（润色结果、建议、token 对数概率）
+	EncryptionKeyID int64 `gorm:"not null;default:0"` // 数据密钥ID（0=明文）
+
 	// 时间戳
 	CreatedAt time.Time `gorm:"autoCreateTime"`
 }
@@ -331,26 +427,33 @@ func (PolishVersionPO) TableName() string {
 	return "polish_versions"
 }
 
-// ToEntity 转换为领域实体
-func (po *PolishVersionPO) ToEntity() *entity.PolishVersion {
+// ToEntity 转换为领域实体（加密的内容字段解密后返回）
+func (po *PolishVersionPO) ToEntity(ctx context.Context) (*entity.PolishVersion, error) {
+	c, err := openerFor(ctx, po.EncryptionKeyID)
+	if err != nil {
+		return nil, err
+	}
+	suggestionsJSON := c.openJSON("suggestions", po.Suggestions)
+
 	version := &entity.PolishVersion{
 		ID:              po.ID,
 		RecordID:        po.RecordID,
 		VersionType:     po.VersionType,
-		PolishedContent: po.PolishedContent,
+		PolishedContent: c.openText("polished_content", po.PolishedContent),
 		PolishedLength:  po.PolishedLength,
 		ModelUsed:       po.ModelUsed,
 		PromptID:        po.PromptID,
 		ProcessTimeMs:   po.ProcessTimeMs,
 		Status:          po.Status,
 		ErrorMessage:    po.ErrorMessage,
+		TokenLogProbs:   unmarshalTokenLogProbs(c.openJSON("token_log_probs", po.TokenLogProbs)),
 		CreatedAt:       po.CreatedAt,
 	}
 
 	// 解析 JSON 数组
-	if po.Suggestions != nil && *po.Suggestions != "" {
+	if suggestionsJSON != nil && *suggestionsJSON != "" {
 		var suggestions []string
-		if err := json.Unmarshal([]byte(*po.Suggestions), &suggestions); err == nil {
+		if err := json.Unmarshal([]byte(*suggestionsJSON), &suggestions); err == nil {
 			version.Suggestions = suggestions
 		} else {
 			version.Suggestions = []string{}
@@ -359,21 +462,28 @@ func (po *PolishVersionPO) ToEntity() *entity.PolishVersion {
 		version.Suggestions = []string{}
 	}
 
-	return version
+	if c.err != nil {
+		return nil, fmt.Errorf("failed to decrypt polish version %d: %w", po.ID, c.err)
+	}
+	return version, nil
 }
 
 // FromEntity 从领域实体创建PO
-func (po *PolishVersionPO) FromEntity(e *entity.PolishVersion) {
+// ownerID 为主记录所属用户，启用内容加密时用该用户的数据密钥加密内容字段
+func (po *PolishVersionPO) FromEntity(ctx context.Context, e *entity.PolishVersion, ownerID int64) error {
+	c := sealerFor(ctx, ownerID)
+
 	po.ID = e.ID
 	po.RecordID = e.RecordID
 	po.VersionType = e.VersionType
-	po.PolishedContent = e.PolishedContent
+	po.PolishedContent = c.sealText("polished_content", e.PolishedContent)
 	po.PolishedLength = e.PolishedLength
 	po.ModelUsed = e.ModelUsed
 	po.PromptID = e.PromptID
 	po.ProcessTimeMs = e.ProcessTimeMs
 	po.Status = e.Status
 	po.ErrorMessage = e.ErrorMessage
+	po.TokenLogProbs = c.sealJSON("token_log_probs", marshalTokenLogProbs(e.TokenLogProbs))
 	po.CreatedAt = e.CreatedAt
 
 	// 序列化 JSON 数组
@@ -381,13 +491,116 @@ func (po *PolishVersionPO) FromEntity(e *entity.PolishVersion) {
 		jsonBytes, err := json.Marshal(e.Suggestions)
 		if err == nil {
 			jsonStr := string(jsonBytes)
-			po.Suggestions = &jsonStr
+			po.Suggestions = c.sealJSON("suggestions", &jsonStr)
 		} else {
 			po.Suggestions = nil
 		}
 	} else {
 		po.Suggestions = nil
 	}
+
+	po.EncryptionKeyID = c.keyID()
+	if c.err != nil {
+		return fmt.Errorf("failed to encrypt polish version: %w", c.err)
+	}
+	return nil
+}
+
+// PolishRevisionPO 多轮修改记录持久化对象
+type PolishRevisionPO struct {
+	ID       int64 `gorm:"primaryKey;autoIncrement"`
+	RecordID int64 `gorm:"not null;uniqueIndex:idx_record_revision"`
+	Revision int   `gorm:"not null;uniqueIndex:idx_record_revision"`
+
+	Instruction string `gorm:"type:text;not null"`
+	BaseContent string `gorm:"type:text;not null"`
+
+	PolishedContent string  `gorm:"type:text;not null;default:''"`
+	PolishedLength  int     `gorm:"not null;default:0"`
+	ComparisonData  *string `gorm:"type:jsonb"` // 对比数据JSON（指针类型，允许NULL）
+	ChangesCount    int     `gorm:"not null;default:0"`
+
+	Provider      string `gorm:"type:varchar(50);not null;default:''"`
+	Model         string `gorm:"type:varchar(100);not null;default:''"`
+	ProcessTimeMs int    `gorm:"not null;default:0"`
+
+	Status       string `gorm:"type:varchar(20);not null;default:'success'"`
+	ErrorMessage string `gorm:"type:text;not null;default:''"`
+
+	// 内容加密（指令、修改前文本、修改结果、对比数据）
+	EncryptionKeyID int64 `gorm:"not null;default:0"` // 数据密钥ID（0=明文）
+
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// TableName 指定表名
+func (PolishRevisionPO) TableName() string {
+	return "polish_revisions"
+}
+
+// ToEntity 转换为领域实体（加密的内容字段解密后返回）
+func (po *PolishRevisionPO) ToEntity(ctx context.Context) (*entity.PolishRevision, error) {
+	c, err := openerFor(ctx, po.EncryptionKeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	revision := &entity.PolishRevision{
+		ID:              po.ID,
+		RecordID:        po.RecordID,
+		Revision:        po.Revision,
+		Instruction:     c.openText("instruction", po.Instruction),
+		BaseContent:     c.openText("base_content", po.BaseContent),
+		PolishedContent: c.openText("polished_content", po.PolishedContent),
+		PolishedLength:  po.PolishedLength,
+		ChangesCount:    po.ChangesCount,
+		Provider:        po.Provider,
+		Model:           po.Model,
+		ProcessTimeMs:   po.ProcessTimeMs,
+		Status:          po.Status,
+		ErrorMessage:    po.ErrorMessage,
+		CreatedAt:       po.CreatedAt,
+	}
+	if comparisonData := c.openJSON("comparison_data", po.ComparisonData); comparisonData != nil {
+		revision.ComparisonData = *comparisonData
+	}
+
+	if c.err != nil {
+		return nil, fmt.Errorf("failed to decrypt polish revision %d: %w", po.ID, c.err)
+	}
+	return revision, nil
+}
+
+// FromEntity 从领域实体创建PO
+// ownerID 为主记录所属用户，启用内容加密时用该用户的数据密钥加密内容字段
+func (po *PolishRevisionPO) FromEntity(ctx context.Context, e *entity.PolishRevision, ownerID int64) error {
+	c := sealerFor(ctx, ownerID)
+
+	po.ID = e.ID
+	po.RecordID = e.RecordID
+	po.Revision = e.Revision
+	po.Instruction = c.sealText("instruction", e.Instruction)
+	po.BaseContent = c.sealText("base_content", e.BaseContent)
+	po.PolishedContent = c.sealText("polished_content", e.PolishedContent)
+	po.PolishedLength = e.PolishedLength
+	if e.ComparisonData != "" {
+		po.ComparisonData = c.sealJSON("comparison_data", &e.ComparisonData)
+	} else {
+		po.ComparisonData = nil
+	}
+	po.ChangesCount = e.ChangesCount
+	po.Provider = e.Provider
+	po.Model = e.Model
+	po.ProcessTimeMs = e.ProcessTimeMs
+	po.Status = e.Status
+	po.ErrorMessage = e.ErrorMessage
+	po.CreatedAt = e.CreatedAt
+
+	po.EncryptionKeyID = c.keyID()
+	if c.err != nil {
+		return fmt.Errorf("failed to encrypt polish revision: %w", c.err)
+	}
+	return nil
 }
 
 // PolishPromptPO Prompt模板持久化对象
@@ -504,3 +717,314 @@ func (po *PolishPromptPO) FromEntity(e *entity.PolishPrompt) {
 		po.Tags = nil
 	}
 }
+
+// ChangeFeedbackPO 修改反馈持久化对象
+type ChangeFeedbackPO struct {
+	ID         int64     `gorm:"primaryKey;autoIncrement"`
+	UserID     int64     `gorm:"not null;index:idx_user_id_feedback"`
+	TraceID    string    `gorm:"type:varchar(20);not null"`
+	ChangeType string    `gorm:"type:varchar(20);not null;index:idx_change_type_feedback"`
+	Signature  string    `gorm:"type:varchar(64);not null;index:idx_signature_feedback"`
+	Action     string    `gorm:"type:varchar(20);not null"`
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+}
+
+// TableName 指定表名
+func (ChangeFeedbackPO) TableName() string {
+	return "change_feedbacks"
+}
+
+// ToEntity 转换为领域实体
+func (po *ChangeFeedbackPO) ToEntity() *entity.ChangeFeedback {
+	return &entity.ChangeFeedback{
+		ID:         po.ID,
+		UserID:     po.UserID,
+		TraceID:    po.TraceID,
+		ChangeType: po.ChangeType,
+		Signature:  po.Signature,
+		Action:     po.Action,
+		CreatedAt:  po.CreatedAt,
+	}
+}
+
+// FromEntity 从领域实体创建PO
+func (po *ChangeFeedbackPO) FromEntity(e *entity.ChangeFeedback) {
+	po.ID = e.ID
+	po.UserID = e.UserID
+	po.TraceID = e.TraceID
+	po.ChangeType = e.ChangeType
+	po.Signature = e.Signature
+	po.Action = e.Action
+	po.CreatedAt = e.CreatedAt
+}
+
+// ComparisonActionPO 对比操作日志持久化对象
+type ComparisonActionPO struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement"`
+	RecordID  int64     `gorm:"not null;uniqueIndex:idx_record_seq_action,priority:1"`
+	TraceID   string    `gorm:"type:varchar(20);not null"`
+	UserID    int64     `gorm:"not null"`
+	Seq       int       `gorm:"not null;uniqueIndex:idx_record_seq_action,priority:2"`
+	Action    string    `gorm:"type:varchar(20);not null"`
+	TargetSeq int       `gorm:"default:0"`
+	Changes   string    `gorm:"type:jsonb;not null"` // 状态变化JSON数组
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// TableName 指定表名
+func (ComparisonActionPO) TableName() string {
+	return "comparison_actions"
+}
+
+// ToEntity 转换为领域实体
+func (po *ComparisonActionPO) ToEntity() *entity.ComparisonAction {
+	action := &entity.ComparisonAction{
+		ID:        po.ID,
+		RecordID:  po.RecordID,
+		TraceID:   po.TraceID,
+		UserID:    po.UserID,
+		Seq:       po.Seq,
+		Action:    po.Action,
+		TargetSeq: po.TargetSeq,
+		CreatedAt: po.CreatedAt,
+	}
+
+	if err := json.Unmarshal([]byte(po.Changes), &action.Changes); err != nil {
+		action.Changes = []entity.ActionChangeRecord{}
+	}
+
+	return action
+}
+
+// FromEntity 从领域实体创建PO
+func (po *ComparisonActionPO) FromEntity(e *entity.ComparisonAction) {
+	po.ID = e.ID
+	po.RecordID = e.RecordID
+	po.TraceID = e.TraceID
+	po.UserID = e.UserID
+	po.Seq = e.Seq
+	po.Action = e.Action
+	po.TargetSeq = e.TargetSeq
+	po.CreatedAt = e.CreatedAt
+
+	changes := e.Changes
+	if changes == nil {
+		changes = []entity.ActionChangeRecord{}
+	}
+	jsonBytes, err := json.Marshal(changes)
+	if err != nil {
+		jsonBytes = []byte("[]")
+	}
+	po.Changes = string(jsonBytes)
+}
+
+// DataKeyPO 内容加密数据密钥持久化对象
+type DataKeyPO struct {
+	ID          int64     `gorm:"primaryKey;autoIncrement"`
+	UserID      int64     `gorm:"not null"`
+	MasterKeyID string    `gorm:"type:varchar(64);not null"`
+	WrappedKey  []byte    `gorm:"type:bytea;not null"`
+	Active      bool      `gorm:"not null"`
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime"`
+}
+
+// TableName 指定表名
+func (DataKeyPO) TableName() string {
+	return "encryption_data_keys"
+}
+
+// ToWrappedKey 转换为密钥环使用的数据密钥
+func (po *DataKeyPO) ToWrappedKey() *encryption.WrappedKey {
+	return &encryption.WrappedKey{
+		ID:          po.ID,
+		UserID:      po.UserID,
+		MasterKeyID: po.MasterKeyID,
+		Wrapped:     po.WrappedKey,
+		Active:      po.Active,
+		CreatedAt:   po.CreatedAt,
+	}
+}
+
+// marshalTokenLogProbs 序列化 token 对数概率（为空时返回 nil，数据库存储 NULL）
+func marshalTokenLogProbs(tokens []entity.TokenLogProb) *string {
+	if len(tokens) == 0 {
+		return nil
+	}
+	jsonBytes, err := json.Marshal(tokens)
+	if err != nil {
+		return nil
+	}
+	jsonStr := string(jsonBytes)
+	return &jsonStr
+}
+
+// unmarshalTokenLogProbs 解析 token 对数概率
+func unmarshalTokenLogProbs(data *string) []entity.TokenLogProb {
+	if data == nil || *data == "" {
+		return nil
+	}
+	var tokens []entity.TokenLogProb
+	if err := json.Unmarshal([]byte(*data), &tokens); err != nil {
+		return nil
+	}
+	return tokens
+}
+
+// marshalSentenceSources 序列化句子来源
+// nil 返回 nil（不更新该字段）；非 nil 的空切片序列化为 []，用于清除之前的按句组合结果
+func marshalSentenceSources(sources []entity.SentenceSource) *string {
+	if sources == nil {
+		return nil
+	}
+	jsonBytes, err := json.Marshal(sources)
+	if err != nil {
+		return nil
+	}
+	jsonStr := string(jsonBytes)
+	return &jsonStr
+}
+
+// unmarshalSentenceSources 解析句子来源
+func unmarshalSentenceSources(data *string) []entity.SentenceSource {
+	if data == nil || *data == "" {
+		return nil
+	}
+	var sources []entity.SentenceSource
+	if err := json.Unmarshal([]byte(*data), &sources); err != nil {
+		return nil
+	}
+	if len(sources) == 0 {
+		return nil
+	}
+	return sources
+}
+
+// ProjectPO 项目持久化对象
+type ProjectPO struct {
+	ID          int64     `gorm:"primaryKey;autoIncrement"`
+	UserID      int64     `gorm:"not null;uniqueIndex:idx_user_name_project,priority:1"`
+	Name        string    `gorm:"type:varchar(100);not null;uniqueIndex:idx_user_name_project,priority:2"`
+	Description string    `gorm:"type:text;not null;default:''"`
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime"`
+}
+
+// TableName 指定表名
+func (ProjectPO) TableName() string {
+	return "projects"
+}
+
+// ToEntity 转换为领域实体
+func (po *ProjectPO) ToEntity() *entity.Project {
+	return &entity.Project{
+		ID:          po.ID,
+		UserID:      po.UserID,
+		Name:        po.Name,
+		Description: po.Description,
+		CreatedAt:   po.CreatedAt,
+		UpdatedAt:   po.UpdatedAt,
+	}
+}
+
+// FromEntity 从领域实体创建PO
+func (po *ProjectPO) FromEntity(e *entity.Project) {
+	po.ID = e.ID
+	po.UserID = e.UserID
+	po.Name = e.Name
+	po.Description = e.Description
+	po.CreatedAt = e.CreatedAt
+	po.UpdatedAt = e.UpdatedAt
+}
+
+// TagPO 标签持久化对象
+type TagPO struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement"`
+	UserID    int64     `gorm:"not null;uniqueIndex:idx_user_name_tag,priority:1"`
+	Name      string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_user_name_tag,priority:2"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+// TableName 指定表名
+func (TagPO) TableName() string {
+	return "tags"
+}
+
+// ToEntity 转换为领域实体
+func (po *TagPO) ToEntity() *entity.Tag {
+	return &entity.Tag{
+		ID:        po.ID,
+		UserID:    po.UserID,
+		Name:      po.Name,
+		CreatedAt: po.CreatedAt,
+		UpdatedAt: po.UpdatedAt,
+	}
+}
+
+// FromEntity 从领域实体创建PO
+func (po *TagPO) FromEntity(e *entity.Tag) {
+	po.ID = e.ID
+	po.UserID = e.UserID
+	po.Name = e.Name
+	po.CreatedAt = e.CreatedAt
+	po.UpdatedAt = e.UpdatedAt
+}
+
+// RecordTagPO 润色记录标签关联持久化对象
+type RecordTagPO struct {
+	RecordID  int64     `gorm:"primaryKey"`
+	TagID     int64     `gorm:"primaryKey;index:idx_tag_id_record_tag"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// TableName 指定表名
+func (RecordTagPO) TableName() string {
+	return "polish_record_tags"
+}
+
+// GlossaryTermPO 术语表条目持久化对象
+type GlossaryTermPO struct {
+	ID             int64     `gorm:"primaryKey;autoIncrement"`
+	UserID         int64     `gorm:"not null;uniqueIndex:idx_user_language_source_term,priority:1"`
+	SourceLanguage string    `gorm:"type:varchar(10);not null;uniqueIndex:idx_user_language_source_term,priority:2"`
+	TargetLanguage string    `gorm:"type:varchar(10);not null;uniqueIndex:idx_user_language_source_term,priority:3"`
+	SourceTerm     string    `gorm:"type:varchar(200);not null;uniqueIndex:idx_user_language_source_term,priority:4"`
+	TargetTerm     string    `gorm:"type:varchar(200);not null"`
+	Note           string    `gorm:"type:varchar(500);not null;default:''"`
+	CreatedAt      time.Time `gorm:"autoCreateTime"`
+	UpdatedAt      time.Time `gorm:"autoUpdateTime"`
+}
+
+// TableName 指定表名
+func (GlossaryTermPO) TableName() string {
+	return "glossary_terms"
+}
+
+// ToEntity 转换为领域实体
+func (po *GlossaryTermPO) ToEntity() *entity.GlossaryTerm {
+	return &entity.GlossaryTerm{
+		ID:             po.ID,
+		UserID:         po.UserID,
+		SourceLanguage: po.SourceLanguage,
+		TargetLanguage: po.TargetLanguage,
+		SourceTerm:     po.SourceTerm,
+		TargetTerm:     po.TargetTerm,
+		Note:           po.Note,
+		CreatedAt:      po.CreatedAt,
+		UpdatedAt:      po.UpdatedAt,
+	}
+}
+
+// FromEntity 从领域实体创建PO
+func (po *GlossaryTermPO) FromEntity(e *entity.GlossaryTerm) {
+	po.ID = e.ID
+	po.UserID = e.UserID
+	po.SourceLanguage = e.SourceLanguage
+	po.TargetLanguage = e.TargetLanguage
+	po.SourceTerm = e.SourceTerm
+	po.TargetTerm = e.TargetTerm
+	po.Note = e.Note
+	po.CreatedAt = e.CreatedAt
+	po.UpdatedAt = e.UpdatedAt
+}
@@ -59,72 +59,55 @@ func (r *polishPromptRepositoryImpl) GetByID(ctx context.Context, id int64) (*en
 // 查询策略（按优先级）：
 // 1. 精确匹配：versionType + language + style
 // 2. 降级匹配：versionType + language + style='all'
-// 3. 再降级：versionType + language='all' + style='all'
+// 3. 再降级：versionType + language='all' + style（没有为该语言单独配置的 Prompt 时使用通用语言 Prompt）
+// 4. 最后：versionType + language='all' + style='all'
 func (r *polishPromptRepositoryImpl) GetActive(ctx context.Context, versionType, language, style string) (*entity.PolishPrompt, error) {
-	var po PolishPromptPO
-
-	// 策略1：精确匹配
-	err := r.db.WithContext(ctx).
-		Where("version_type = ? AND language = ? AND style = ? AND is_active = ?", versionType, language, style, true).
-		Order("version DESC, weight DESC").
-		First(&po).Error
-
-	if err == nil {
-		logger.Info("found exact match prompt",
-			zap.String("version_type", versionType),
-			zap.String("language", language),
-			zap.String("style", style),
-			zap.Int64("prompt_id", po.ID))
-		return po.ToEntity(), nil
-	}
-
-	if err != gorm.ErrRecordNotFound {
-		logger.Error("failed to query exact match prompt", zap.Error(err))
-		return nil, fmt.Errorf("failed to query prompt: %w", err)
-	}
-
-	// 策略2：降级匹配（style='all'）
-	err = r.db.WithContext(ctx).
-		Where("version_type = ? AND language = ? AND style = ? AND is_active = ?", versionType, language, "all", true).
-		Order("version DESC, weight DESC").
-		First(&po).Error
-
-	if err == nil {
-		logger.Info("found style-generic prompt",
-			zap.String("version_type", versionType),
-			zap.String("language", language),
-			zap.Int64("prompt_id", po.ID))
-		return po.ToEntity(), nil
+	candidates := []struct {
+		language string
+		style    string
+		match    string
+	}{
+		{language, style, "exact"},
+		{language, "all", "style-generic"},
+		{"all", style, "language-generic"},
+		{"all", "all", "generic"},
 	}
 
-	if err != gorm.ErrRecordNotFound {
-		logger.Error("failed to query style-generic prompt", zap.Error(err))
-		return nil, fmt.Errorf("failed to query prompt: %w", err)
-	}
-
-	// 策略3：再降级（language='all' + style='all'）
-	err = r.db.WithContext(ctx).
-		Where("version_type = ? AND language = ? AND style = ? AND is_active = ?", versionType, "all", "all", true).
-		Order("version DESC, weight DESC").
-		First(&po).Error
-
-	if err == nil {
-		logger.Info("found language-generic prompt",
-			zap.String("version_type", versionType),
-			zap.Int64("prompt_id", po.ID))
-		return po.ToEntity(), nil
-	}
+	queried := make(map[string]bool, len(candidates))
+	for _, candidate := range candidates {
+		// 风格或语言本身就是 all 时，候选会重复
+		key := candidate.language + "/" + candidate.style
+		if queried[key] {
+			continue
+		}
+		queried[key] = true
+
+		var po PolishPromptPO
+		err := r.db.WithContext(ctx).
+			Where("version_type = ? AND language = ? AND style = ? AND is_active = ?", versionType, candidate.language, candidate.style, true).
+			Order("version DESC, weight DESC").
+			First(&po).Error
+
+		if err == nil {
+			logger.Info("found "+candidate.match+" prompt",
+				zap.String("version_type", versionType),
+				zap.String("language", language),
+				zap.String("style", style),
+				zap.Int64("prompt_id", po.ID))
+			return po.ToEntity(), nil
+		}
 
-	if err == gorm.ErrRecordNotFound {
-		logger.Warn("no active prompt found",
-			zap.String("version_type", versionType),
-			zap.String("language", language),
-			zap.String("style", style))
-		return nil, fmt.Errorf("no active prompt found for version_type=%s, language=%s, style=%s", versionType, language, style)
+		if err != gorm.ErrRecordNotFound {
+			logger.Error("failed to query "+candidate.match+" prompt", zap.Error(err))
+			return nil, fmt.Errorf("failed to query prompt: %w", err)
+		}
 	}
 
-	logger.Error("failed to query language-generic prompt", zap.Error(err))
-	return nil, fmt.Errorf("failed to query prompt: %w", err)
+	logger.Warn("no active prompt found",
+		zap.String("version_type", versionType),
+		zap.String("language", language),
+		zap.String("style", style))
+	return nil, fmt.Errorf("no active prompt found for version_type=%s, language=%s, style=%s", versionType, language, style)
 }
 
 // List 列出Prompts（支持过滤）
@@ -25,7 +25,10 @@ func NewPolishRepository(db *gorm.DB) repository.PolishRepository {
 // Create 创建记录
 func (r *polishRepositoryImpl) Create(ctx context.Context, record *entity.PolishRecord) error {
 	po := &PolishRecordPO{}
-	po.FromEntity(record)
+	if err := po.FromEntity(ctx, record); err != nil {
+		logger.Error("failed to encrypt polish record", zap.Error(err))
+		return err
+	}
 
 	if err := r.db.WithContext(ctx).Create(po).Error; err != nil {
 		logger.Error("failed to create polish record", zap.Error(err))
@@ -52,7 +55,7 @@ func (r *polishRepositoryImpl) GetByID(ctx context.Context, id int64) (*entity.P
 		return nil, fmt.Errorf("failed to get polish record: %w", err)
 	}
 
-	return po.ToEntity(), nil
+	return po.ToEntity(ctx)
 }
 
 // GetByTraceID 根据TraceID获取记录
@@ -67,23 +70,42 @@ func (r *polishRepositoryImpl) GetByTraceID(ctx context.Context, traceID string)
 		return nil, fmt.Errorf("failed to get polish record: %w", err)
 	}
 
-	return po.ToEntity(), nil
+	return po.ToEntity(ctx)
 }
 
 // Update 更新记录
 func (r *polishRepositoryImpl) Update(ctx context.Context, record *entity.PolishRecord) error {
 	po := &PolishRecordPO{}
-	po.FromEntity(record)
-
-	result := r.db.WithContext(ctx).Model(&PolishRecordPO{}).Where("id = ?", record.ID).Updates(po)
-	if result.Error != nil {
-		logger.Error("failed to update polish record", zap.Int64("id", record.ID), zap.Error(result.Error))
-		return fmt.Errorf("failed to update polish record: %w", result.Error)
+	if err := po.FromEntity(ctx, record); err != nil {
+		logger.Error("failed to encrypt polish record", zap.Int64("id", record.ID), zap.Error(err))
+		return err
 	}
 
-	if result.RowsAffected == 0 {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 项目和收藏只通过 SetProject/SetFavorite 修改，避免覆盖并发的整理操作；来源记录创建后不再修改
+		result := tx.Model(&PolishRecordPO{}).Where("id = ?", record.ID).Omit("project_id", "is_favorite", "parent_trace_id").Updates(po)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		// 最终文本和选区总是写入（包括空值，重新生成润色结果后需要清空）；
+		// 配置了内容加密时内容列整体重写，避免同一行混用新旧数据密钥
+		columns := []string{"final_content", "selection_start", "selection_end"}
+		if contentKeyring.Load() != nil {
+			columns = append([]string{"selection_start", "selection_end"}, recordContentColumns...)
+		}
+		return tx.Model(&PolishRecordPO{}).Where("id = ?", record.ID).Select(columns).Updates(po).Error
+	})
+	if err == gorm.ErrRecordNotFound {
 		return fmt.Errorf("polish record not found: id=%d", record.ID)
 	}
+	if err != nil {
+		logger.Error("failed to update polish record", zap.Int64("id", record.ID), zap.Error(err))
+		return fmt.Errorf("failed to update polish record: %w", err)
+	}
 
 	return nil
 }
@@ -109,18 +131,49 @@ func (r *polishRepositoryImpl) List(ctx context.Context, opts repository.QueryOp
 
 	query := r.buildQuery(ctx, opts)
 
-	// 排序
-	if opts.OrderBy != "" {
-		order := opts.OrderBy
+	// 排序（排序字段值相同时按 ID 排序，保证分页稳定）
+	column, ok := recordSortColumns[opts.OrderBy]
+	if !ok {
+		column = recordSortColumns["created_at"]
+	}
+	direction := "ASC"
+	if opts.OrderDesc {
+		direction = "DESC"
+	}
+	order := column + " " + direction + ", id " + direction
+
+	// 游标分页：从上一页最后一条记录之后开始
+	if opts.CursorMode && opts.Cursor != nil {
+		value, err := opts.Cursor.SortValue()
+		if err != nil {
+			return nil, err
+		}
+		operator := ">"
 		if opts.OrderDesc {
-			order += " DESC"
+			operator = "<"
+		}
+		query = query.Where("("+column+", id) "+operator+" (?, ?)", value, opts.Cursor.ID)
+	}
+
+	// 全文检索时优先按相关度排序，相关度相同时按原排序
+	if opts.SearchText != nil {
+		if rankOrder := searchRankOrder(*opts.SearchText, order); rankOrder != nil {
+			query = query.Order(*rankOrder)
+			order = ""
 		}
+	}
+
+	if order != "" {
 		query = query.Order(order)
 	}
 
 	// 分页
 	if opts.Limit > 0 {
-		query = query.Offset(opts.Offset).Limit(opts.Limit)
+		if opts.CursorMode {
+			query = query.Limit(opts.Limit)
+		} else {
+			query = query.Offset(opts.Offset).Limit(opts.Limit)
+		}
 	}
 
 	// 执行查询
@@ -129,7 +182,7 @@ func (r *polishRepositoryImpl) List(ctx context.Context, opts repository.QueryOp
 		return nil, fmt.Errorf("failed to list polish records: %w", err)
 	}
 
-	return ToEntityList(pos), nil
+	return ToEntityList(ctx, pos)
 }
 
 // Count 统计记录数量
@@ -151,7 +204,10 @@ func (r *polishRepositoryImpl) BatchCreate(ctx context.Context, records []*entit
 	pos := make([]*PolishRecordPO, len(records))
 	for i, record := range records {
 		po := &PolishRecordPO{}
-		po.FromEntity(record)
+		if err := po.FromEntity(ctx, record); err != nil {
+			logger.Error("failed to encrypt polish record", zap.Error(err))
+			return err
+		}
 		pos[i] = po
 	}
 
@@ -170,6 +226,14 @@ func (r *polishRepositoryImpl) BatchCreate(ctx context.Context, records []*entit
 	return nil
 }
 
+// recordSortColumns 排序字段对应的列表达式（可为空的列按 0 排序，与迁移 000009 中的索引表达式一致）
+var recordSortColumns = map[string]string{
+	"created_at":      "created_at",
+	"process_time_ms": "COALESCE(process_time_ms, 0)",
+	"original_length": "original_length",
+	"changes_count":   "COALESCE(changes_count, 0)",
+}
+
 // buildQuery 构建查询条件
 func (r *polishRepositoryImpl) buildQuery(ctx context.Context, opts repository.QueryOptions) *gorm.DB {
 	query := r.db.WithContext(ctx)
@@ -177,7 +241,7 @@ func (r *polishRepositoryImpl) buildQuery(ctx context.Context, opts repository.Q
 	// 字段选择优化
 	if opts.ExcludeText {
 		// 排除大文本字段，提高查询性能
-		query = query.Select("id, trace_id, user_id, style, language, original_length, polished_length, provider, model, process_time_ms, status, created_at, updated_at")
+		query = query.Select("id, trace_id, user_id, style, language, original_length, polished_length, provider, model, process_time_ms, status, changes_count, project_id, is_favorite, parent_trace_id, created_at, updated_at")
 	} else if len(opts.SelectFields) > 0 {
 		query = query.Select(opts.SelectFields)
 	}
@@ -212,5 +276,32 @@ func (r *polishRepositoryImpl) buildQuery(ctx context.Context, opts repository.Q
 		query = query.Where("created_at <= ?", *opts.EndTime)
 	}
 
+	// 整理信息过滤
+	if opts.ProjectID != nil {
+		if *opts.ProjectID == 0 {
+			query = query.Where("project_id IS NULL")
+		} else {
+			query = query.Where("project_id = ?", *opts.ProjectID)
+		}
+	}
+
+	if opts.TagID != nil {
+		query = query.Where("id IN (SELECT record_id FROM polish_record_tags WHERE tag_id = ?)", *opts.TagID)
+	}
+
+	if opts.Favorite != nil {
+		query = query.Where("is_favorite = ?", *opts.Favorite)
+	}
+
+	// 重新润色谱系
+	if opts.ParentTraceID != nil {
+		query = query.Where("parent_trace_id = ?", *opts.ParentTraceID)
+	}
+
+	// 全文检索
+	if opts.SearchText != nil {
+		query = applySearch(query, *opts.SearchText)
+	}
+
 	return query
 }
@@ -0,0 +1,102 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"paper_ai/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// userPurgeBatchSize 用户批量删除时每批删除的数量
+const userPurgeBatchSize = 500
+
+// MarkStaleProcessingFailed 将 before 之前创建、仍处于 processing 状态的记录标记为失败
+// 多版本润色在生成过程中进程崩溃或被终止时会留下这类记录
+func (r *polishRepositoryImpl) MarkStaleProcessingFailed(ctx context.Context, before time.Time, errorMessage string) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&PolishRecordPO{}).
+		Where("status = ? AND created_at < ?", "processing", before).
+		Updates(map[string]interface{}{
+			"status":        "failed",
+			"error_message": errorMessage,
+		})
+	if result.Error != nil {
+		logger.Error("failed to mark stale processing records", zap.Error(result.Error))
+		return 0, fmt.Errorf("failed to mark stale processing records: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
+
+// PurgeCreatedBefore 物理删除 before 之前创建的记录（包括已软删除的）
+// polish_versions、comparison_actions 通过外键 ON DELETE CASCADE 一并删除
+func (r *polishRepositoryImpl) PurgeCreatedBefore(ctx context.Context, before time.Time, batchSize int) (int64, error) {
+	return r.purgeInBatches(ctx,
+		"SELECT id FROM polish_records WHERE created_at < ?",
+		[]interface{}{before}, batchSize)
+}
+
+// PurgeByUserRetention 按用户设置的保留天数（users.retention_days > 0）物理删除过期记录
+func (r *polishRepositoryImpl) PurgeByUserRetention(ctx context.Context, batchSize int) (int64, error) {
+	return r.purgeInBatches(ctx, `
+		SELECT r.id FROM polish_records r
+		JOIN users u ON u.id = r.user_id
+		WHERE u.retention_days > 0 AND r.created_at < ? - u.retention_days * INTERVAL '1 day'`,
+		[]interface{}{time.Now()}, batchSize)
+}
+
+// PurgeByID 物理删除单条记录（包括已软删除的）
+func (r *polishRepositoryImpl) PurgeByID(ctx context.Context, id int64) error {
+	result := r.db.WithContext(ctx).Unscoped().Delete(&PolishRecordPO{}, id)
+	if result.Error != nil {
+		logger.Error("failed to purge polish record", zap.Int64("id", id), zap.Error(result.Error))
+		return fmt.Errorf("failed to purge polish record: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("polish record not found: id=%d", id)
+	}
+
+	return nil
+}
+
+// PurgeByUser 物理删除用户在 [start, end) 内创建的记录（包括已软删除的），start/end 为空表示不限
+func (r *polishRepositoryImpl) PurgeByUser(ctx context.Context, userID int64, start, end *time.Time) (int64, error) {
+	query := "SELECT id FROM polish_records WHERE user_id = ?"
+	args := []interface{}{userID}
+	if start != nil {
+		query += " AND created_at >= ?"
+		args = append(args, *start)
+	}
+	if end != nil {
+		query += " AND created_at < ?"
+		args = append(args, *end)
+	}
+
+	return r.purgeInBatches(ctx, query, args, userPurgeBatchSize)
+}
+
+// purgeInBatches 物理删除 selectIDs 查询出的记录，每批最多 batchSize 条，避免长事务和大范围锁
+func (r *polishRepositoryImpl) purgeInBatches(ctx context.Context, selectIDs string, args []interface{}, batchSize int) (int64, error) {
+	sql := "DELETE FROM polish_records WHERE id IN (" + selectIDs + " LIMIT ?)"
+	args = append(args, batchSize)
+
+	var total int64
+	for {
+		result := r.db.WithContext(ctx).Exec(sql, args...)
+		if result.Error != nil {
+			logger.Error("failed to purge polish records", zap.Int64("deleted", total), zap.Error(result.Error))
+			return total, fmt.Errorf("failed to purge polish records: %w", result.Error)
+		}
+
+		total += result.RowsAffected
+		if result.RowsAffected < int64(batchSize) {
+			return total, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+	}
+}
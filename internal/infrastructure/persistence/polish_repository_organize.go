@@ -0,0 +1,40 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+
+	"paper_ai/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// SetProject 设置记录所属项目（projectID 为 nil 表示移出项目），不修改 updated_at
+func (r *polishRepositoryImpl) SetProject(ctx context.Context, id int64, projectID *int64) error {
+	result := r.db.WithContext(ctx).Model(&PolishRecordPO{}).Where("id = ?", id).UpdateColumn("project_id", projectID)
+	if result.Error != nil {
+		logger.Error("failed to set project of polish record", zap.Int64("id", id), zap.Error(result.Error))
+		return fmt.Errorf("failed to set project of polish record: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("polish record not found: id=%d", id)
+	}
+
+	return nil
+}
+
+// SetFavorite 设置记录是否收藏，不修改 updated_at
+func (r *polishRepositoryImpl) SetFavorite(ctx context.Context, id int64, favorite bool) error {
+	result := r.db.WithContext(ctx).Model(&PolishRecordPO{}).Where("id = ?", id).UpdateColumn("is_favorite", favorite)
+	if result.Error != nil {
+		logger.Error("failed to set favorite of polish record", zap.Int64("id", id), zap.Error(result.Error))
+		return fmt.Errorf("failed to set favorite of polish record: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("polish record not found: id=%d", id)
+	}
+
+	return nil
+}
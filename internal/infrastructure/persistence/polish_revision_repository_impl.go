@@ -0,0 +1,103 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+
+	"paper_ai/internal/domain/entity"
+	"paper_ai/internal/domain/repository"
+	"paper_ai/pkg/logger"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// polishRevisionRepositoryImpl 多轮修改记录仓储实现
+type polishRevisionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewPolishRevisionRepository 创建多轮修改记录仓储实现
+func NewPolishRevisionRepository(db *gorm.DB) repository.PolishRevisionRepository {
+	return &polishRevisionRepositoryImpl{db: db}
+}
+
+// Create 创建修订，修订号为该记录当前最大修订号 + 1（并发创建时由唯一索引保证不重复）
+func (r *polishRevisionRepositoryImpl) Create(ctx context.Context, revision *entity.PolishRevision) error {
+	ownerID, err := recordOwnerForSealing(ctx, r.db, revision.RecordID)
+	if err != nil {
+		return err
+	}
+
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var maxRevision int
+		if err := tx.Model(&PolishRevisionPO{}).
+			Where("record_id = ?", revision.RecordID).
+			Select("COALESCE(MAX(revision), 0)").
+			Scan(&maxRevision).Error; err != nil {
+			return err
+		}
+
+		revision.Revision = maxRevision + 1
+
+		po := &PolishRevisionPO{}
+		if err := po.FromEntity(ctx, revision, ownerID); err != nil {
+			return err
+		}
+		if err := tx.Create(po).Error; err != nil {
+			return err
+		}
+
+		revision.ID = po.ID
+		revision.CreatedAt = po.CreatedAt
+		return nil
+	})
+	if err != nil {
+		logger.Error("failed to create polish revision", zap.Int64("record_id", revision.RecordID), zap.Error(err))
+		return fmt.Errorf("failed to create polish revision: %w", err)
+	}
+
+	return nil
+}
+
+// ListByRecordID 按修订号升序获取记录的所有修订
+func (r *polishRevisionRepositoryImpl) ListByRecordID(ctx context.Context, recordID int64) ([]*entity.PolishRevision, error) {
+	var pos []*PolishRevisionPO
+	if err := r.db.WithContext(ctx).
+		Where("record_id = ?", recordID).
+		Order("revision ASC").
+		Find(&pos).Error; err != nil {
+		logger.Error("failed to list polish revisions", zap.Int64("record_id", recordID), zap.Error(err))
+		return nil, fmt.Errorf("failed to list polish revisions: %w", err)
+	}
+
+	revisions := make([]*entity.PolishRevision, len(pos))
+	for i, po := range pos {
+		revision, err := po.ToEntity(ctx)
+		if err != nil {
+			return nil, err
+		}
+		revisions[i] = revision
+	}
+	return revisions, nil
+}
+
+// GetByRecordIDAndRevision 获取记录的指定修订，不存在时返回 nil, nil
+func (r *polishRevisionRepositoryImpl) GetByRecordIDAndRevision(ctx context.Context, recordID int64, revision int) (*entity.PolishRevision, error) {
+	var pos []*PolishRevisionPO
+	if err := r.db.WithContext(ctx).
+		Where("record_id = ? AND revision = ?", recordID, revision).
+		Limit(1).
+		Find(&pos).Error; err != nil {
+		logger.Error("failed to get polish revision",
+			zap.Int64("record_id", recordID),
+			zap.Int("revision", revision),
+			zap.Error(err))
+		return nil, fmt.Errorf("failed to get polish revision: %w", err)
+	}
+
+	if len(pos) == 0 {
+		return nil, nil
+	}
+	return pos[0].ToEntity(ctx)
+}
@@ -0,0 +1,57 @@
+package persistence
+
+import (
+	"strings"
+
+	"paper_ai/pkg/language"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// searchFinalContent 检索使用的最终文本（未应用修改时为润色结果），与迁移 000008 中的索引表达式一致
+const searchFinalContent = "COALESCE(NULLIF(final_content, ''), polished_content)"
+
+// searchDocument 全文检索文档，与迁移 000008 中的索引表达式一致
+const searchDocument = "to_tsvector('simple', original_content || ' ' || " + searchFinalContent + ")"
+
+// searchTSQuery 检索词解析为 tsquery（websearch 语法：空格为且、OR、"短语"、-排除）
+const searchTSQuery = "websearch_to_tsquery('simple', ?)"
+
+// likeEscaper 转义 LIKE 模式中的通配符
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// useTrigramSearch 检索词包含中日韩文字时使用三元组子串匹配（simple 分词无法切分这些语言）
+func useTrigramSearch(text string) bool {
+	return language.ContainsCJK(text)
+}
+
+// applySearch 添加全文检索条件
+// 全文检索匹配单词；三元组匹配时按空白拆分检索词，每个词都必须出现在原文或最终文本中
+func applySearch(query *gorm.DB, text string) *gorm.DB {
+	// 已加密记录的内容为密文，无法检索
+	query = query.Where("encryption_key_id = 0")
+
+	if useTrigramSearch(text) {
+		for _, term := range strings.Fields(text) {
+			pattern := "%" + likeEscaper.Replace(term) + "%"
+			query = query.Where("(original_content ILIKE ? OR "+searchFinalContent+" ILIKE ?)", pattern, pattern)
+		}
+		return query
+	}
+	return query.Where(searchDocument+" @@ "+searchTSQuery, text)
+}
+
+// searchRankOrder 按相关度降序、再按 then 排序（then 为空表示只按相关度）
+// 三元组匹配没有相关度，返回 nil
+func searchRankOrder(text, then string) *clause.OrderBy {
+	if useTrigramSearch(text) {
+		return nil
+	}
+
+	sql := "ts_rank(" + searchDocument + ", " + searchTSQuery + ") DESC"
+	if then != "" {
+		sql += ", " + then
+	}
+	return &clause.OrderBy{Expression: clause.Expr{SQL: sql, Vars: []interface{}{text}, WithoutParentheses: true}}
+}
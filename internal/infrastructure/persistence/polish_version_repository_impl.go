@@ -24,8 +24,15 @@ func NewPolishVersionRepository(db *gorm.DB) repository.PolishVersionRepository
 
 // Create 创建版本记录
 func (r *polishVersionRepositoryImpl) Create(ctx context.Context, version *entity.PolishVersion) error {
+	ownerID, err := r.ownerOf(ctx, version.RecordID)
+	if err != nil {
+		return err
+	}
 	po := &PolishVersionPO{}
-	po.FromEntity(version)
+	if err := po.FromEntity(ctx, version, ownerID); err != nil {
+		logger.Error("failed to encrypt polish version", zap.Error(err))
+		return err
+	}
 
 	if err := r.db.WithContext(ctx).Create(po).Error; err != nil {
 		logger.Error("failed to create polish version", zap.Error(err))
@@ -48,8 +55,15 @@ func (r *polishVersionRepositoryImpl) CreateBatch(ctx context.Context, versions
 	// 转换为PO
 	pos := make([]*PolishVersionPO, len(versions))
 	for i, v := range versions {
+		ownerID, err := r.ownerOf(ctx, v.RecordID)
+		if err != nil {
+			return err
+		}
 		po := &PolishVersionPO{}
-		po.FromEntity(v)
+		if err := po.FromEntity(ctx, v, ownerID); err != nil {
+			logger.Error("failed to encrypt polish version", zap.Error(err))
+			return err
+		}
 		pos[i] = po
 	}
 
@@ -80,7 +94,7 @@ func (r *polishVersionRepositoryImpl) GetByID(ctx context.Context, id int64) (*e
 		return nil, fmt.Errorf("failed to get polish version: %w", err)
 	}
 
-	return po.ToEntity(), nil
+	return po.ToEntity(ctx)
 }
 
 // GetByRecordID 获取某条主记录的所有版本
@@ -99,7 +113,9 @@ func (r *polishVersionRepositoryImpl) GetByRecordID(ctx context.Context, recordI
 	// 转换为实体
 	versions := make([]*entity.PolishVersion, len(pos))
 	for i, po := range pos {
-		versions[i] = po.ToEntity()
+		if versions[i], err = po.ToEntity(ctx); err != nil {
+			return nil, err
+		}
 	}
 
 	return versions, nil
@@ -120,27 +136,69 @@ func (r *polishVersionRepositoryImpl) GetByRecordIDAndType(ctx context.Context,
 		return nil, fmt.Errorf("failed to get polish version: %w", err)
 	}
 
-	return po.ToEntity(), nil
+	return po.ToEntity(ctx)
 }
 
 // Update 更新版本记录
 func (r *polishVersionRepositoryImpl) Update(ctx context.Context, version *entity.PolishVersion) error {
+	ownerID, err := r.ownerOf(ctx, version.RecordID)
+	if err != nil {
+		return err
+	}
 	po := &PolishVersionPO{}
-	po.FromEntity(version)
-
-	result := r.db.WithContext(ctx).Model(&PolishVersionPO{}).Where("id = ?", version.ID).Updates(po)
-	if result.Error != nil {
-		logger.Error("failed to update polish version", zap.Int64("id", version.ID), zap.Error(result.Error))
-		return fmt.Errorf("failed to update polish version: %w", result.Error)
+	if err := po.FromEntity(ctx, version, ownerID); err != nil {
+		logger.Error("failed to encrypt polish version", zap.Int64("id", version.ID), zap.Error(err))
+		return err
 	}
 
-	if result.RowsAffected == 0 {
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&PolishVersionPO{}).Where("id = ?", version.ID).Updates(po)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		// 配置了内容加密时内容列整体重写（包括空值），避免同一行混用新旧数据密钥
+		if contentKeyring.Load() != nil {
+			return tx.Model(&PolishVersionPO{}).Where("id = ?", version.ID).Select(versionContentColumns).Updates(po).Error
+		}
+		return nil
+	})
+	if err == gorm.ErrRecordNotFound {
 		return fmt.Errorf("polish version not found: id=%d", version.ID)
 	}
+	if err != nil {
+		logger.Error("failed to update polish version", zap.Int64("id", version.ID), zap.Error(err))
+		return fmt.Errorf("failed to update polish version: %w", err)
+	}
 
 	return nil
 }
 
+// ownerOf 获取版本所属主记录的用户ID（加密版本内容时选择数据密钥），未启用内容加密时不查询
+func (r *polishVersionRepositoryImpl) ownerOf(ctx context.Context, recordID int64) (int64, error) {
+	return recordOwnerForSealing(ctx, r.db, recordID)
+}
+
+// recordOwnerForSealing 获取主记录所属用户ID（加密版本、修订内容时选择数据密钥），未启用内容加密时不查询
+func recordOwnerForSealing(ctx context.Context, db *gorm.DB, recordID int64) (int64, error) {
+	if !contentEncryptionEnabled() {
+		return 0, nil
+	}
+
+	var userIDs []int64
+	if err := db.WithContext(ctx).Unscoped().Model(&PolishRecordPO{}).Where("id = ?", recordID).Pluck("user_id", &userIDs).Error; err != nil {
+		logger.Error("failed to get owner of polish record", zap.Int64("record_id", recordID), zap.Error(err))
+		return 0, fmt.Errorf("failed to get owner of polish record: %w", err)
+	}
+	if len(userIDs) == 0 {
+		return 0, fmt.Errorf("polish record not found: id=%d", recordID)
+	}
+	return userIDs[0], nil
+}
+
 // Delete 删除版本记录
 func (r *polishVersionRepositoryImpl) Delete(ctx context.Context, id int64) error {
 	result := r.db.WithContext(ctx).Delete(&PolishVersionPO{}, id)
@@ -232,3 +290,42 @@ func (r *polishVersionRepositoryImpl) GetStatsByVersionType(ctx context.Context)
 
 	return statsMap, nil
 }
+
+// GetStatsByPromptID 按使用的Prompt统计（忽略未关联Prompt的版本）
+func (r *polishVersionRepositoryImpl) GetStatsByPromptID(ctx context.Context) (map[int64]*repository.PromptVersionStats, error) {
+	type statsResult struct {
+		PromptID     int64
+		TotalCount   int64
+		SuccessCount int64
+		FailedCount  int64
+	}
+
+	var results []statsResult
+	err := r.db.WithContext(ctx).Model(&PolishVersionPO{}).
+		Select(`
+			prompt_id,
+			COUNT(*) as total_count,
+			SUM(CASE WHEN status = 'success' THEN 1 ELSE 0 END) as success_count,
+			SUM(CASE WHEN status = 'failed' THEN 1 ELSE 0 END) as failed_count
+		`).
+		Where("prompt_id > 0").
+		Group("prompt_id").
+		Find(&results).Error
+
+	if err != nil {
+		logger.Error("failed to get stats by prompt id", zap.Error(err))
+		return nil, fmt.Errorf("failed to get stats by prompt id: %w", err)
+	}
+
+	statsMap := make(map[int64]*repository.PromptVersionStats, len(results))
+	for _, r := range results {
+		statsMap[r.PromptID] = &repository.PromptVersionStats{
+			PromptID:     r.PromptID,
+			TotalCount:   r.TotalCount,
+			SuccessCount: r.SuccessCount,
+			FailedCount:  r.FailedCount,
+		}
+	}
+
+	return statsMap, nil
+}
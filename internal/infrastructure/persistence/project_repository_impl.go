@@ -0,0 +1,142 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"paper_ai/internal/domain/entity"
+	"paper_ai/internal/domain/repository"
+	"paper_ai/pkg/logger"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// projectRepositoryImpl 项目仓储实现
+type projectRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewProjectRepository 创建项目仓储实现
+func NewProjectRepository(db *gorm.DB) repository.ProjectRepository {
+	return &projectRepositoryImpl{db: db}
+}
+
+// Create 创建项目
+func (r *projectRepositoryImpl) Create(ctx context.Context, project *entity.Project) error {
+	po := &ProjectPO{}
+	po.FromEntity(project)
+
+	if err := r.db.WithContext(ctx).Create(po).Error; err != nil {
+		logger.Error("failed to create project", zap.Error(err))
+		return fmt.Errorf("failed to create project: %w", err)
+	}
+
+	// 回写ID和时间戳
+	project.ID = po.ID
+	project.CreatedAt = po.CreatedAt
+	project.UpdatedAt = po.UpdatedAt
+
+	return nil
+}
+
+// GetByID 根据ID获取项目，不存在时返回 nil, nil
+func (r *projectRepositoryImpl) GetByID(ctx context.Context, id int64) (*entity.Project, error) {
+	var po ProjectPO
+	if err := r.db.WithContext(ctx).First(&po, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		logger.Error("failed to get project", zap.Int64("id", id), zap.Error(err))
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	return po.ToEntity(), nil
+}
+
+// GetByName 获取用户的同名项目，不存在时返回 nil, nil
+func (r *projectRepositoryImpl) GetByName(ctx context.Context, userID int64, name string) (*entity.Project, error) {
+	var po ProjectPO
+	if err := r.db.WithContext(ctx).Where("user_id = ? AND name = ?", userID, name).First(&po).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		logger.Error("failed to get project by name", zap.Int64("user_id", userID), zap.Error(err))
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	return po.ToEntity(), nil
+}
+
+// ListByUser 列出用户的所有项目（按名称排序）
+func (r *projectRepositoryImpl) ListByUser(ctx context.Context, userID int64) ([]*entity.Project, error) {
+	var pos []*ProjectPO
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("name").Find(&pos).Error; err != nil {
+		logger.Error("failed to list projects", zap.Int64("user_id", userID), zap.Error(err))
+		return nil, fmt.Errorf("failed to list projects: %w", err)
+	}
+
+	projects := make([]*entity.Project, len(pos))
+	for i, po := range pos {
+		projects[i] = po.ToEntity()
+	}
+	return projects, nil
+}
+
+// CountRecords 统计用户各项目中的润色记录数（不含已删除的记录）
+func (r *projectRepositoryImpl) CountRecords(ctx context.Context, userID int64) (map[int64]int64, error) {
+	var rows []struct {
+		ProjectID int64
+		Count     int64
+	}
+	err := r.db.WithContext(ctx).Model(&PolishRecordPO{}).
+		Select("project_id, COUNT(*) AS count").
+		Where("user_id = ? AND project_id IS NOT NULL", userID).
+		Group("project_id").
+		Find(&rows).Error
+	if err != nil {
+		logger.Error("failed to count records by project", zap.Int64("user_id", userID), zap.Error(err))
+		return nil, fmt.Errorf("failed to count records by project: %w", err)
+	}
+
+	counts := make(map[int64]int64, len(rows))
+	for _, row := range rows {
+		counts[row.ProjectID] = row.Count
+	}
+	return counts, nil
+}
+
+// Update 更新项目名称和描述（描述可以清空）
+func (r *projectRepositoryImpl) Update(ctx context.Context, project *entity.Project) error {
+	po := &ProjectPO{}
+	po.FromEntity(project)
+
+	result := r.db.WithContext(ctx).Model(&ProjectPO{}).Where("id = ?", project.ID).
+		Select("name", "description").Updates(po)
+	if result.Error != nil {
+		logger.Error("failed to update project", zap.Int64("id", project.ID), zap.Error(result.Error))
+		return fmt.Errorf("failed to update project: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("project not found: id=%d", project.ID)
+	}
+
+	return nil
+}
+
+// Delete 删除项目（polish_records.project_id 通过外键 ON DELETE SET NULL 置空）
+func (r *projectRepositoryImpl) Delete(ctx context.Context, id int64) error {
+	result := r.db.WithContext(ctx).Delete(&ProjectPO{}, id)
+	if result.Error != nil {
+		logger.Error("failed to delete project", zap.Int64("id", id), zap.Error(result.Error))
+		return fmt.Errorf("failed to delete project: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("project not found: id=%d", id)
+	}
+
+	return nil
+}
@@ -72,11 +72,12 @@ func (r *RefreshTokenRepositoryImpl) RevokeAllByUserID(ctx context.Context, user
 		}).Error
 }
 
-// DeleteExpired 删除过期的令牌（定期清理）
-func (r *RefreshTokenRepositoryImpl) DeleteExpired(ctx context.Context) error {
-	return r.db.WithContext(ctx).
+// DeleteExpired 删除过期的令牌（定期清理），返回删除数量
+func (r *RefreshTokenRepositoryImpl) DeleteExpired(ctx context.Context) (int64, error) {
+	result := r.db.WithContext(ctx).
 		Where("expires_at < ?", time.Now()).
-		Delete(&RefreshTokenPO{}).Error
+		Delete(&RefreshTokenPO{})
+	return result.RowsAffected, result.Error
 }
 
 // GetValidTokensByUserID 获取用户的有效令牌列表
@@ -0,0 +1,167 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"paper_ai/internal/domain/entity"
+	"paper_ai/internal/domain/repository"
+	"paper_ai/pkg/logger"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// tagRepositoryImpl 标签仓储实现
+type tagRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewTagRepository 创建标签仓储实现
+func NewTagRepository(db *gorm.DB) repository.TagRepository {
+	return &tagRepositoryImpl{db: db}
+}
+
+// Create 创建标签
+func (r *tagRepositoryImpl) Create(ctx context.Context, tag *entity.Tag) error {
+	po := &TagPO{}
+	po.FromEntity(tag)
+
+	if err := r.db.WithContext(ctx).Create(po).Error; err != nil {
+		logger.Error("failed to create tag", zap.Error(err))
+		return fmt.Errorf("failed to create tag: %w", err)
+	}
+
+	// 回写ID和时间戳
+	tag.ID = po.ID
+	tag.CreatedAt = po.CreatedAt
+	tag.UpdatedAt = po.UpdatedAt
+
+	return nil
+}
+
+// GetByID 根据ID获取标签，不存在时返回 nil, nil
+func (r *tagRepositoryImpl) GetByID(ctx context.Context, id int64) (*entity.Tag, error) {
+	var po TagPO
+	if err := r.db.WithContext(ctx).First(&po, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		logger.Error("failed to get tag", zap.Int64("id", id), zap.Error(err))
+		return nil, fmt.Errorf("failed to get tag: %w", err)
+	}
+
+	return po.ToEntity(), nil
+}
+
+// GetByName 获取用户的同名标签，不存在时返回 nil, nil
+func (r *tagRepositoryImpl) GetByName(ctx context.Context, userID int64, name string) (*entity.Tag, error) {
+	var po TagPO
+	if err := r.db.WithContext(ctx).Where("user_id = ? AND name = ?", userID, name).First(&po).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		logger.Error("failed to get tag by name", zap.Int64("user_id", userID), zap.Error(err))
+		return nil, fmt.Errorf("failed to get tag: %w", err)
+	}
+
+	return po.ToEntity(), nil
+}
+
+// ListByUser 列出用户的所有标签（按名称排序）
+func (r *tagRepositoryImpl) ListByUser(ctx context.Context, userID int64) ([]*entity.Tag, error) {
+	var pos []*TagPO
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("name").Find(&pos).Error; err != nil {
+		logger.Error("failed to list tags", zap.Int64("user_id", userID), zap.Error(err))
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	tags := make([]*entity.Tag, len(pos))
+	for i, po := range pos {
+		tags[i] = po.ToEntity()
+	}
+	return tags, nil
+}
+
+// Update 更新标签名称
+func (r *tagRepositoryImpl) Update(ctx context.Context, tag *entity.Tag) error {
+	result := r.db.WithContext(ctx).Model(&TagPO{}).Where("id = ?", tag.ID).Update("name", tag.Name)
+	if result.Error != nil {
+		logger.Error("failed to update tag", zap.Int64("id", tag.ID), zap.Error(result.Error))
+		return fmt.Errorf("failed to update tag: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("tag not found: id=%d", tag.ID)
+	}
+
+	return nil
+}
+
+// Delete 删除标签（polish_record_tags 通过外键 ON DELETE CASCADE 一并删除）
+func (r *tagRepositoryImpl) Delete(ctx context.Context, id int64) error {
+	result := r.db.WithContext(ctx).Delete(&TagPO{}, id)
+	if result.Error != nil {
+		logger.Error("failed to delete tag", zap.Int64("id", id), zap.Error(result.Error))
+		return fmt.Errorf("failed to delete tag: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("tag not found: id=%d", id)
+	}
+
+	return nil
+}
+
+// ListByRecordIDs 批量获取记录的标签（按名称排序）
+func (r *tagRepositoryImpl) ListByRecordIDs(ctx context.Context, recordIDs []int64) (map[int64][]*entity.Tag, error) {
+	result := make(map[int64][]*entity.Tag)
+	if len(recordIDs) == 0 {
+		return result, nil
+	}
+
+	var rows []struct {
+		RecordID int64
+		TagPO
+	}
+	err := r.db.WithContext(ctx).Table("polish_record_tags rt").
+		Select("rt.record_id, t.*").
+		Joins("JOIN tags t ON t.id = rt.tag_id").
+		Where("rt.record_id IN ?", recordIDs).
+		Order("t.name").
+		Find(&rows).Error
+	if err != nil {
+		logger.Error("failed to list tags of records", zap.Error(err))
+		return nil, fmt.Errorf("failed to list tags of records: %w", err)
+	}
+
+	for _, row := range rows {
+		result[row.RecordID] = append(result[row.RecordID], row.TagPO.ToEntity())
+	}
+	return result, nil
+}
+
+// SetRecordTags 替换记录的全部标签
+func (r *tagRepositoryImpl) SetRecordTags(ctx context.Context, recordID int64, tagIDs []int64) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("record_id = ?", recordID).Delete(&RecordTagPO{}).Error; err != nil {
+			return err
+		}
+		if len(tagIDs) == 0 {
+			return nil
+		}
+
+		pos := make([]*RecordTagPO, len(tagIDs))
+		for i, tagID := range tagIDs {
+			pos[i] = &RecordTagPO{RecordID: recordID, TagID: tagID}
+		}
+		return tx.Create(&pos).Error
+	})
+	if err != nil {
+		logger.Error("failed to set tags of record", zap.Int64("record_id", recordID), zap.Error(err))
+		return fmt.Errorf("failed to set tags of record: %w", err)
+	}
+
+	return nil
+}
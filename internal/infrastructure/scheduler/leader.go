@@ -0,0 +1,133 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"paper_ai/internal/infrastructure/metrics"
+	"paper_ai/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// Leader 判断本实例是否应执行定时任务
+type Leader interface {
+	IsLeader() bool
+}
+
+// LeaderElector 基于 PostgreSQL advisory lock 的 leader 选举
+// 持有锁的实例为 leader；锁绑定在一个独占的数据库连接上，进程退出或连接断开时数据库自动释放，
+// 其他副本在下一次检查时接替
+type LeaderElector struct {
+	db       *sql.DB
+	key      int64
+	interval time.Duration
+
+	mu     sync.Mutex
+	conn   *sql.Conn // 持有锁的连接（非 leader 时为 nil）
+	leader atomic.Bool
+}
+
+// NewLeaderElector 创建 leader 选举器，key 为 advisory lock 键（所有副本必须一致）
+func NewLeaderElector(db *sql.DB, key int64, interval time.Duration) *LeaderElector {
+	return &LeaderElector{
+		db:       db,
+		key:      key,
+		interval: interval,
+	}
+}
+
+// IsLeader 实现 Leader 接口
+func (e *LeaderElector) IsLeader() bool {
+	return e.leader.Load()
+}
+
+// Run 周期性竞选 leader（已是 leader 时检查连接是否仍然有效），阻塞直到 ctx 取消，退出时释放锁
+func (e *LeaderElector) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		e.check(ctx)
+
+		select {
+		case <-ctx.Done():
+			e.release()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// check 竞选或确认 leader 身份
+func (e *LeaderElector) check(ctx context.Context) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.conn != nil {
+		if err := e.conn.PingContext(ctx); err == nil {
+			return
+		} else if ctx.Err() == nil {
+			logger.Warn("scheduler leader connection lost, giving up leadership", zap.Error(err))
+		}
+		e.dropLocked()
+		return
+	}
+
+	conn, err := e.db.Conn(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Warn("failed to get connection for scheduler leader election", zap.Error(err))
+		}
+		return
+	}
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", e.key).Scan(&acquired); err != nil {
+		if ctx.Err() == nil {
+			logger.Warn("failed to acquire scheduler leader lock", zap.Error(err))
+		}
+		discardConn(conn)
+		return
+	}
+	if !acquired {
+		conn.Close()
+		return
+	}
+
+	e.conn = conn
+	e.leader.Store(true)
+	metrics.SetSchedulerLeader(true)
+	logger.Info("scheduler leadership acquired", zap.Int64("lock_key", e.key))
+}
+
+// release 主动释放锁（关闭时调用，便于其他副本尽快接替）
+func (e *LeaderElector) release() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.conn == nil {
+		return
+	}
+	e.dropLocked()
+	logger.Info("scheduler leadership released", zap.Int64("lock_key", e.key))
+}
+
+// dropLocked 放弃 leader 身份并断开持有锁的连接，调用方需持有 mu
+func (e *LeaderElector) dropLocked() {
+	e.leader.Store(false)
+	metrics.SetSchedulerLeader(false)
+	discardConn(e.conn)
+	e.conn = nil
+}
+
+// discardConn 断开连接而不是归还连接池：advisory lock 属于会话，
+// 归还后锁会随连接留在池中，只有物理断开才能保证数据库释放锁
+func discardConn(conn *sql.Conn) {
+	_ = conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+	_ = conn.Close()
+}
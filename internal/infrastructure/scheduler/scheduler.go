@@ -0,0 +1,120 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"paper_ai/internal/infrastructure/metrics"
+	"paper_ai/internal/infrastructure/tracing"
+	"paper_ai/pkg/logger"
+
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+// JobFunc 定时任务
+type JobFunc func(ctx context.Context) error
+
+// Scheduler 进程内定时任务调度器（cron 表达式）
+// 所有副本都会按计划触发任务，只有 leader 实际执行；同一任务上次未结束时跳过本次
+type Scheduler struct {
+	cron   *cron.Cron
+	leader Leader
+
+	ctx    context.Context // 任务使用的上下文，Stop 时取消
+	cancel context.CancelFunc
+}
+
+// New 创建调度器
+func New(leader Leader) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	cronLog := cronLogger{}
+	return &Scheduler{
+		cron: cron.New(cron.WithLogger(cronLog), cron.WithChain(
+			cron.Recover(cronLog),
+			cron.SkipIfStillRunning(cronLog),
+		)),
+		leader: leader,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Register 注册定时任务，spec 为标准 5 段 cron 表达式或 @every 1h、@daily 等描述符，为空时不启用
+func (s *Scheduler) Register(name, spec string, job JobFunc) error {
+	if spec == "" {
+		logger.Info("scheduler job disabled", zap.String("job", name))
+		return nil
+	}
+
+	if _, err := s.cron.AddFunc(spec, func() { s.run(name, job) }); err != nil {
+		return fmt.Errorf("invalid schedule for job %s: %w", name, err)
+	}
+	logger.Info("scheduler job registered", zap.String("job", name), zap.String("schedule", spec))
+	return nil
+}
+
+// Start 启动调度
+func (s *Scheduler) Start() {
+	s.cron.Start()
+}
+
+// Stop 停止调度并等待正在执行的任务结束，ctx 超时后取消任务上下文
+func (s *Scheduler) Stop(ctx context.Context) {
+	done := s.cron.Stop()
+	select {
+	case <-done.Done():
+	case <-ctx.Done():
+		logger.Warn("scheduler jobs still running at shutdown, cancelling")
+	}
+	s.cancel()
+}
+
+// run 执行一次任务（非 leader 时跳过）
+func (s *Scheduler) run(name string, job JobFunc) {
+	if !s.leader.IsLeader() {
+		logger.Debug("scheduler job skipped, not leader", zap.String("job", name))
+		return
+	}
+
+	ctx, span := tracing.Start(s.ctx, "scheduler."+name)
+	start := time.Now()
+	err := job(ctx)
+	duration := time.Since(start)
+	tracing.End(span, err)
+	metrics.ObserveSchedulerJob(name, duration, err)
+
+	if err != nil {
+		logger.Error("scheduler job failed",
+			zap.String("job", name),
+			zap.Duration("duration", duration),
+			zap.Error(err))
+		return
+	}
+	logger.Info("scheduler job completed",
+		zap.String("job", name),
+		zap.Duration("duration", duration))
+}
+
+// cronLogger 将 cron 内部日志（任务 panic、跳过重叠执行）转到 zap
+type cronLogger struct{}
+
+// Info 实现 cron.Logger（调度细节只在 debug 级别输出）
+func (cronLogger) Info(msg string, keysAndValues ...interface{}) {
+	logger.Debug("cron: "+msg, keyValueFields(keysAndValues)...)
+}
+
+// Error 实现 cron.Logger
+func (cronLogger) Error(err error, msg string, keysAndValues ...interface{}) {
+	logger.Error("cron: "+msg, append(keyValueFields(keysAndValues), zap.Error(err))...)
+}
+
+// keyValueFields 把 key/value 交替的参数转换为 zap 字段
+func keyValueFields(keysAndValues []interface{}) []zap.Field {
+	fields := make([]zap.Field, 0, len(keysAndValues)/2)
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		fields = append(fields, zap.Any(fmt.Sprint(keysAndValues[i]), keysAndValues[i+1]))
+	}
+	return fields
+}
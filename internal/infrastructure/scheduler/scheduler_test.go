@@ -0,0 +1,99 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"paper_ai/pkg/logger"
+)
+
+func init() {
+	_ = logger.Init()
+}
+
+// fakeLeader 可控的 leader 状态
+type fakeLeader struct {
+	leader atomic.Bool
+}
+
+func (l *fakeLeader) IsLeader() bool {
+	return l.leader.Load()
+}
+
+func TestRun_SkipsWhenNotLeader(t *testing.T) {
+	leader := &fakeLeader{}
+	s := New(leader)
+
+	calls := 0
+	job := func(ctx context.Context) error {
+		calls++
+		return nil
+	}
+
+	s.run("test_job", job)
+	if calls != 0 {
+		t.Fatalf("非 leader 不应执行任务，实际执行 %d 次", calls)
+	}
+
+	leader.leader.Store(true)
+	s.run("test_job", job)
+	s.run("test_job", func(ctx context.Context) error { return errors.New("boom") })
+	if calls != 1 {
+		t.Fatalf("leader 应执行任务 1 次，实际 %d 次", calls)
+	}
+}
+
+func TestRegister_Spec(t *testing.T) {
+	s := New(&fakeLeader{})
+	noop := func(ctx context.Context) error { return nil }
+
+	if err := s.Register("disabled", "", noop); err != nil {
+		t.Fatalf("空计划应视为不启用: %v", err)
+	}
+	if err := s.Register("hourly", "@every 1h", noop); err != nil {
+		t.Fatalf("合法计划注册失败: %v", err)
+	}
+	if err := s.Register("broken", "every hour", noop); err == nil {
+		t.Fatal("非法计划应返回错误")
+	}
+	if got := len(s.cron.Entries()); got != 1 {
+		t.Fatalf("应只注册 1 个任务，实际 %d 个", got)
+	}
+}
+
+func TestStop_CancelsRunningJobAfterTimeout(t *testing.T) {
+	leader := &fakeLeader{}
+	leader.leader.Store(true)
+	s := New(leader)
+
+	started := make(chan struct{})
+	cancelled := make(chan struct{})
+	if err := s.Register("slow", "@every 1s", func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		close(cancelled)
+		return ctx.Err()
+	}); err != nil {
+		t.Fatal(err)
+	}
+	s.Start()
+
+	select {
+	case <-started:
+	case <-time.After(3 * time.Second):
+		t.Fatal("任务未按计划启动")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	s.Stop(ctx)
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("Stop 超时后应取消任务上下文")
+	}
+}
@@ -0,0 +1,137 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+
+	"paper_ai/internal/config"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName 追踪器名称
+const instrumentationName = "paper_ai"
+
+// ExporterEnum 导出器类型枚举
+const (
+	ExporterNone   = "none"   // 不导出（no-op）
+	ExporterStdout = "stdout" // 输出到标准输出
+	ExporterOTLP   = "otlp"   // OTLP HTTP
+)
+
+// ShutdownFunc 关闭追踪（刷新未导出的 span）
+type ShutdownFunc func(ctx context.Context) error
+
+// Init 根据配置初始化全局 TracerProvider
+// exporter 为 none 时保持 OpenTelemetry 默认的 no-op 实现，不产生任何开销
+func Init(ctx context.Context, cfg *config.TracingConfig) (ShutdownFunc, error) {
+	var exporter sdktrace.SpanExporter
+	var err error
+
+	switch cfg.Exporter {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case ExporterOTLP:
+		opts := []otlptracehttp.Option{}
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(cfg.Endpoint))
+		}
+		if cfg.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		if len(cfg.Headers) > 0 {
+			headers := make(map[string]string, len(cfg.Headers))
+			for key, value := range cfg.Headers {
+				headers[key] = value.Value()
+			}
+			opts = append(opts, otlptracehttp.WithHeaders(headers))
+		}
+		exporter, err = otlptracehttp.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("unsupported tracing exporter: %s", cfg.Exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s exporter: %w", cfg.Exporter, err)
+	}
+
+	provider := NewProvider(cfg, exporter)
+	return provider.Shutdown, nil
+}
+
+// NewProvider 使用指定导出器创建 TracerProvider 并设置为全局实现
+// 测试中可传入写到缓冲区的 stdouttrace 导出器
+func NewProvider(cfg *config.TracingConfig, exporter sdktrace.SpanExporter) *sdktrace.TracerProvider {
+	serviceName := cfg.ServiceName
+	if serviceName == "" {
+		serviceName = instrumentationName
+	}
+
+	sampler := sdktrace.AlwaysSample()
+	if cfg.SampleRatio > 0 && cfg.SampleRatio < 1 {
+		sampler = sdktrace.TraceIDRatioBased(cfg.SampleRatio)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sampler)),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceName(serviceName),
+		)),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+	return provider
+}
+
+// Tracer 获取追踪器（使用当前全局 TracerProvider）
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Start 开始一个内部 span
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// RecordError 在 span 上记录错误并标记为失败（err 为空时不做处理）
+func RecordError(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
+
+// End 结束 span，err 不为空时记录错误
+func End(span trace.Span, err error) {
+	RecordError(span, err)
+	span.End()
+}
+
+// NewHTTPTransport 创建带追踪的 HTTP Transport（用于调用外部服务）
+// span 名称为 "<peer> <METHOD> <path>"，并向下游传播 traceparent 请求头
+func NewHTTPTransport(peer string) http.RoundTripper {
+	return otelhttp.NewTransport(http.DefaultTransport,
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			return fmt.Sprintf("%s %s %s", peer, r.Method, r.URL.Path)
+		}),
+	)
+}
@@ -0,0 +1,106 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"paper_ai/internal/config"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// newTestProvider 使用写到缓冲区的 stdout 导出器初始化追踪，测试结束后恢复 no-op
+func newTestProvider(t *testing.T) (*bytes.Buffer, func()) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	exporter, err := stdouttrace.New(stdouttrace.WithWriter(&buf))
+	if err != nil {
+		t.Fatalf("创建导出器失败: %v", err)
+	}
+	provider := NewProvider(&config.TracingConfig{ServiceName: "paper_ai_test"}, exporter)
+	t.Cleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+
+	flush := func() {
+		if err := provider.Shutdown(context.Background()); err != nil {
+			t.Fatalf("关闭追踪失败: %v", err)
+		}
+	}
+	return &buf, flush
+}
+
+func TestInit_NoneIsNoop(t *testing.T) {
+	shutdown, err := Init(context.Background(), &config.TracingConfig{Exporter: ExporterNone})
+	if err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("shutdown() error = %v", err)
+	}
+
+	_, span := Start(context.Background(), "noop")
+	if span.IsRecording() {
+		t.Error("none 导出器下 span 不应该被记录")
+	}
+	span.End()
+
+	if _, err := Init(context.Background(), &config.TracingConfig{Exporter: "zipkin"}); err == nil {
+		t.Error("不支持的导出器应该返回错误")
+	}
+}
+
+func TestStartEnd(t *testing.T) {
+	buf, flush := newTestProvider(t)
+
+	ctx, parent := Start(context.Background(), "parent")
+	_, child := Start(ctx, "child")
+	End(child, errors.New("provider timeout"))
+	End(parent, nil)
+	flush()
+
+	out := buf.String()
+	for _, want := range []string{`"Name":"parent"`, `"Name":"child"`, "provider timeout", "paper_ai_test"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("导出结果缺少 %s:\n%s", want, out)
+		}
+	}
+	if strings.Count(out, parent.SpanContext().TraceID().String()) < 2 {
+		t.Error("子 span 应该与父 span 属于同一条链路")
+	}
+}
+
+func TestNewHTTPTransport(t *testing.T) {
+	buf, flush := newTestProvider(t)
+
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ctx, span := Start(context.Background(), "polish")
+	client := &http.Client{Transport: NewHTTPTransport("claude")}
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+"/v1/messages", nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	resp.Body.Close()
+	span.End()
+	flush()
+
+	if !strings.Contains(traceparent, span.SpanContext().TraceID().String()) {
+		t.Errorf("traceparent 请求头应该包含当前链路ID, got %q", traceparent)
+	}
+	if !strings.Contains(buf.String(), `"Name":"claude POST /v1/messages"`) {
+		t.Errorf("应该导出 HTTP 客户端 span:\n%s", buf.String())
+	}
+}
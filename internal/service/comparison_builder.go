@@ -0,0 +1,376 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"paper_ai/internal/domain/entity"
+	"paper_ai/internal/domain/model"
+	"paper_ai/internal/domain/repository"
+	"paper_ai/internal/infrastructure/ai/types"
+	"paper_ai/internal/infrastructure/comparison"
+	"paper_ai/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// comparisonBuilder 对比数据构建器
+// ComparisonService 与 PolishMultiVersionService 共用，保证两条路径生成的标注和置信度一致
+type comparisonBuilder struct {
+	diffEngine      *comparison.DiffEngine
+	positionCalc    *comparison.PositionCalculator
+	classifier      *comparison.ChangeClassifier
+	reasonGenerator *comparison.ReasonGenerator
+	confidenceCalc  *comparison.ConfidenceCalculator
+	feedbackRepo    repository.ChangeFeedbackRepository // 可选：为 nil 时不使用历史接受率
+}
+
+// newComparisonBuilder 创建对比数据构建器
+func newComparisonBuilder(feedbackRepo repository.ChangeFeedbackRepository) *comparisonBuilder {
+	return &comparisonBuilder{
+		diffEngine:      comparison.NewDiffEngine(),
+		positionCalc:    comparison.NewPositionCalculator(),
+		classifier:      comparison.NewChangeClassifier(),
+		reasonGenerator: comparison.NewReasonGenerator(),
+		confidenceCalc:  comparison.NewConfidenceCalculator(),
+		feedbackRepo:    feedbackRepo,
+	}
+}
+
+// comparisonInput 构建对比数据的输入
+type comparisonInput struct {
+	TraceID       string
+	Original      string
+	Polished      string
+	TokenLogProbs []entity.TokenLogProb // 可选：润色文本的 token 对数概率
+	PeerContents  []string              // 可选：同一原文的其他版本润色文本（用于计算多版本一致性）
+	Selection     *comparisonSelection  // 可选：选区润色时只对比选中的片段（TokenLogProbs 对应润色后的片段）
+}
+
+// comparisonSelection 选区润色时原文和润色文本中对应的片段（基于 rune，End 不包含），片段之外的文本相同
+type comparisonSelection struct {
+	OriginalStart int
+	OriginalEnd   int
+	PolishedStart int
+	PolishedEnd   int
+}
+
+// recordSelection 取选区润色记录在原文和当前润色文本中的片段
+// 片段之外的文本已不一致（例如多轮修改改动了上下文）时返回 nil，按整段对比
+func recordSelection(record *entity.PolishRecord) *comparisonSelection {
+	if record.Selection == nil {
+		return nil
+	}
+
+	original, polished := []rune(record.OriginalContent), []rune(record.PolishedContent)
+	start, end := record.Selection.Start, record.Selection.End
+	if start < 0 || end < start || end > len(original) {
+		return nil
+	}
+	polishedEnd := len(polished) - (len(original) - end)
+	if polishedEnd < start ||
+		string(original[:start]) != string(polished[:start]) ||
+		string(original[end:]) != string(polished[polishedEnd:]) {
+		return nil
+	}
+
+	return &comparisonSelection{
+		OriginalStart: start,
+		OriginalEnd:   end,
+		PolishedStart: start,
+		PolishedEnd:   polishedEnd,
+	}
+}
+
+// build 生成对比数据
+func (b *comparisonBuilder) build(ctx context.Context, in comparisonInput) *model.ComparisonResult {
+	// 1. 生成标注列表
+	annotations := b.annotate(ctx, in)
+
+	// 2. 计算元数据和统计信息
+	metadata, statistics := b.calculateStats(in.Original, in.Polished, annotations)
+
+	return &model.ComparisonResult{
+		TraceID:         in.TraceID,
+		OriginalContent: in.Original,
+		PolishedContent: in.Polished,
+		Annotations:     annotations,
+		Metadata:        metadata,
+		Statistics:      statistics,
+	}
+}
+
+// annotate 对比原文和润色文本，生成标注列表
+func (b *comparisonBuilder) annotate(ctx context.Context, in comparisonInput) []model.Change {
+	if in.Selection != nil {
+		return b.annotateSelection(ctx, in)
+	}
+
+	// 1. 运行 diff 算法
+	diffs := b.diffEngine.GenerateDiff(in.Original, in.Polished)
+
+	// 2. 提取修改信息
+	changes := b.diffEngine.GetChanges(diffs)
+
+	// 3. 计算位置
+	positions := b.positionCalc.CalculatePositions(in.Polished, changes)
+
+	// 4. 生成标注
+	return b.buildAnnotations(ctx, positions, in, changes)
+}
+
+// annotateSelection 只对比选中的片段，标注位置换算到整段文本中
+func (b *comparisonBuilder) annotateSelection(ctx context.Context, in comparisonInput) []model.Change {
+	sel := in.Selection
+	original, polished := []rune(in.Original), []rune(in.Polished)
+
+	annotations := b.annotate(ctx, comparisonInput{
+		TraceID:       in.TraceID,
+		Original:      string(original[sel.OriginalStart:sel.OriginalEnd]),
+		Polished:      string(polished[sel.PolishedStart:sel.PolishedEnd]),
+		TokenLogProbs: in.TokenLogProbs,
+	})
+
+	lineOffset := strings.Count(string(polished[:sel.PolishedStart]), "\n")
+	for i := range annotations {
+		annotations[i].PolishedPosition.Start += sel.PolishedStart
+		annotations[i].PolishedPosition.End += sel.PolishedStart
+		if annotations[i].PolishedPosition.Line > 0 {
+			annotations[i].PolishedPosition.Line += lineOffset
+		}
+		annotations[i].OriginalPosition.Start += sel.OriginalStart
+		annotations[i].OriginalPosition.End += sel.OriginalStart
+	}
+	return annotations
+}
+
+// buildAnnotations 构建标注列表
+func (b *comparisonBuilder) buildAnnotations(ctx context.Context, positions []comparison.PositionInfo, in comparisonInput, changes []comparison.ChangeInfo) []model.Change {
+	// 分类修改类型
+	changeTypes := make([]model.ChangeType, len(positions))
+	for i, pos := range positions {
+		changeTypes[i] = b.classifier.Classify(pos.OriginalText, pos.PolishedText)
+	}
+
+	// 收集置信度信号
+	agreements := b.versionAgreements(in, positions, changes)
+	historyBySignature, historyByType := b.loadAcceptStats(ctx, positions, changeTypes)
+
+	annotations := make([]model.Change, 0, len(positions))
+
+	for i, pos := range positions {
+		changeType := changeTypes[i]
+
+		// 生成修改理由
+		reason := b.reasonGenerator.Generate(changeType, pos.OriginalText, pos.PolishedText)
+
+		// 生成替代方案
+		alternatives := b.reasonGenerator.GenerateAlternatives(changeType, pos.OriginalText)
+
+		// 计算置信度
+		signals := comparison.ConfidenceSignals{}
+		if agreements != nil {
+			signals.VersionAgreement = agreements[i]
+		}
+		if probability, ok := comparison.SpanProbability(in.TokenLogProbs, in.Polished, pos.Start, pos.End); ok {
+			signals.TokenProbability = &probability
+		}
+		signature := comparison.ChangeSignature(changeType, pos.OriginalText, pos.PolishedText)
+		if stats := pickAcceptStats(historyBySignature[signature], historyByType[string(changeType)]); stats != nil {
+			rate := stats.AcceptRate()
+			signals.HistoricalAccept = &rate
+			signals.HistoricalSamples = stats.TotalCount
+		}
+		confidence, factors := b.confidenceCalc.Calculate(changeType, pos.OriginalText, pos.PolishedText, signals)
+
+		// 获取影响维度
+		impact := b.reasonGenerator.GetImpact(changeType)
+
+		// 建议高亮颜色
+		highlightColor := b.classifier.SuggestHighlightColor(changeType)
+
+		annotations = append(annotations, model.Change{
+			ID:   fmt.Sprintf("change_%d", i+1),
+			Type: changeType,
+			PolishedPosition: model.Position{
+				Start: pos.Start,
+				End:   pos.End,
+				Line:  pos.Line,
+			},
+			PolishedText: pos.PolishedText,
+			OriginalText: pos.OriginalText,
+			OriginalPosition: model.Position{
+				Start: pos.OriginalStart,
+				End:   pos.OriginalEnd,
+			},
+			Reason:            reason,
+			Alternatives:      alternatives,
+			Confidence:        confidence,
+			ConfidenceFactors: factors,
+			Impact:            impact,
+			HighlightColor:    highlightColor,
+			Status:            model.ActionStatusPending,
+		})
+	}
+
+	return annotations
+}
+
+// versionAgreements 计算每个修改的多版本一致率
+// 目标文本本身视为参与比较的一个版本（与某个 peer 完全相同时不重复计数）
+func (b *comparisonBuilder) versionAgreements(in comparisonInput, positions []comparison.PositionInfo, changes []comparison.ChangeInfo) []*float64 {
+	if len(in.PeerContents) == 0 {
+		return nil
+	}
+
+	versions := [][]comparison.ChangeInfo{changes}
+	for _, peer := range in.PeerContents {
+		if peer == in.Polished {
+			continue
+		}
+		peerDiffs := b.diffEngine.GenerateDiff(in.Original, peer)
+		versions = append(versions, b.diffEngine.GetChanges(peerDiffs))
+	}
+
+	return comparison.VersionAgreement(positions, versions)
+}
+
+// loadAcceptStats 加载历史接受率（按签名与按类型）
+// 查询失败时只记录日志，不影响对比数据生成
+func (b *comparisonBuilder) loadAcceptStats(ctx context.Context, positions []comparison.PositionInfo, changeTypes []model.ChangeType) (map[string]*repository.AcceptStats, map[string]*repository.AcceptStats) {
+	if b.feedbackRepo == nil || len(positions) == 0 {
+		return nil, nil
+	}
+
+	signatures := make([]string, len(positions))
+	for i, pos := range positions {
+		signatures[i] = comparison.ChangeSignature(changeTypes[i], pos.OriginalText, pos.PolishedText)
+	}
+
+	bySignature, err := b.feedbackRepo.GetAcceptStatsBySignatures(ctx, signatures)
+	if err != nil {
+		logger.Warn("failed to load accept stats by signature", zap.Error(err))
+	}
+
+	byType, err := b.feedbackRepo.GetAcceptStatsByType(ctx)
+	if err != nil {
+		logger.Warn("failed to load accept stats by type", zap.Error(err))
+	}
+
+	return bySignature, byType
+}
+
+// minSignatureSamples 使用签名级历史统计所需的最少样本数
+const minSignatureSamples = 3
+
+// pickAcceptStats 优先使用相同修改的历史统计，样本不足时退化为同类型修改的统计
+func pickAcceptStats(bySignature, byType *repository.AcceptStats) *repository.AcceptStats {
+	if bySignature != nil && bySignature.TotalCount >= minSignatureSamples {
+		return bySignature
+	}
+	return byType
+}
+
+// calculateStats 计算统计信息
+func (b *comparisonBuilder) calculateStats(original, polished string, annotations []model.Change) (model.Metadata, model.Statistics) {
+	originalWordCount := comparison.CountWords(original)
+	polishedWordCount := comparison.CountWords(polished)
+
+	// 统计各类修改数量
+	var vocabCount, grammarCount, structureCount int
+	for _, ann := range annotations {
+		switch ann.Type {
+		case model.ChangeTypeVocabulary:
+			vocabCount++
+		case model.ChangeTypeGrammar:
+			grammarCount++
+		case model.ChangeTypeStructure:
+			structureCount++
+		}
+	}
+
+	// 计算学术性提升（简单算法：词汇优化占比 * 100）
+	academicImprovement := 0.0
+	if len(annotations) > 0 {
+		academicImprovement = float64(vocabCount) / float64(len(annotations)) * 100
+		// 限制在0-100之间
+		if academicImprovement > 100 {
+			academicImprovement = 100
+		}
+	}
+
+	metadata := model.Metadata{
+		OriginalWordCount:        originalWordCount,
+		PolishedWordCount:        polishedWordCount,
+		TotalChanges:             len(annotations),
+		AcademicScoreImprovement: academicImprovement,
+	}
+
+	statistics := model.Statistics{
+		VocabularyChanges: vocabCount,
+		GrammarChanges:    grammarCount,
+		StructureChanges:  structureCount,
+	}
+
+	return metadata, statistics
+}
+
+// buildFeedbacks 将用户对修改的操作转换为反馈记录
+func buildFeedbacks(userID int64, traceID string, changes []model.Change) []*entity.ChangeFeedback {
+	feedbacks := make([]*entity.ChangeFeedback, 0, len(changes))
+	for _, change := range changes {
+		var action string
+		switch change.Status {
+		case model.ActionStatusAccepted:
+			action = entity.FeedbackActionAccepted
+		case model.ActionStatusRejected:
+			action = entity.FeedbackActionRejected
+		default:
+			continue
+		}
+
+		feedbacks = append(feedbacks, &entity.ChangeFeedback{
+			UserID:     userID,
+			TraceID:    traceID,
+			ChangeType: string(change.Type),
+			Signature:  comparison.ChangeSignature(change.Type, change.OriginalText, change.PolishedText),
+			Action:     action,
+		})
+	}
+	return feedbacks
+}
+
+// loadPeerContents 获取多版本润色中所有成功版本的润色文本
+func loadPeerContents(ctx context.Context, versionRepo repository.PolishVersionRepository, recordID int64) []string {
+	if versionRepo == nil {
+		return nil
+	}
+
+	versions, err := versionRepo.GetByRecordID(ctx, recordID)
+	if err != nil {
+		logger.Warn("failed to load peer versions", zap.Int64("record_id", recordID), zap.Error(err))
+		return nil
+	}
+
+	contents := make([]string, 0, len(versions))
+	for _, version := range versions {
+		if version.Status == "success" && version.PolishedContent != "" {
+			contents = append(contents, version.PolishedContent)
+		}
+	}
+	return contents
+}
+
+// toEntityTokenLogProbs 将提供商返回的 token 对数概率转换为实体类型
+func toEntityTokenLogProbs(tokens []types.TokenLogProb) []entity.TokenLogProb {
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	result := make([]entity.TokenLogProb, len(tokens))
+	for i, token := range tokens {
+		result[i] = entity.TokenLogProb{Token: token.Token, LogProb: token.LogProb}
+	}
+	return result
+}
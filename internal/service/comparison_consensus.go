@@ -0,0 +1,278 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"paper_ai/internal/domain/entity"
+	"paper_ai/internal/domain/model"
+	"paper_ai/internal/infrastructure/comparison"
+	apperrors "paper_ai/pkg/errors"
+	"paper_ai/pkg/logger"
+	"paper_ai/pkg/reqctx"
+
+	"go.uber.org/zap"
+)
+
+// versionTypeOrder 版本展示顺序
+var versionTypeOrder = map[string]int{
+	entity.VersionTypeConservative: 0,
+	entity.VersionTypeBalanced:     1,
+	entity.VersionTypeAggressive:   2,
+}
+
+// GetConsensus 获取多版本一致性视图
+// 将所有成功版本对齐到原文，标记所有版本一致、部分版本一致或仅单个版本做出的修改
+func (s *ComparisonService) GetConsensus(ctx context.Context, traceID string, userID int64) (*model.ConsensusResult, error) {
+	ctx = reqctx.WithTraceID(ctx, traceID)
+	record, err := s.getMultiVersionRecord(ctx, traceID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.buildConsensus(ctx, record)
+}
+
+// ApplyConsensus 按区间选择版本写法，生成并保存最终文本
+// 最终文本像自由编辑一样合并到标注中，并记录一条 consensus 操作（可撤销）
+func (s *ComparisonService) ApplyConsensus(ctx context.Context, traceID string, userID int64, req *model.ConsensusApplyRequest) (*model.ConsensusApplyResponse, error) {
+	ctx = reqctx.WithTraceID(ctx, traceID)
+	record, err := s.getMultiVersionRecord(ctx, traceID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.buildConsensus(ctx, record)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. 校验选择
+	spanIndex := make(map[string]int, len(result.Spans))
+	for i, span := range result.Spans {
+		spanIndex[span.ID] = i
+	}
+	for spanID, selection := range req.Selections {
+		idx, ok := spanIndex[spanID]
+		if !ok {
+			return nil, apperrors.NewInvalidParameterError(fmt.Sprintf("区间 %s 不存在", spanID))
+		}
+		if _, ok := spanOptionText(result.Spans[idx], selection); !ok {
+			return nil, apperrors.NewInvalidParameterError(fmt.Sprintf("区间 %s 的选择无效: %s", spanID, selection))
+		}
+	}
+
+	defaultSelection := req.Default
+	if defaultSelection == "" {
+		defaultSelection = model.ConsensusSelectOriginal
+	}
+	if defaultSelection != model.ConsensusSelectOriginal &&
+		defaultSelection != model.ConsensusSelectRecommended &&
+		!containsString(result.Versions, defaultSelection) {
+		return nil, apperrors.NewInvalidParameterError(fmt.Sprintf("无效的默认选择: %s", defaultSelection))
+	}
+
+	// 2. 从原文开始，逐个区间替换为选中的写法
+	origRunes := []rune(result.OriginalContent)
+	var builder strings.Builder
+	pos := 0
+	appliedSpans := 0
+
+	for _, span := range result.Spans {
+		selection, ok := req.Selections[span.ID]
+		if !ok {
+			selection = defaultSelection
+		}
+
+		text, _ := spanOptionText(span, selection)
+		if text != span.OriginalText {
+			appliedSpans++
+		}
+
+		builder.WriteString(string(origRunes[pos:span.OriginalPosition.Start]))
+		builder.WriteString(text)
+		pos = span.OriginalPosition.End
+	}
+	builder.WriteString(string(origRunes[pos:]))
+
+	finalContent := builder.String()
+
+	// 3. 与原文重新 diff 并合并到标注（同自由编辑），保证后续操作和撤销/重做基于选中的写法
+	comparisonResult, err := s.GenerateComparison(ctx, traceID)
+	if err != nil {
+		return nil, err
+	}
+	diffs := s.builder.diffEngine.GenerateDiff(comparisonResult.OriginalContent, finalContent)
+	changes := s.mergeEdits(comparisonResult, s.builder.diffEngine.GetChanges(diffs))
+
+	if s.applyChanges(comparisonResult) != finalContent {
+		// 理论上不会发生：标注无法还原选择结果时拒绝保存，避免最终文本与标注不一致
+		logger.FromContext(ctx).Error("consensus content mismatch after merging annotations")
+		return nil, fmt.Errorf("合并区间选择失败")
+	}
+
+	// 4. 保存并记录日志
+	record.FinalContent = finalContent
+	if err := s.saveComparisonData(ctx, record, comparisonResult); err != nil {
+		logger.FromContext(ctx).Error("failed to save consensus comparison data", zap.Error(err))
+		return nil, fmt.Errorf("保存对比数据失败: %w", err)
+	}
+	s.logAction(ctx, record, entity.ComparisonActionConsensus, 0, changes)
+
+	return &model.ConsensusApplyResponse{
+		Success:      true,
+		FinalContent: finalContent,
+		AppliedSpans: appliedSpans,
+	}, nil
+}
+
+// getMultiVersionRecord 获取并校验多版本润色记录
+func (s *ComparisonService) getMultiVersionRecord(ctx context.Context, traceID string, userID int64) (*entity.PolishRecord, error) {
+	record, err := s.polishRepo.GetByTraceID(ctx, traceID)
+	if err != nil {
+		return nil, apperrors.NewNotFoundError("润色记录不存在")
+	}
+
+	if record.UserID != userID {
+		return nil, apperrors.NewForbiddenError("无权访问该记录")
+	}
+
+	if err := checkContentStored(record); err != nil {
+		return nil, err
+	}
+
+	if !record.IsMultiVersionMode() {
+		return nil, apperrors.NewInvalidParameterError("该记录不是多版本润色")
+	}
+
+	return record, nil
+}
+
+// buildConsensus 对齐所有成功版本并生成一致性视图
+func (s *ComparisonService) buildConsensus(ctx context.Context, record *entity.PolishRecord) (*model.ConsensusResult, error) {
+	versions, err := s.versionRepo.GetByRecordID(ctx, record.ID)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to get versions", zap.Int64("record_id", record.ID), zap.Error(err))
+		return nil, fmt.Errorf("获取版本失败: %w", err)
+	}
+
+	successful := make([]*entity.PolishVersion, 0, len(versions))
+	for _, version := range versions {
+		if version.Status == "success" {
+			successful = append(successful, version)
+		}
+	}
+	if len(successful) == 0 {
+		return nil, apperrors.NewInvalidParameterError("没有可对齐的成功版本")
+	}
+	sort.Slice(successful, func(i, j int) bool {
+		return versionTypeOrder[successful[i].VersionType] < versionTypeOrder[successful[j].VersionType]
+	})
+
+	// 1. 计算每个版本相对原文的修改
+	versionTypes := make([]string, len(successful))
+	versionChanges := make([]comparison.VersionChanges, len(successful))
+	for i, version := range successful {
+		diffs := s.builder.diffEngine.GenerateWordDiff(record.OriginalContent, version.PolishedContent)
+		versionTypes[i] = version.VersionType
+		versionChanges[i] = comparison.VersionChanges{
+			VersionType: version.VersionType,
+			Changes:     s.builder.diffEngine.GetChanges(diffs),
+		}
+	}
+
+	// 2. 对齐
+	aligned := s.aligner.Align(record.OriginalContent, versionChanges)
+
+	// 3. 构建视图
+	result := &model.ConsensusResult{
+		TraceID:         record.TraceID,
+		OriginalContent: record.OriginalContent,
+		FinalContent:    record.FinalContent,
+		Versions:        versionTypes,
+		Spans:           make([]model.ConsensusSpan, 0, len(aligned)),
+	}
+
+	origRunes := []rune(record.OriginalContent)
+	var consensus strings.Builder
+	pos := 0
+
+	for i, span := range aligned {
+		options := make([]model.ConsensusOption, len(span.Options))
+		for j, option := range span.Options {
+			options[j] = model.ConsensusOption{Text: option.Text, Versions: option.Versions}
+		}
+
+		result.Spans = append(result.Spans, model.ConsensusSpan{
+			ID: fmt.Sprintf("span_%d", i+1),
+			OriginalPosition: model.Position{
+				Start: span.OriginalStart,
+				End:   span.OriginalEnd,
+			},
+			OriginalText:      span.OriginalText,
+			Options:           options,
+			UnchangedVersions: span.UnchangedVersions,
+			Agreement:         span.Agreement,
+			Confidence:        math.Round(float64(span.Support)/float64(len(successful))*100) / 100,
+			Recommended:       span.Options[0].Versions[0],
+		})
+
+		switch span.Agreement {
+		case comparison.AgreementAll:
+			result.Summary.AllAgree++
+		case comparison.AgreementSome:
+			result.Summary.SomeAgree++
+		default:
+			result.Summary.OneVersion++
+		}
+
+		// 一致文本只应用所有版本一致的修改
+		consensus.WriteString(string(origRunes[pos:span.OriginalStart]))
+		if span.Agreement == comparison.AgreementAll {
+			consensus.WriteString(span.Options[0].Text)
+		} else {
+			consensus.WriteString(span.OriginalText)
+		}
+		pos = span.OriginalEnd
+	}
+	consensus.WriteString(string(origRunes[pos:]))
+
+	result.ConsensusContent = consensus.String()
+	result.Summary.TotalSpans = len(result.Spans)
+
+	return result, nil
+}
+
+// spanOptionText 根据选择获取区间文本
+// selection 可以是 original、recommended 或版本类型；版本未修改该区间时返回原文
+func spanOptionText(span model.ConsensusSpan, selection string) (string, bool) {
+	switch selection {
+	case model.ConsensusSelectOriginal:
+		return span.OriginalText, true
+	case model.ConsensusSelectRecommended:
+		return span.Options[0].Text, true
+	}
+
+	for _, option := range span.Options {
+		if containsString(option.Versions, selection) {
+			return option.Text, true
+		}
+	}
+	if containsString(span.UnchangedVersions, selection) {
+		return span.OriginalText, true
+	}
+	return "", false
+}
+
+// containsString 判断切片中是否包含指定字符串
+func containsString(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
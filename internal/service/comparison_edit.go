@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"paper_ai/internal/domain/entity"
+	"paper_ai/internal/domain/model"
+	"paper_ai/internal/infrastructure/comparison"
+	apperrors "paper_ai/pkg/errors"
+	"paper_ai/pkg/logger"
+	"paper_ai/pkg/reqctx"
+
+	"go.uber.org/zap"
+)
+
+// manualChangeReason 手动修改的理由
+const manualChangeReason = "用户手动编辑"
+
+// EditContent 对最终文本的任意区间进行自由编辑（仅主记录）
+// 编辑后的最终文本与原文重新 diff，据此更新标注，保证标注与最终文本一致：
+//   - 原文区间与已有修改相同的片段沿用该修改：内容与建议一致为 accepted，否则为 edited
+//   - 其余片段作为新的手动修改（manual，edited）加入标注
+//   - 未出现在编辑结果中的已应用修改，以及被编辑覆盖的待处理修改标记为 rejected
+func (s *ComparisonService) EditContent(ctx context.Context, traceID string, userID int64, req *model.ContentEditRequest) (*model.ChangeActionResponse, error) {
+	ctx = reqctx.WithTraceID(ctx, traceID)
+
+	// 1. 获取记录和当前对比数据
+	record, err := s.getOwnedRecord(ctx, traceID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.GenerateComparison(ctx, traceID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 在当前最终文本上应用编辑
+	current := []rune(s.applyChanges(result))
+	if req.Start > req.End || req.End > len(current) {
+		return nil, apperrors.NewInvalidParameterError(fmt.Sprintf("编辑区间 [%d, %d) 超出最终文本范围（共 %d 个字符）", req.Start, req.End, len(current)))
+	}
+	edited := string(current[:req.Start]) + req.Text + string(current[req.End:])
+
+	// 3. 与原文重新 diff，并更新标注状态
+	diffs := s.builder.diffEngine.GenerateDiff(result.OriginalContent, edited)
+	finalChanges := s.builder.diffEngine.GetChanges(diffs)
+	changes := s.mergeEdits(result, finalChanges)
+
+	// 4. 保存并记录日志
+	updatedContent := s.applyChanges(result)
+	if updatedContent != edited {
+		// 理论上不会发生：标注无法还原编辑结果时拒绝保存，避免最终文本与标注不一致
+		logger.FromContext(ctx).Error("edited content mismatch after merging annotations",
+			zap.Int("start", req.Start),
+			zap.Int("end", req.End))
+		return nil, fmt.Errorf("合并编辑失败")
+	}
+
+	record.FinalContent = updatedContent
+	if err := s.saveComparisonData(ctx, record, result); err != nil {
+		logger.FromContext(ctx).Error("failed to save edited comparison data", zap.Error(err))
+		return nil, fmt.Errorf("保存对比数据失败: %w", err)
+	}
+	s.logAction(ctx, record, entity.ComparisonActionFreeEdit, 0, changes)
+
+	appliedChanges := make([]string, 0, len(changes))
+	for _, change := range changes {
+		appliedChanges = append(appliedChanges, change.ChangeID)
+	}
+	pendingChanges := []string{}
+	for _, ann := range result.Annotations {
+		if ann.Status == model.ActionStatusPending {
+			pendingChanges = append(pendingChanges, ann.ID)
+		}
+	}
+
+	return &model.ChangeActionResponse{
+		Success:        true,
+		UpdatedContent: updatedContent,
+		AppliedChanges: appliedChanges,
+		PendingChanges: pendingChanges,
+	}, nil
+}
+
+// mergeEdits 将编辑结果相对原文的修改合并到标注中，返回状态变化记录
+func (s *ComparisonService) mergeEdits(result *model.ComparisonResult, finalChanges []comparison.ChangeInfo) []entity.ActionChangeRecord {
+	records := make([]entity.ActionChangeRecord, 0)
+	matched := make(map[int]bool, len(result.Annotations))
+	manualCount := 0
+	for _, ann := range result.Annotations {
+		if ann.Manual {
+			manualCount++
+		}
+	}
+
+	// 1. 逐个处理编辑结果中的修改
+	manualChanges := make([]model.Change, 0)
+	offset := 0 // 编辑结果相对原文的位置偏移（rune）
+	for _, change := range finalChanges {
+		finalStart := change.OriginalStart + offset
+		finalEnd := finalStart + runeLen(change.PolishedText)
+		offset += runeLen(change.PolishedText) - (change.OriginalEnd - change.OriginalStart)
+
+		if idx := findByOriginalRange(result.Annotations, change.OriginalStart, change.OriginalEnd); idx != -1 {
+			ann := &result.Annotations[idx]
+			matched[idx] = true
+			record := setStatus(ann, editedStatus(ann, change.PolishedText), change.PolishedText)
+			if !record.IsNoop() {
+				records = append(records, record)
+			}
+			continue
+		}
+
+		// 被本次修改覆盖的其他修改不再适用
+		for i := range result.Annotations {
+			ann := &result.Annotations[i]
+			if matched[i] || ann.Status == model.ActionStatusRejected ||
+				!rangesOverlap(ann.OriginalPosition.Start, ann.OriginalPosition.End, change.OriginalStart, change.OriginalEnd) {
+				continue
+			}
+			matched[i] = true
+			records = append(records, setStatus(ann, model.ActionStatusRejected, ""))
+		}
+
+		manualCount++
+		manual := s.builder.manualChange(fmt.Sprintf("edit_%d", manualCount), change, finalStart, finalEnd)
+		manualChanges = append(manualChanges, manual)
+		records = append(records, entity.ActionChangeRecord{
+			ChangeID:  manual.ID,
+			Before:    string(model.ActionStatusRejected),
+			After:     string(manual.Status),
+			AfterText: manual.EditedText,
+		})
+	}
+
+	// 2. 未出现在编辑结果中的已应用修改视为被用户撤回
+	for i := range result.Annotations {
+		ann := &result.Annotations[i]
+		if !matched[i] && ann.Status.IsApplied() {
+			records = append(records, setStatus(ann, model.ActionStatusRejected, ""))
+		}
+	}
+
+	result.Annotations = append(result.Annotations, manualChanges...)
+	return records
+}
+
+// manualChange 根据编辑结果中的修改生成手动修改标注
+// polished_position 为该修改在最终文本中的位置
+func (b *comparisonBuilder) manualChange(id string, change comparison.ChangeInfo, finalStart, finalEnd int) model.Change {
+	changeType := b.classifier.Classify(change.OriginalText, change.PolishedText)
+
+	return model.Change{
+		ID:   id,
+		Type: changeType,
+		PolishedPosition: model.Position{
+			Start: finalStart,
+			End:   finalEnd,
+		},
+		PolishedText: change.PolishedText,
+		OriginalText: change.OriginalText,
+		OriginalPosition: model.Position{
+			Start: change.OriginalStart,
+			End:   change.OriginalEnd,
+		},
+		Reason:         manualChangeReason,
+		Alternatives:   []model.Alternative{},
+		Confidence:     1,
+		Impact:         b.reasonGenerator.GetImpact(changeType),
+		HighlightColor: b.classifier.SuggestHighlightColor(changeType),
+		Status:         model.ActionStatusEdited,
+		EditedText:     change.PolishedText,
+		Manual:         true,
+	}
+}
+
+// editedStatus 用户给出替换文本后修改应处的状态
+// 与建议相同视为接受，与原文相同视为拒绝，否则为手动编辑
+func editedStatus(ann *model.Change, text string) model.ActionStatus {
+	switch text {
+	case ann.PolishedText:
+		if ann.Manual {
+			return model.ActionStatusEdited
+		}
+		return model.ActionStatusAccepted
+	case ann.OriginalText:
+		return model.ActionStatusRejected
+	default:
+		return model.ActionStatusEdited
+	}
+}
+
+// findByOriginalRange 查找原文区间完全相同的修改（有多个时优先已应用的修改）
+func findByOriginalRange(annotations []model.Change, start, end int) int {
+	found := -1
+	for i, ann := range annotations {
+		if ann.OriginalPosition.Start != start || ann.OriginalPosition.End != end {
+			continue
+		}
+		if ann.Status.IsApplied() {
+			return i
+		}
+		if found == -1 {
+			found = i
+		}
+	}
+	return found
+}
+
+// rangesOverlap 判断两个原文区间是否重叠（插入视为宽度为 0 的区间，落在另一区间内或边界上即重叠）
+func rangesOverlap(aStart, aEnd, bStart, bEnd int) bool {
+	if aStart == aEnd || bStart == bEnd {
+		return aStart <= bEnd && bStart <= aEnd
+	}
+	return aStart < bEnd && bStart < aEnd
+}
+
+// runeLen 计算字符串的 rune 数
+func runeLen(text string) int {
+	return len([]rune(text))
+}
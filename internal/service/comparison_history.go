@@ -0,0 +1,342 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"paper_ai/internal/domain/entity"
+	"paper_ai/internal/domain/model"
+	"paper_ai/internal/domain/repository"
+	apperrors "paper_ai/pkg/errors"
+	"paper_ai/pkg/logger"
+	"paper_ai/pkg/reqctx"
+
+	"go.uber.org/zap"
+)
+
+// 操作历史只覆盖主记录（未指定 version）上的操作，包括手动编辑；
+// 指定版本的操作直接改写版本内容，不记录到操作日志；
+// 选择版本会重新生成对比数据，并追加一条 reset 操作作为历史分界
+
+// GetHistory 获取操作历史
+func (s *ComparisonService) GetHistory(ctx context.Context, traceID string, userID int64) (*model.ActionHistoryResponse, error) {
+	ctx = reqctx.WithTraceID(ctx, traceID)
+	record, err := s.getOwnedRecord(ctx, traceID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	actions, err := s.listActions(ctx, record)
+	if err != nil {
+		return nil, err
+	}
+
+	done, undone := undoRedoStacks(actions)
+
+	result := &model.ActionHistoryResponse{
+		TraceID: traceID,
+		Entries: make([]model.ActionLogEntry, len(actions)),
+		CanUndo: len(done) > 0,
+		CanRedo: len(undone) > 0,
+	}
+	if len(done) > 0 {
+		result.UndoSeq = done[len(done)-1].Seq
+	}
+	if len(undone) > 0 {
+		result.RedoSeq = undone[len(undone)-1].Seq
+	}
+
+	for i, action := range actions {
+		changes := make([]model.ActionChange, len(action.Changes))
+		for j, change := range action.Changes {
+			changes[j] = model.ActionChange{
+				ChangeID:   change.ChangeID,
+				Before:     model.ActionStatus(change.Before),
+				After:      model.ActionStatus(change.After),
+				BeforeText: change.BeforeText,
+				AfterText:  change.AfterText,
+			}
+		}
+		result.Entries[i] = model.ActionLogEntry{
+			Seq:       action.Seq,
+			Action:    action.Action,
+			TargetSeq: action.TargetSeq,
+			Changes:   changes,
+			CreatedAt: action.CreatedAt,
+		}
+	}
+
+	return result, nil
+}
+
+// GetContentAt 获取执行完第 seq 条操作后的最终文本（seq 为 0 表示执行任何操作之前）
+// 从当前状态出发，按倒序回退 seq 之后的操作
+func (s *ComparisonService) GetContentAt(ctx context.Context, traceID string, userID int64, seq int) (*model.HistoryContentResponse, error) {
+	ctx = reqctx.WithTraceID(ctx, traceID)
+	record, err := s.getOwnedRecord(ctx, traceID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	actions, err := s.listActions(ctx, record)
+	if err != nil {
+		return nil, err
+	}
+
+	if seq < 0 || seq > len(actions) {
+		return nil, apperrors.NewInvalidParameterError(fmt.Sprintf("操作序号 %d 超出范围（共 %d 条操作）", seq, len(actions)))
+	}
+	// 重置之前的操作针对的是旧的对比数据，无法在当前对比数据上还原
+	if resetSeq := lastResetSeq(actions); seq < resetSeq {
+		return nil, apperrors.NewInvalidParameterError(fmt.Sprintf("第 %d 条操作之前重新选择了版本，只能查看序号 >= %d 的历史", resetSeq, resetSeq))
+	}
+
+	result, err := s.GenerateComparison(ctx, traceID)
+	if err != nil {
+		return nil, err
+	}
+
+	index := annotationIndex(result)
+	for i := len(actions) - 1; i >= 0 && actions[i].Seq > seq; i-- {
+		changes := actions[i].Changes
+		for j := len(changes) - 1; j >= 0; j-- {
+			if idx, ok := index[changes[j].ChangeID]; ok {
+				setStatus(&result.Annotations[idx], model.ActionStatus(changes[j].Before), changes[j].BeforeText)
+			}
+		}
+	}
+
+	statuses := make(map[string]model.ActionStatus, len(result.Annotations))
+	for _, ann := range result.Annotations {
+		statuses[ann.ID] = ann.Status
+	}
+
+	return &model.HistoryContentResponse{
+		TraceID:      traceID,
+		Seq:          seq,
+		FinalContent: s.applyChanges(result),
+		Statuses:     statuses,
+	}, nil
+}
+
+// Undo 撤销最近一次操作
+func (s *ComparisonService) Undo(ctx context.Context, traceID string, userID int64) (*model.ChangeActionResponse, error) {
+	return s.revertAction(ctx, traceID, userID, true)
+}
+
+// Redo 重做最近一次撤销的操作
+func (s *ComparisonService) Redo(ctx context.Context, traceID string, userID int64) (*model.ChangeActionResponse, error) {
+	return s.revertAction(ctx, traceID, userID, false)
+}
+
+// revertAction 执行撤销（undo=true）或重做（undo=false）
+// 撤销/重做本身也作为一条操作追加到日志中，日志中的已有记录不会被修改
+func (s *ComparisonService) revertAction(ctx context.Context, traceID string, userID int64, undo bool) (*model.ChangeActionResponse, error) {
+	ctx = reqctx.WithTraceID(ctx, traceID)
+	record, err := s.getOwnedRecord(ctx, traceID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	actions, err := s.listActions(ctx, record)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. 找到要撤销/重做的操作
+	done, undone := undoRedoStacks(actions)
+	var target *entity.ComparisonAction
+	logAction := entity.ComparisonActionRedo
+	if undo {
+		if len(done) == 0 {
+			return nil, apperrors.NewInvalidParameterError("没有可撤销的操作")
+		}
+		target = done[len(done)-1]
+		logAction = entity.ComparisonActionUndo
+	} else {
+		if len(undone) == 0 {
+			return nil, apperrors.NewInvalidParameterError("没有可重做的操作")
+		}
+		target = undone[len(undone)-1]
+	}
+
+	// 2. 获取当前对比数据并恢复状态
+	result, err := s.GenerateComparison(ctx, traceID)
+	if err != nil {
+		return nil, err
+	}
+
+	index := annotationIndex(result)
+	changes := make([]entity.ActionChangeRecord, 0, len(target.Changes))
+	appliedChanges := make([]string, 0, len(target.Changes))
+	for i := range target.Changes {
+		// 撤销按倒序恢复，重做按正序重放
+		change := target.Changes[i]
+		status, text := change.After, change.AfterText
+		if undo {
+			change = target.Changes[len(target.Changes)-1-i]
+			status, text = change.Before, change.BeforeText
+		}
+
+		idx, ok := index[change.ChangeID]
+		if !ok {
+			continue
+		}
+		changes = append(changes, setStatus(&result.Annotations[idx], model.ActionStatus(status), text))
+		appliedChanges = append(appliedChanges, change.ChangeID)
+	}
+
+	// 3. 保存并记录日志
+	updatedContent := s.applyChanges(result)
+	record.FinalContent = updatedContent
+	if err := s.saveComparisonData(ctx, record, result); err != nil {
+		logger.FromContext(ctx).Error("failed to save reverted comparison data", zap.Error(err))
+		return nil, fmt.Errorf("保存对比数据失败: %w", err)
+	}
+	if err := s.appendAction(ctx, record, logAction, target.Seq, changes); err != nil {
+		return nil, err
+	}
+
+	pendingChanges := []string{}
+	for _, ann := range result.Annotations {
+		if ann.Status == model.ActionStatusPending {
+			pendingChanges = append(pendingChanges, ann.ID)
+		}
+	}
+
+	return &model.ChangeActionResponse{
+		Success:        true,
+		UpdatedContent: updatedContent,
+		AppliedChanges: appliedChanges,
+		PendingChanges: pendingChanges,
+	}, nil
+}
+
+// getOwnedRecord 获取润色记录并验证权限
+func (s *ComparisonService) getOwnedRecord(ctx context.Context, traceID string, userID int64) (*entity.PolishRecord, error) {
+	record, err := s.polishRepo.GetByTraceID(ctx, traceID)
+	if err != nil {
+		return nil, apperrors.NewNotFoundError("润色记录不存在")
+	}
+
+	if record.UserID != userID {
+		return nil, apperrors.NewForbiddenError("无权访问该记录")
+	}
+
+	if err := checkContentStored(record); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// listActions 获取记录的操作日志
+func (s *ComparisonService) listActions(ctx context.Context, record *entity.PolishRecord) ([]*entity.ComparisonAction, error) {
+	if s.actionRepo == nil {
+		return []*entity.ComparisonAction{}, nil
+	}
+
+	actions, err := s.actionRepo.ListByRecordID(ctx, record.ID)
+	if err != nil {
+		return nil, fmt.Errorf("获取操作历史失败: %w", err)
+	}
+	return actions, nil
+}
+
+// logAction 记录操作日志，失败只记录日志，不影响主流程
+func (s *ComparisonService) logAction(ctx context.Context, record *entity.PolishRecord, action string, targetSeq int, changes []entity.ActionChangeRecord) {
+	if err := s.appendAction(ctx, record, action, targetSeq, changes); err != nil {
+		logger.FromContext(ctx).Warn("failed to log comparison action",
+			zap.String("action", action),
+			zap.Error(err))
+	}
+}
+
+// appendAction 追加操作日志
+func (s *ComparisonService) appendAction(ctx context.Context, record *entity.PolishRecord, action string, targetSeq int, changes []entity.ActionChangeRecord) error {
+	return appendComparisonAction(ctx, s.actionRepo, record, action, targetSeq, changes)
+}
+
+// appendComparisonAction 追加操作日志（普通操作没有状态变化时不记录）
+func appendComparisonAction(ctx context.Context, repo repository.ComparisonActionRepository, record *entity.PolishRecord, action string, targetSeq int, changes []entity.ActionChangeRecord) error {
+	if repo == nil {
+		return nil
+	}
+
+	entry := &entity.ComparisonAction{
+		RecordID:  record.ID,
+		TraceID:   record.TraceID,
+		UserID:    record.UserID,
+		Action:    action,
+		TargetSeq: targetSeq,
+		Changes:   changes,
+	}
+	if entry.IsStatusAction() && len(changes) == 0 {
+		return nil
+	}
+
+	return repo.Append(ctx, entry)
+}
+
+// undoRedoStacks 根据操作日志计算撤销栈和重做栈
+// 普通操作入撤销栈并清空重做栈；撤销从撤销栈移到重做栈；重做从重做栈移回撤销栈；重置清空两个栈
+func undoRedoStacks(actions []*entity.ComparisonAction) (done, undone []*entity.ComparisonAction) {
+	for _, action := range actions {
+		switch action.Action {
+		case entity.ComparisonActionReset:
+			done = nil
+			undone = nil
+		case entity.ComparisonActionUndo:
+			if len(done) > 0 {
+				undone = append(undone, done[len(done)-1])
+				done = done[:len(done)-1]
+			}
+		case entity.ComparisonActionRedo:
+			if len(undone) > 0 {
+				done = append(done, undone[len(undone)-1])
+				undone = undone[:len(undone)-1]
+			}
+		default:
+			done = append(done, action)
+			undone = nil
+		}
+	}
+	return done, undone
+}
+
+// lastResetSeq 最近一次重置操作的序号（没有时返回 0）
+func lastResetSeq(actions []*entity.ComparisonAction) int {
+	for i := len(actions) - 1; i >= 0; i-- {
+		if actions[i].Action == entity.ComparisonActionReset {
+			return actions[i].Seq
+		}
+	}
+	return 0
+}
+
+// setStatus 更新修改状态（editedText 仅在 edited 状态下保留），并返回状态变化记录
+func setStatus(ann *model.Change, status model.ActionStatus, editedText string) entity.ActionChangeRecord {
+	if status != model.ActionStatusEdited {
+		editedText = ""
+	}
+
+	change := entity.ActionChangeRecord{
+		ChangeID:   ann.ID,
+		Before:     string(ann.Status),
+		After:      string(status),
+		BeforeText: ann.EditedText,
+		AfterText:  editedText,
+	}
+	ann.Status = status
+	ann.EditedText = editedText
+	return change
+}
+
+// annotationIndex 建立修改ID到下标的索引
+func annotationIndex(result *model.ComparisonResult) map[string]int {
+	index := make(map[string]int, len(result.Annotations))
+	for i, ann := range result.Annotations {
+		index[ann.ID] = i
+	}
+	return index
+}
@@ -7,6 +7,7 @@ import (
 	"sort"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"paper_ai/internal/domain/entity"
 	"paper_ai/internal/domain/model"
@@ -14,6 +15,7 @@ import (
 	"paper_ai/internal/infrastructure/comparison"
 	apperrors "paper_ai/pkg/errors"
 	"paper_ai/pkg/logger"
+	"paper_ai/pkg/reqctx"
 
 	"go.uber.org/zap"
 )
@@ -22,35 +24,45 @@ import (
 type ComparisonService struct {
 	polishRepo       repository.PolishRepository
 	versionRepo      repository.PolishVersionRepository
-	diffEngine       *comparison.DiffEngine
-	positionCalc     *comparison.PositionCalculator
-	classifier       *comparison.ChangeClassifier
-	reasonGenerator  *comparison.ReasonGenerator
+	feedbackRepo     repository.ChangeFeedbackRepository
+	actionRepo       repository.ComparisonActionRepository
+	builder          *comparisonBuilder
+	aligner          *comparison.ConsensusAligner
 }
 
 // NewComparisonService 创建对比服务
 func NewComparisonService(
 	polishRepo repository.PolishRepository,
 	versionRepo repository.PolishVersionRepository,
+	feedbackRepo repository.ChangeFeedbackRepository,
+	actionRepo repository.ComparisonActionRepository,
 ) *ComparisonService {
 	return &ComparisonService{
-		polishRepo:      polishRepo,
-		versionRepo:     versionRepo,
-		diffEngine:      comparison.NewDiffEngine(),
-		positionCalc:    comparison.NewPositionCalculator(),
-		classifier:      comparison.NewChangeClassifier(),
-		reasonGenerator: comparison.NewReasonGenerator(),
+		polishRepo:   polishRepo,
+		versionRepo:  versionRepo,
+		feedbackRepo: feedbackRepo,
+		actionRepo:   actionRepo,
+		builder:      newComparisonBuilder(feedbackRepo),
+		aligner:      comparison.NewConsensusAligner(),
 	}
 }
 
 // GenerateComparison 生成对比数据
 func (s *ComparisonService) GenerateComparison(ctx context.Context, traceID string) (*model.ComparisonResult, error) {
+	ctx = reqctx.WithTraceID(ctx, traceID)
+
 	// 1. 获取润色记录
 	record, err := s.polishRepo.GetByTraceID(ctx, traceID)
 	if err != nil {
-		logger.Error("failed to get polish record", zap.String("trace_id", traceID), zap.Error(err))
+		logger.FromContext(ctx).Error("failed to get polish record", zap.Error(err))
 		return nil, apperrors.NewNotFoundError("润色记录不存在")
 	}
+	if err := checkContentStored(record); err != nil {
+		return nil, err
+	}
+	if record.IsTranslateMode() {
+		return nil, apperrors.NewInvalidParameterError("翻译记录不支持逐字对比，请使用双语对照")
+	}
 
 	// 2. 如果已有对比数据，直接返回
 	if record.ComparisonData != "" {
@@ -64,18 +76,14 @@ func (s *ComparisonService) GenerateComparison(ctx context.Context, traceID stri
 	}
 
 	// 3. 生成对比数据
-	result, err := s.generateComparisonData(record)
-	if err != nil {
-		logger.Error("failed to generate comparison data", zap.String("trace_id", traceID), zap.Error(err))
-		return nil, err
-	}
+	result := s.generateComparisonData(ctx, record)
 
 	// 4. 添加 final_content
 	result.FinalContent = record.FinalContent
 
 	// 5. 保存对比数据
 	if err := s.saveComparisonData(ctx, record, result); err != nil {
-		logger.Warn("failed to save comparison data", zap.String("trace_id", traceID), zap.Error(err))
+		logger.FromContext(ctx).Warn("failed to save comparison data", zap.Error(err))
 		// 不返回错误，继续返回生成的对比数据
 	}
 
@@ -83,122 +91,21 @@ func (s *ComparisonService) GenerateComparison(ctx context.Context, traceID stri
 }
 
 // generateComparisonData 生成对比数据
-func (s *ComparisonService) generateComparisonData(record *entity.PolishRecord) (*model.ComparisonResult, error) {
-	original := record.OriginalContent
-	polished := record.PolishedContent
-
-	// 1. 运行 diff 算法
-	diffs := s.diffEngine.GenerateDiff(original, polished)
-
-	// 2. 提取修改信息
-	changes := s.diffEngine.GetChanges(diffs)
-
-	// 3. 计算位置
-	positions := s.positionCalc.CalculatePositions(polished, changes)
-
-	// 4. 生成标注列表
-	annotations := s.buildAnnotations(positions)
-
-	// 5. 计算元数据和统计信息
-	metadata, statistics := s.calculateStats(original, polished, annotations)
-
-	return &model.ComparisonResult{
-		TraceID:         record.TraceID,
-		OriginalContent: original,
-		PolishedContent: polished,
-		Annotations:     annotations,
-		Metadata:        metadata,
-		Statistics:      statistics,
-	}, nil
-}
-
-// buildAnnotations 构建标注列表
-func (s *ComparisonService) buildAnnotations(positions []comparison.PositionInfo) []model.Change {
-	annotations := make([]model.Change, 0, len(positions))
-
-	for i, pos := range positions {
-		// 分类修改类型
-		changeType := s.classifier.Classify(pos.OriginalText, pos.PolishedText)
-
-		// 生成修改理由
-		reason := s.reasonGenerator.Generate(changeType, pos.OriginalText, pos.PolishedText)
-
-		// 生成替代方案
-		alternatives := s.reasonGenerator.GenerateAlternatives(changeType, pos.OriginalText)
-
-		// 计算置信度
-		confidence := s.reasonGenerator.CalculateConfidence(changeType, pos.OriginalText, pos.PolishedText)
-
-		// 获取影响维度
-		impact := s.reasonGenerator.GetImpact(changeType)
-
-		// 建议高亮颜色
-		highlightColor := s.classifier.SuggestHighlightColor(changeType)
-
-		annotations = append(annotations, model.Change{
-			ID:   fmt.Sprintf("change_%d", i+1),
-			Type: changeType,
-			PolishedPosition: model.Position{
-				Start: pos.Start,
-				End:   pos.End,
-				Line:  pos.Line,
-			},
-			PolishedText:   pos.PolishedText,
-			OriginalText:   pos.OriginalText,
-			Reason:         reason,
-			Alternatives:   alternatives,
-			Confidence:     confidence,
-			Impact:         impact,
-			HighlightColor: highlightColor,
-			Status:         model.ActionStatusPending,
-		})
-	}
-
-	return annotations
-}
-
-// calculateStats 计算统计信息
-func (s *ComparisonService) calculateStats(original, polished string, annotations []model.Change) (model.Metadata, model.Statistics) {
-	originalWordCount := comparison.CountWords(original)
-	polishedWordCount := comparison.CountWords(polished)
-
-	// 统计各类修改数量
-	var vocabCount, grammarCount, structureCount int
-	for _, ann := range annotations {
-		switch ann.Type {
-		case model.ChangeTypeVocabulary:
-			vocabCount++
-		case model.ChangeTypeGrammar:
-			grammarCount++
-		case model.ChangeTypeStructure:
-			structureCount++
-		}
-	}
-
-	// 计算学术性提升（简单算法：词汇优化占比 * 100）
-	academicImprovement := 0.0
-	if len(annotations) > 0 {
-		academicImprovement = float64(vocabCount) / float64(len(annotations)) * 100
-		// 限制在0-100之间
-		if academicImprovement > 100 {
-			academicImprovement = 100
-		}
-	}
-
-	metadata := model.Metadata{
-		OriginalWordCount:        originalWordCount,
-		PolishedWordCount:        polishedWordCount,
-		TotalChanges:             len(annotations),
-		AcademicScoreImprovement: academicImprovement,
+func (s *ComparisonService) generateComparisonData(ctx context.Context, record *entity.PolishRecord) *model.ComparisonResult {
+	in := comparisonInput{
+		TraceID:       record.TraceID,
+		Original:      record.OriginalContent,
+		Polished:      record.PolishedContent,
+		TokenLogProbs: record.TokenLogProbs,
+		Selection:     recordSelection(record),
 	}
 
-	statistics := model.Statistics{
-		VocabularyChanges: vocabCount,
-		GrammarChanges:    grammarCount,
-		StructureChanges:  structureCount,
+	// 多版本润色：其他版本用于计算多版本一致性
+	if record.IsMultiVersionMode() {
+		in.PeerContents = loadPeerContents(ctx, s.versionRepo, record.ID)
 	}
 
-	return metadata, statistics
+	return s.builder.build(ctx, in)
 }
 
 // saveComparisonData 保存对比数据
@@ -240,8 +147,15 @@ func (s *ComparisonService) parseComparisonData(record *entity.PolishRecord) (*m
 }
 
 // GetComparison 获取对比数据（外部接口）
-// versionType: 可选参数，指定多版本润色中的某个版本（conservative/balanced/aggressive）
-func (s *ComparisonService) GetComparison(ctx context.Context, traceID string, userID int64, versionType string) (*model.ComparisonResult, error) {
+// opts.VersionType: 可选参数，指定多版本润色中的某个版本（conservative/balanced/aggressive）
+// opts.AutoAcceptThreshold: 自动接受置信度达到阈值的待处理修改
+// opts.MinConfidence: 只返回置信度达到该值的修改（不影响已保存的数据）
+func (s *ComparisonService) GetComparison(ctx context.Context, traceID string, userID int64, opts *model.ComparisonOptions) (*model.ComparisonResult, error) {
+	ctx = reqctx.WithTraceID(ctx, traceID)
+	if opts == nil {
+		opts = &model.ComparisonOptions{}
+	}
+
 	// 1. 获取润色记录
 	record, err := s.polishRepo.GetByTraceID(ctx, traceID)
 	if err != nil {
@@ -253,13 +167,94 @@ func (s *ComparisonService) GetComparison(ctx context.Context, traceID string, u
 		return nil, apperrors.NewForbiddenError("无权访问该记录")
 	}
 
-	// 3. 如果指定了版本类型，使用该版本的内容
-	if versionType != "" {
-		return s.generateComparisonForVersion(ctx, record, versionType)
+	if err := checkContentStored(record); err != nil {
+		return nil, err
+	}
+	if record.IsTranslateMode() {
+		return nil, apperrors.NewInvalidParameterError("翻译记录不支持逐字对比，请使用双语对照")
+	}
+
+	// 3. 如果指定了版本类型，使用该版本的内容；否则使用主记录（兼容单版本润色）
+	var result *model.ComparisonResult
+	if opts.VersionType != "" {
+		result, err = s.generateComparisonForVersion(ctx, record, opts.VersionType)
+	} else {
+		result, err = s.GenerateComparison(ctx, traceID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. 自动接受高置信度修改
+	if opts.AutoAcceptThreshold > 0 {
+		s.autoAccept(ctx, record, result, opts)
+	}
+
+	// 5. 过滤低置信度修改
+	if opts.MinConfidence > 0 {
+		filterByConfidence(result, opts.MinConfidence)
+	}
+
+	return result, nil
+}
+
+// autoAccept 自动接受置信度达到阈值的待处理修改
+// 仅主记录模式下保存结果；指定版本时只影响本次返回的数据
+func (s *ComparisonService) autoAccept(ctx context.Context, record *entity.PolishRecord, result *model.ComparisonResult, opts *model.ComparisonOptions) {
+	accepted := make([]model.Change, 0)
+	changes := make([]entity.ActionChangeRecord, 0)
+	for i := range result.Annotations {
+		ann := &result.Annotations[i]
+		if ann.Status == model.ActionStatusPending && ann.Confidence >= opts.AutoAcceptThreshold {
+			changes = append(changes, setStatus(ann, model.ActionStatusAccepted, ""))
+			accepted = append(accepted, *ann)
+		}
+	}
+	if len(accepted) == 0 {
+		return
+	}
+
+	result.FinalContent = s.applyChanges(result)
+
+	if opts.VersionType != "" {
+		return
+	}
+
+	record.FinalContent = result.FinalContent
+	if err := s.saveComparisonData(ctx, record, result); err != nil {
+		logger.FromContext(ctx).Warn("failed to save auto-accepted comparison data", zap.Error(err))
+	}
+	s.logAction(ctx, record, entity.ComparisonActionAutoAccept, 0, changes)
+	s.recordFeedback(ctx, record, accepted)
+}
+
+// filterByConfidence 过滤置信度低于阈值的修改，并记录被隐藏的数量
+func filterByConfidence(result *model.ComparisonResult, minConfidence float64) {
+	visible := make([]model.Change, 0, len(result.Annotations))
+	for _, ann := range result.Annotations {
+		if ann.Confidence >= minConfidence {
+			visible = append(visible, ann)
+		}
+	}
+	result.Metadata.HiddenChanges = len(result.Annotations) - len(visible)
+	result.Annotations = visible
+}
+
+// recordFeedback 记录用户对修改的接受/拒绝，用于统计历史接受率
+// 失败只记录日志，不影响主流程
+func (s *ComparisonService) recordFeedback(ctx context.Context, record *entity.PolishRecord, changes []model.Change) {
+	if s.feedbackRepo == nil {
+		return
 	}
 
-	// 4. 未指定版本，使用主记录（兼容单版本润色）
-	return s.GenerateComparison(ctx, traceID)
+	feedbacks := buildFeedbacks(record.UserID, record.TraceID, changes)
+	if len(feedbacks) == 0 {
+		return
+	}
+
+	if err := s.feedbackRepo.BatchCreate(ctx, feedbacks); err != nil {
+		logger.FromContext(ctx).Warn("failed to record change feedback", zap.Error(err))
+	}
 }
 
 // generateComparisonForVersion 为指定版本生成对比数据
@@ -267,7 +262,7 @@ func (s *ComparisonService) generateComparisonForVersion(ctx context.Context, re
 	// 1. 查询指定版本
 	version, err := s.versionRepo.GetByRecordIDAndType(ctx, record.ID, versionType)
 	if err != nil {
-		logger.Error("failed to get version",
+		logger.FromContext(ctx).Error("failed to get version",
 			zap.Int64("record_id", record.ID),
 			zap.String("version_type", versionType),
 			zap.Error(err))
@@ -279,38 +274,23 @@ func (s *ComparisonService) generateComparisonForVersion(ctx context.Context, re
 		return nil, apperrors.NewInvalidParameterError(fmt.Sprintf("版本 %s 生成失败: %s", versionType, version.ErrorMessage))
 	}
 
-	// 3. 使用版本的润色内容生成对比
-	original := record.OriginalContent
-	polished := version.PolishedContent
-
-	// 4. 运行 diff 算法
-	diffs := s.diffEngine.GenerateDiff(original, polished)
-
-	// 5. 提取修改信息
-	changes := s.diffEngine.GetChanges(diffs)
-
-	// 6. 计算位置
-	positions := s.positionCalc.CalculatePositions(polished, changes)
-
-	// 7. 生成标注列表
-	annotations := s.buildAnnotations(positions)
-
-	// 8. 计算元数据和统计信息
-	metadata, statistics := s.calculateStats(original, polished, annotations)
+	// 3. 使用版本的润色内容生成对比（其他成功版本用于计算多版本一致性）
+	result := s.builder.build(ctx, comparisonInput{
+		TraceID:       record.TraceID,
+		Original:      record.OriginalContent,
+		Polished:      version.PolishedContent,
+		TokenLogProbs: version.TokenLogProbs,
+		PeerContents:  loadPeerContents(ctx, s.versionRepo, record.ID),
+	})
+	result.FinalContent = record.FinalContent // 添加 final_content
 
-	return &model.ComparisonResult{
-		TraceID:         record.TraceID,
-		OriginalContent: original,
-		PolishedContent: polished,
-		FinalContent:    record.FinalContent, // 添加 final_content
-		Annotations:     annotations,
-		Metadata:        metadata,
-		Statistics:      statistics,
-	}, nil
+	return result, nil
 }
 
 // ApplyAction 应用用户操作（接受/拒绝修改）
 func (s *ComparisonService) ApplyAction(ctx context.Context, traceID string, userID int64, versionType string, req *model.ChangeActionRequest) (*model.ChangeActionResponse, error) {
+	ctx = reqctx.WithTraceID(ctx, traceID)
+
 	// 1. 获取记录
 	record, err := s.polishRepo.GetByTraceID(ctx, traceID)
 	if err != nil {
@@ -322,6 +302,10 @@ func (s *ComparisonService) ApplyAction(ctx context.Context, traceID string, use
 		return nil, apperrors.NewForbiddenError("无权访问该记录")
 	}
 
+	if err := checkContentStored(record); err != nil {
+		return nil, err
+	}
+
 	// 3. 获取对比数据（根据是否指定版本）
 	var result *model.ComparisonResult
 	if versionType != "" {
@@ -347,13 +331,21 @@ func (s *ComparisonService) ApplyAction(ctx context.Context, traceID string, use
 	}
 
 	// 5. 应用操作
+	target := &result.Annotations[changeIndex]
+	var change entity.ActionChangeRecord
 	if req.Action == "accept" {
-		result.Annotations[changeIndex].Status = model.ActionStatusAccepted
+		change = setStatus(target, model.ActionStatusAccepted, "")
 	} else if req.Action == "reject" {
-		result.Annotations[changeIndex].Status = model.ActionStatusRejected
+		change = setStatus(target, model.ActionStatusRejected, "")
+	} else if req.Action == "edit" {
+		if req.EditedText == nil {
+			return nil, apperrors.NewInvalidParameterError("edit 操作需要提供 edited_text")
+		}
+		change = setStatus(target, editedStatus(target, *req.EditedText), *req.EditedText)
 	}
 
-	// 6. 生成更新后的内容
+	// 6. 记录用户反馈并生成更新后的内容
+	s.recordFeedback(ctx, record, result.Annotations[changeIndex:changeIndex+1])
 	updatedContent := s.applyChanges(result)
 
 	// 7. 统计已应用和待处理的修改
@@ -375,7 +367,7 @@ func (s *ComparisonService) ApplyAction(ctx context.Context, traceID string, use
 
 		version.PolishedContent = updatedContent
 		if err := s.versionRepo.Update(ctx, version); err != nil {
-			logger.Error("failed to update version content",
+			logger.FromContext(ctx).Error("failed to update version content",
 				zap.Int64("version_id", version.ID),
 				zap.String("version_type", versionType),
 				zap.Error(err))
@@ -385,7 +377,10 @@ func (s *ComparisonService) ApplyAction(ctx context.Context, traceID string, use
 		// 单版本模式：更新主记录的 final_content
 		record.FinalContent = updatedContent
 		if err := s.saveComparisonData(ctx, record, result); err != nil {
-			logger.Warn("failed to save updated comparison data", zap.Error(err))
+			logger.FromContext(ctx).Warn("failed to save updated comparison data", zap.Error(err))
+		}
+		if !change.IsNoop() {
+			s.logAction(ctx, record, req.Action, 0, []entity.ActionChangeRecord{change})
 		}
 	}
 
@@ -398,18 +393,18 @@ func (s *ComparisonService) ApplyAction(ctx context.Context, traceID string, use
 }
 
 // applyChanges 应用所有接受的修改，生成最终文本
-// 策略：从原文开始，应用所有 accepted 状态的修改
+// 策略：从原文开始，应用所有 accepted 状态的修改以及 edited 状态的用户编辑
 func (s *ComparisonService) applyChanges(result *model.ComparisonResult) string {
 	// 1. 如果没有任何修改或全部拒绝，返回原文
-	hasAcceptedChanges := false
+	hasAppliedChanges := false
 	for _, ann := range result.Annotations {
-		if ann.Status == model.ActionStatusAccepted {
-			hasAcceptedChanges = true
+		if ann.Status.IsApplied() {
+			hasAppliedChanges = true
 			break
 		}
 	}
 
-	if !hasAcceptedChanges {
+	if !hasAppliedChanges {
 		return result.OriginalContent
 	}
 
@@ -426,64 +421,81 @@ func (s *ComparisonService) applyChanges(result *model.ComparisonResult) string
 		return result.PolishedContent
 	}
 
-	// 3. 部分接受：需要重新构建文本
-	// 使用 DiffEngine 重新计算，基于原文应用接受的修改
+	// 3. 部分接受或存在用户编辑：需要重新构建文本
 	return s.rebuildTextWithAcceptedChanges(result)
 }
 
-// rebuildTextWithAcceptedChanges 重新构建文本（只应用接受的修改）
+// rebuildTextWithAcceptedChanges 重新构建文本（只应用接受和编辑的修改）
+// 按修改在原文中的位置替换；旧数据没有原文位置时，退回在原文中查找原始文本
 func (s *ComparisonService) rebuildTextWithAcceptedChanges(result *model.ComparisonResult) string {
-	// 简化实现：使用字符串替换
-	// 从原文开始，逐个应用接受的修改
+	origRunes := []rune(result.OriginalContent)
 
-	finalText := result.OriginalContent
-
-	// 收集所有接受的修改，并按原文中的位置排序（从后往前替换，避免位置偏移）
-	type acceptedChange struct {
-		originalText string
-		polishedText string
-		firstIndex   int // 在原文中首次出现的位置
+	type appliedChange struct {
+		start int
+		end   int
+		text  string
 	}
 
-	acceptedChanges := []acceptedChange{}
+	appliedChanges := []appliedChange{}
 
 	for _, ann := range result.Annotations {
-		if ann.Status == model.ActionStatusAccepted {
-			// 在原文中查找该词的位置
-			idx := strings.Index(finalText, ann.OriginalText)
-			if idx != -1 {
-				acceptedChanges = append(acceptedChanges, acceptedChange{
-					originalText: ann.OriginalText,
-					polishedText: ann.PolishedText,
-					firstIndex:   idx,
-				})
+		if !ann.Status.IsApplied() {
+			continue
+		}
+
+		text := ann.PolishedText
+		if ann.Status == model.ActionStatusEdited {
+			text = ann.EditedText
+		}
+
+		start, end := ann.OriginalPosition.Start, ann.OriginalPosition.End
+		if end <= start && ann.OriginalText != "" {
+			// 旧数据：在原文中查找该词的位置
+			idx := strings.Index(result.OriginalContent, ann.OriginalText)
+			if idx == -1 {
+				continue
 			}
+			start = utf8.RuneCountInString(result.OriginalContent[:idx])
+			end = start + utf8.RuneCountInString(ann.OriginalText)
+		}
+		if start < 0 || end > len(origRunes) || start > end {
+			continue
 		}
+
+		appliedChanges = append(appliedChanges, appliedChange{start: start, end: end, text: text})
 	}
 
-	// 按位置从后往前排序（避免替换后位置偏移）
-	sort.Slice(acceptedChanges, func(i, j int) bool {
-		return acceptedChanges[i].firstIndex > acceptedChanges[j].firstIndex
+	// 按位置从前往后排序（稳定排序保证同一位置的插入保持原有顺序）
+	sort.SliceStable(appliedChanges, func(i, j int) bool {
+		return appliedChanges[i].start < appliedChanges[j].start
 	})
 
-	// 应用替换
-	for _, change := range acceptedChanges {
-		// 只替换第一次出现的位置
-		idx := strings.Index(finalText, change.originalText)
-		if idx != -1 {
-			finalText = finalText[:idx] + change.polishedText + finalText[idx+len(change.originalText):]
+	// 依次拼接未修改的原文和替换文本，跳过与已应用修改重叠的修改
+	var builder strings.Builder
+	pos := 0
+	for _, change := range appliedChanges {
+		if change.start < pos {
+			continue
 		}
+		builder.WriteString(string(origRunes[pos:change.start]))
+		builder.WriteString(change.text)
+		pos = change.end
 	}
+	builder.WriteString(string(origRunes[pos:]))
 
-	return finalText
+	return builder.String()
 }
 
-// BatchAcceptAll 一键接受所有修改
-func (s *ComparisonService) BatchAcceptAll(ctx context.Context, traceID string, userID int64, versionType string) (*model.BatchActionResponse, error) {
+// BatchApply 批量接受或拒绝修改
+// 指定 change_ids 时只处理这些修改（不论当前状态），否则处理所有待处理的修改；
+// 类型和置信度筛选条件进一步缩小处理范围
+func (s *ComparisonService) BatchApply(ctx context.Context, traceID string, userID int64, versionType string, req *model.BatchActionRequest) (*model.BatchActionResponse, error) {
+	ctx = reqctx.WithTraceID(ctx, traceID)
+
 	// 1. 获取记录
 	record, err := s.polishRepo.GetByTraceID(ctx, traceID)
 	if err != nil {
-		logger.Error("failed to get polish record", zap.String("trace_id", traceID), zap.Error(err))
+		logger.FromContext(ctx).Error("failed to get polish record", zap.Error(err))
 		return nil, apperrors.NewNotFoundError("润色记录不存在")
 	}
 
@@ -492,6 +504,10 @@ func (s *ComparisonService) BatchAcceptAll(ctx context.Context, traceID string,
 		return nil, apperrors.NewForbiddenError("无权访问该记录")
 	}
 
+	if err := checkContentStored(record); err != nil {
+		return nil, err
+	}
+
 	// 3. 获取对比数据（根据是否指定版本）
 	var result *model.ComparisonResult
 	if versionType != "" {
@@ -503,19 +519,38 @@ func (s *ComparisonService) BatchAcceptAll(ctx context.Context, traceID string,
 		return nil, err
 	}
 
-	// 4. 批量接受所有修改
-	appliedCount := 0
-	for i := range result.Annotations {
-		if result.Annotations[i].Status == model.ActionStatusPending {
-			result.Annotations[i].Status = model.ActionStatusAccepted
-			appliedCount++
+	// 4. 确定要处理的修改
+	targets, err := selectBatchTargets(result, req)
+	if err != nil {
+		return nil, err
+	}
+
+	status := model.ActionStatusAccepted
+	logActionType := entity.ComparisonActionAcceptAll
+	if req.Action == "reject_all" {
+		status = model.ActionStatusRejected
+		logActionType = entity.ComparisonActionRejectAll
+	}
+
+	// 5. 批量更新状态
+	updated := make([]model.Change, 0, len(targets))
+	changes := make([]entity.ActionChangeRecord, 0, len(targets))
+	appliedChanges := make([]string, 0, len(targets))
+	for _, i := range targets {
+		change := setStatus(&result.Annotations[i], status, "")
+		if change.IsNoop() {
+			continue
 		}
+		changes = append(changes, change)
+		updated = append(updated, result.Annotations[i])
+		appliedChanges = append(appliedChanges, result.Annotations[i].ID)
 	}
+	s.recordFeedback(ctx, record, updated)
 
-	// 5. 生成最终文本（全部接受 = 润色后文本）
-	finalContent := result.PolishedContent
+	// 6. 生成最终文本
+	finalContent := s.applyChanges(result)
 
-	// 6. 保存更新（根据是否指定版本更新不同的表）
+	// 7. 保存更新（根据是否指定版本更新不同的表）
 	if versionType != "" {
 		// 多版本模式：更新版本表的 polished_content
 		version, err := s.versionRepo.GetByRecordIDAndType(ctx, record.ID, versionType)
@@ -525,7 +560,7 @@ func (s *ComparisonService) BatchAcceptAll(ctx context.Context, traceID string,
 
 		version.PolishedContent = finalContent
 		if err := s.versionRepo.Update(ctx, version); err != nil {
-			logger.Error("failed to update version content",
+			logger.FromContext(ctx).Error("failed to update version content",
 				zap.Int64("version_id", version.ID),
 				zap.String("version_type", versionType),
 				zap.Error(err))
@@ -535,13 +570,48 @@ func (s *ComparisonService) BatchAcceptAll(ctx context.Context, traceID string,
 		// 单版本模式：更新主记录的 final_content
 		record.FinalContent = finalContent
 		if err := s.saveComparisonData(ctx, record, result); err != nil {
-			logger.Warn("failed to save updated comparison data", zap.Error(err))
+			logger.FromContext(ctx).Warn("failed to save updated comparison data", zap.Error(err))
 		}
+		s.logAction(ctx, record, logActionType, 0, changes)
 	}
 
 	return &model.BatchActionResponse{
 		Success:        true,
 		UpdatedContent: finalContent,
-		AppliedCount:   appliedCount,
+		AppliedCount:   len(appliedChanges),
+		AppliedChanges: appliedChanges,
+		StatusCounts:   model.CountStatuses(result.Annotations),
 	}, nil
 }
+
+// selectBatchTargets 按 change_ids 和筛选条件确定批量操作要处理的修改下标
+func selectBatchTargets(result *model.ComparisonResult, req *model.BatchActionRequest) ([]int, error) {
+	if req.MinConfidence != nil && req.MaxConfidence != nil && *req.MinConfidence > *req.MaxConfidence {
+		return nil, apperrors.NewInvalidParameterError("min_confidence 不能大于 max_confidence")
+	}
+
+	targets := make([]int, 0, len(result.Annotations))
+	if len(req.ChangeIDs) == 0 {
+		for i := range result.Annotations {
+			if result.Annotations[i].Status == model.ActionStatusPending && req.Matches(&result.Annotations[i]) {
+				targets = append(targets, i)
+			}
+		}
+		return targets, nil
+	}
+
+	index := annotationIndex(result)
+	seen := make(map[int]bool, len(req.ChangeIDs))
+	for _, id := range req.ChangeIDs {
+		i, ok := index[id]
+		if !ok {
+			return nil, apperrors.NewInvalidParameterError(fmt.Sprintf("修改不存在: %s", id))
+		}
+		if seen[i] || !req.Matches(&result.Annotations[i]) {
+			continue
+		}
+		seen[i] = true
+		targets = append(targets, i)
+	}
+	return targets, nil
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"paper_ai/internal/domain/entity"
+	"paper_ai/internal/domain/model"
 	"paper_ai/internal/domain/repository"
 	"paper_ai/pkg/logger"
 )
@@ -66,10 +67,31 @@ func (m *MockPolishRepository) BatchCreate(ctx context.Context, records []*entit
 func (m *MockPolishRepository) GetStatistics(ctx context.Context, opts repository.StatisticsOptions) (*repository.Statistics, error) {
 	return nil, nil
 }
+func (m *MockPolishRepository) MarkStaleProcessingFailed(ctx context.Context, before time.Time, errorMessage string) (int64, error) {
+	return 0, nil
+}
+func (m *MockPolishRepository) PurgeCreatedBefore(ctx context.Context, before time.Time, batchSize int) (int64, error) {
+	return 0, nil
+}
+func (m *MockPolishRepository) PurgeByUserRetention(ctx context.Context, batchSize int) (int64, error) {
+	return 0, nil
+}
+func (m *MockPolishRepository) PurgeByID(ctx context.Context, id int64) error {
+	return nil
+}
+func (m *MockPolishRepository) PurgeByUser(ctx context.Context, userID int64, start, end *time.Time) (int64, error) {
+	return 0, nil
+}
+func (m *MockPolishRepository) SetProject(ctx context.Context, id int64, projectID *int64) error {
+	return nil
+}
+func (m *MockPolishRepository) SetFavorite(ctx context.Context, id int64, favorite bool) error {
+	return nil
+}
 
 func TestComparisonService_GenerateComparison(t *testing.T) {
 	mockRepo := NewMockPolishRepository()
-	service := NewComparisonService(mockRepo)
+	service := NewComparisonService(mockRepo, nil, nil, nil)
 
 	// 准备测试数据
 	testRecord := &entity.PolishRecord{
@@ -158,7 +180,7 @@ func TestComparisonService_GenerateComparison(t *testing.T) {
 
 func TestComparisonService_GetComparison(t *testing.T) {
 	mockRepo := NewMockPolishRepository()
-	service := NewComparisonService(mockRepo)
+	service := NewComparisonService(mockRepo, nil, nil, nil)
 
 	testRecord := &entity.PolishRecord{
 		TraceID:         "1732701603456",
@@ -173,7 +195,7 @@ func TestComparisonService_GetComparison(t *testing.T) {
 	ctx := context.Background()
 
 	t.Run("获取对比数据", func(t *testing.T) {
-		result, err := service.GetComparison(ctx, "1732701603456", 12345)
+		result, err := service.GetComparison(ctx, "1732701603456", 12345, nil)
 		if err != nil {
 			t.Fatalf("GetComparison() 失败: %v", err)
 		}
@@ -186,7 +208,7 @@ func TestComparisonService_GetComparison(t *testing.T) {
 	})
 
 	t.Run("权限验证 - 不同用户", func(t *testing.T) {
-		_, err := service.GetComparison(ctx, "1732701603456", 99999)
+		_, err := service.GetComparison(ctx, "1732701603456", 99999, nil)
 		if err == nil {
 			t.Error("应该拒绝不同用户的访问")
 		}
@@ -195,7 +217,7 @@ func TestComparisonService_GetComparison(t *testing.T) {
 
 func TestComparisonService_ComplexText(t *testing.T) {
 	mockRepo := NewMockPolishRepository()
-	service := NewComparisonService(mockRepo)
+	service := NewComparisonService(mockRepo, nil, nil, nil)
 
 	// 更复杂的文本
 	complexRecord := &entity.PolishRecord{
@@ -252,7 +274,7 @@ Nevertheless, substantial obstacles remain to be surmounted.`,
 
 func TestComparisonService_SaveAndRetrieve(t *testing.T) {
 	mockRepo := NewMockPolishRepository()
-	service := NewComparisonService(mockRepo)
+	service := NewComparisonService(mockRepo, nil, nil, nil)
 
 	testRecord := &entity.PolishRecord{
 		TraceID:         "1732701603999",
@@ -284,3 +306,287 @@ func TestComparisonService_SaveAndRetrieve(t *testing.T) {
 
 	t.Logf("对比数据已保存，修改数量: %d", savedRecord.ChangesCount)
 }
+
+// MockComparisonActionRepository 模拟操作日志仓储
+type MockComparisonActionRepository struct {
+	actions []*entity.ComparisonAction
+}
+
+func (m *MockComparisonActionRepository) Append(ctx context.Context, action *entity.ComparisonAction) error {
+	action.Seq = len(m.actions) + 1
+	m.actions = append(m.actions, action)
+	return nil
+}
+
+func (m *MockComparisonActionRepository) ListByRecordID(ctx context.Context, recordID int64) ([]*entity.ComparisonAction, error) {
+	return m.actions, nil
+}
+
+func TestComparisonService_UndoRedo(t *testing.T) {
+	mockRepo := NewMockPolishRepository()
+	actionRepo := &MockComparisonActionRepository{}
+	service := NewComparisonService(mockRepo, nil, nil, actionRepo)
+
+	mockRepo.AddMockRecord(&entity.PolishRecord{
+		TraceID:         "1732701604000",
+		UserID:          12345,
+		OriginalContent: "We use a new method.",
+		PolishedContent: "We use a novel method.",
+		Status:          "success",
+		CreatedAt:       time.Now(),
+	})
+
+	ctx := context.Background()
+	result, err := service.GetComparison(ctx, "1732701604000", 12345, nil)
+	if err != nil {
+		t.Fatalf("GetComparison() 失败: %v", err)
+	}
+	if len(result.Annotations) == 0 {
+		t.Fatal("应该检测到修改")
+	}
+	changeID := result.Annotations[0].ID
+
+	if _, err := service.ApplyAction(ctx, "1732701604000", 12345, "", &model.ChangeActionRequest{ChangeID: changeID, Action: "accept"}); err != nil {
+		t.Fatalf("ApplyAction() 失败: %v", err)
+	}
+
+	undoResp, err := service.Undo(ctx, "1732701604000", 12345)
+	if err != nil {
+		t.Fatalf("Undo() 失败: %v", err)
+	}
+	if undoResp.UpdatedContent != "We use a new method." {
+		t.Errorf("撤销后内容 = %q", undoResp.UpdatedContent)
+	}
+
+	redoResp, err := service.Redo(ctx, "1732701604000", 12345)
+	if err != nil {
+		t.Fatalf("Redo() 失败: %v", err)
+	}
+	if redoResp.UpdatedContent != "We use a novel method." {
+		t.Errorf("重做后内容 = %q", redoResp.UpdatedContent)
+	}
+
+	if _, err := service.Redo(ctx, "1732701604000", 12345); err == nil {
+		t.Error("没有可重做的操作时应该返回错误")
+	}
+
+	history, err := service.GetHistory(ctx, "1732701604000", 12345)
+	if err != nil {
+		t.Fatalf("GetHistory() 失败: %v", err)
+	}
+	if len(history.Entries) != 3 || !history.CanUndo || history.CanRedo {
+		t.Errorf("操作历史错误: %d 条, can_undo=%v, can_redo=%v", len(history.Entries), history.CanUndo, history.CanRedo)
+	}
+
+	content, err := service.GetContentAt(ctx, "1732701604000", 12345, 0)
+	if err != nil {
+		t.Fatalf("GetContentAt() 失败: %v", err)
+	}
+	if content.FinalContent != "We use a new method." {
+		t.Errorf("第 0 步内容 = %q", content.FinalContent)
+	}
+}
+
+func TestComparisonService_Edit(t *testing.T) {
+	mockRepo := NewMockPolishRepository()
+	actionRepo := &MockComparisonActionRepository{}
+	service := NewComparisonService(mockRepo, nil, nil, actionRepo)
+
+	mockRepo.AddMockRecord(&entity.PolishRecord{
+		TraceID:         "1732701604100",
+		UserID:          12345,
+		OriginalContent: "We use a new method to solve the problem.",
+		PolishedContent: "We use a novel method to address the problem.",
+		Status:          "success",
+		CreatedAt:       time.Now(),
+	})
+
+	ctx := context.Background()
+	result, err := service.GetComparison(ctx, "1732701604100", 12345, nil)
+	if err != nil {
+		t.Fatalf("GetComparison() 失败: %v", err)
+	}
+	if len(result.Annotations) == 0 {
+		t.Fatal("应该检测到修改")
+	}
+	target := result.Annotations[0]
+
+	t.Run("编辑单个修改", func(t *testing.T) {
+		text := "fresh"
+		resp, err := service.ApplyAction(ctx, "1732701604100", 12345, "", &model.ChangeActionRequest{
+			ChangeID:   target.ID,
+			Action:     "edit",
+			EditedText: &text,
+		})
+		if err != nil {
+			t.Fatalf("ApplyAction(edit) 失败: %v", err)
+		}
+		want := []rune(result.OriginalContent)
+		expected := string(want[:target.OriginalPosition.Start]) + text + string(want[target.OriginalPosition.End:])
+		if resp.UpdatedContent != expected {
+			t.Errorf("编辑后内容 = %q, want %q", resp.UpdatedContent, expected)
+		}
+
+		if _, err := service.ApplyAction(ctx, "1732701604100", 12345, "", &model.ChangeActionRequest{ChangeID: target.ID, Action: "edit"}); err == nil {
+			t.Error("缺少 edited_text 时应该返回错误")
+		}
+	})
+
+	t.Run("自由编辑最终文本", func(t *testing.T) {
+		current, err := service.GetComparison(ctx, "1732701604100", 12345, nil)
+		if err != nil {
+			t.Fatalf("GetComparison() 失败: %v", err)
+		}
+		final := []rune(current.FinalContent)
+		start := len(final) - len("problem.")
+		resp, err := service.EditContent(ctx, "1732701604100", 12345, &model.ContentEditRequest{Start: start, End: len(final) - 1, Text: "issue"})
+		if err != nil {
+			t.Fatalf("EditContent() 失败: %v", err)
+		}
+		expected := string(final[:start]) + "issue."
+		if resp.UpdatedContent != expected {
+			t.Errorf("自由编辑后内容 = %q, want %q", resp.UpdatedContent, expected)
+		}
+
+		updated, _ := service.GetComparison(ctx, "1732701604100", 12345, nil)
+		manual := 0
+		for _, ann := range updated.Annotations {
+			if ann.Manual {
+				manual++
+				if ann.Status != model.ActionStatusEdited {
+					t.Errorf("手动修改状态 = %s, want edited", ann.Status)
+				}
+			}
+			if ann.ID == target.ID && (ann.Status != model.ActionStatusEdited || ann.EditedText != "fresh") {
+				t.Errorf("已编辑的修改应保持不变: %+v", ann)
+			}
+		}
+		if manual == 0 {
+			t.Error("自由编辑应该产生手动修改")
+		}
+
+		undo, err := service.Undo(ctx, "1732701604100", 12345)
+		if err != nil {
+			t.Fatalf("Undo() 失败: %v", err)
+		}
+		if undo.UpdatedContent != string(final) {
+			t.Errorf("撤销自由编辑后内容 = %q, want %q", undo.UpdatedContent, string(final))
+		}
+	})
+
+	t.Run("编辑区间越界", func(t *testing.T) {
+		if _, err := service.EditContent(ctx, "1732701604100", 12345, &model.ContentEditRequest{Start: 0, End: 1000}); err == nil {
+			t.Error("越界的编辑区间应该返回错误")
+		}
+	})
+}
+
+func TestComparisonService_BatchApply(t *testing.T) {
+	mockRepo := NewMockPolishRepository()
+	service := NewComparisonService(mockRepo, nil, nil, nil)
+
+	mockRepo.AddMockRecord(&entity.PolishRecord{
+		TraceID:         "1732701604200",
+		UserID:          12345,
+		OriginalContent: "We use a new method to solve the problem.",
+		PolishedContent: "We use a novel method to address the issue.",
+		Status:          "success",
+		CreatedAt:       time.Now(),
+	})
+
+	ctx := context.Background()
+	result, err := service.GetComparison(ctx, "1732701604200", 12345, nil)
+	if err != nil {
+		t.Fatalf("GetComparison() 失败: %v", err)
+	}
+	if len(result.Annotations) < 2 {
+		t.Fatalf("应该检测到多处修改，得到 %d", len(result.Annotations))
+	}
+	first := result.Annotations[0].ID
+
+	t.Run("指定修改ID接受", func(t *testing.T) {
+		resp, err := service.BatchApply(ctx, "1732701604200", 12345, "", &model.BatchActionRequest{Action: "accept_all", ChangeIDs: []string{first}})
+		if err != nil {
+			t.Fatalf("BatchApply() 失败: %v", err)
+		}
+		if resp.AppliedCount != 1 || resp.StatusCounts.Accepted != 1 || resp.StatusCounts.Pending != len(result.Annotations)-1 {
+			t.Errorf("状态统计错误: applied=%d, counts=%+v", resp.AppliedCount, resp.StatusCounts)
+		}
+	})
+
+	t.Run("拒绝剩余全部修改", func(t *testing.T) {
+		resp, err := service.BatchApply(ctx, "1732701604200", 12345, "", &model.BatchActionRequest{Action: "reject_all"})
+		if err != nil {
+			t.Fatalf("BatchApply() 失败: %v", err)
+		}
+		if resp.StatusCounts.Pending != 0 || resp.StatusCounts.Accepted != 1 {
+			t.Errorf("状态统计错误: %+v", resp.StatusCounts)
+		}
+		if resp.UpdatedContent == result.OriginalContent || resp.UpdatedContent == result.PolishedContent {
+			t.Errorf("部分接受后的内容不正确: %q", resp.UpdatedContent)
+		}
+	})
+
+	t.Run("按置信度筛选", func(t *testing.T) {
+		// 使用新的记录（上面的子测试已处理完所有修改），并固定每个修改的置信度
+		mockRepo.AddMockRecord(&entity.PolishRecord{
+			TraceID:         "1732701604201",
+			UserID:          12345,
+			OriginalContent: "We use a new method to solve the problem.",
+			PolishedContent: "We use a novel method to address the issue.",
+			Status:          "success",
+			CreatedAt:       time.Now(),
+		})
+		fresh, err := service.GenerateComparison(ctx, "1732701604201")
+		if err != nil {
+			t.Fatalf("GenerateComparison() 失败: %v", err)
+		}
+		confident := make(map[string]bool)
+		for i := range fresh.Annotations {
+			fresh.Annotations[i].Confidence = 0.3
+			if i%2 == 0 {
+				fresh.Annotations[i].Confidence = 0.9
+				confident[fresh.Annotations[i].ID] = true
+			}
+		}
+		record, _ := mockRepo.GetByTraceID(ctx, "1732701604201")
+		if err := service.saveComparisonData(ctx, record, fresh); err != nil {
+			t.Fatalf("saveComparisonData() 失败: %v", err)
+		}
+
+		high := 1.1
+		resp, err := service.BatchApply(ctx, "1732701604201", 12345, "", &model.BatchActionRequest{Action: "accept_all", MinConfidence: &high})
+		if err != nil {
+			t.Fatalf("BatchApply() 失败: %v", err)
+		}
+		if resp.AppliedCount != 0 || resp.StatusCounts.Accepted != 0 || resp.StatusCounts.Pending != len(fresh.Annotations) {
+			t.Errorf("阈值高于所有置信度时不应接受任何修改: applied=%d, counts=%+v", resp.AppliedCount, resp.StatusCounts)
+		}
+
+		threshold := 0.9
+		resp, err = service.BatchApply(ctx, "1732701604201", 12345, "", &model.BatchActionRequest{Action: "accept_all", MinConfidence: &threshold})
+		if err != nil {
+			t.Fatalf("BatchApply() 失败: %v", err)
+		}
+		if resp.AppliedCount != len(confident) || resp.StatusCounts.Accepted != len(confident) ||
+			resp.StatusCounts.Pending != len(fresh.Annotations)-len(confident) {
+			t.Errorf("应该只接受置信度 >= %.1f 的修改: applied=%d, counts=%+v", threshold, resp.AppliedCount, resp.StatusCounts)
+		}
+		for _, id := range resp.AppliedChanges {
+			if !confident[id] {
+				t.Errorf("置信度低于阈值的修改 %s 不应被接受", id)
+			}
+		}
+
+		low, ceiling := 0.5, 0.2
+		if _, err := service.BatchApply(ctx, "1732701604200", 12345, "", &model.BatchActionRequest{Action: "accept_all", MinConfidence: &low, MaxConfidence: &ceiling}); err == nil {
+			t.Error("min_confidence 大于 max_confidence 时应该返回错误")
+		}
+	})
+
+	t.Run("不存在的修改ID", func(t *testing.T) {
+		if _, err := service.BatchApply(ctx, "1732701604200", 12345, "", &model.BatchActionRequest{Action: "accept_all", ChangeIDs: []string{"change_999"}}); err == nil {
+			t.Error("不存在的修改ID应该返回错误")
+		}
+	})
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 
 	"paper_ai/internal/domain/repository"
 	"paper_ai/pkg/logger"
@@ -13,7 +14,7 @@ import (
 // FeatureService 功能开关服务
 type FeatureService struct {
 	userRepo repository.UserRepository
-	config   *FeatureConfig
+	config   atomic.Pointer[FeatureConfig] // 支持配置热更新
 }
 
 // FeatureConfig 功能配置
@@ -28,17 +29,21 @@ type FeatureConfig struct {
 
 // NewFeatureService 创建功能开关服务
 func NewFeatureService(userRepo repository.UserRepository, config *FeatureConfig) *FeatureService {
-	return &FeatureService{
-		userRepo: userRepo,
-		config:   config,
-	}
+	s := &FeatureService{userRepo: userRepo}
+	s.config.Store(config)
+	return s
+}
+
+// UpdateConfig 替换功能配置（配置热更新时调用，对之后的请求生效）
+func (s *FeatureService) UpdateConfig(config *FeatureConfig) {
+	s.config.Store(config)
 }
 
 // CheckMultiVersionPermission 检查用户是否有多版本润色权限
 // 返回：hasPermission, reason, error
 func (s *FeatureService) CheckMultiVersionPermission(ctx context.Context, userID int64) (bool, string, error) {
 	// 1. 检查全局开关
-	if !s.config.MultiVersionEnabled {
+	if !s.config.Load().MultiVersionEnabled {
 		logger.Warn("multi-version feature is globally disabled")
 		return false, "多版本润色功能暂未开放", nil
 	}
@@ -70,15 +75,15 @@ func (s *FeatureService) CheckMultiVersionPermission(ctx context.Context, userID
 
 // IsMultiVersionEnabled 判断多版本功能是否全局启用
 func (s *FeatureService) IsMultiVersionEnabled() bool {
-	return s.config.MultiVersionEnabled
+	return s.config.Load().MultiVersionEnabled
 }
 
 // GetDefaultMode 获取默认模式
 func (s *FeatureService) GetDefaultMode() string {
-	return s.config.DefaultMode
+	return s.config.Load().DefaultMode
 }
 
 // GetMaxConcurrent 获取最大并发数
 func (s *FeatureService) GetMaxConcurrent() int {
-	return s.config.MaxConcurrent
+	return s.config.Load().MaxConcurrent
 }
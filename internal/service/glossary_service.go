@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"paper_ai/internal/domain/entity"
+	"paper_ai/internal/domain/model"
+	"paper_ai/internal/domain/repository"
+	apperrors "paper_ai/pkg/errors"
+	"paper_ai/pkg/language"
+	"paper_ai/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// GlossaryService 术语表服务（翻译时保持术语译法一致）
+type GlossaryService struct {
+	glossaryRepo repository.GlossaryRepository
+}
+
+// NewGlossaryService 创建术语表服务
+func NewGlossaryService(glossaryRepo repository.GlossaryRepository) *GlossaryService {
+	return &GlossaryService{glossaryRepo: glossaryRepo}
+}
+
+// ListTerms 列出用户的术语
+func (s *GlossaryService) ListTerms(ctx context.Context, userID int64) ([]*model.GlossaryTermInfo, error) {
+	terms, err := s.glossaryRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("获取术语表失败", err)
+	}
+	return toGlossaryTermInfos(terms), nil
+}
+
+// CreateTerm 创建术语
+func (s *GlossaryService) CreateTerm(ctx context.Context, userID int64, req *model.GlossaryTermRequest) (*model.GlossaryTermInfo, error) {
+	term, err := normalizeGlossaryTerm(req)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := s.glossaryRepo.CountByUser(ctx, userID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("创建术语失败", err)
+	}
+	if count >= model.MaxGlossaryTerms {
+		return nil, apperrors.NewInvalidParameterError(fmt.Sprintf("术语表最多 %d 条", model.MaxGlossaryTerms))
+	}
+	if err := s.checkSourceTermFree(ctx, userID, term, 0); err != nil {
+		return nil, err
+	}
+
+	term.UserID = userID
+	if err := s.glossaryRepo.Create(ctx, term); err != nil {
+		return nil, apperrors.NewInternalError("创建术语失败", err)
+	}
+
+	logger.FromContext(ctx).Info("glossary term created", zap.Int64("term_id", term.ID))
+	return toGlossaryTermInfo(term), nil
+}
+
+// UpdateTerm 修改术语
+func (s *GlossaryService) UpdateTerm(ctx context.Context, userID, termID int64, req *model.GlossaryTermRequest) (*model.GlossaryTermInfo, error) {
+	term, err := s.getTerm(ctx, userID, termID)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := normalizeGlossaryTerm(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkSourceTermFree(ctx, userID, updated, termID); err != nil {
+		return nil, err
+	}
+
+	term.SourceLanguage = updated.SourceLanguage
+	term.TargetLanguage = updated.TargetLanguage
+	term.SourceTerm = updated.SourceTerm
+	term.TargetTerm = updated.TargetTerm
+	term.Note = updated.Note
+	if err := s.glossaryRepo.Update(ctx, term); err != nil {
+		return nil, apperrors.NewInternalError("更新术语失败", err)
+	}
+	return toGlossaryTermInfo(term), nil
+}
+
+// DeleteTerm 删除术语
+func (s *GlossaryService) DeleteTerm(ctx context.Context, userID, termID int64) error {
+	if _, err := s.getTerm(ctx, userID, termID); err != nil {
+		return err
+	}
+
+	if err := s.glossaryRepo.Delete(ctx, termID); err != nil {
+		return apperrors.NewInternalError("删除术语失败", err)
+	}
+
+	logger.FromContext(ctx).Info("glossary term deleted", zap.Int64("term_id", termID))
+	return nil
+}
+
+// MatchTerms 返回用户术语表中属于指定语言对、且在文本里出现的术语（源术语较长的在前）
+func (s *GlossaryService) MatchTerms(ctx context.Context, userID int64, sourceLanguage, targetLanguage, text string) ([]*entity.GlossaryTerm, error) {
+	terms, err := s.glossaryRepo.ListByLanguagePair(ctx, userID, sourceLanguage, targetLanguage)
+	if err != nil {
+		return nil, apperrors.NewInternalError("获取术语表失败", err)
+	}
+	return matchGlossary(terms, text), nil
+}
+
+// getTerm 获取用户的术语并校验所有权
+func (s *GlossaryService) getTerm(ctx context.Context, userID, termID int64) (*entity.GlossaryTerm, error) {
+	term, err := s.glossaryRepo.GetByID(ctx, termID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("获取术语失败", err)
+	}
+	if term == nil || term.UserID != userID {
+		return nil, apperrors.NewNotFoundError("术语不存在")
+	}
+	return term, nil
+}
+
+// checkSourceTermFree 检查源术语在同一语言对下未被用户的其他术语使用（exceptID 为正在修改的术语）
+func (s *GlossaryService) checkSourceTermFree(ctx context.Context, userID int64, term *entity.GlossaryTerm, exceptID int64) error {
+	existing, err := s.glossaryRepo.GetBySourceTerm(ctx, userID, term.SourceLanguage, term.TargetLanguage, term.SourceTerm)
+	if err != nil {
+		return apperrors.NewInternalError("获取术语失败", err)
+	}
+	if existing != nil && existing.ID != exceptID {
+		return apperrors.NewInvalidParameterError("该术语已存在")
+	}
+	return nil
+}
+
+// normalizeGlossaryTerm 去除首尾空白并校验语言对、术语、译法和备注
+func normalizeGlossaryTerm(req *model.GlossaryTermRequest) (*entity.GlossaryTerm, error) {
+	if !language.IsSupported(req.SourceLanguage) {
+		return nil, apperrors.NewInvalidParameterError(fmt.Sprintf("不支持的源语言: %s", req.SourceLanguage))
+	}
+	if !language.IsSupported(req.TargetLanguage) {
+		return nil, apperrors.NewInvalidParameterError(fmt.Sprintf("不支持的目标语言: %s", req.TargetLanguage))
+	}
+	if req.SourceLanguage == req.TargetLanguage {
+		return nil, apperrors.NewInvalidParameterError("源语言和目标语言不能相同")
+	}
+
+	sourceTerm, err := normalizeName(req.SourceTerm, "术语", model.MaxGlossaryTermLength)
+	if err != nil {
+		return nil, err
+	}
+	targetTerm, err := normalizeName(req.TargetTerm, "译法", model.MaxGlossaryTermLength)
+	if err != nil {
+		return nil, err
+	}
+	note := strings.TrimSpace(req.Note)
+	if utf8.RuneCountInString(note) > model.MaxGlossaryNoteLength {
+		return nil, apperrors.NewInvalidParameterError(fmt.Sprintf("备注不能超过 %d 个字符", model.MaxGlossaryNoteLength))
+	}
+	return &entity.GlossaryTerm{
+		SourceLanguage: req.SourceLanguage,
+		TargetLanguage: req.TargetLanguage,
+		SourceTerm:     sourceTerm,
+		TargetTerm:     targetTerm,
+		Note:           note,
+	}, nil
+}
+
+// matchGlossary 筛选在文本中出现的术语（不区分大小写），源术语较长的在前
+func matchGlossary(terms []*entity.GlossaryTerm, text string) []*entity.GlossaryTerm {
+	matched := make([]*entity.GlossaryTerm, 0)
+	for _, term := range terms {
+		if containsTerm(text, term.SourceTerm) {
+			matched = append(matched, term)
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		return utf8.RuneCountInString(matched[i].SourceTerm) > utf8.RuneCountInString(matched[j].SourceTerm)
+	})
+	return matched
+}
+
+// containsTerm 文本中是否出现术语（不区分大小写）
+func containsTerm(text, term string) bool {
+	return term != "" && strings.Contains(strings.ToLower(text), strings.ToLower(term))
+}
+
+// toGlossaryTermInfo 术语实体转换为术语信息
+func toGlossaryTermInfo(term *entity.GlossaryTerm) *model.GlossaryTermInfo {
+	return &model.GlossaryTermInfo{
+		ID:             term.ID,
+		SourceLanguage: term.SourceLanguage,
+		TargetLanguage: term.TargetLanguage,
+		SourceTerm:     term.SourceTerm,
+		TargetTerm:     term.TargetTerm,
+		Note:           term.Note,
+		CreatedAt:      term.CreatedAt,
+		UpdatedAt:      term.UpdatedAt,
+	}
+}
+
+// toGlossaryTermInfos 批量转换术语
+func toGlossaryTermInfos(terms []*entity.GlossaryTerm) []*model.GlossaryTermInfo {
+	infos := make([]*model.GlossaryTermInfo, len(terms))
+	for i, term := range terms {
+		infos[i] = toGlossaryTermInfo(term)
+	}
+	return infos
+}
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"paper_ai/internal/config"
+	"paper_ai/internal/domain/model"
+	"paper_ai/internal/infrastructure/ai"
+	"paper_ai/internal/infrastructure/database"
+	"paper_ai/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// HealthService 健康检查服务（存活/就绪探针）
+type HealthService struct {
+	providerFactory *ai.ProviderFactory
+	config          atomic.Pointer[config.HealthConfig] // 支持配置热更新
+
+	// 依赖检查函数（默认使用全局数据库实例）
+	pingDB          func(ctx context.Context) error
+	migrationStatus func(ctx context.Context) (uint, bool, error)
+
+	shuttingDown atomic.Bool
+
+	probeMu    sync.Mutex
+	probeCache map[string]providerProbe
+}
+
+// providerProbe 提供商探测结果缓存
+type providerProbe struct {
+	err       error
+	latency   time.Duration
+	checkedAt time.Time
+}
+
+// NewHealthService 创建健康检查服务
+func NewHealthService(providerFactory *ai.ProviderFactory, cfg *config.HealthConfig) *HealthService {
+	s := &HealthService{
+		providerFactory: providerFactory,
+		pingDB:          database.Health,
+		migrationStatus: database.MigrationStatus,
+		probeCache:      make(map[string]providerProbe),
+	}
+	s.config.Store(cfg)
+	return s
+}
+
+// UpdateConfig 替换健康检查配置（配置热更新时调用）
+// 提供商可能已重建，同时清空探测缓存
+func (s *HealthService) UpdateConfig(cfg *config.HealthConfig) {
+	s.config.Store(cfg)
+
+	s.probeMu.Lock()
+	s.probeCache = make(map[string]providerProbe)
+	s.probeMu.Unlock()
+}
+
+// SetShuttingDown 标记服务正在关闭，此后就绪检查直接失败
+func (s *HealthService) SetShuttingDown() {
+	s.shuttingDown.Store(true)
+}
+
+// Readiness 就绪检查：数据库连通、迁移状态正常、至少一个 AI 提供商可用
+// 各组件并发检查，单个组件超时由 check_timeout 控制
+func (s *HealthService) Readiness(ctx context.Context) *model.ReadinessReport {
+	if s.shuttingDown.Load() {
+		return &model.ReadinessReport{
+			Status:    model.ReadinessStatusShuttingDown,
+			CheckedAt: time.Now(),
+		}
+	}
+
+	timeout := s.config.Load().CheckTimeout
+	checks := []func(context.Context) model.ComponentHealth{
+		s.checkDatabase,
+		s.checkMigrations,
+		s.checkProviders,
+	}
+
+	components := make([]model.ComponentHealth, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check func(context.Context) model.ComponentHealth) {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			components[i] = check(checkCtx)
+		}(i, check)
+	}
+	wg.Wait()
+
+	report := &model.ReadinessReport{
+		Status:     model.ReadinessStatusReady,
+		Components: components,
+		CheckedAt:  time.Now(),
+	}
+	for _, component := range components {
+		if component.Status != model.ComponentStatusUp {
+			report.Status = model.ReadinessStatusNotReady
+			logger.FromContext(ctx).Warn("readiness check failed",
+				zap.String("component", component.Name),
+				zap.String("error", component.Error))
+		}
+	}
+	return report
+}
+
+// checkDatabase 检查数据库连通性
+func (s *HealthService) checkDatabase(ctx context.Context) model.ComponentHealth {
+	start := time.Now()
+	err := s.pingDB(ctx)
+	return newComponentHealth("database", start, err, nil)
+}
+
+// checkMigrations 检查迁移是否处于 dirty 状态（迁移中途失败，表结构可能不完整）
+func (s *HealthService) checkMigrations(ctx context.Context) model.ComponentHealth {
+	start := time.Now()
+	version, dirty, err := s.migrationStatus(ctx)
+	if err != nil {
+		return newComponentHealth("migrations", start, err, nil)
+	}
+
+	component := newComponentHealth("migrations", start, nil, map[string]interface{}{
+		"version": version,
+		"dirty":   dirty,
+	})
+	if dirty {
+		component.Status = model.ComponentStatusDown
+		component.Error = "migration is dirty, manual intervention required"
+	}
+	return component
+}
+
+// checkProviders 检查 AI 提供商，至少一个可用即为 up
+// 未开启探测时只检查是否已配置；开启后实际请求提供商 API，结果按 provider_probe_ttl 缓存
+func (s *HealthService) checkProviders(ctx context.Context) model.ComponentHealth {
+	start := time.Now()
+
+	names := s.providerFactory.ListProviders()
+	sort.Strings(names)
+
+	details := make(map[string]interface{}, len(names))
+	available := 0
+	for _, name := range names {
+		status := s.providerStatus(ctx, name)
+		if status["status"] == model.ComponentStatusUp {
+			available++
+		}
+		details[name] = status
+	}
+
+	component := newComponentHealth("ai_providers", start, nil, details)
+	if available == 0 {
+		component.Status = model.ComponentStatusDown
+		component.Error = "no available AI provider"
+	}
+	return component
+}
+
+// providerStatus 获取单个提供商的状态
+func (s *HealthService) providerStatus(ctx context.Context, name string) map[string]interface{} {
+	status := map[string]interface{}{"status": model.ComponentStatusUp}
+
+	provider, err := s.providerFactory.GetProvider(name)
+	if err != nil {
+		status["status"] = model.ComponentStatusDown
+		status["error"] = err.Error()
+		return status
+	}
+
+	checker, ok := provider.(ai.HealthChecker)
+	if !s.config.Load().ProviderProbe || !ok {
+		status["probed"] = false
+		return status
+	}
+
+	probe, cached := s.probeProvider(ctx, name, checker)
+	status["probed"] = true
+	status["cached"] = cached
+	status["latency_ms"] = probe.latency.Milliseconds()
+	status["checked_at"] = probe.checkedAt
+	if probe.err != nil {
+		status["status"] = model.ComponentStatusDown
+		status["error"] = probe.err.Error()
+	}
+	return status
+}
+
+// probeProvider 探测提供商（带缓存），返回探测结果以及是否来自缓存
+func (s *HealthService) probeProvider(ctx context.Context, name string, checker ai.HealthChecker) (providerProbe, bool) {
+	s.probeMu.Lock()
+	probe, exists := s.probeCache[name]
+	s.probeMu.Unlock()
+	if exists && time.Since(probe.checkedAt) < s.config.Load().ProviderProbeTTL {
+		return probe, true
+	}
+
+	start := time.Now()
+	err := checker.Ping(ctx)
+	probe = providerProbe{
+		err:       err,
+		latency:   time.Since(start),
+		checkedAt: time.Now(),
+	}
+
+	s.probeMu.Lock()
+	s.probeCache[name] = probe
+	s.probeMu.Unlock()
+	return probe, false
+}
+
+// newComponentHealth 根据检查结果构建组件状态
+func newComponentHealth(name string, start time.Time, err error, details map[string]interface{}) model.ComponentHealth {
+	component := model.ComponentHealth{
+		Name:      name,
+		Status:    model.ComponentStatusUp,
+		LatencyMs: time.Since(start).Milliseconds(),
+		Details:   details,
+	}
+	if err != nil {
+		component.Status = model.ComponentStatusDown
+		component.Error = err.Error()
+	}
+	return component
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"paper_ai/internal/domain/repository"
+	"paper_ai/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// purgeBatchSize 清理历史记录时每批删除的数量
+const purgeBatchSize = 1000
+
+// staleProcessingMessage 超时未完成记录的错误信息
+const staleProcessingMessage = "processing interrupted: no result before timeout (service restarted or crashed)"
+
+// MaintenanceService 运维维护服务（清理过期数据、重算统计）
+type MaintenanceService struct {
+	tokenRepo   repository.RefreshTokenRepository
+	polishRepo  repository.PolishRepository
+	promptRepo  repository.PolishPromptRepository
+	versionRepo repository.PolishVersionRepository
+}
+
+// NewMaintenanceService 创建运维维护服务实例
+func NewMaintenanceService(
+	tokenRepo repository.RefreshTokenRepository,
+	polishRepo repository.PolishRepository,
+	promptRepo repository.PolishPromptRepository,
+	versionRepo repository.PolishVersionRepository,
+) *MaintenanceService {
+	return &MaintenanceService{
+		tokenRepo:   tokenRepo,
+		polishRepo:  polishRepo,
+		promptRepo:  promptRepo,
+		versionRepo: versionRepo,
+	}
+}
+
+// PurgeExpiredTokens 删除过期的刷新令牌，返回删除数量
+func (s *MaintenanceService) PurgeExpiredTokens(ctx context.Context) (int64, error) {
+	deleted, err := s.tokenRepo.DeleteExpired(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge expired refresh tokens: %w", err)
+	}
+
+	logger.FromContext(ctx).Info("expired refresh tokens purged", zap.Int64("deleted", deleted))
+	return deleted, nil
+}
+
+// FailStaleProcessing 将创建超过 staleAfter 仍处于 processing 状态的记录标记为失败，返回更新数量
+func (s *MaintenanceService) FailStaleProcessing(ctx context.Context, staleAfter time.Duration) (int64, error) {
+	updated, err := s.polishRepo.MarkStaleProcessingFailed(ctx, time.Now().Add(-staleAfter), staleProcessingMessage)
+	if err != nil {
+		return 0, err
+	}
+
+	if updated > 0 {
+		logger.FromContext(ctx).Warn("stale processing records marked as failed",
+			zap.Int64("updated", updated),
+			zap.Duration("stale_after", staleAfter))
+	}
+	return updated, nil
+}
+
+// ApplyRetention 按保留策略物理删除润色记录（版本记录级联删除），返回删除数量
+// maxAge 为全局保留时长（0 表示不按全局策略删除）；用户设置了 retention_days 的记录按用户设置删除
+func (s *MaintenanceService) ApplyRetention(ctx context.Context, maxAge time.Duration) (int64, error) {
+	var total int64
+	if maxAge > 0 {
+		cutoff := time.Now().Add(-maxAge)
+		deleted, err := s.polishRepo.PurgeCreatedBefore(ctx, cutoff, purgeBatchSize)
+		total += deleted
+		if err != nil {
+			return total, err
+		}
+		logger.FromContext(ctx).Info("global retention applied to polish records",
+			zap.Int64("deleted", deleted),
+			zap.Time("cutoff", cutoff))
+	}
+
+	deleted, err := s.polishRepo.PurgeByUserRetention(ctx, purgeBatchSize)
+	total += deleted
+	if err != nil {
+		return total, err
+	}
+	logger.FromContext(ctx).Info("user retention applied to polish records", zap.Int64("deleted", deleted))
+
+	return total, nil
+}
+
+// RecomputePromptStatistics 根据生成版本的成功/失败情况重算每个Prompt的成功率（百分比），返回更新的Prompt数量
+// 没有生成记录的Prompt保持原值，平均满意度不在此处计算
+func (s *MaintenanceService) RecomputePromptStatistics(ctx context.Context) (int, error) {
+	stats, err := s.versionRepo.GetStatsByPromptID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	prompts, err := s.promptRepo.List(ctx, repository.PromptFilter{})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list prompts: %w", err)
+	}
+
+	updated := 0
+	for _, prompt := range prompts {
+		stat, ok := stats[prompt.ID]
+		if !ok || stat.TotalCount == 0 {
+			continue
+		}
+
+		successRate := math.Round(float64(stat.SuccessCount)/float64(stat.TotalCount)*10000) / 100
+		if err := s.promptRepo.UpdateStatistics(ctx, prompt.ID, successRate, prompt.AvgSatisfaction); err != nil {
+			return updated, err
+		}
+		updated++
+	}
+
+	logger.FromContext(ctx).Info("prompt statistics recomputed",
+		zap.Int("prompts", len(prompts)),
+		zap.Int("updated", updated))
+	return updated, nil
+}
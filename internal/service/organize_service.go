@@ -0,0 +1,372 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"paper_ai/internal/domain/entity"
+	"paper_ai/internal/domain/model"
+	"paper_ai/internal/domain/repository"
+	apperrors "paper_ai/pkg/errors"
+	"paper_ai/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// OrganizeService 润色记录整理服务（项目、标签、收藏）
+type OrganizeService struct {
+	projectRepo repository.ProjectRepository
+	tagRepo     repository.TagRepository
+	polishRepo  repository.PolishRepository
+}
+
+// NewOrganizeService 创建润色记录整理服务
+func NewOrganizeService(projectRepo repository.ProjectRepository, tagRepo repository.TagRepository, polishRepo repository.PolishRepository) *OrganizeService {
+	return &OrganizeService{
+		projectRepo: projectRepo,
+		tagRepo:     tagRepo,
+		polishRepo:  polishRepo,
+	}
+}
+
+// ListProjects 列出用户的项目（含记录数）
+func (s *OrganizeService) ListProjects(ctx context.Context, userID int64) ([]*model.ProjectInfo, error) {
+	projects, err := s.projectRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("获取项目列表失败", err)
+	}
+	counts, err := s.projectRepo.CountRecords(ctx, userID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("获取项目列表失败", err)
+	}
+
+	infos := make([]*model.ProjectInfo, len(projects))
+	for i, project := range projects {
+		infos[i] = toProjectInfo(project, counts[project.ID])
+	}
+	return infos, nil
+}
+
+// CreateProject 创建项目
+func (s *OrganizeService) CreateProject(ctx context.Context, userID int64, req *model.ProjectRequest) (*model.ProjectInfo, error) {
+	name, err := normalizeName(req.Name, "项目名称", model.MaxProjectNameLength)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkProjectNameFree(ctx, userID, name, 0); err != nil {
+		return nil, err
+	}
+
+	project := &entity.Project{
+		UserID:      userID,
+		Name:        name,
+		Description: strings.TrimSpace(req.Description),
+	}
+	if err := s.projectRepo.Create(ctx, project); err != nil {
+		return nil, apperrors.NewInternalError("创建项目失败", err)
+	}
+
+	logger.FromContext(ctx).Info("project created", zap.Int64("project_id", project.ID))
+	return toProjectInfo(project, 0), nil
+}
+
+// UpdateProject 修改项目名称和描述
+func (s *OrganizeService) UpdateProject(ctx context.Context, userID, projectID int64, req *model.ProjectRequest) (*model.ProjectInfo, error) {
+	project, err := s.getProject(ctx, userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := normalizeName(req.Name, "项目名称", model.MaxProjectNameLength)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkProjectNameFree(ctx, userID, name, projectID); err != nil {
+		return nil, err
+	}
+
+	project.Name = name
+	project.Description = strings.TrimSpace(req.Description)
+	if err := s.projectRepo.Update(ctx, project); err != nil {
+		return nil, apperrors.NewInternalError("更新项目失败", err)
+	}
+
+	counts, err := s.projectRepo.CountRecords(ctx, userID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("更新项目失败", err)
+	}
+	return toProjectInfo(project, counts[project.ID]), nil
+}
+
+// DeleteProject 删除项目（项目中的记录保留，移出项目）
+func (s *OrganizeService) DeleteProject(ctx context.Context, userID, projectID int64) error {
+	if _, err := s.getProject(ctx, userID, projectID); err != nil {
+		return err
+	}
+
+	if err := s.projectRepo.Delete(ctx, projectID); err != nil {
+		return apperrors.NewInternalError("删除项目失败", err)
+	}
+
+	logger.FromContext(ctx).Info("project deleted", zap.Int64("project_id", projectID))
+	return nil
+}
+
+// ListTags 列出用户的标签
+func (s *OrganizeService) ListTags(ctx context.Context, userID int64) ([]*model.TagInfo, error) {
+	tags, err := s.tagRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("获取标签列表失败", err)
+	}
+	return toTagInfos(tags), nil
+}
+
+// CreateTag 创建标签
+func (s *OrganizeService) CreateTag(ctx context.Context, userID int64, req *model.TagRequest) (*model.TagInfo, error) {
+	name, err := normalizeName(req.Name, "标签名称", model.MaxTagNameLength)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkTagNameFree(ctx, userID, name, 0); err != nil {
+		return nil, err
+	}
+
+	tag := &entity.Tag{UserID: userID, Name: name}
+	if err := s.tagRepo.Create(ctx, tag); err != nil {
+		return nil, apperrors.NewInternalError("创建标签失败", err)
+	}
+	return toTagInfo(tag), nil
+}
+
+// UpdateTag 重命名标签
+func (s *OrganizeService) UpdateTag(ctx context.Context, userID, tagID int64, req *model.TagRequest) (*model.TagInfo, error) {
+	tag, err := s.getTag(ctx, userID, tagID)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := normalizeName(req.Name, "标签名称", model.MaxTagNameLength)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkTagNameFree(ctx, userID, name, tagID); err != nil {
+		return nil, err
+	}
+
+	tag.Name = name
+	if err := s.tagRepo.Update(ctx, tag); err != nil {
+		return nil, apperrors.NewInternalError("更新标签失败", err)
+	}
+	return toTagInfo(tag), nil
+}
+
+// DeleteTag 删除标签（同时从所有记录上移除）
+func (s *OrganizeService) DeleteTag(ctx context.Context, userID, tagID int64) error {
+	if _, err := s.getTag(ctx, userID, tagID); err != nil {
+		return err
+	}
+
+	if err := s.tagRepo.Delete(ctx, tagID); err != nil {
+		return apperrors.NewInternalError("删除标签失败", err)
+	}
+	return nil
+}
+
+// SetRecordProject 设置记录所属项目（projectID 为 nil 表示移出项目）
+func (s *OrganizeService) SetRecordProject(ctx context.Context, userID int64, traceID string, projectID *int64) error {
+	record, err := s.getRecord(ctx, userID, traceID)
+	if err != nil {
+		return err
+	}
+	if err := s.CheckProject(ctx, userID, projectID); err != nil {
+		return err
+	}
+
+	if err := s.polishRepo.SetProject(ctx, record.ID, projectID); err != nil {
+		return apperrors.NewInternalError("设置项目失败", err)
+	}
+	return nil
+}
+
+// SetRecordFavorite 收藏/取消收藏记录
+func (s *OrganizeService) SetRecordFavorite(ctx context.Context, userID int64, traceID string, favorite bool) error {
+	record, err := s.getRecord(ctx, userID, traceID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.polishRepo.SetFavorite(ctx, record.ID, favorite); err != nil {
+		return apperrors.NewInternalError("设置收藏失败", err)
+	}
+	return nil
+}
+
+// SetRecordTags 替换记录的全部标签，返回设置后的标签
+func (s *OrganizeService) SetRecordTags(ctx context.Context, userID int64, traceID string, tagIDs []int64) ([]*model.TagInfo, error) {
+	record, err := s.getRecord(ctx, userID, traceID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 去重
+	seen := make(map[int64]bool, len(tagIDs))
+	unique := make([]int64, 0, len(tagIDs))
+	for _, tagID := range tagIDs {
+		if !seen[tagID] {
+			seen[tagID] = true
+			unique = append(unique, tagID)
+		}
+	}
+	if len(unique) > model.MaxTagsPerRecord {
+		return nil, apperrors.NewInvalidParameterError(fmt.Sprintf("每条记录最多 %d 个标签", model.MaxTagsPerRecord))
+	}
+
+	// 校验标签归属
+	tags := make([]*entity.Tag, len(unique))
+	for i, tagID := range unique {
+		if tags[i], err = s.getTag(ctx, userID, tagID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.tagRepo.SetRecordTags(ctx, record.ID, unique); err != nil {
+		return nil, apperrors.NewInternalError("设置标签失败", err)
+	}
+
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Name < tags[j].Name })
+	return toTagInfos(tags), nil
+}
+
+// CheckProject 检查项目存在且属于用户（projectID 为 nil 时不检查）
+func (s *OrganizeService) CheckProject(ctx context.Context, userID int64, projectID *int64) error {
+	if projectID == nil {
+		return nil
+	}
+	if s == nil {
+		return apperrors.NewInvalidParameterError("项目不存在")
+	}
+	_, err := s.getProject(ctx, userID, *projectID)
+	return err
+}
+
+// AttachTags 为记录加载标签，加载失败只记录日志（未配置整理服务时跳过）
+func (s *OrganizeService) AttachTags(ctx context.Context, records ...*entity.PolishRecord) {
+	if s == nil || len(records) == 0 {
+		return
+	}
+
+	ids := make([]int64, len(records))
+	for i, record := range records {
+		ids[i] = record.ID
+	}
+	tagsByRecord, err := s.tagRepo.ListByRecordIDs(ctx, ids)
+	if err != nil {
+		logger.FromContext(ctx).Warn("failed to load tags of records", zap.Error(err))
+		return
+	}
+
+	for _, record := range records {
+		record.Tags = tagsByRecord[record.ID]
+	}
+}
+
+// getProject 获取用户的项目，不存在或不属于该用户时返回 NotFound
+func (s *OrganizeService) getProject(ctx context.Context, userID, projectID int64) (*entity.Project, error) {
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("获取项目失败", err)
+	}
+	if project == nil || project.UserID != userID {
+		return nil, apperrors.NewNotFoundError("项目不存在")
+	}
+	return project, nil
+}
+
+// getTag 获取用户的标签，不存在或不属于该用户时返回 NotFound
+func (s *OrganizeService) getTag(ctx context.Context, userID, tagID int64) (*entity.Tag, error) {
+	tag, err := s.tagRepo.GetByID(ctx, tagID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("获取标签失败", err)
+	}
+	if tag == nil || tag.UserID != userID {
+		return nil, apperrors.NewNotFoundError(fmt.Sprintf("标签不存在: %d", tagID))
+	}
+	return tag, nil
+}
+
+// getRecord 获取用户的润色记录并校验所有权
+func (s *OrganizeService) getRecord(ctx context.Context, userID int64, traceID string) (*entity.PolishRecord, error) {
+	record, err := s.polishRepo.GetByTraceID(ctx, traceID)
+	if err != nil {
+		return nil, apperrors.NewNotFoundError("润色记录不存在")
+	}
+	if record.UserID != userID {
+		return nil, apperrors.NewForbiddenError("无权修改该记录")
+	}
+	return record, nil
+}
+
+// checkProjectNameFree 检查项目名称未被用户的其他项目使用（exceptID 为正在修改的项目）
+func (s *OrganizeService) checkProjectNameFree(ctx context.Context, userID int64, name string, exceptID int64) error {
+	existing, err := s.projectRepo.GetByName(ctx, userID, name)
+	if err != nil {
+		return apperrors.NewInternalError("获取项目失败", err)
+	}
+	if existing != nil && existing.ID != exceptID {
+		return apperrors.NewInvalidParameterError("项目名称已存在")
+	}
+	return nil
+}
+
+// checkTagNameFree 检查标签名称未被用户的其他标签使用（exceptID 为正在修改的标签）
+func (s *OrganizeService) checkTagNameFree(ctx context.Context, userID int64, name string, exceptID int64) error {
+	existing, err := s.tagRepo.GetByName(ctx, userID, name)
+	if err != nil {
+		return apperrors.NewInternalError("获取标签失败", err)
+	}
+	if existing != nil && existing.ID != exceptID {
+		return apperrors.NewInvalidParameterError("标签名称已存在")
+	}
+	return nil
+}
+
+// normalizeName 去除首尾空白并校验名称长度
+func normalizeName(name, label string, maxLength int) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", apperrors.NewInvalidParameterError(label + "不能为空")
+	}
+	if utf8.RuneCountInString(name) > maxLength {
+		return "", apperrors.NewInvalidParameterError(fmt.Sprintf("%s不能超过 %d 个字符", label, maxLength))
+	}
+	return name, nil
+}
+
+// toProjectInfo 项目实体转换为项目信息
+func toProjectInfo(project *entity.Project, recordCount int64) *model.ProjectInfo {
+	return &model.ProjectInfo{
+		ID:          project.ID,
+		Name:        project.Name,
+		Description: project.Description,
+		RecordCount: recordCount,
+		CreatedAt:   project.CreatedAt,
+		UpdatedAt:   project.UpdatedAt,
+	}
+}
+
+// toTagInfo 标签实体转换为标签信息
+func toTagInfo(tag *entity.Tag) *model.TagInfo {
+	return &model.TagInfo{ID: tag.ID, Name: tag.Name, CreatedAt: tag.CreatedAt}
+}
+
+// toTagInfos 批量转换标签
+func toTagInfos(tags []*entity.Tag) []*model.TagInfo {
+	infos := make([]*model.TagInfo, len(tags))
+	for i, tag := range tags {
+		infos[i] = toTagInfo(tag)
+	}
+	return infos
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"paper_ai/internal/domain/entity"
+)
+
+// mockTagRepository 模拟标签仓储
+type mockTagRepository struct {
+	tags       map[int64]*entity.Tag
+	recordTags map[int64][]int64
+}
+
+func newMockTagRepository(tags ...*entity.Tag) *mockTagRepository {
+	m := &mockTagRepository{
+		tags:       make(map[int64]*entity.Tag),
+		recordTags: make(map[int64][]int64),
+	}
+	for _, tag := range tags {
+		m.tags[tag.ID] = tag
+	}
+	return m
+}
+
+func (m *mockTagRepository) Create(ctx context.Context, tag *entity.Tag) error {
+	m.tags[tag.ID] = tag
+	return nil
+}
+func (m *mockTagRepository) GetByID(ctx context.Context, id int64) (*entity.Tag, error) {
+	return m.tags[id], nil
+}
+func (m *mockTagRepository) GetByName(ctx context.Context, userID int64, name string) (*entity.Tag, error) {
+	for _, tag := range m.tags {
+		if tag.UserID == userID && tag.Name == name {
+			return tag, nil
+		}
+	}
+	return nil, nil
+}
+func (m *mockTagRepository) ListByUser(ctx context.Context, userID int64) ([]*entity.Tag, error) {
+	return nil, nil
+}
+func (m *mockTagRepository) Update(ctx context.Context, tag *entity.Tag) error {
+	return nil
+}
+func (m *mockTagRepository) Delete(ctx context.Context, id int64) error {
+	delete(m.tags, id)
+	return nil
+}
+func (m *mockTagRepository) ListByRecordIDs(ctx context.Context, recordIDs []int64) (map[int64][]*entity.Tag, error) {
+	return nil, nil
+}
+func (m *mockTagRepository) SetRecordTags(ctx context.Context, recordID int64, tagIDs []int64) error {
+	m.recordTags[recordID] = tagIDs
+	return nil
+}
+
+func TestNormalizeName(t *testing.T) {
+	name, err := normalizeName("  毕业论文  ", "项目名称", 10)
+	if err != nil || name != "毕业论文" {
+		t.Errorf("normalizeName() = %q, %v", name, err)
+	}
+
+	if _, err := normalizeName("   ", "项目名称", 10); err == nil {
+		t.Error("expected error for blank name")
+	}
+
+	// 长度按字符计算
+	if _, err := normalizeName(strings.Repeat("文", 10), "项目名称", 10); err != nil {
+		t.Errorf("unexpected error for 10 runes: %v", err)
+	}
+	if _, err := normalizeName(strings.Repeat("文", 11), "项目名称", 10); err == nil {
+		t.Error("expected error for 11 runes")
+	}
+}
+
+func TestOrganizeService_SetRecordTags(t *testing.T) {
+	ctx := context.Background()
+	polishRepo := NewMockPolishRepository()
+	polishRepo.records["trace-1"] = &entity.PolishRecord{ID: 1, TraceID: "trace-1", UserID: 7}
+	tagRepo := newMockTagRepository(
+		&entity.Tag{ID: 10, UserID: 7, Name: "引言"},
+		&entity.Tag{ID: 11, UserID: 7, Name: "摘要"},
+		&entity.Tag{ID: 12, UserID: 8, Name: "其他用户"},
+	)
+	service := NewOrganizeService(nil, tagRepo, polishRepo)
+
+	// 重复的标签只保留一个，返回结果按名称排序
+	tags, err := service.SetRecordTags(ctx, 7, "trace-1", []int64{10, 11, 10})
+	if err != nil {
+		t.Fatalf("SetRecordTags() error = %v", err)
+	}
+	if got := tagRepo.recordTags[1]; len(got) != 2 || got[0] != 10 || got[1] != 11 {
+		t.Errorf("saved tag ids = %v, want [10 11]", got)
+	}
+	if len(tags) != 2 || tags[0].Name > tags[1].Name {
+		t.Errorf("returned tags not sorted by name: %+v", tags)
+	}
+
+	// 其他用户的标签
+	if _, err := service.SetRecordTags(ctx, 7, "trace-1", []int64{12}); err == nil {
+		t.Error("expected error for tag of another user")
+	}
+
+	// 其他用户的记录
+	if _, err := service.SetRecordTags(ctx, 8, "trace-1", []int64{12}); err == nil {
+		t.Error("expected error for record of another user")
+	}
+
+	// 空列表清除标签
+	if _, err := service.SetRecordTags(ctx, 7, "trace-1", []int64{}); err != nil {
+		t.Fatalf("SetRecordTags() error = %v", err)
+	}
+	if got := tagRepo.recordTags[1]; len(got) != 0 {
+		t.Errorf("saved tag ids = %v, want []", got)
+	}
+}
@@ -11,9 +11,11 @@ import (
 	"paper_ai/internal/domain/repository"
 	"paper_ai/internal/infrastructure/ai"
 	"paper_ai/internal/infrastructure/ai/types"
+	"paper_ai/internal/infrastructure/metrics"
 	apperrors "paper_ai/pkg/errors"
 	"paper_ai/pkg/idgen"
 	"paper_ai/pkg/logger"
+	"paper_ai/pkg/reqctx"
 	"go.uber.org/zap"
 )
 
@@ -21,37 +23,53 @@ import (
 type PolishService struct {
 	providerFactory *ai.ProviderFactory
 	polishRepo      repository.PolishRepository // 仓储接口（依赖注入）
+	privacyService  *PrivacyService             // 隐私设置（为 nil 时总是保存内容）
+	organizeService *OrganizeService            // 项目、标签（为 nil 时不支持指定项目，记录不加载标签）
 }
 
 // NewPolishService 创建润色服务
-func NewPolishService(factory *ai.ProviderFactory, repo repository.PolishRepository) *PolishService {
+func NewPolishService(factory *ai.ProviderFactory, repo repository.PolishRepository, privacyService *PrivacyService, organizeService *OrganizeService) *PolishService {
 	return &PolishService{
 		providerFactory: factory,
 		polishRepo:      repo,
+		privacyService:  privacyService,
+		organizeService: organizeService,
 	}
 }
 
+// generateTraceID 从 context 中获取 TraceID，如果没有则使用 Snowflake ID 生成器生成纯数字 TraceID
+func generateTraceID(ctx context.Context) string {
+	if traceID := reqctx.TraceID(ctx); traceID != "" {
+		return traceID
+	}
+
+	id, err := idgen.GenerateID()
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to generate trace ID", zap.Error(err))
+		// 降级方案：使用时间戳
+		return strconv.FormatInt(time.Now().UnixNano(), 10)
+	}
+	return strconv.FormatInt(id, 10)
+}
+
 // Polish 执行段落润色
 func (s *PolishService) Polish(ctx context.Context, req *model.PolishRequest, userID int64) (*types.PolishResponse, error) {
 	startTime := time.Now()
 
-	// 从context中获取traceID，如果没有则生成唯一ID
-	traceID, ok := ctx.Value("trace_id").(string)
-	if !ok || traceID == "" {
-		// 使用 Snowflake ID 生成器生成纯数字 TraceID
-		id, err := idgen.GenerateID()
-		if err != nil {
-			logger.Error("failed to generate trace ID", zap.Error(err))
-			// 降级方案：使用时间戳
-			traceID = strconv.FormatInt(time.Now().UnixNano(), 10)
-		} else {
-			traceID = strconv.FormatInt(id, 10)
-		}
+	traceID := generateTraceID(ctx)
+	// 写入上下文，后续日志和 AI 提供商请求都会带上 TraceID
+	ctx = reqctx.WithTraceID(ctx, traceID)
+
+	// 检查指定的项目（先于其他校验，之后保存的失败记录才能归入该项目；不属于用户的项目不写入记录）
+	if err := s.organizeService.CheckProject(ctx, userID, req.ProjectID); err != nil {
+		req.ProjectID = nil
+		s.saveFailedRecord(ctx, traceID, req, userID, err)
+		return nil, err
 	}
 
 	// 参数验证
 	if err := req.Validate(); err != nil {
-		logger.Warn("invalid polish request", zap.Error(err))
+		logger.FromContext(ctx).Warn("invalid polish request", zap.Error(err))
 		// 记录失败的请求
 		s.saveFailedRecord(ctx, traceID, req, userID, err)
 		return nil, apperrors.NewInvalidParameterError(err.Error())
@@ -68,7 +86,7 @@ func (s *PolishService) Polish(ctx context.Context, req *model.PolishRequest, us
 		// 使用默认提供商
 		provider, err = s.providerFactory.GetDefaultProvider()
 		if err != nil {
-			logger.Error("failed to get default provider", zap.Error(err))
+			logger.FromContext(ctx).Error("failed to get default provider", zap.Error(err))
 			s.saveFailedRecord(ctx, traceID, req, userID, err)
 			return nil, err
 		}
@@ -77,29 +95,36 @@ func (s *PolishService) Polish(ctx context.Context, req *model.PolishRequest, us
 		// 使用指定的提供商
 		provider, err = s.providerFactory.GetProvider(req.Provider)
 		if err != nil {
-			logger.Error("failed to get provider", zap.String("provider", req.Provider), zap.Error(err))
+			logger.FromContext(ctx).Error("failed to get provider", zap.String("provider", req.Provider), zap.Error(err))
 			s.saveFailedRecord(ctx, traceID, req, userID, err)
 			return nil, err
 		}
 	}
 
-	// 构建AI请求
+	// 构建AI请求（选区润色时只发送选中的片段，前后文作为上下文）
 	aiReq := &types.PolishRequest{
 		Content:  req.Content,
 		Style:    req.Style,
 		Language: req.Language,
 	}
+	var selection *entity.TextSelection
+	if req.Selection != nil {
+		selection = trimSelection(req.Content, req.Selection)
+		aiReq = selectionRequest(req, selection)
+	}
 
 	// 调用AI服务
-	logger.Info("calling ai provider for polish",
+	logger.FromContext(ctx).Info("calling ai provider for polish",
 		zap.String("provider", req.Provider),
 		zap.Int("content_length", len(req.Content)),
-		zap.Int64("user_id", userID),
+		zap.Bool("selection", selection != nil),
 	)
 
+	callStart := time.Now()
 	resp, err := provider.Polish(ctx, aiReq)
+	observeProviderCall(req.Provider, "single", callStart, resp, err)
 	if err != nil {
-		logger.Error("ai provider polish failed",
+		logger.FromContext(ctx).Error("ai provider polish failed",
 			zap.String("provider", req.Provider),
 			zap.Error(err),
 		)
@@ -107,29 +132,48 @@ func (s *PolishService) Polish(ctx context.Context, req *model.PolishRequest, us
 		return nil, err
 	}
 
+	// 把润色后的片段拼回整段
+	if selection != nil {
+		spliceSelection(req.Content, selection, resp)
+	}
+
 	// 计算处理时间
 	processTime := time.Since(startTime).Milliseconds()
 
 	// 保存成功记录
-	s.saveSuccessRecord(ctx, traceID, req, resp, userID, int(processTime))
+	s.saveSuccessRecord(ctx, traceID, req, resp, selection, userID, int(processTime))
 
 	// 设置 TraceID 到响应中
 	resp.TraceID = traceID
 
-	logger.Info("polish completed successfully",
+	logger.FromContext(ctx).Info("polish completed successfully",
 		zap.String("provider", req.Provider),
-		zap.String("trace_id", traceID),
 		zap.Int("original_length", resp.OriginalLength),
 		zap.Int("polished_length", resp.PolishedLength),
 		zap.Int64("process_time_ms", processTime),
-		zap.Int64("user_id", userID),
 	)
 
 	return resp, nil
 }
 
-// saveSuccessRecord 保存成功记录
-func (s *PolishService) saveSuccessRecord(ctx context.Context, traceID string, req *model.PolishRequest, resp *types.PolishResponse, userID int64, processTime int) {
+// observeProviderCall 记录 AI 提供商调用指标
+func observeProviderCall(provider, versionType string, start time.Time, resp *types.PolishResponse, err error) {
+	call := metrics.ProviderCall{
+		Provider:    provider,
+		VersionType: versionType,
+		Duration:    time.Since(start),
+		Err:         err,
+	}
+	if resp != nil {
+		call.Model = resp.ModelUsed
+		call.InputTokens = resp.Usage.InputTokens
+		call.OutputTokens = resp.Usage.OutputTokens
+	}
+	metrics.ObserveProviderCall(call)
+}
+
+// saveSuccessRecord 保存成功记录（selection 为选区润色时润色的原文片段）
+func (s *PolishService) saveSuccessRecord(ctx context.Context, traceID string, req *model.PolishRequest, resp *types.PolishResponse, selection *entity.TextSelection, userID int64, processTime int) {
 	if s.polishRepo == nil {
 		return // 如果没有配置数据库，跳过保存
 	}
@@ -145,12 +189,19 @@ func (s *PolishService) saveSuccessRecord(ctx context.Context, traceID string, r
 		PolishedLength:  resp.PolishedLength,
 		Provider:        resp.ProviderUsed,
 		Model:           resp.ModelUsed,
+		TokenLogProbs:   toEntityTokenLogProbs(resp.TokenLogProbs),
 		ProcessTimeMs:   processTime,
 		Status:          "success",
+		Selection:       selection,
+		ProjectID:       req.ProjectID,
+		ParentTraceID:   req.ParentTraceID,
+	}
+	if s.privacyService.ShouldOmitContent(ctx, userID) {
+		record.OmitContent()
 	}
 
 	if err := s.polishRepo.Create(ctx, record); err != nil {
-		logger.Error("failed to save polish record", zap.String("trace_id", traceID), zap.Error(err))
+		logger.FromContext(ctx).Error("failed to save polish record", zap.Error(err))
 	}
 }
 
@@ -168,10 +219,15 @@ func (s *PolishService) saveFailedRecord(ctx context.Context, traceID string, re
 		Language:        req.Language,
 		Status:          "failed",
 		ErrorMessage:    err.Error(),
+		ProjectID:       req.ProjectID,
+		ParentTraceID:   req.ParentTraceID,
+	}
+	if s.privacyService.ShouldOmitContent(ctx, userID) {
+		record.OmitContent()
 	}
 
 	if err := s.polishRepo.Create(ctx, record); err != nil {
-		logger.Error("failed to save failed polish record", zap.String("trace_id", traceID), zap.Error(err))
+		logger.FromContext(ctx).Error("failed to save failed polish record", zap.Error(err))
 	}
 }
 
@@ -193,32 +249,72 @@ func (s *PolishService) GetRecordByTraceID(ctx context.Context, traceID string,
 
 	// 转换为展示用的记录（历史记录中应该显示 final_content）
 	s.convertRecordForDisplay(record)
+	s.organizeService.AttachTags(ctx, record)
 
 	return record, nil
 }
 
-// ListRecords 获取记录列表
-func (s *PolishService) ListRecords(ctx context.Context, opts repository.QueryOptions) ([]*entity.PolishRecord, int64, error) {
+// ListRecords 获取记录列表，返回记录、总数和下一页游标（没有更多记录时为空）
+// 游标分页时不统计总数（返回 0）；全文检索按相关度排序，不返回游标
+func (s *PolishService) ListRecords(ctx context.Context, opts repository.QueryOptions) ([]*entity.PolishRecord, int64, string, error) {
 	if s.polishRepo == nil {
-		return nil, 0, apperrors.NewInternalError("database not configured", nil)
+		return nil, 0, "", apperrors.NewInternalError("database not configured", nil)
+	}
+
+	if opts.CursorMode {
+		return s.listRecordsByCursor(ctx, opts)
 	}
 
 	records, err := s.polishRepo.List(ctx, opts)
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, "", err
 	}
 
 	total, err := s.polishRepo.Count(ctx, opts)
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, "", err
+	}
+
+	// 还有下一页时返回游标，客户端可从第一页开始改用游标分页
+	nextCursor := ""
+	if len(records) > 0 && opts.SearchText == nil && int64(opts.Offset+len(records)) < total {
+		nextCursor = repository.NewCursor(opts.OrderBy, opts.OrderDesc, records[len(records)-1]).Encode()
 	}
 
 	// 转换所有记录为展示格式
 	for _, record := range records {
 		s.convertRecordForDisplay(record)
 	}
+	s.organizeService.AttachTags(ctx, records...)
+
+	return records, total, nextCursor, nil
+}
+
+// listRecordsByCursor 游标分页查询记录列表（多查一条判断是否还有下一页）
+func (s *PolishService) listRecordsByCursor(ctx context.Context, opts repository.QueryOptions) ([]*entity.PolishRecord, int64, string, error) {
+	if opts.SearchText != nil {
+		return nil, 0, "", apperrors.NewInvalidParameterError("全文检索不支持游标分页")
+	}
+
+	pageSize := opts.Limit
+	opts.Limit = pageSize + 1
+	records, err := s.polishRepo.List(ctx, opts)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	nextCursor := ""
+	if len(records) > pageSize {
+		records = records[:pageSize]
+		nextCursor = repository.NewCursor(opts.OrderBy, opts.OrderDesc, records[pageSize-1]).Encode()
+	}
+
+	for _, record := range records {
+		s.convertRecordForDisplay(record)
+	}
+	s.organizeService.AttachTags(ctx, records...)
 
-	return records, total, nil
+	return records, 0, nextCursor, nil
 }
 
 // convertRecordForDisplay 将记录转换为展示格式
@@ -4,7 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -14,11 +14,14 @@ import (
 	"paper_ai/internal/domain/repository"
 	"paper_ai/internal/infrastructure/ai"
 	"paper_ai/internal/infrastructure/ai/types"
-	"paper_ai/internal/infrastructure/comparison"
+	"paper_ai/internal/infrastructure/metrics"
+	"paper_ai/internal/infrastructure/tracing"
 	apperrors "paper_ai/pkg/errors"
-	"paper_ai/pkg/idgen"
+	"paper_ai/pkg/language"
 	"paper_ai/pkg/logger"
+	"paper_ai/pkg/reqctx"
 
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 )
 
@@ -29,10 +32,10 @@ type PolishMultiVersionService struct {
 	versionRepo     repository.PolishVersionRepository
 	promptService   *PromptService
 	featureService  *FeatureService
-	diffEngine      *comparison.DiffEngine
-	positionCalc    *comparison.PositionCalculator
-	classifier      *comparison.ChangeClassifier
-	reasonGenerator *comparison.ReasonGenerator
+	actionRepo      repository.ComparisonActionRepository
+	privacyService  *PrivacyService  // 隐私设置（为 nil 时总是保存内容）
+	organizeService *OrganizeService // 项目（为 nil 时不支持指定项目）
+	builder         *comparisonBuilder
 }
 
 // NewPolishMultiVersionService 创建多版本润色服务
@@ -42,6 +45,10 @@ func NewPolishMultiVersionService(
 	versionRepo repository.PolishVersionRepository,
 	promptService *PromptService,
 	featureService *FeatureService,
+	feedbackRepo repository.ChangeFeedbackRepository,
+	actionRepo repository.ComparisonActionRepository,
+	privacyService *PrivacyService,
+	organizeService *OrganizeService,
 ) *PolishMultiVersionService {
 	return &PolishMultiVersionService{
 		providerFactory: factory,
@@ -49,26 +56,24 @@ func NewPolishMultiVersionService(
 		versionRepo:     versionRepo,
 		promptService:   promptService,
 		featureService:  featureService,
-		diffEngine:      comparison.NewDiffEngine(),
-		positionCalc:    comparison.NewPositionCalculator(),
-		classifier:      comparison.NewChangeClassifier(),
-		reasonGenerator: comparison.NewReasonGenerator(),
+		actionRepo:      actionRepo,
+		privacyService:  privacyService,
+		organizeService: organizeService,
+		builder:         newComparisonBuilder(feedbackRepo),
 	}
 }
 
 // PolishMultiVersion 执行多版本润色
 func (s *PolishMultiVersionService) PolishMultiVersion(ctx context.Context, req *model.PolishMultiVersionRequest, userID int64) (*model.PolishMultiVersionResponse, error) {
 	startTime := time.Now()
+	defer metrics.MultiVersionStarted()()
 
 	// 生成TraceID
-	traceID, err := s.generateTraceID(ctx)
-	if err != nil {
-		return nil, err
-	}
+	traceID := generateTraceID(ctx)
+	// 写入上下文，后续日志（含各版本的并发生成）和 AI 提供商请求都会带上 TraceID
+	ctx = reqctx.WithTraceID(ctx, traceID)
 
-	logger.Info("multi-version polish started",
-		zap.String("trace_id", traceID),
-		zap.Int64("user_id", userID),
+	logger.FromContext(ctx).Info("multi-version polish started",
 		zap.String("language", req.Language),
 		zap.String("style", req.Style))
 
@@ -78,7 +83,7 @@ func (s *PolishMultiVersionService) PolishMultiVersion(ctx context.Context, req
 		return nil, fmt.Errorf("failed to check permission: %w", err)
 	}
 	if !hasPermission {
-		logger.Warn("user does not have multi-version permission", zap.Int64("user_id", userID), zap.String("reason", reason))
+		logger.FromContext(ctx).Warn("user does not have multi-version permission", zap.String("reason", reason))
 		return nil, apperrors.NewForbiddenError(reason)
 	}
 
@@ -86,6 +91,9 @@ func (s *PolishMultiVersionService) PolishMultiVersion(ctx context.Context, req
 	if err := s.validateAndSetDefaults(req); err != nil {
 		return nil, apperrors.NewInvalidParameterError(err.Error())
 	}
+	if err := s.organizeService.CheckProject(ctx, userID, req.ProjectID); err != nil {
+		return nil, err
+	}
 
 	// 3. 获取AI提供商
 	provider, err := s.getProvider(req.Provider)
@@ -104,10 +112,17 @@ func (s *PolishMultiVersionService) PolishMultiVersion(ctx context.Context, req
 		Provider:        req.Provider,
 		Mode:            entity.ModeMulti,
 		Status:          "processing",
+		ProjectID:       req.ProjectID,
+		ParentTraceID:   req.ParentTraceID,
+	}
+	// 不保存内容模式：主记录和版本记录只保存元数据，内容只在本次响应中返回
+	omitContent := s.privacyService.ShouldOmitContent(ctx, userID)
+	if omitContent {
+		mainRecord.OmitContent()
 	}
 
 	if err := s.polishRepo.Create(ctx, mainRecord); err != nil {
-		logger.Error("failed to create main record", zap.Error(err))
+		logger.FromContext(ctx).Error("failed to create main record", zap.Error(err))
 		return nil, fmt.Errorf("failed to create main record: %w", err)
 	}
 
@@ -115,7 +130,7 @@ func (s *PolishMultiVersionService) PolishMultiVersion(ctx context.Context, req
 	versionTypes := s.determineVersionTypes(req.Versions)
 
 	// 6. 并发调用AI生成多个版本
-	versionResults := s.generateVersionsConcurrently(ctx, versionTypes, req, provider, mainRecord.ID)
+	versionResults := s.generateVersionsConcurrently(ctx, versionTypes, req, provider, mainRecord.ID, omitContent)
 
 	// 7. 统计结果
 	successCount := 0
@@ -163,16 +178,18 @@ func (s *PolishMultiVersionService) PolishMultiVersion(ctx context.Context, req
 	mainRecord.Model = modelUsed
 	mainRecord.SelectedVersion = selectedVersion // 保存默认选择的版本
 	mainRecord.ProcessTimeMs = totalProcessTime
+	if omitContent {
+		mainRecord.OmitContent()
+	}
 
 	if err := s.polishRepo.Update(ctx, mainRecord); err != nil {
-		logger.Error("failed to update main record", zap.Error(err))
+		logger.FromContext(ctx).Error("failed to update main record", zap.Error(err))
 		// 不返回错误，因为主要工作已完成
 	}
 
 	// 记录总耗时
 	totalElapsed := time.Since(startTime).Milliseconds()
-	logger.Info("multi-version polish completed",
-		zap.String("trace_id", traceID),
+	logger.FromContext(ctx).Info("multi-version polish completed",
 		zap.Int("success_count", successCount),
 		zap.Int("failed_count", failedCount),
 		zap.Int64("total_elapsed_ms", totalElapsed))
@@ -194,18 +211,29 @@ func (s *PolishMultiVersionService) generateVersionsConcurrently(
 	req *model.PolishMultiVersionRequest,
 	provider ai.AIProvider,
 	recordID int64,
+	omitContent bool,
 ) map[string]*model.VersionResult {
 	var wg sync.WaitGroup
 	results := make(map[string]*model.VersionResult)
 	mu := sync.Mutex{}
 
+	// 并发数上限在请求开始时读取，配置热更新只影响之后的请求
+	maxConcurrent := s.featureService.GetMaxConcurrent()
+	if maxConcurrent < 1 {
+		maxConcurrent = 1
+	}
+	sem := make(chan struct{}, maxConcurrent)
+
 	// 并发生成每个版本
 	for _, versionType := range versionTypes {
 		wg.Add(1)
 		go func(vt string) {
 			defer wg.Done()
 
-			result := s.generateSingleVersion(ctx, vt, req, provider, recordID)
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			result := s.generateSingleVersion(ctx, vt, req, provider, recordID, omitContent)
 
 			mu.Lock()
 			results[vt] = result
@@ -226,10 +254,17 @@ func (s *PolishMultiVersionService) generateSingleVersion(
 	req *model.PolishMultiVersionRequest,
 	provider ai.AIProvider,
 	recordID int64,
+	omitContent bool,
 ) *model.VersionResult {
 	startTime := time.Now()
 
-	logger.Info("generating version",
+	ctx, span := tracing.Start(ctx, "PolishMultiVersionService.generateSingleVersion",
+		attribute.String("version_type", versionType),
+		attribute.String("provider", req.Provider),
+		attribute.Int64("record_id", recordID))
+	defer span.End()
+
+	logger.FromContext(ctx).Info("generating version",
 		zap.String("version_type", versionType),
 		zap.String("language", req.Language),
 		zap.String("style", req.Style))
@@ -237,7 +272,8 @@ func (s *PolishMultiVersionService) generateSingleVersion(
 	// 1. 渲染Prompt
 	renderedPrompt, err := s.promptService.RenderPrompt(ctx, versionType, req.Language, req.Style, req.Content)
 	if err != nil {
-		logger.Error("failed to render prompt",
+		tracing.RecordError(span, err)
+		logger.FromContext(ctx).Error("failed to render prompt",
 			zap.String("version_type", versionType),
 			zap.Error(err))
 		return &model.VersionResult{
@@ -253,9 +289,12 @@ func (s *PolishMultiVersionService) generateSingleVersion(
 		Language: req.Language,
 	}
 
+	callStart := time.Now()
 	polishResp, err := provider.Polish(ctx, polishReq)
+	observeProviderCall(req.Provider, versionType, callStart, polishResp, err)
 	if err != nil {
-		logger.Error("failed to call AI provider",
+		tracing.RecordError(span, err)
+		logger.FromContext(ctx).Error("failed to call AI provider",
 			zap.String("version_type", versionType),
 			zap.Error(err))
 
@@ -269,6 +308,7 @@ func (s *PolishMultiVersionService) generateSingleVersion(
 	}
 
 	processTimeMs := int(time.Since(startTime).Milliseconds())
+	span.SetAttributes(attribute.String("model", polishResp.ModelUsed))
 
 	// 3. 保存版本记录
 	version := &entity.PolishVersion{
@@ -277,14 +317,18 @@ func (s *PolishMultiVersionService) generateSingleVersion(
 		PolishedContent: polishResp.PolishedContent,
 		PolishedLength:  len(polishResp.PolishedContent),
 		Suggestions:     polishResp.Suggestions,
+		TokenLogProbs:   toEntityTokenLogProbs(polishResp.TokenLogProbs),
 		ModelUsed:       polishResp.ModelUsed,
 		PromptID:        renderedPrompt.PromptID,
 		ProcessTimeMs:   processTimeMs,
 		Status:          "success",
 	}
+	if omitContent {
+		version.OmitContent()
+	}
 
 	if err := s.versionRepo.Create(ctx, version); err != nil {
-		logger.Error("failed to save version record",
+		logger.FromContext(ctx).Error("failed to save version record",
 			zap.String("version_type", versionType),
 			zap.Error(err))
 		// 不返回错误，因为AI调用已成功
@@ -292,11 +336,11 @@ func (s *PolishMultiVersionService) generateSingleVersion(
 
 	// 4. 增加Prompt使用次数
 	if err := s.promptService.IncrementUsage(ctx, renderedPrompt.PromptID); err != nil {
-		logger.Error("failed to increment prompt usage", zap.Error(err))
+		logger.FromContext(ctx).Error("failed to increment prompt usage", zap.Error(err))
 		// 不影响主流程
 	}
 
-	logger.Info("version generated successfully",
+	logger.FromContext(ctx).Info("version generated successfully",
 		zap.String("version_type", versionType),
 		zap.Int("process_time_ms", processTimeMs))
 
@@ -322,7 +366,7 @@ func (s *PolishMultiVersionService) saveFailedVersion(ctx context.Context, recor
 	}
 
 	if err := s.versionRepo.Create(ctx, version); err != nil {
-		logger.Error("failed to save failed version record", zap.Error(err))
+		logger.FromContext(ctx).Error("failed to save failed version record", zap.Error(err))
 	}
 }
 
@@ -341,7 +385,9 @@ func (s *PolishMultiVersionService) validateAndSetDefaults(req *model.PolishMult
 		req.Style = "academic"
 	}
 	if req.Language == "" {
-		req.Language = "en"
+		req.Language = language.DetectOrDefault(req.Content)
+	} else if !language.IsSupported(req.Language) {
+		return fmt.Errorf("invalid language, must be one of: %s", strings.Join(language.Supported(), ", "))
 	}
 	if req.Provider == "" {
 		req.Provider = config.Get().AI.DefaultProvider
@@ -381,31 +427,11 @@ func (s *PolishMultiVersionService) determineVersionTypes(requestedVersions []st
 	}
 }
 
-// generateTraceID 生成TraceID
-func (s *PolishMultiVersionService) generateTraceID(ctx context.Context) (string, error) {
-	// 从context中获取traceID，如果没有则生成
-	traceID, ok := ctx.Value("trace_id").(string)
-	if ok && traceID != "" {
-		return traceID, nil
-	}
-
-	// 使用Snowflake ID生成器
-	id, err := idgen.GenerateID()
-	if err != nil {
-		logger.Error("failed to generate trace ID", zap.Error(err))
-		// 降级方案：使用时间戳
-		return strconv.FormatInt(time.Now().UnixNano(), 10), nil
-	}
-
-	return strconv.FormatInt(id, 10), nil
-}
-
 // SelectVersion 选择一个版本并更新主记录
 // 将选中版本的内容复制到主记录的 polished_content、final_content 以及 comparison_data
 func (s *PolishMultiVersionService) SelectVersion(ctx context.Context, traceID string, userID int64, versionType string) error {
-	logger.Info("selecting version",
-		zap.String("trace_id", traceID),
-		zap.Int64("user_id", userID),
+	ctx = reqctx.WithTraceID(ctx, traceID)
+	logger.FromContext(ctx).Info("selecting version",
 		zap.String("version_type", versionType))
 
 	// 1. 验证版本类型
@@ -413,29 +439,16 @@ func (s *PolishMultiVersionService) SelectVersion(ctx context.Context, traceID s
 		return apperrors.NewInvalidParameterError(fmt.Sprintf("无效的版本类型: %s", versionType))
 	}
 
-	// 2. 获取主记录
-	mainRecord, err := s.polishRepo.GetByTraceID(ctx, traceID)
+	// 2-4. 获取主记录，验证权限和记录模式（必须是多版本模式）
+	mainRecord, err := s.loadMultiVersionRecord(ctx, traceID, userID)
 	if err != nil {
-		logger.Error("failed to get main record",
-			zap.String("trace_id", traceID),
-			zap.Error(err))
-		return apperrors.NewNotFoundError("润色记录不存在")
-	}
-
-	// 3. 验证权限
-	if mainRecord.UserID != userID {
-		return apperrors.NewForbiddenError("无权访问该记录")
-	}
-
-	// 4. 验证记录模式（必须是多版本模式）
-	if mainRecord.Mode != entity.ModeMulti {
-		return apperrors.NewInvalidParameterError("该记录不是多版本润色")
+		return err
 	}
 
 	// 5. 获取指定版本
 	version, err := s.versionRepo.GetByRecordIDAndType(ctx, mainRecord.ID, versionType)
 	if err != nil {
-		logger.Error("failed to get version",
+		logger.FromContext(ctx).Error("failed to get version",
 			zap.Int64("record_id", mainRecord.ID),
 			zap.String("version_type", versionType),
 			zap.Error(err))
@@ -447,22 +460,19 @@ func (s *PolishMultiVersionService) SelectVersion(ctx context.Context, traceID s
 		return apperrors.NewInvalidParameterError(fmt.Sprintf("版本 %s 生成失败: %s", versionType, version.ErrorMessage))
 	}
 
-	// 7. 生成对比数据
-	comparisonResult, err := s.generateComparisonData(mainRecord.OriginalContent, version.PolishedContent, traceID)
-	if err != nil {
-		logger.Error("failed to generate comparison data",
-			zap.String("trace_id", traceID),
-			zap.String("version_type", versionType),
-			zap.Error(err))
-		return fmt.Errorf("生成对比数据失败: %w", err)
-	}
+	// 7. 生成对比数据（其他成功版本用于计算多版本一致性）
+	comparisonResult := s.builder.build(ctx, comparisonInput{
+		TraceID:       traceID,
+		Original:      mainRecord.OriginalContent,
+		Polished:      version.PolishedContent,
+		TokenLogProbs: version.TokenLogProbs,
+		PeerContents:  loadPeerContents(ctx, s.versionRepo, mainRecord.ID),
+	})
 
 	// 8. 序列化对比数据
 	comparisonJSON, err := json.Marshal(comparisonResult)
 	if err != nil {
-		logger.Error("failed to marshal comparison data",
-			zap.String("trace_id", traceID),
-			zap.Error(err))
+		logger.FromContext(ctx).Error("failed to marshal comparison data", zap.Error(err))
 		return fmt.Errorf("序列化对比数据失败: %w", err)
 	}
 
@@ -471,144 +481,60 @@ func (s *PolishMultiVersionService) SelectVersion(ctx context.Context, traceID s
 	mainRecord.PolishedLength = version.PolishedLength
 	// 注意：FinalContent 不在这里赋值，而是在用户接受/拒绝修改时才更新
 	mainRecord.Model = version.ModelUsed
+	mainRecord.TokenLogProbs = version.TokenLogProbs
 	mainRecord.SelectedVersion = versionType // 记录用户选择的版本
 	mainRecord.ComparisonData = string(comparisonJSON)
 	mainRecord.ChangesCount = comparisonResult.Metadata.TotalChanges
 	mainRecord.AcceptedChanges = []string{}  // 初始为空
 	mainRecord.RejectedChanges = []string{}  // 初始为空
+	mainRecord.SentenceSources = []entity.SentenceSource{} // 清除之前的按句组合结果
 	mainRecord.ProcessTimeMs = version.ProcessTimeMs
 	mainRecord.UpdatedAt = time.Now()
 
 	// 10. 保存更新
 	if err := s.polishRepo.Update(ctx, mainRecord); err != nil {
-		logger.Error("failed to update main record",
+		logger.FromContext(ctx).Error("failed to update main record",
 			zap.Int64("record_id", mainRecord.ID),
 			zap.String("version_type", versionType),
 			zap.Error(err))
 		return fmt.Errorf("更新记录失败: %w", err)
 	}
 
-	logger.Info("version selected successfully",
-		zap.String("trace_id", traceID),
+	s.logReset(ctx, mainRecord)
+
+	logger.FromContext(ctx).Info("version selected successfully",
 		zap.String("version_type", versionType),
 		zap.Int("changes_count", comparisonResult.Metadata.TotalChanges))
 
 	return nil
 }
 
-// generateComparisonData 生成对比数据（复用 ComparisonService 的逻辑）
-func (s *PolishMultiVersionService) generateComparisonData(original, polished, traceID string) (*model.ComparisonResult, error) {
-	// 1. 运行 diff 算法
-	diffs := s.diffEngine.GenerateDiff(original, polished)
-
-	// 2. 提取修改信息
-	changes := s.diffEngine.GetChanges(diffs)
-
-	// 3. 计算位置
-	positions := s.positionCalc.CalculatePositions(polished, changes)
-
-	// 4. 生成标注列表
-	annotations := s.buildAnnotations(positions)
-
-	// 5. 计算元数据和统计信息
-	metadata, statistics := s.calculateStats(original, polished, annotations)
-
-	return &model.ComparisonResult{
-		TraceID:         traceID,
-		OriginalContent: original,
-		PolishedContent: polished,
-		FinalContent:    "", // 选择版本时 final_content 为空，等待用户操作
-		Annotations:     annotations,
-		Metadata:        metadata,
-		Statistics:      statistics,
-	}, nil
-}
-
-// buildAnnotations 构建标注列表
-func (s *PolishMultiVersionService) buildAnnotations(positions []comparison.PositionInfo) []model.Change {
-	annotations := make([]model.Change, 0, len(positions))
-
-	for i, pos := range positions {
-		// 分类修改类型
-		changeType := s.classifier.Classify(pos.OriginalText, pos.PolishedText)
-
-		// 生成修改理由
-		reason := s.reasonGenerator.Generate(changeType, pos.OriginalText, pos.PolishedText)
-
-		// 生成替代方案
-		alternatives := s.reasonGenerator.GenerateAlternatives(changeType, pos.OriginalText)
-
-		// 计算置信度
-		confidence := s.reasonGenerator.CalculateConfidence(changeType, pos.OriginalText, pos.PolishedText)
-
-		// 获取影响维度
-		impact := s.reasonGenerator.GetImpact(changeType)
-
-		// 建议高亮颜色
-		highlightColor := s.classifier.SuggestHighlightColor(changeType)
-
-		annotations = append(annotations, model.Change{
-			ID:   fmt.Sprintf("change_%d", i+1),
-			Type: changeType,
-			PolishedPosition: model.Position{
-				Start: pos.Start,
-				End:   pos.End,
-				Line:  pos.Line,
-			},
-			PolishedText:   pos.PolishedText,
-			OriginalText:   pos.OriginalText,
-			Reason:         reason,
-			Alternatives:   alternatives,
-			Confidence:     confidence,
-			Impact:         impact,
-			HighlightColor: highlightColor,
-			Status:         model.ActionStatusPending,
-		})
-	}
-
-	return annotations
-}
-
-// calculateStats 计算统计信息
-func (s *PolishMultiVersionService) calculateStats(original, polished string, annotations []model.Change) (model.Metadata, model.Statistics) {
-	originalWordCount := comparison.CountWords(original)
-	polishedWordCount := comparison.CountWords(polished)
-
-	// 统计各类修改数量
-	var vocabCount, grammarCount, structureCount int
-	for _, ann := range annotations {
-		switch ann.Type {
-		case model.ChangeTypeVocabulary:
-			vocabCount++
-		case model.ChangeTypeGrammar:
-			grammarCount++
-		case model.ChangeTypeStructure:
-			structureCount++
-		}
+// loadMultiVersionRecord 获取多版本润色主记录，并验证权限和记录模式
+func (s *PolishMultiVersionService) loadMultiVersionRecord(ctx context.Context, traceID string, userID int64) (*entity.PolishRecord, error) {
+	mainRecord, err := s.polishRepo.GetByTraceID(ctx, traceID)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to get main record", zap.Error(err))
+		return nil, apperrors.NewNotFoundError("润色记录不存在")
 	}
 
-	// 计算学术性提升（简单算法：词汇优化占比 * 100）
-	academicImprovement := 0.0
-	if len(annotations) > 0 {
-		academicImprovement = float64(vocabCount) / float64(len(annotations)) * 100
-		// 限制在0-100之间
-		if academicImprovement > 100 {
-			academicImprovement = 100
-		}
+	if mainRecord.UserID != userID {
+		return nil, apperrors.NewForbiddenError("无权访问该记录")
 	}
 
-	metadata := model.Metadata{
-		OriginalWordCount:        originalWordCount,
-		PolishedWordCount:        polishedWordCount,
-		TotalChanges:             len(annotations),
-		AcademicScoreImprovement: academicImprovement,
+	if mainRecord.Mode != entity.ModeMulti {
+		return nil, apperrors.NewInvalidParameterError("该记录不是多版本润色")
 	}
 
-	statistics := model.Statistics{
-		VocabularyChanges: vocabCount,
-		GrammarChanges:    grammarCount,
-		StructureChanges:  structureCount,
+	if err := checkContentStored(mainRecord); err != nil {
+		return nil, err
 	}
 
-	return metadata, statistics
+	return mainRecord, nil
+}
+
+// logReset 对比数据重新生成后记录重置操作，之前的操作不再可撤销
+func (s *PolishMultiVersionService) logReset(ctx context.Context, mainRecord *entity.PolishRecord) {
+	if err := appendComparisonAction(ctx, s.actionRepo, mainRecord, entity.ComparisonActionReset, 0, nil); err != nil {
+		logger.FromContext(ctx).Warn("failed to log comparison reset", zap.Error(err))
+	}
 }
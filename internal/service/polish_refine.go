@@ -0,0 +1,309 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"paper_ai/internal/config"
+	"paper_ai/internal/domain/entity"
+	"paper_ai/internal/domain/model"
+	"paper_ai/internal/domain/repository"
+	"paper_ai/internal/infrastructure/ai"
+	"paper_ai/internal/infrastructure/ai/types"
+	apperrors "paper_ai/pkg/errors"
+	"paper_ai/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// RefineService 多轮修改服务
+// 用户看到润色结果后用自然语言指令继续修改：原文、之前的结果和指令作为多轮对话发送给提供商，
+// 每轮保存为记录的一个修订（带本轮的对比数据），成功的修订成为记录当前的润色结果
+type RefineService struct {
+	providerFactory *ai.ProviderFactory
+	polishRepo      repository.PolishRepository
+	revisionRepo    repository.PolishRevisionRepository
+	actionRepo      repository.ComparisonActionRepository
+	privacyService  *PrivacyService // 隐私设置（为 nil 时总是保存内容）
+	builder         *comparisonBuilder
+}
+
+// NewRefineService 创建多轮修改服务
+func NewRefineService(
+	factory *ai.ProviderFactory,
+	polishRepo repository.PolishRepository,
+	revisionRepo repository.PolishRevisionRepository,
+	feedbackRepo repository.ChangeFeedbackRepository,
+	actionRepo repository.ComparisonActionRepository,
+	privacyService *PrivacyService,
+) *RefineService {
+	return &RefineService{
+		providerFactory: factory,
+		polishRepo:      polishRepo,
+		revisionRepo:    revisionRepo,
+		actionRepo:      actionRepo,
+		privacyService:  privacyService,
+		builder:         newComparisonBuilder(feedbackRepo),
+	}
+}
+
+// Refine 按修改指令修改记录当前的润色文本，返回本轮修订（含对比数据）
+func (s *RefineService) Refine(ctx context.Context, traceID string, req *model.RefineRequest, userID int64) (*model.RevisionInfo, error) {
+	startTime := time.Now()
+
+	instruction, err := normalizeInstruction(req.Instruction)
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := s.loadRecord(ctx, traceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkContentStored(record); err != nil {
+		return nil, err
+	}
+	if record.IsTranslateMode() {
+		return nil, apperrors.NewInvalidParameterError("翻译记录不支持多轮修改")
+	}
+	current := firstNonEmpty(record.FinalContent, record.PolishedContent)
+	if record.Status == "processing" || current == "" {
+		return nil, apperrors.NewInvalidParameterError("该记录没有润色结果，无法继续修改")
+	}
+	if s.privacyService.ShouldOmitContent(ctx, userID) {
+		return nil, apperrors.NewInvalidParameterError("不保存内容模式下无法多轮修改")
+	}
+
+	revisions, err := s.revisionRepo.ListByRecordID(ctx, record.ID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("获取修订记录失败", err)
+	}
+	if len(revisions) >= model.MaxRevisionsPerRecord {
+		return nil, apperrors.NewInvalidParameterError(fmt.Sprintf("每条记录最多修改 %d 轮，请使用重新润色", model.MaxRevisionsPerRecord))
+	}
+
+	providerName := firstNonEmpty(req.Provider, record.Provider, config.Get().AI.DefaultProvider)
+	provider, err := s.providerFactory.GetProvider(providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).Info("refinement started",
+		zap.String("trace_id", traceID),
+		zap.String("provider", providerName),
+		zap.Int("history_turns", len(revisions)))
+
+	revision := &entity.PolishRevision{
+		RecordID:    record.ID,
+		Instruction: instruction,
+		BaseContent: current,
+		Provider:    providerName,
+	}
+
+	callStart := time.Now()
+	resp, err := provider.Refine(ctx, &types.RefineRequest{
+		Original:    record.OriginalContent,
+		Style:       record.Style,
+		Language:    record.Language,
+		History:     refineHistory(revisions),
+		Current:     current,
+		Instruction: instruction,
+	})
+	observeProviderCall(providerName, "refine", callStart, resp, err)
+	if err != nil {
+		logger.FromContext(ctx).Error("ai provider refinement failed", zap.String("provider", providerName), zap.Error(err))
+		revision.Status = "failed"
+		revision.ErrorMessage = err.Error()
+		revision.ProcessTimeMs = int(time.Since(startTime).Milliseconds())
+		if saveErr := s.revisionRepo.Create(ctx, revision); saveErr != nil {
+			logger.FromContext(ctx).Error("failed to save failed revision", zap.Error(saveErr))
+		}
+		return nil, err
+	}
+
+	// 本轮对比：修改前文本 → 修改结果
+	comparisonResult := s.builder.build(ctx, comparisonInput{
+		TraceID:       traceID,
+		Original:      current,
+		Polished:      resp.PolishedContent,
+		TokenLogProbs: toEntityTokenLogProbs(resp.TokenLogProbs),
+	})
+	comparisonJSON, err := json.Marshal(comparisonResult)
+	if err != nil {
+		return nil, fmt.Errorf("序列化对比数据失败: %w", err)
+	}
+
+	revision.PolishedContent = resp.PolishedContent
+	revision.PolishedLength = len(resp.PolishedContent)
+	revision.ComparisonData = string(comparisonJSON)
+	revision.ChangesCount = comparisonResult.Metadata.TotalChanges
+	revision.Model = resp.ModelUsed
+	revision.Status = "success"
+	revision.ProcessTimeMs = int(time.Since(startTime).Milliseconds())
+	if err := s.revisionRepo.Create(ctx, revision); err != nil {
+		return nil, apperrors.NewInternalError("保存修订失败", err)
+	}
+
+	if err := s.applyRevision(ctx, record, revision, resp); err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).Info("refinement completed",
+		zap.String("trace_id", traceID),
+		zap.Int("revision", revision.Revision),
+		zap.Int("changes_count", revision.ChangesCount))
+
+	info := toRevisionInfo(revision)
+	info.Comparison = comparisonResult
+	return info, nil
+}
+
+// ListRevisions 按修订号升序获取记录的所有修订（不含对比数据）
+func (s *RefineService) ListRevisions(ctx context.Context, traceID string, userID int64) ([]*model.RevisionInfo, error) {
+	record, err := s.loadRecord(ctx, traceID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.revisionRepo.ListByRecordID(ctx, record.ID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("获取修订记录失败", err)
+	}
+
+	infos := make([]*model.RevisionInfo, len(revisions))
+	for i, revision := range revisions {
+		infos[i] = toRevisionInfo(revision)
+	}
+	return infos, nil
+}
+
+// GetRevision 获取记录的指定修订（含对比数据）
+func (s *RefineService) GetRevision(ctx context.Context, traceID string, revisionNumber int, userID int64) (*model.RevisionInfo, error) {
+	record, err := s.loadRecord(ctx, traceID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	revision, err := s.revisionRepo.GetByRecordIDAndRevision(ctx, record.ID, revisionNumber)
+	if err != nil {
+		return nil, apperrors.NewInternalError("获取修订记录失败", err)
+	}
+	if revision == nil {
+		return nil, apperrors.NewNotFoundError(fmt.Sprintf("修订 %d 不存在", revisionNumber))
+	}
+
+	info := toRevisionInfo(revision)
+	if revision.ComparisonData != "" {
+		var comparisonResult model.ComparisonResult
+		if err := json.Unmarshal([]byte(revision.ComparisonData), &comparisonResult); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal comparison data: %w", err)
+		}
+		info.Comparison = &comparisonResult
+	}
+	return info, nil
+}
+
+// applyRevision 修订结果成为记录当前的润色结果
+// 重新生成原文与新结果的对比数据，之前的接受/拒绝状态和最终文本清空，操作日志记录重置
+func (s *RefineService) applyRevision(ctx context.Context, record *entity.PolishRecord, revision *entity.PolishRevision, resp *types.PolishResponse) error {
+	tokenLogProbs := toEntityTokenLogProbs(resp.TokenLogProbs)
+	comparisonResult := s.builder.build(ctx, comparisonInput{
+		TraceID:       record.TraceID,
+		Original:      record.OriginalContent,
+		Polished:      revision.PolishedContent,
+		TokenLogProbs: tokenLogProbs,
+	})
+	comparisonJSON, err := json.Marshal(comparisonResult)
+	if err != nil {
+		return fmt.Errorf("序列化对比数据失败: %w", err)
+	}
+
+	record.PolishedContent = revision.PolishedContent
+	record.PolishedLength = revision.PolishedLength
+	record.FinalContent = ""
+	record.Provider = revision.Provider
+	record.Model = revision.Model
+	record.TokenLogProbs = tokenLogProbs
+	record.ComparisonData = string(comparisonJSON)
+	record.ChangesCount = comparisonResult.Metadata.TotalChanges
+	record.AcceptedChanges = []string{}
+	record.RejectedChanges = []string{}
+	record.SentenceSources = []entity.SentenceSource{} // 清除之前的按句组合结果
+	record.Selection = nil                             // 修改针对整段文本，不再限于选区
+	record.UpdatedAt = time.Now()
+
+	if err := s.polishRepo.Update(ctx, record); err != nil {
+		logger.FromContext(ctx).Error("failed to apply revision to record",
+			zap.Int64("record_id", record.ID),
+			zap.Int("revision", revision.Revision),
+			zap.Error(err))
+		return apperrors.NewInternalError("更新记录失败", err)
+	}
+
+	if err := appendComparisonAction(ctx, s.actionRepo, record, entity.ComparisonActionReset, 0, nil); err != nil {
+		logger.FromContext(ctx).Warn("failed to log comparison reset", zap.Error(err))
+	}
+	return nil
+}
+
+// loadRecord 获取记录并验证所有权
+func (s *RefineService) loadRecord(ctx context.Context, traceID string, userID int64) (*entity.PolishRecord, error) {
+	record, err := s.polishRepo.GetByTraceID(ctx, traceID)
+	if err != nil {
+		return nil, apperrors.NewNotFoundError("润色记录不存在")
+	}
+	if record.UserID != userID {
+		return nil, apperrors.NewForbiddenError("无权访问该记录")
+	}
+	return record, nil
+}
+
+// normalizeInstruction 去除首尾空白并校验修改指令长度
+func normalizeInstruction(instruction string) (string, error) {
+	instruction = strings.TrimSpace(instruction)
+	if instruction == "" {
+		return "", apperrors.NewInvalidParameterError("instruction 不能为空")
+	}
+	if utf8.RuneCountInString(instruction) > model.MaxRefineInstructionLength {
+		return "", apperrors.NewInvalidParameterError(fmt.Sprintf("instruction 不能超过 %d 个字符", model.MaxRefineInstructionLength))
+	}
+	return instruction, nil
+}
+
+// refineHistory 由之前成功的修订构建对话历史
+// 每轮的润色文本取该轮的修改前文本（用户提出指令时看到的文本，包括其间接受/拒绝修改的结果）
+func refineHistory(revisions []*entity.PolishRevision) []types.RefineTurn {
+	history := make([]types.RefineTurn, 0, len(revisions))
+	for _, revision := range revisions {
+		if !revision.IsSuccess() {
+			continue
+		}
+		history = append(history, types.RefineTurn{
+			Polished:    revision.BaseContent,
+			Instruction: revision.Instruction,
+		})
+	}
+	return history
+}
+
+// toRevisionInfo 修订实体转换为修订信息（不含对比数据）
+func toRevisionInfo(revision *entity.PolishRevision) *model.RevisionInfo {
+	return &model.RevisionInfo{
+		Revision:        revision.Revision,
+		Instruction:     revision.Instruction,
+		BaseContent:     revision.BaseContent,
+		PolishedContent: revision.PolishedContent,
+		PolishedLength:  revision.PolishedLength,
+		ChangesCount:    revision.ChangesCount,
+		Provider:        revision.Provider,
+		Model:           revision.Model,
+		ProcessTimeMs:   revision.ProcessTimeMs,
+		Status:          revision.Status,
+		ErrorMessage:    revision.ErrorMessage,
+		CreatedAt:       revision.CreatedAt,
+	}
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"paper_ai/internal/domain/entity"
+	"paper_ai/internal/domain/model"
+)
+
+func TestNormalizeInstruction(t *testing.T) {
+	tests := []struct {
+		name        string
+		instruction string
+		want        string
+		wantErr     bool
+	}{
+		{"trims whitespace", "  更正式一些 \n", "更正式一些", false},
+		{"empty", "   ", "", true},
+		{"max length in runes", strings.Repeat("改", model.MaxRefineInstructionLength), strings.Repeat("改", model.MaxRefineInstructionLength), false},
+		{"too long", strings.Repeat("a", model.MaxRefineInstructionLength+1), "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeInstruction(tt.instruction)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("normalizeInstruction() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("normalizeInstruction() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRefineHistory(t *testing.T) {
+	revisions := []*entity.PolishRevision{
+		{Revision: 1, Instruction: "more formal", BaseContent: "v0", PolishedContent: "v1", Status: "success"},
+		{Revision: 2, Instruction: "shorter", BaseContent: "v1", Status: "failed"},
+		{Revision: 3, Instruction: "shorter", BaseContent: "v1 edited", PolishedContent: "v2", Status: "success"},
+	}
+
+	history := refineHistory(revisions)
+	if len(history) != 2 {
+		t.Fatalf("refineHistory() returned %d turns, want 2", len(history))
+	}
+	if history[0].Polished != "v0" || history[0].Instruction != "more formal" {
+		t.Errorf("history[0] = %+v", history[0])
+	}
+	// 修改前文本包含用户在两轮之间接受/拒绝修改的结果
+	if history[1].Polished != "v1 edited" || history[1].Instruction != "shorter" {
+		t.Errorf("history[1] = %+v", history[1])
+	}
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+
+	"paper_ai/internal/domain/entity"
+	"paper_ai/internal/domain/model"
+	"paper_ai/internal/domain/repository"
+	apperrors "paper_ai/pkg/errors"
+	"paper_ai/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// RepolishService 基于已有记录重新润色（新记录通过 ParentTraceID 关联来源记录）
+type RepolishService struct {
+	polishRepo          repository.PolishRepository
+	polishService       *PolishService
+	multiVersionService *PolishMultiVersionService
+}
+
+// NewRepolishService 创建重新润色服务
+func NewRepolishService(polishRepo repository.PolishRepository, polishService *PolishService, multiVersionService *PolishMultiVersionService) *RepolishService {
+	return &RepolishService{
+		polishRepo:          polishRepo,
+		polishService:       polishService,
+		multiVersionService: multiVersionService,
+	}
+}
+
+// Repolish 用来源记录的原文或最终文本重新润色，未指定的参数沿用来源记录，新记录归入来源记录的项目
+func (s *RepolishService) Repolish(ctx context.Context, parentTraceID string, req *model.RepolishRequest, userID int64) (*model.RepolishResponse, error) {
+	parent, err := s.polishRepo.GetByTraceID(ctx, parentTraceID)
+	if err != nil {
+		return nil, apperrors.NewNotFoundError("润色记录不存在")
+	}
+	if parent.UserID != userID {
+		return nil, apperrors.NewForbiddenError("无权访问该记录")
+	}
+	if parent.IsTranslateMode() {
+		return nil, apperrors.NewInvalidParameterError("翻译记录无法重新润色，请重新翻译")
+	}
+
+	mode, source, err := resolveRepolishOptions(parent, req)
+	if err != nil {
+		return nil, err
+	}
+	content, err := repolishContent(parent, source)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).Info("repolish started",
+		zap.String("parent_trace_id", parentTraceID),
+		zap.String("mode", mode),
+		zap.String("source", source))
+
+	resp := &model.RepolishResponse{
+		ParentTraceID: parentTraceID,
+		Mode:          mode,
+		Source:        source,
+	}
+
+	if mode == entity.ModeMulti {
+		result, err := s.multiVersionService.PolishMultiVersion(ctx, &model.PolishMultiVersionRequest{
+			Content:       content,
+			Style:         firstNonEmpty(req.Style, parent.Style),
+			Language:      firstNonEmpty(req.Language, parent.Language),
+			Provider:      firstNonEmpty(req.Provider, parent.Provider),
+			Versions:      req.Versions,
+			ProjectID:     parent.ProjectID,
+			ParentTraceID: parentTraceID,
+		}, userID)
+		if err != nil {
+			return nil, err
+		}
+		resp.TraceID = result.TraceID
+		resp.Result = result
+		return resp, nil
+	}
+
+	result, err := s.polishService.Polish(ctx, &model.PolishRequest{
+		Content:       content,
+		Style:         firstNonEmpty(req.Style, parent.Style),
+		Language:      firstNonEmpty(req.Language, parent.Language),
+		Provider:      firstNonEmpty(req.Provider, parent.Provider),
+		ProjectID:     parent.ProjectID,
+		ParentTraceID: parentTraceID,
+	}, userID)
+	if err != nil {
+		return nil, err
+	}
+	resp.TraceID = result.TraceID
+	resp.Result = result
+	return resp, nil
+}
+
+// resolveRepolishOptions 校验并确定重新润色的模式和文本来源
+func resolveRepolishOptions(parent *entity.PolishRecord, req *model.RepolishRequest) (string, string, error) {
+	mode := req.Mode
+	if mode == "" {
+		mode = entity.ModeSingle
+		if parent.IsMultiVersionMode() {
+			mode = entity.ModeMulti
+		}
+	}
+	if !entity.IsValidMode(mode) {
+		return "", "", apperrors.NewInvalidParameterError("mode 必须是 single 或 multi")
+	}
+	if mode == entity.ModeSingle && len(req.Versions) > 0 {
+		return "", "", apperrors.NewInvalidParameterError("versions 只适用于多版本模式")
+	}
+
+	source := req.Source
+	if source == "" {
+		source = model.RepolishSourceOriginal
+	}
+	if source != model.RepolishSourceOriginal && source != model.RepolishSourceFinal {
+		return "", "", apperrors.NewInvalidParameterError("source 必须是 original 或 final")
+	}
+	return mode, source, nil
+}
+
+// repolishContent 取来源记录中用于重新润色的文本
+// 最终文本优先使用应用修改后的 FinalContent，没有时使用润色结果（多版本为已选择的版本）
+func repolishContent(parent *entity.PolishRecord, source string) (string, error) {
+	if parent.ContentOmitted {
+		return "", apperrors.NewInvalidParameterError("该记录未保存内容（不保存内容模式），无法重新润色")
+	}
+
+	if source == model.RepolishSourceOriginal {
+		if parent.OriginalContent == "" {
+			return "", apperrors.NewInvalidParameterError("该记录没有原文")
+		}
+		return parent.OriginalContent, nil
+	}
+
+	content := firstNonEmpty(parent.FinalContent, parent.PolishedContent)
+	if content == "" {
+		return "", apperrors.NewInvalidParameterError("该记录没有润色结果，只能使用原文重新润色")
+	}
+	return content, nil
+}
+
+// firstNonEmpty 返回第一个非空字符串
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
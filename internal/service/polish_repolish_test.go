@@ -0,0 +1,66 @@
+package service
+
+import (
+	"testing"
+
+	"paper_ai/internal/domain/entity"
+	"paper_ai/internal/domain/model"
+)
+
+func TestResolveRepolishOptions(t *testing.T) {
+	single := &entity.PolishRecord{Mode: entity.ModeSingle}
+	multi := &entity.PolishRecord{Mode: entity.ModeMulti}
+
+	tests := []struct {
+		name       string
+		parent     *entity.PolishRecord
+		req        model.RepolishRequest
+		wantMode   string
+		wantSource string
+		wantErr    bool
+	}{
+		{"defaults follow parent single", single, model.RepolishRequest{}, entity.ModeSingle, model.RepolishSourceOriginal, false},
+		{"defaults follow parent multi", multi, model.RepolishRequest{}, entity.ModeMulti, model.RepolishSourceOriginal, false},
+		{"override mode and source", single, model.RepolishRequest{Mode: entity.ModeMulti, Source: model.RepolishSourceFinal}, entity.ModeMulti, model.RepolishSourceFinal, false},
+		{"invalid mode", single, model.RepolishRequest{Mode: "batch"}, "", "", true},
+		{"invalid source", single, model.RepolishRequest{Source: "polished"}, "", "", true},
+		{"versions in single mode", multi, model.RepolishRequest{Mode: entity.ModeSingle, Versions: []string{"balanced"}}, "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mode, source, err := resolveRepolishOptions(tt.parent, &tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveRepolishOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if mode != tt.wantMode || source != tt.wantSource {
+				t.Errorf("resolveRepolishOptions() = %q, %q, want %q, %q", mode, source, tt.wantMode, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestRepolishContent(t *testing.T) {
+	record := &entity.PolishRecord{OriginalContent: "original", PolishedContent: "polished"}
+	if got, _ := repolishContent(record, model.RepolishSourceOriginal); got != "original" {
+		t.Errorf("original source = %q", got)
+	}
+	if got, _ := repolishContent(record, model.RepolishSourceFinal); got != "polished" {
+		t.Errorf("final source without final content = %q, want polished content", got)
+	}
+
+	record.FinalContent = "final"
+	if got, _ := repolishContent(record, model.RepolishSourceFinal); got != "final" {
+		t.Errorf("final source = %q", got)
+	}
+
+	failed := &entity.PolishRecord{OriginalContent: "original", Status: "failed"}
+	if _, err := repolishContent(failed, model.RepolishSourceFinal); err == nil {
+		t.Error("expected error for final source of failed record")
+	}
+
+	omitted := &entity.PolishRecord{ContentOmitted: true}
+	if _, err := repolishContent(omitted, model.RepolishSourceOriginal); err == nil {
+		t.Error("expected error for record without stored content")
+	}
+}
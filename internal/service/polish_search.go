@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"paper_ai/internal/domain/model"
+	"paper_ai/internal/domain/repository"
+	apperrors "paper_ai/pkg/errors"
+	"paper_ai/pkg/language"
+)
+
+const (
+	snippetLeadRunes = 30  // 摘要中第一个匹配词之前保留的字符数
+	snippetMaxRunes  = 160 // 摘要最大字符数
+)
+
+// SearchRecords 全文检索润色历史（opts.SearchText 为检索词），excludeText 为 true 时结果中只保留摘要、不返回全文
+// 已加密的记录不参与检索
+func (s *PolishService) SearchRecords(ctx context.Context, opts repository.QueryOptions, excludeText bool) ([]*model.RecordSearchHit, int64, error) {
+	if opts.SearchText == nil || strings.TrimSpace(*opts.SearchText) == "" {
+		return nil, 0, apperrors.NewInvalidParameterError("检索词不能为空")
+	}
+	if utf8.RuneCountInString(*opts.SearchText) > model.MaxSearchTextLength {
+		return nil, 0, apperrors.NewInvalidParameterError(fmt.Sprintf("检索词不能超过 %d 个字符", model.MaxSearchTextLength))
+	}
+
+	records, total, _, err := s.ListRecords(ctx, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	terms := highlightTerms(*opts.SearchText)
+	// 与仓储层一致：包含中日韩文字时检索为子串匹配，否则为单词匹配
+	wordBoundary := !language.ContainsCJK(*opts.SearchText)
+	hits := make([]*model.RecordSearchHit, len(records))
+	for i, record := range records {
+		hits[i] = &model.RecordSearchHit{
+			Record:          record,
+			OriginalSnippet: buildSnippet(record.OriginalContent, terms, wordBoundary),
+			PolishedSnippet: buildSnippet(firstNonEmpty(record.FinalContent, record.PolishedContent), terms, wordBoundary),
+		}
+		if excludeText {
+			record.OriginalContent = ""
+			record.PolishedContent = ""
+			record.FinalContent = ""
+			record.ComparisonData = ""
+			record.TokenLogProbs = nil
+		}
+	}
+
+	return hits, total, nil
+}
+
+// highlightTerms 从检索词中提取需要高亮的词（小写）
+// 中日韩检索词按空白拆分；其他按 websearch 语法拆分为单词，忽略排除词（-word）和 or
+func highlightTerms(text string) []string {
+	seen := make(map[string]bool)
+	var terms []string
+	add := func(term string) {
+		term = strings.ToLower(term)
+		if term != "" && !seen[term] {
+			seen[term] = true
+			terms = append(terms, term)
+		}
+	}
+
+	if language.ContainsCJK(text) {
+		for _, field := range strings.Fields(text) {
+			add(field)
+		}
+		return terms
+	}
+
+	for _, field := range strings.Fields(text) {
+		if strings.HasPrefix(field, "-") || strings.EqualFold(field, "or") {
+			continue
+		}
+		for _, word := range strings.FieldsFunc(field, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) {
+			add(word)
+		}
+	}
+	return terms
+}
+
+// snippetMatch 摘要中的一个匹配位置（字符下标，左闭右开）
+type snippetMatch struct {
+	start, end int
+}
+
+// buildSnippet 截取 text 中第一个匹配词附近的片段：内容做 HTML 转义，匹配词以 <mark></mark> 标记，截断处以 … 表示
+// wordBoundary 为 true 时只匹配完整单词；没有匹配时返回空字符串
+func buildSnippet(text string, terms []string, wordBoundary bool) string {
+	if text == "" || len(terms) == 0 {
+		return ""
+	}
+
+	runes := []rune(text)
+	matches := findMatches(runes, terms, wordBoundary)
+	if len(matches) == 0 {
+		return ""
+	}
+
+	start := matches[0].start - snippetLeadRunes
+	if start < 0 {
+		start = 0
+	}
+	end := start + snippetMaxRunes
+	if end < matches[0].end {
+		end = matches[0].end
+	}
+	if end > len(runes) {
+		end = len(runes)
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	pos := start
+	for _, m := range matches {
+		if m.end > end {
+			break
+		}
+		b.WriteString(html.EscapeString(string(runes[pos:m.start])))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(string(runes[m.start:m.end])))
+		b.WriteString("</mark>")
+		pos = m.end
+	}
+	b.WriteString(html.EscapeString(string(runes[pos:end])))
+	if end < len(runes) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
+
+// findMatches 查找所有不重叠的匹配位置（忽略大小写，同一位置优先匹配较长的词）
+func findMatches(runes []rune, terms []string, wordBoundary bool) []snippetMatch {
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	termRunes := make([][]rune, len(terms))
+	for i, term := range terms {
+		termRunes[i] = []rune(term)
+	}
+	sort.SliceStable(termRunes, func(i, j int) bool { return len(termRunes[i]) > len(termRunes[j]) })
+
+	var matches []snippetMatch
+	for i := 0; i < len(lower); {
+		matched := 0
+		for _, term := range termRunes {
+			if hasPrefixAt(lower, i, term) && (!wordBoundary || isWordBoundary(lower, i, i+len(term))) {
+				matched = len(term)
+				break
+			}
+		}
+		if matched == 0 {
+			i++
+			continue
+		}
+		matches = append(matches, snippetMatch{start: i, end: i + matched})
+		i += matched
+	}
+	return matches
+}
+
+// hasPrefixAt runes 从 i 开始是否为 term
+func hasPrefixAt(runes []rune, i int, term []rune) bool {
+	if len(term) == 0 || i+len(term) > len(runes) {
+		return false
+	}
+	for j, r := range term {
+		if runes[i+j] != r {
+			return false
+		}
+	}
+	return true
+}
+
+// isWordBoundary [start, end) 前后是否都不是字母或数字
+func isWordBoundary(runes []rune, start, end int) bool {
+	isWord := func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }
+	if start > 0 && isWord(runes[start-1]) {
+		return false
+	}
+	return end >= len(runes) || !isWord(runes[end])
+}
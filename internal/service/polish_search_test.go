@@ -0,0 +1,87 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestHighlightTerms(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{`Neural networks`, []string{"neural", "networks"}},
+		{`"deep learning" or transformer -cnn`, []string{"deep", "learning", "transformer"}},
+		{`state-of-the-art BERT bert`, []string{"state", "of", "the", "art", "bert"}},
+		{`深度学习  模型`, []string{"深度学习", "模型"}},
+	}
+
+	for _, tt := range tests {
+		if got := highlightTerms(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("highlightTerms(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestBuildSnippet(t *testing.T) {
+	tests := []struct {
+		name         string
+		text         string
+		terms        []string
+		wordBoundary bool
+		want         string
+	}{
+		{
+			name:         "高亮并转义",
+			text:         "We train a <b>Neural</b> network & evaluate networks.",
+			terms:        []string{"neural", "network"},
+			wordBoundary: true,
+			want:         "We train a &lt;b&gt;<mark>Neural</mark>&lt;/b&gt; <mark>network</mark> &amp; evaluate networks.",
+		},
+		{
+			name:         "中文子串匹配",
+			text:         "本文提出一种基于深度学习的方法",
+			terms:        []string{"深度学习"},
+			wordBoundary: false,
+			want:         "本文提出一种基于<mark>深度学习</mark>的方法",
+		},
+		{
+			name:         "同一位置优先匹配较长的词",
+			text:         "机器学习模型",
+			terms:        []string{"学习", "机器学习"},
+			wordBoundary: false,
+			want:         "<mark>机器学习</mark>模型",
+		},
+		{
+			name:         "没有匹配",
+			text:         "networks only",
+			terms:        []string{"network"},
+			wordBoundary: true,
+			want:         "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := buildSnippet(tt.text, tt.terms, tt.wordBoundary); got != tt.want {
+				t.Errorf("buildSnippet() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildSnippet_Truncate(t *testing.T) {
+	text := strings.Repeat("a ", 100) + "target" + strings.Repeat(" b", 200)
+	got := buildSnippet(text, []string{"target"}, true)
+
+	if !strings.HasPrefix(got, "…") || !strings.HasSuffix(got, "…") {
+		t.Errorf("截断的摘要应以 … 开头和结尾: %q", got)
+	}
+	if !strings.Contains(got, "<mark>target</mark>") {
+		t.Errorf("摘要应包含高亮的匹配词: %q", got)
+	}
+	if n := len([]rune(strings.NewReplacer("<mark>", "", "</mark>", "", "…", "").Replace(got))); n != snippetMaxRunes {
+		t.Errorf("摘要长度 = %d, want %d", n, snippetMaxRunes)
+	}
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"paper_ai/internal/domain/entity"
+	"paper_ai/internal/domain/model"
+	"paper_ai/internal/infrastructure/ai/types"
+)
+
+// trimSelection 去掉选区首尾的空白（提供商返回的片段不带首尾空白，拼接时保留原文的空白）
+// 调用前选区已通过校验（在文本范围内且不只包含空白）
+func trimSelection(content string, selection *model.TextSelection) *entity.TextSelection {
+	runes := []rune(content)
+	start, end := selection.Start, selection.End
+	for start < end && unicode.IsSpace(runes[start]) {
+		start++
+	}
+	for end > start && unicode.IsSpace(runes[end-1]) {
+		end--
+	}
+	return &entity.TextSelection{Start: start, End: end}
+}
+
+// selectionRequest 构建选区润色请求：只发送选中的片段，前后文作为上下文
+func selectionRequest(req *model.PolishRequest, selection *entity.TextSelection) *types.PolishRequest {
+	runes := []rune(req.Content)
+	return &types.PolishRequest{
+		Content:       string(runes[selection.Start:selection.End]),
+		Style:         req.Style,
+		Language:      req.Language,
+		ContextBefore: string(runes[:selection.Start]),
+		ContextAfter:  string(runes[selection.End:]),
+	}
+}
+
+// spliceSelection 把润色后的片段拼回整段文本，响应中的内容和长度改为整段的
+func spliceSelection(content string, selection *entity.TextSelection, resp *types.PolishResponse) {
+	runes := []rune(content)
+	polished := strings.TrimSpace(resp.PolishedContent)
+
+	resp.PolishedContent = string(runes[:selection.Start]) + polished + string(runes[selection.End:])
+	resp.OriginalLength = len(content)
+	resp.PolishedLength = len(resp.PolishedContent)
+	resp.Selection = &types.TextSpan{
+		Start: selection.Start,
+		End:   selection.Start + utf8.RuneCountInString(polished),
+	}
+}
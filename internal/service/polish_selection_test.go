@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"paper_ai/internal/domain/entity"
+	"paper_ai/internal/domain/model"
+	"paper_ai/internal/infrastructure/ai/types"
+)
+
+func TestSelectionSplice(t *testing.T) {
+	content := "第一句保持不变。 this sentence are bad. Last one stays."
+	runes := []rune(content)
+	start := len([]rune("第一句保持不变。"))
+	end := len(runes) - len([]rune("Last one stays."))
+
+	selection := trimSelection(content, &model.TextSelection{Start: start, End: end})
+	if got := string(runes[selection.Start:selection.End]); got != "this sentence are bad." {
+		t.Fatalf("trimSelection() selected %q", got)
+	}
+
+	aiReq := selectionRequest(&model.PolishRequest{Content: content, Style: "academic", Language: "en"}, selection)
+	if aiReq.Content != "this sentence are bad." || aiReq.ContextBefore != "第一句保持不变。 " || aiReq.ContextAfter != " Last one stays." {
+		t.Fatalf("selectionRequest() = %+v", aiReq)
+	}
+
+	resp := &types.PolishResponse{PolishedContent: "\nThis sentence is poor.\n"}
+	spliceSelection(content, selection, resp)
+
+	want := "第一句保持不变。 This sentence is poor. Last one stays."
+	if resp.PolishedContent != want {
+		t.Fatalf("spliceSelection() = %q, want %q", resp.PolishedContent, want)
+	}
+	if resp.PolishedLength != len(want) || resp.OriginalLength != len(content) {
+		t.Errorf("lengths = %d, %d", resp.OriginalLength, resp.PolishedLength)
+	}
+	if got := string([]rune(want)[resp.Selection.Start:resp.Selection.End]); got != "This sentence is poor." {
+		t.Errorf("response selection covers %q", got)
+	}
+}
+
+func TestRecordSelection(t *testing.T) {
+	record := &entity.PolishRecord{
+		OriginalContent: "Keep this. the results is good. Keep that.",
+		PolishedContent: "Keep this. The results are good. Keep that.",
+		Selection:       &entity.TextSelection{Start: 11, End: 31},
+	}
+
+	sel := recordSelection(record)
+	if sel == nil {
+		t.Fatal("recordSelection() = nil")
+	}
+	if sel.PolishedStart != 11 || sel.PolishedEnd != 32 {
+		t.Errorf("polished span = [%d, %d), want [11, 32)", sel.PolishedStart, sel.PolishedEnd)
+	}
+
+	// 上下文被修改后按整段对比
+	record.PolishedContent = "Keep these. The results are good. Keep that."
+	if sel := recordSelection(record); sel != nil {
+		t.Errorf("recordSelection() = %+v, want nil when context changed", sel)
+	}
+}
+
+func TestComparisonBuilder_SelectionLimitsAnnotations(t *testing.T) {
+	original := "Keep this. the results is good. Keep that."
+	polished := "Keep this. The results are good. Keep that."
+	record := &entity.PolishRecord{
+		OriginalContent: original,
+		PolishedContent: polished,
+		Selection:       &entity.TextSelection{Start: 11, End: 31},
+	}
+
+	result := newComparisonBuilder(nil).build(context.Background(), comparisonInput{
+		Original:  original,
+		Polished:  polished,
+		Selection: recordSelection(record),
+	})
+
+	if result.OriginalContent != original || result.PolishedContent != polished {
+		t.Fatal("comparison should keep the full paragraph")
+	}
+	if len(result.Annotations) == 0 {
+		t.Fatal("expected annotations within the selection")
+	}
+	polishedRunes := []rune(polished)
+	for _, ann := range result.Annotations {
+		if ann.PolishedPosition.Start < 11 || ann.PolishedPosition.End > 32 {
+			t.Errorf("annotation %s at [%d, %d) is outside the selection", ann.ID, ann.PolishedPosition.Start, ann.PolishedPosition.End)
+		}
+		if got := string(polishedRunes[ann.PolishedPosition.Start:ann.PolishedPosition.End]); got != ann.PolishedText {
+			t.Errorf("annotation %s position covers %q, want %q", ann.ID, got, ann.PolishedText)
+		}
+	}
+}
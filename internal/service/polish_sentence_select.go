@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"paper_ai/internal/domain/entity"
+	"paper_ai/internal/domain/model"
+	"paper_ai/internal/infrastructure/comparison"
+	apperrors "paper_ai/pkg/errors"
+	"paper_ai/pkg/logger"
+	"paper_ai/pkg/reqctx"
+
+	"go.uber.org/zap"
+)
+
+// GetAlignedSentences 获取多版本按句对齐结果，供用户逐句选择版本
+func (s *PolishMultiVersionService) GetAlignedSentences(ctx context.Context, traceID string, userID int64) (*model.SentenceAlignmentResponse, error) {
+	ctx = reqctx.WithTraceID(ctx, traceID)
+	mainRecord, err := s.loadMultiVersionRecord(ctx, traceID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	versionTypes, sentences, err := s.alignSentences(ctx, mainRecord)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.SentenceAlignmentResponse{
+		TraceID:   traceID,
+		Versions:  versionTypes,
+		Sentences: make([]model.AlignedSentence, len(sentences)),
+	}
+	for i, sentence := range sentences {
+		identical := true
+		for _, text := range sentence.Texts {
+			if text != sentence.Texts[versionTypes[0]] {
+				identical = false
+				break
+			}
+		}
+
+		result.Sentences[i] = model.AlignedSentence{
+			Index:     sentence.Index,
+			Original:  sentence.OriginalText,
+			Versions:  sentence.Texts,
+			Identical: identical,
+		}
+	}
+
+	return result, nil
+}
+
+// SelectSentences 按句组合多个版本并更新主记录
+// 将组合文本、每句来源以及重新生成的对比数据保存到主记录
+func (s *PolishMultiVersionService) SelectSentences(ctx context.Context, traceID string, userID int64, req *model.SelectVersionRequest) ([]entity.SentenceSource, error) {
+	ctx = reqctx.WithTraceID(ctx, traceID)
+	logger.FromContext(ctx).Info("selecting sentences",
+		zap.Int("selections", len(req.Sentences)),
+		zap.String("default", req.Default))
+
+	// 1. 获取主记录
+	mainRecord, err := s.loadMultiVersionRecord(ctx, traceID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 按句对齐所有成功版本
+	versionTypes, sentences, err := s.alignSentences(ctx, mainRecord)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 验证选择
+	isValidSource := func(source string) bool {
+		return source == entity.SentenceSourceOriginal || containsString(versionTypes, source)
+	}
+
+	defaultSource := req.Default
+	if defaultSource == "" {
+		defaultSource = entity.SentenceSourceOriginal
+	}
+	if !isValidSource(defaultSource) {
+		return nil, apperrors.NewInvalidParameterError(fmt.Sprintf("无效的默认版本: %s", defaultSource))
+	}
+	for index, source := range req.Sentences {
+		if index < 0 || index >= len(sentences) {
+			return nil, apperrors.NewInvalidParameterError(fmt.Sprintf("句子序号 %d 超出范围（共 %d 句）", index, len(sentences)))
+		}
+		if !isValidSource(source) {
+			return nil, apperrors.NewInvalidParameterError(fmt.Sprintf("第 %d 句的版本无效: %s", index, source))
+		}
+	}
+
+	// 4. 组合文本并记录每句来源
+	var builder strings.Builder
+	sources := make([]entity.SentenceSource, len(sentences))
+	pos := 0
+	for i, sentence := range sentences {
+		source, ok := req.Sentences[sentence.Index]
+		if !ok {
+			source = defaultSource
+		}
+
+		text := sentence.OriginalText
+		if source != entity.SentenceSourceOriginal {
+			text = sentence.Texts[source]
+		}
+		builder.WriteString(text)
+
+		length := utf8.RuneCountInString(text)
+		sources[i] = entity.SentenceSource{
+			Index:         sentence.Index,
+			Version:       source,
+			OriginalStart: sentence.OriginalStart,
+			OriginalEnd:   sentence.OriginalEnd,
+			Start:         pos,
+			End:           pos + length,
+		}
+		pos += length
+	}
+	composed := builder.String()
+
+	// 5. 重新生成对比数据（所有成功版本用于计算多版本一致性）
+	comparisonResult := s.builder.build(ctx, comparisonInput{
+		TraceID:      traceID,
+		Original:     mainRecord.OriginalContent,
+		Polished:     composed,
+		PeerContents: loadPeerContents(ctx, s.versionRepo, mainRecord.ID),
+	})
+
+	comparisonJSON, err := json.Marshal(comparisonResult)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to marshal comparison data", zap.Error(err))
+		return nil, fmt.Errorf("序列化对比数据失败: %w", err)
+	}
+
+	// 6. 更新主记录
+	mainRecord.PolishedContent = composed
+	mainRecord.PolishedLength = len(composed)
+	// 注意：FinalContent 不在这里赋值，而是在用户接受/拒绝修改时才更新
+	mainRecord.TokenLogProbs = nil // 组合文本来自多个版本，任何一个版本的 logprobs 都不对应该文本
+	mainRecord.SelectedVersion = entity.SelectedVersionComposite
+	mainRecord.SentenceSources = sources
+	mainRecord.ComparisonData = string(comparisonJSON)
+	mainRecord.ChangesCount = comparisonResult.Metadata.TotalChanges
+	mainRecord.AcceptedChanges = []string{}
+	mainRecord.RejectedChanges = []string{}
+	mainRecord.UpdatedAt = time.Now()
+
+	if err := s.polishRepo.Update(ctx, mainRecord); err != nil {
+		logger.FromContext(ctx).Error("failed to update main record",
+			zap.Int64("record_id", mainRecord.ID),
+			zap.Error(err))
+		return nil, fmt.Errorf("更新记录失败: %w", err)
+	}
+
+	s.logReset(ctx, mainRecord)
+
+	logger.FromContext(ctx).Info("sentences selected successfully",
+		zap.Int("sentence_count", len(sources)),
+		zap.Int("changes_count", comparisonResult.Metadata.TotalChanges))
+
+	return sources, nil
+}
+
+// alignSentences 将所有成功版本按句对齐到原文
+func (s *PolishMultiVersionService) alignSentences(ctx context.Context, mainRecord *entity.PolishRecord) ([]string, []comparison.AlignedSentence, error) {
+	versions, err := s.versionRepo.GetByRecordID(ctx, mainRecord.ID)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to get versions",
+			zap.Int64("record_id", mainRecord.ID),
+			zap.Error(err))
+		return nil, nil, fmt.Errorf("获取版本失败: %w", err)
+	}
+
+	successful := make([]*entity.PolishVersion, 0, len(versions))
+	for _, version := range versions {
+		if version.Status == "success" {
+			successful = append(successful, version)
+		}
+	}
+	if len(successful) == 0 {
+		return nil, nil, apperrors.NewInvalidParameterError("没有可选择的成功版本")
+	}
+	sort.Slice(successful, func(i, j int) bool {
+		return versionTypeOrder[successful[i].VersionType] < versionTypeOrder[successful[j].VersionType]
+	})
+
+	versionTypes := make([]string, len(successful))
+	versionChanges := make([]comparison.VersionChanges, len(successful))
+	for i, version := range successful {
+		diffs := s.builder.diffEngine.GenerateWordDiff(mainRecord.OriginalContent, version.PolishedContent)
+		versionTypes[i] = version.VersionType
+		versionChanges[i] = comparison.VersionChanges{
+			VersionType: version.VersionType,
+			Changes:     s.builder.diffEngine.GetChanges(diffs),
+		}
+	}
+
+	sentences := comparison.NewSentenceAligner().Align(mainRecord.OriginalContent, versionChanges)
+	return versionTypes, sentences, nil
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"paper_ai/internal/domain/entity"
+	"paper_ai/internal/domain/model"
+	"paper_ai/internal/domain/repository"
+)
+
+// mockVersionRepository 模拟润色版本仓储
+type mockVersionRepository struct {
+	versions []*entity.PolishVersion
+}
+
+func (m *mockVersionRepository) Create(ctx context.Context, version *entity.PolishVersion) error {
+	m.versions = append(m.versions, version)
+	return nil
+}
+func (m *mockVersionRepository) CreateBatch(ctx context.Context, versions []*entity.PolishVersion) error {
+	m.versions = append(m.versions, versions...)
+	return nil
+}
+func (m *mockVersionRepository) GetByID(ctx context.Context, id int64) (*entity.PolishVersion, error) {
+	return nil, nil
+}
+func (m *mockVersionRepository) GetByRecordID(ctx context.Context, recordID int64) ([]*entity.PolishVersion, error) {
+	var result []*entity.PolishVersion
+	for _, version := range m.versions {
+		if version.RecordID == recordID {
+			result = append(result, version)
+		}
+	}
+	return result, nil
+}
+func (m *mockVersionRepository) GetByRecordIDAndType(ctx context.Context, recordID int64, versionType string) (*entity.PolishVersion, error) {
+	return nil, nil
+}
+func (m *mockVersionRepository) Update(ctx context.Context, version *entity.PolishVersion) error {
+	return nil
+}
+func (m *mockVersionRepository) Delete(ctx context.Context, id int64) error {
+	return nil
+}
+func (m *mockVersionRepository) DeleteByRecordID(ctx context.Context, recordID int64) error {
+	return nil
+}
+func (m *mockVersionRepository) Count(ctx context.Context, filter repository.VersionFilter) (int64, error) {
+	return int64(len(m.versions)), nil
+}
+func (m *mockVersionRepository) GetStatsByVersionType(ctx context.Context) (map[string]*repository.VersionTypeStats, error) {
+	return nil, nil
+}
+func (m *mockVersionRepository) GetStatsByPromptID(ctx context.Context) (map[int64]*repository.PromptVersionStats, error) {
+	return nil, nil
+}
+
+func TestSelectSentences_ClearsTokenLogProbs(t *testing.T) {
+	polishRepo := NewMockPolishRepository()
+	versionRepo := &mockVersionRepository{versions: []*entity.PolishVersion{
+		{RecordID: 1, VersionType: entity.VersionTypeConservative, Status: "success",
+			PolishedContent: "We use a novel method. The results are good."},
+		{RecordID: 1, VersionType: entity.VersionTypeAggressive, Status: "success",
+			PolishedContent: "We adopt a new method. The results are excellent."},
+	}}
+	service := NewPolishMultiVersionService(nil, polishRepo, versionRepo, nil, nil, nil, &MockComparisonActionRepository{}, nil, nil)
+
+	// 之前选择了保守版本，主记录保存了该版本的 logprobs
+	polishRepo.AddMockRecord(&entity.PolishRecord{
+		ID:              1,
+		TraceID:         "1732701604300",
+		UserID:          12345,
+		Mode:            entity.ModeMulti,
+		OriginalContent: "We use a new method. The results are good.",
+		PolishedContent: "We use a novel method. The results are good.",
+		TokenLogProbs:   []entity.TokenLogProb{{Token: "novel", LogProb: -0.1}},
+		SelectedVersion: entity.VersionTypeConservative,
+		Status:          "success",
+		CreatedAt:       time.Now(),
+	})
+
+	ctx := context.Background()
+	_, err := service.SelectSentences(ctx, "1732701604300", 12345, &model.SelectVersionRequest{
+		Sentences: map[int]string{0: entity.VersionTypeConservative, 1: entity.VersionTypeAggressive},
+	})
+	if err != nil {
+		t.Fatalf("SelectSentences() error = %v", err)
+	}
+
+	record, _ := polishRepo.GetByTraceID(ctx, "1732701604300")
+	if record.SelectedVersion != entity.SelectedVersionComposite {
+		t.Errorf("SelectedVersion = %q, want %q", record.SelectedVersion, entity.SelectedVersionComposite)
+	}
+	if len(record.TokenLogProbs) != 0 {
+		t.Errorf("TokenLogProbs = %v, want cleared for composed content", record.TokenLogProbs)
+	}
+}
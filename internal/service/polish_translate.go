@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"paper_ai/internal/config"
+	"paper_ai/internal/domain/entity"
+	"paper_ai/internal/domain/model"
+	"paper_ai/internal/domain/repository"
+	"paper_ai/internal/infrastructure/ai"
+	"paper_ai/internal/infrastructure/ai/types"
+	"paper_ai/internal/infrastructure/comparison"
+	apperrors "paper_ai/pkg/errors"
+	"paper_ai/pkg/logger"
+	"paper_ai/pkg/reqctx"
+
+	"go.uber.org/zap"
+)
+
+// TranslateService 翻译润色服务
+// 把源语言草稿翻译为目标语言的学术文本，原文中出现的术语表术语要求提供商使用固定译法；
+// 翻译记录的 mode 为 translate，用双语对照（按句对齐）代替逐字对比
+type TranslateService struct {
+	providerFactory *ai.ProviderFactory
+	polishRepo      repository.PolishRepository
+	glossaryService *GlossaryService
+	privacyService  *PrivacyService  // 隐私设置（为 nil 时总是保存内容）
+	organizeService *OrganizeService // 项目（为 nil 时不支持指定项目）
+}
+
+// NewTranslateService 创建翻译润色服务
+func NewTranslateService(
+	factory *ai.ProviderFactory,
+	polishRepo repository.PolishRepository,
+	glossaryService *GlossaryService,
+	privacyService *PrivacyService,
+	organizeService *OrganizeService,
+) *TranslateService {
+	return &TranslateService{
+		providerFactory: factory,
+		polishRepo:      polishRepo,
+		glossaryService: glossaryService,
+		privacyService:  privacyService,
+		organizeService: organizeService,
+	}
+}
+
+// Translate 执行翻译润色
+func (s *TranslateService) Translate(ctx context.Context, req *model.TranslateRequest, userID int64) (*model.TranslateResponse, error) {
+	startTime := time.Now()
+
+	traceID := generateTraceID(ctx)
+	ctx = reqctx.WithTraceID(ctx, traceID)
+
+	// 检查指定的项目（不属于用户的项目不写入失败记录）
+	if err := s.organizeService.CheckProject(ctx, userID, req.ProjectID); err != nil {
+		req.ProjectID = nil
+		s.saveRecord(ctx, s.failedRecord(traceID, req, userID, err))
+		return nil, err
+	}
+
+	// 参数验证
+	if err := req.Validate(); err != nil {
+		logger.FromContext(ctx).Warn("invalid translate request", zap.Error(err))
+		s.saveRecord(ctx, s.failedRecord(traceID, req, userID, err))
+		return nil, apperrors.NewInvalidParameterError(err.Error())
+	}
+	if err := req.SetDefaults(); err != nil {
+		s.saveRecord(ctx, s.failedRecord(traceID, req, userID, err))
+		return nil, apperrors.NewInvalidParameterError(err.Error())
+	}
+
+	// 获取AI提供商
+	if req.Provider == "" {
+		req.Provider = config.Get().AI.DefaultProvider
+	}
+	provider, err := s.providerFactory.GetProvider(req.Provider)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to get provider", zap.String("provider", req.Provider), zap.Error(err))
+		s.saveRecord(ctx, s.failedRecord(traceID, req, userID, err))
+		return nil, err
+	}
+
+	// 原文中出现的术语（术语表读取失败时不使用术语表，不影响翻译）
+	var terms []*entity.GlossaryTerm
+	if req.UseGlossary == nil || *req.UseGlossary {
+		terms, err = s.glossaryService.MatchTerms(ctx, userID, req.SourceLanguage, req.TargetLanguage, req.Content)
+		if err != nil {
+			logger.FromContext(ctx).Warn("failed to load glossary, translating without it", zap.Error(err))
+			terms = nil
+		}
+	}
+	glossary := make([]types.GlossaryEntry, len(terms))
+	for i, term := range terms {
+		glossary[i] = types.GlossaryEntry{Source: term.SourceTerm, Target: term.TargetTerm}
+	}
+
+	logger.FromContext(ctx).Info("calling ai provider for translation",
+		zap.String("provider", req.Provider),
+		zap.String("source_language", req.SourceLanguage),
+		zap.String("target_language", req.TargetLanguage),
+		zap.Int("content_length", len(req.Content)),
+		zap.Int("glossary_terms", len(glossary)))
+
+	callStart := time.Now()
+	resp, err := provider.Translate(ctx, &types.TranslateRequest{
+		Content:        req.Content,
+		SourceLanguage: req.SourceLanguage,
+		TargetLanguage: req.TargetLanguage,
+		Style:          req.Style,
+		Glossary:       glossary,
+	})
+	observeProviderCall(req.Provider, "translate", callStart, resp, err)
+	if err != nil {
+		logger.FromContext(ctx).Error("ai provider translation failed", zap.String("provider", req.Provider), zap.Error(err))
+		s.saveRecord(ctx, s.failedRecord(traceID, req, userID, err))
+		return nil, err
+	}
+
+	processTime := time.Since(startTime).Milliseconds()
+
+	record := &entity.PolishRecord{
+		TraceID:         traceID,
+		UserID:          userID,
+		OriginalContent: req.Content,
+		Style:           req.Style,
+		Language:        req.TargetLanguage,
+		SourceLanguage:  req.SourceLanguage,
+		PolishedContent: resp.PolishedContent,
+		OriginalLength:  resp.OriginalLength,
+		PolishedLength:  resp.PolishedLength,
+		Provider:        resp.ProviderUsed,
+		Model:           resp.ModelUsed,
+		Mode:            entity.ModeTranslate,
+		ProcessTimeMs:   int(processTime),
+		Status:          "success",
+		ProjectID:       req.ProjectID,
+	}
+	s.saveRecord(ctx, record)
+
+	logger.FromContext(ctx).Info("translation completed successfully",
+		zap.String("provider", req.Provider),
+		zap.Int("original_length", resp.OriginalLength),
+		zap.Int("translated_length", resp.PolishedLength),
+		zap.Int64("process_time_ms", processTime))
+
+	return &model.TranslateResponse{
+		TraceID:           traceID,
+		TranslatedContent: resp.PolishedContent,
+		SourceLanguage:    req.SourceLanguage,
+		TargetLanguage:    req.TargetLanguage,
+		OriginalLength:    resp.OriginalLength,
+		TranslatedLength:  resp.PolishedLength,
+		GlossaryTerms:     toGlossaryTermInfos(terms),
+		ProviderUsed:      resp.ProviderUsed,
+		ModelUsed:         resp.ModelUsed,
+	}, nil
+}
+
+// GetBilingual 获取翻译记录的双语对照：原文和译文按句对齐，并检查术语表译法是否一致（使用当前术语表）
+func (s *TranslateService) GetBilingual(ctx context.Context, traceID string, userID int64) (*model.BilingualComparison, error) {
+	record, err := s.polishRepo.GetByTraceID(ctx, traceID)
+	if err != nil {
+		return nil, apperrors.NewNotFoundError("润色记录不存在")
+	}
+	if record.UserID != userID {
+		return nil, apperrors.NewForbiddenError("无权访问该记录")
+	}
+	if !record.IsTranslateMode() {
+		return nil, apperrors.NewInvalidParameterError("只有翻译记录支持双语对照，润色记录请使用对比接口")
+	}
+	if err := checkContentStored(record); err != nil {
+		return nil, err
+	}
+	if record.PolishedContent == "" {
+		return nil, apperrors.NewInvalidParameterError("该记录没有译文")
+	}
+
+	// 只检查该记录语言对下的术语
+	terms, err := s.glossaryService.MatchTerms(ctx, userID, record.SourceLanguage, record.Language, record.OriginalContent)
+	if err != nil {
+		return nil, err
+	}
+
+	pairs, inconsistent := buildBilingualPairs(record.OriginalContent, record.PolishedContent, terms)
+	return &model.BilingualComparison{
+		TraceID:           record.TraceID,
+		SourceLanguage:    record.SourceLanguage,
+		TargetLanguage:    record.Language,
+		SourceContent:     record.OriginalContent,
+		TranslatedContent: record.PolishedContent,
+		Pairs:             pairs,
+		InconsistentTerms: inconsistent,
+	}, nil
+}
+
+// buildBilingualPairs 按句对齐原文和译文，检查每组句子中出现的术语是否使用了术语表译法
+// 返回对齐结果和译文没有使用术语表译法的次数
+func buildBilingualPairs(source, target string, terms []*entity.GlossaryTerm) ([]*model.BilingualPair, int) {
+	sourceRunes, targetRunes := []rune(source), []rune(target)
+	aligned := comparison.AlignBilingual(source, target)
+
+	inconsistent := 0
+	pairs := make([]*model.BilingualPair, len(aligned))
+	for i, a := range aligned {
+		pair := &model.BilingualPair{
+			Index:       i,
+			SourceText:  string(sourceRunes[a.SourceStart:a.SourceEnd]),
+			TargetText:  string(targetRunes[a.TargetStart:a.TargetEnd]),
+			SourceStart: a.SourceStart,
+			SourceEnd:   a.SourceEnd,
+			TargetStart: a.TargetStart,
+			TargetEnd:   a.TargetEnd,
+		}
+		for _, term := range terms {
+			if !containsTerm(pair.SourceText, term.SourceTerm) {
+				continue
+			}
+			check := &model.TermCheck{
+				SourceTerm: term.SourceTerm,
+				TargetTerm: term.TargetTerm,
+				Consistent: containsTerm(pair.TargetText, term.TargetTerm),
+			}
+			if !check.Consistent {
+				inconsistent++
+			}
+			pair.Terms = append(pair.Terms, check)
+		}
+		pairs[i] = pair
+	}
+	return pairs, inconsistent
+}
+
+// failedRecord 构建失败的翻译记录
+func (s *TranslateService) failedRecord(traceID string, req *model.TranslateRequest, userID int64, err error) *entity.PolishRecord {
+	return &entity.PolishRecord{
+		TraceID:         traceID,
+		UserID:          userID,
+		OriginalContent: req.Content,
+		Style:           req.Style,
+		Language:        req.TargetLanguage,
+		SourceLanguage:  req.SourceLanguage,
+		Mode:            entity.ModeTranslate,
+		Status:          "failed",
+		ErrorMessage:    err.Error(),
+		ProjectID:       req.ProjectID,
+	}
+}
+
+// saveRecord 保存翻译记录（不保存内容模式下只保存元数据）
+func (s *TranslateService) saveRecord(ctx context.Context, record *entity.PolishRecord) {
+	if s.polishRepo == nil {
+		return
+	}
+	if s.privacyService.ShouldOmitContent(ctx, record.UserID) {
+		record.OmitContent()
+	}
+
+	if err := s.polishRepo.Create(ctx, record); err != nil {
+		logger.FromContext(ctx).Error("failed to save translation record", zap.Error(err))
+	}
+}
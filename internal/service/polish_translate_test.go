@@ -0,0 +1,111 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"paper_ai/internal/domain/entity"
+	"paper_ai/internal/domain/model"
+)
+
+func TestMatchGlossary(t *testing.T) {
+	terms := []*entity.GlossaryTerm{
+		{SourceTerm: "注意力", TargetTerm: "attention"},
+		{SourceTerm: "自注意力机制", TargetTerm: "self-attention mechanism"},
+		{SourceTerm: "卷积", TargetTerm: "convolution"},
+		{SourceTerm: "Transformer", TargetTerm: "Transformer"},
+	}
+
+	matched := matchGlossary(terms, "本文基于transformer的自注意力机制。")
+	got := make([]string, len(matched))
+	for i, term := range matched {
+		got[i] = term.SourceTerm
+	}
+	want := []string{"Transformer", "自注意力机制", "注意力"}
+	if len(got) != len(want) {
+		t.Fatalf("matchGlossary() = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("matchGlossary()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestNormalizeGlossaryTerm_LanguagePair(t *testing.T) {
+	term, err := normalizeGlossaryTerm(&model.GlossaryTermRequest{
+		SourceLanguage: "zh", TargetLanguage: "en", SourceTerm: " 注意力 ", TargetTerm: "attention",
+	})
+	if err != nil {
+		t.Fatalf("normalizeGlossaryTerm() error = %v", err)
+	}
+	if term.SourceLanguage != "zh" || term.TargetLanguage != "en" || term.SourceTerm != "注意力" {
+		t.Errorf("normalizeGlossaryTerm() = %+v", term)
+	}
+
+	invalid := []*model.GlossaryTermRequest{
+		{SourceLanguage: "zh", TargetLanguage: "zh", SourceTerm: "注意力", TargetTerm: "attention"},
+		{SourceLanguage: "xx", TargetLanguage: "en", SourceTerm: "注意力", TargetTerm: "attention"},
+		{SourceLanguage: "zh", TargetLanguage: "", SourceTerm: "注意力", TargetTerm: "attention"},
+	}
+	for _, req := range invalid {
+		if _, err := normalizeGlossaryTerm(req); err == nil {
+			t.Errorf("normalizeGlossaryTerm(%s → %s) should fail", req.SourceLanguage, req.TargetLanguage)
+		}
+	}
+}
+
+func TestBuildBilingualPairs_TermChecks(t *testing.T) {
+	source := "我们使用注意力机制。卷积层提取局部特征。"
+	target := "We use the attention mechanism. Convolutional layers extract local features."
+	terms := []*entity.GlossaryTerm{
+		{SourceTerm: "注意力机制", TargetTerm: "attention mechanism"},
+		{SourceTerm: "卷积层", TargetTerm: "convolution layer"},
+	}
+
+	pairs, inconsistent := buildBilingualPairs(source, target, terms)
+	if len(pairs) != 2 {
+		t.Fatalf("buildBilingualPairs() returned %d pairs, want 2", len(pairs))
+	}
+	if inconsistent != 1 {
+		t.Errorf("inconsistent = %d, want 1", inconsistent)
+	}
+	if len(pairs[0].Terms) != 1 || !pairs[0].Terms[0].Consistent {
+		t.Errorf("pairs[0].Terms = %+v, want one consistent term", pairs[0].Terms)
+	}
+	if len(pairs[1].Terms) != 1 || pairs[1].Terms[0].Consistent {
+		t.Errorf("pairs[1].Terms = %+v, want one inconsistent term", pairs[1].Terms)
+	}
+	if pairs[1].SourceText != string([]rune(source)[pairs[1].SourceStart:pairs[1].SourceEnd]) {
+		t.Errorf("pairs[1].SourceText %q does not match its offsets", pairs[1].SourceText)
+	}
+}
+
+func TestTranslateRequestSetDefaults(t *testing.T) {
+	req := &model.TranslateRequest{Content: "草稿"}
+	if err := req.SetDefaults(); err != nil {
+		t.Fatalf("SetDefaults() error = %v", err)
+	}
+	if req.SourceLanguage != "zh" || req.TargetLanguage != "en" || req.Style != "academic" {
+		t.Errorf("SetDefaults() = %+v, want zh → en academic", req)
+	}
+
+	// 源语言与默认语言相同时取第一个不同的开放语言
+	english := &model.TranslateRequest{Content: "We propose a novel method."}
+	if err := english.SetDefaults(); err != nil {
+		t.Fatalf("SetDefaults() error = %v", err)
+	}
+	if english.SourceLanguage != "en" || english.TargetLanguage != "zh" {
+		t.Errorf("SetDefaults() = %s → %s, want en → zh", english.SourceLanguage, english.TargetLanguage)
+	}
+
+	french := &model.TranslateRequest{Content: "Nous proposons une nouvelle méthode pour la détection des anomalies."}
+	if err := french.SetDefaults(); err == nil || !strings.Contains(err.Error(), "unsupported source language") {
+		t.Errorf("SetDefaults() with an unsupported detected language error = %v", err)
+	}
+
+	same := &model.TranslateRequest{Content: "draft", SourceLanguage: "en", TargetLanguage: "en"}
+	if err := same.SetDefaults(); err == nil {
+		t.Error("SetDefaults() with the same source and target language should fail")
+	}
+}
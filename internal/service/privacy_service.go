@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"paper_ai/internal/domain/entity"
+	"paper_ai/internal/domain/model"
+	"paper_ai/internal/domain/repository"
+	apperrors "paper_ai/pkg/errors"
+	"paper_ai/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// PrivacyService 用户隐私服务（隐私设置、删除润色历史）
+type PrivacyService struct {
+	userRepo   repository.UserRepository
+	polishRepo repository.PolishRepository
+}
+
+// NewPrivacyService 创建隐私服务实例
+func NewPrivacyService(userRepo repository.UserRepository, polishRepo repository.PolishRepository) *PrivacyService {
+	return &PrivacyService{
+		userRepo:   userRepo,
+		polishRepo: polishRepo,
+	}
+}
+
+// GetSettings 获取用户隐私设置
+func (s *PrivacyService) GetSettings(ctx context.Context, userID int64) (*model.PrivacySettings, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return toPrivacySettings(user), nil
+}
+
+// UpdateSettings 更新用户隐私设置（只修改请求中提供的字段）
+func (s *PrivacyService) UpdateSettings(ctx context.Context, userID int64, req *model.UpdatePrivacySettingsRequest) (*model.PrivacySettings, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.RetentionDays != nil {
+		if *req.RetentionDays < 0 || *req.RetentionDays > model.MaxRetentionDays {
+			return nil, apperrors.NewInvalidParameterError("retention_days 必须在 0 到 3650 之间")
+		}
+		user.RetentionDays = *req.RetentionDays
+	}
+	if req.NoStoreContent != nil {
+		user.NoStoreContent = *req.NoStoreContent
+	}
+
+	user.UpdatedAt = time.Now()
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		logger.FromContext(ctx).Error("failed to update privacy settings", zap.Error(err))
+		return nil, apperrors.NewInternalError("更新隐私设置失败", err)
+	}
+
+	logger.FromContext(ctx).Info("privacy settings updated",
+		zap.Int("retention_days", user.RetentionDays),
+		zap.Bool("no_store_content", user.NoStoreContent))
+	return toPrivacySettings(user), nil
+}
+
+// ShouldOmitContent 判断用户是否开启了不保存内容模式
+// 查询失败时按开启处理（宁可丢失历史内容，也不违背用户的隐私设置）；未配置隐私服务时总是保存内容
+func (s *PrivacyService) ShouldOmitContent(ctx context.Context, userID int64) bool {
+	if s == nil {
+		return false
+	}
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil || user == nil {
+		logger.FromContext(ctx).Warn("failed to load privacy settings, content will not be stored", zap.Error(err))
+		return true
+	}
+	return user.NoStoreContent
+}
+
+// DeleteRecord 删除用户的一条润色记录（物理删除，版本记录和对比操作日志级联删除）
+func (s *PrivacyService) DeleteRecord(ctx context.Context, userID int64, traceID string) error {
+	record, err := s.polishRepo.GetByTraceID(ctx, traceID)
+	if err != nil {
+		return apperrors.NewNotFoundError("润色记录不存在")
+	}
+	if record.UserID != userID {
+		return apperrors.NewForbiddenError("无权删除该记录")
+	}
+
+	if err := s.polishRepo.PurgeByID(ctx, record.ID); err != nil {
+		return apperrors.NewInternalError("删除润色记录失败", err)
+	}
+
+	logger.FromContext(ctx).Info("polish record deleted by user", zap.String("trace_id", traceID))
+	return nil
+}
+
+// DeleteRecords 删除用户在 [start, end) 内创建的润色记录，start/end 为空表示不限，返回删除数量
+func (s *PrivacyService) DeleteRecords(ctx context.Context, userID int64, start, end *time.Time) (int64, error) {
+	if start != nil && end != nil && !start.Before(*end) {
+		return 0, apperrors.NewInvalidParameterError("start_time 必须早于 end_time")
+	}
+
+	deleted, err := s.polishRepo.PurgeByUser(ctx, userID, start, end)
+	if err != nil {
+		return deleted, apperrors.NewInternalError("删除润色记录失败", err)
+	}
+
+	logger.FromContext(ctx).Info("polish records deleted by user",
+		zap.Int64("deleted", deleted),
+		zap.Timep("start_time", start),
+		zap.Timep("end_time", end))
+	return deleted, nil
+}
+
+// checkContentStored 检查记录是否保存了内容（不保存内容模式下的记录无法对比、编辑或选择版本）
+func checkContentStored(record *entity.PolishRecord) error {
+	if record.ContentOmitted {
+		return apperrors.NewInvalidParameterError("该记录未保存内容（不保存内容模式），无法执行此操作")
+	}
+	return nil
+}
+
+// getUser 获取用户，不存在时返回 NotFound
+func (s *PrivacyService) getUser(ctx context.Context, userID int64) (*entity.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("获取用户信息失败", err)
+	}
+	if user == nil {
+		return nil, apperrors.NewNotFoundError("用户不存在")
+	}
+	return user, nil
+}
+
+// toPrivacySettings 用户实体转换为隐私设置
+func toPrivacySettings(user *entity.User) *model.PrivacySettings {
+	return &model.PrivacySettings{
+		RetentionDays:  user.RetentionDays,
+		NoStoreContent: user.NoStoreContent,
+	}
+}
@@ -4,12 +4,17 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"paper_ai/internal/domain/entity"
 	"paper_ai/internal/domain/repository"
+	"paper_ai/internal/infrastructure/metrics"
+	"paper_ai/internal/infrastructure/tracing"
+	lang "paper_ai/pkg/language"
 	"paper_ai/pkg/logger"
 
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 )
 
@@ -28,10 +33,18 @@ func NewPromptService(promptRepo repository.PolishPromptRepository) *PromptServi
 }
 
 // GetPrompt 获取Prompt（带缓存）
-func (s *PromptService) GetPrompt(ctx context.Context, versionType, language, style string) (*entity.PolishPrompt, error) {
+func (s *PromptService) GetPrompt(ctx context.Context, versionType, language, style string) (prompt *entity.PolishPrompt, err error) {
+	ctx, span := tracing.Start(ctx, "PromptService.GetPrompt",
+		attribute.String("version_type", versionType),
+		attribute.String("language", language),
+		attribute.String("style", style))
+	defer func() { tracing.End(span, err) }()
+
 	// 先从缓存获取
 	cacheKey := buildPromptCacheKey(versionType, language, style)
-	if cached := s.cache.get(cacheKey); cached != nil {
+	cached := s.cache.get(cacheKey)
+	span.SetAttributes(attribute.Bool("prompt.cache_hit", cached != nil))
+	if cached != nil {
 		logger.Debug("prompt cache hit",
 			zap.String("version_type", versionType),
 			zap.String("language", language),
@@ -40,7 +53,7 @@ func (s *PromptService) GetPrompt(ctx context.Context, versionType, language, st
 	}
 
 	// 缓存未命中，从数据库查询
-	prompt, err := s.promptRepo.GetActive(ctx, versionType, language, style)
+	prompt, err = s.promptRepo.GetActive(ctx, versionType, language, style)
 	if err != nil {
 		logger.Error("failed to get prompt from database",
 			zap.String("version_type", versionType),
@@ -71,9 +84,10 @@ func (s *PromptService) RenderPrompt(ctx context.Context, versionType, language,
 
 	// 准备变量
 	variables := map[string]string{
-		"content":  content,
-		"language": language,
-		"style":    style,
+		"content":       content,
+		"language":      language,
+		"language_name": lang.Name(language), // 英文名称，供通用语言（all）的 Prompt 使用
+		"style":         style,
 	}
 
 	// 渲染用户提示词
@@ -107,6 +121,11 @@ func (s *PromptService) InvalidateAllCache() {
 	logger.Info("all prompt cache invalidated")
 }
 
+// CacheStats 获取缓存统计（用于监控指标）
+func (s *PromptService) CacheStats() metrics.CacheStats {
+	return s.cache.stats()
+}
+
 // RenderedPrompt 渲染后的Prompt
 type RenderedPrompt struct {
 	PromptID     int64
@@ -125,6 +144,8 @@ type promptCache struct {
 	data    map[string]*cacheEntry
 	maxSize int
 	ttl     time.Duration
+	hits    atomic.Uint64 // 命中次数
+	misses  atomic.Uint64 // 未命中次数（含已过期）
 }
 
 // cacheEntry 缓存条目
@@ -154,17 +175,33 @@ func (c *promptCache) get(key string) *entity.PolishPrompt {
 
 	entry, exists := c.data[key]
 	if !exists {
+		c.misses.Add(1)
 		return nil
 	}
 
 	// 检查是否过期
 	if time.Now().After(entry.expiresAt) {
+		c.misses.Add(1)
 		return nil
 	}
 
+	c.hits.Add(1)
 	return entry.prompt
 }
 
+// stats 获取缓存统计
+func (c *promptCache) stats() metrics.CacheStats {
+	c.mu.RLock()
+	entries := len(c.data)
+	c.mu.RUnlock()
+
+	return metrics.CacheStats{
+		Hits:    c.hits.Load(),
+		Misses:  c.misses.Load(),
+		Entries: entries,
+	}
+}
+
 // set 设置缓存
 func (c *promptCache) set(key string, prompt *entity.PolishPrompt) {
 	c.mu.Lock()
@@ -0,0 +1,9 @@
+-- 删除修改反馈表
+DROP TABLE IF EXISTS change_feedbacks;
+
+-- 删除 token 对数概率字段
+ALTER TABLE polish_versions
+DROP COLUMN IF EXISTS token_logprobs;
+
+ALTER TABLE polish_records
+DROP COLUMN IF EXISTS token_logprobs;
@@ -0,0 +1,38 @@
+-- ============================================
+-- 修改置信度信号
+-- 版本: 003
+-- 说明: 保存模型 token 概率，记录用户对修改的反馈用于统计历史接受率
+-- ============================================
+
+-- ============================================
+-- 1. 保存模型返回的 token 对数概率
+-- ============================================
+ALTER TABLE polish_records
+ADD COLUMN IF NOT EXISTS token_logprobs JSONB;
+
+ALTER TABLE polish_versions
+ADD COLUMN IF NOT EXISTS token_logprobs JSONB;
+
+COMMENT ON COLUMN polish_records.token_logprobs IS '模型返回的 token 对数概率JSON数组(提供商支持时才有)';
+COMMENT ON COLUMN polish_versions.token_logprobs IS '模型返回的 token 对数概率JSON数组(提供商支持时才有)';
+
+-- ============================================
+-- 2. 创建修改反馈表 change_feedbacks
+-- ============================================
+CREATE TABLE IF NOT EXISTS change_feedbacks (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    trace_id VARCHAR(20) NOT NULL,
+    change_type VARCHAR(20) NOT NULL,
+    signature VARCHAR(64) NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_id_feedback ON change_feedbacks(user_id);
+CREATE INDEX IF NOT EXISTS idx_change_type_feedback ON change_feedbacks(change_type);
+CREATE INDEX IF NOT EXISTS idx_signature_feedback ON change_feedbacks(signature);
+
+COMMENT ON TABLE change_feedbacks IS '修改反馈表 - 记录用户接受/拒绝修改的行为，用于计算置信度';
+COMMENT ON COLUMN change_feedbacks.signature IS '修改签名: 类型 + 规范化原文/修改文本的SHA1，不保存原文';
+COMMENT ON COLUMN change_feedbacks.action IS '反馈动作: accepted / rejected';
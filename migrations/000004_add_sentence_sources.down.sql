@@ -0,0 +1,3 @@
+-- 删除句子来源字段
+ALTER TABLE polish_records
+DROP COLUMN IF EXISTS sentence_sources;
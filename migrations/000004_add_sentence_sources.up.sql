@@ -0,0 +1,10 @@
+-- ============================================
+-- 按句组合多版本
+-- 版本: 004
+-- 说明: 保存组合文本中每个句子的来源版本
+-- ============================================
+
+ALTER TABLE polish_records
+ADD COLUMN IF NOT EXISTS sentence_sources JSONB;
+
+COMMENT ON COLUMN polish_records.sentence_sources IS '按句组合多版本时每句的来源JSON数组(selected_version = composite 时有效)';
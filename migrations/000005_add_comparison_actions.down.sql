@@ -0,0 +1,2 @@
+-- 删除对比操作日志表
+DROP TABLE IF EXISTS comparison_actions;
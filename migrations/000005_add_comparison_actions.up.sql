@@ -0,0 +1,28 @@
+-- ============================================
+-- 对比操作日志
+-- 版本: 005
+-- 说明: 只追加的操作日志，支持撤销/重做和查看任意时刻的最终文本
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS comparison_actions (
+    id BIGSERIAL PRIMARY KEY,
+    record_id BIGINT NOT NULL,
+    trace_id VARCHAR(20) NOT NULL,
+    user_id BIGINT NOT NULL,
+    seq INTEGER NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    target_seq INTEGER DEFAULT 0,
+    changes JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_comparison_actions_record FOREIGN KEY (record_id)
+        REFERENCES polish_records(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_record_seq_action ON comparison_actions(record_id, seq);
+
+COMMENT ON TABLE comparison_actions IS '对比操作日志 - 只追加，记录每次修改状态变化';
+COMMENT ON COLUMN comparison_actions.seq IS '记录内的操作序号，从1开始递增';
+COMMENT ON COLUMN comparison_actions.action IS '操作类型: accept / reject / accept_all / auto_accept / undo / redo';
+COMMENT ON COLUMN comparison_actions.target_seq IS '撤销/重做操作对应的原操作序号';
+COMMENT ON COLUMN comparison_actions.changes IS '状态变化JSON数组: [{change_id, before, after}]';
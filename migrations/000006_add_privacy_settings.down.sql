@@ -0,0 +1,5 @@
+-- 删除用户隐私设置
+DROP INDEX IF EXISTS idx_user_created_at_record;
+ALTER TABLE polish_records DROP COLUMN IF EXISTS content_omitted;
+ALTER TABLE users DROP COLUMN IF EXISTS no_store_content;
+ALTER TABLE users DROP COLUMN IF EXISTS retention_days;
@@ -0,0 +1,18 @@
+-- ============================================
+-- 用户隐私设置
+-- 版本: 006
+-- 说明: 用户级历史记录保留天数、不保存内容模式；润色记录标记内容是否已省略
+-- ============================================
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS retention_days INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS no_store_content BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN users.retention_days IS '润色历史保留天数，超过后由定时任务删除（0=不按用户设置删除）';
+COMMENT ON COLUMN users.no_store_content IS '不保存内容模式: 润色记录只保存元数据，不保存原文和润色结果';
+
+ALTER TABLE polish_records ADD COLUMN IF NOT EXISTS content_omitted BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN polish_records.content_omitted IS '是否未保存内容（不保存内容模式下创建的记录）';
+
+-- 按用户和时间范围删除历史记录
+CREATE INDEX IF NOT EXISTS idx_user_created_at_record ON polish_records(user_id, created_at);
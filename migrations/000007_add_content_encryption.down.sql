@@ -0,0 +1,6 @@
+-- 删除润色内容加密
+-- 注意: 回滚前需先解密所有已加密的记录，否则内容无法读取
+ALTER TABLE polish_versions DROP COLUMN IF EXISTS encryption_key_id;
+ALTER TABLE polish_records DROP COLUMN IF EXISTS encryption_key_id;
+
+DROP TABLE IF EXISTS encryption_data_keys;
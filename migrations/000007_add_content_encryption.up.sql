@@ -0,0 +1,33 @@
+-- ============================================
+-- 润色内容加密（信封加密）
+-- 版本: 007
+-- 说明: 每个用户的数据密钥由主密钥包装后保存；润色记录和版本记录保存加密所用的数据密钥 ID
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS encryption_data_keys (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    master_key_id VARCHAR(64) NOT NULL,
+    wrapped_key BYTEA NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_encryption_data_keys_user FOREIGN KEY (user_id)
+        REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- 每个用户最多一个有效数据密钥
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_active_data_key ON encryption_data_keys(user_id) WHERE active;
+CREATE INDEX IF NOT EXISTS idx_master_key_id_data_key ON encryption_data_keys(master_key_id);
+
+COMMENT ON TABLE encryption_data_keys IS '内容加密数据密钥 - 每个用户一个有效密钥，轮换后旧密钥停用但保留用于解密';
+COMMENT ON COLUMN encryption_data_keys.master_key_id IS '包装该数据密钥的主密钥 ID（对应配置 encryption.master_keys）';
+COMMENT ON COLUMN encryption_data_keys.wrapped_key IS '主密钥 AES-GCM 加密后的数据密钥: nonce || ciphertext || tag';
+COMMENT ON COLUMN encryption_data_keys.active IS '是否用于加密新内容';
+
+ALTER TABLE polish_records ADD COLUMN IF NOT EXISTS encryption_key_id BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE polish_versions ADD COLUMN IF NOT EXISTS encryption_key_id BIGINT NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN polish_records.encryption_key_id IS '内容字段加密使用的数据密钥 ID（0=明文）';
+COMMENT ON COLUMN polish_versions.encryption_key_id IS '内容字段加密使用的数据密钥 ID（0=明文）';
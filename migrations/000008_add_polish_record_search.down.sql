@@ -0,0 +1,4 @@
+-- 删除润色历史全文检索索引（pg_trgm 扩展可能被其他对象使用，保留）
+DROP INDEX IF EXISTS idx_polish_records_search_final_trgm;
+DROP INDEX IF EXISTS idx_polish_records_search_original_trgm;
+DROP INDEX IF EXISTS idx_polish_records_search_fts;
@@ -0,0 +1,22 @@
+-- ============================================
+-- 润色历史全文检索
+-- 版本: 008
+-- 说明: 原文和最终文本（未应用修改时为润色结果）的全文索引和三元组索引
+--       全文索引使用 simple 分词配置（不依赖 zhparser），中文检索使用 pg_trgm 三元组匹配
+--       已加密的记录（encryption_key_id <> 0）内容为密文，不建索引，也不参与检索
+-- ============================================
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- 全文索引（表达式必须与查询中的 to_tsvector 表达式完全一致）
+CREATE INDEX IF NOT EXISTS idx_polish_records_search_fts ON polish_records
+    USING GIN (to_tsvector('simple', original_content || ' ' || COALESCE(NULLIF(final_content, ''), polished_content)))
+    WHERE encryption_key_id = 0;
+
+-- 三元组索引（中文等无空格分词语言的 ILIKE 子串匹配）
+CREATE INDEX IF NOT EXISTS idx_polish_records_search_original_trgm ON polish_records
+    USING GIN (original_content gin_trgm_ops)
+    WHERE encryption_key_id = 0;
+CREATE INDEX IF NOT EXISTS idx_polish_records_search_final_trgm ON polish_records
+    USING GIN ((COALESCE(NULLIF(final_content, ''), polished_content)) gin_trgm_ops)
+    WHERE encryption_key_id = 0;
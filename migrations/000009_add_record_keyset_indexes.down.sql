@@ -0,0 +1,5 @@
+-- 删除润色记录列表游标分页索引
+DROP INDEX IF EXISTS idx_user_changes_count_id_record;
+DROP INDEX IF EXISTS idx_user_original_length_id_record;
+DROP INDEX IF EXISTS idx_user_process_time_id_record;
+DROP INDEX IF EXISTS idx_user_created_at_id_record;
@@ -0,0 +1,11 @@
+-- ============================================
+-- 润色记录列表游标分页
+-- 版本: 009
+-- 说明: 按 (user_id, 排序字段, id) 建立索引，支持游标分页（keyset）和按字段排序
+--       可为空的列按 0 排序，索引表达式与查询中的排序表达式一致
+-- ============================================
+
+CREATE INDEX IF NOT EXISTS idx_user_created_at_id_record ON polish_records(user_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_user_process_time_id_record ON polish_records(user_id, (COALESCE(process_time_ms, 0)), id);
+CREATE INDEX IF NOT EXISTS idx_user_original_length_id_record ON polish_records(user_id, original_length, id);
+CREATE INDEX IF NOT EXISTS idx_user_changes_count_id_record ON polish_records(user_id, (COALESCE(changes_count, 0)), id);
@@ -0,0 +1,8 @@
+-- 删除润色记录整理功能（项目、标签、收藏）
+DROP INDEX IF EXISTS idx_user_favorite_record;
+DROP INDEX IF EXISTS idx_project_id_record;
+ALTER TABLE polish_records DROP COLUMN IF EXISTS is_favorite;
+ALTER TABLE polish_records DROP COLUMN IF EXISTS project_id;
+DROP TABLE IF EXISTS polish_record_tags;
+DROP TABLE IF EXISTS tags;
+DROP TABLE IF EXISTS projects;
@@ -0,0 +1,54 @@
+-- ============================================
+-- 润色记录整理：项目（文件夹）、标签、收藏
+-- 版本: 010
+-- 说明: 润色记录可归属一个项目、打多个标签、标记收藏；项目和标签按用户隔离，名称在用户内唯一
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS projects (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_name_project ON projects(user_id, name);
+
+COMMENT ON TABLE projects IS '项目（润色记录文件夹）';
+COMMENT ON COLUMN projects.name IS '项目名称（用户内唯一）';
+
+CREATE TABLE IF NOT EXISTS tags (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_name_tag ON tags(user_id, name);
+
+COMMENT ON TABLE tags IS '用户自定义标签';
+COMMENT ON COLUMN tags.name IS '标签名称（用户内唯一）';
+
+-- 记录与标签多对多关联（记录或标签删除时级联删除关联）
+CREATE TABLE IF NOT EXISTS polish_record_tags (
+    record_id BIGINT NOT NULL REFERENCES polish_records(id) ON DELETE CASCADE,
+    tag_id BIGINT NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (record_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_tag_id_record_tag ON polish_record_tags(tag_id);
+
+COMMENT ON TABLE polish_record_tags IS '润色记录标签关联';
+
+-- 删除项目时记录保留，移出项目
+ALTER TABLE polish_records ADD COLUMN IF NOT EXISTS project_id BIGINT REFERENCES projects(id) ON DELETE SET NULL;
+ALTER TABLE polish_records ADD COLUMN IF NOT EXISTS is_favorite BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN polish_records.project_id IS '所属项目ID（NULL=未归属项目）';
+COMMENT ON COLUMN polish_records.is_favorite IS '是否收藏';
+
+CREATE INDEX IF NOT EXISTS idx_project_id_record ON polish_records(project_id);
+CREATE INDEX IF NOT EXISTS idx_user_favorite_record ON polish_records(user_id) WHERE is_favorite;
@@ -0,0 +1,5 @@
+-- 回滚重新润色的记录谱系
+
+DROP INDEX IF EXISTS idx_parent_trace_id;
+
+ALTER TABLE polish_records DROP COLUMN IF EXISTS parent_trace_id;
@@ -0,0 +1,13 @@
+-- ============================================
+-- 重新润色的记录谱系
+-- 版本: 011
+-- 说明: 基于已有记录重新润色时，新记录通过 parent_trace_id 关联来源记录，历史可按树形展示
+-- ============================================
+
+-- 来源记录被物理删除时保留新记录，断开关联
+ALTER TABLE polish_records ADD COLUMN IF NOT EXISTS parent_trace_id VARCHAR(20)
+    REFERENCES polish_records(trace_id) ON DELETE SET NULL;
+
+COMMENT ON COLUMN polish_records.parent_trace_id IS '重新润色的来源记录TraceID（NULL=不是重新润色产生的记录）';
+
+CREATE INDEX IF NOT EXISTS idx_parent_trace_id ON polish_records(parent_trace_id) WHERE parent_trace_id IS NOT NULL;
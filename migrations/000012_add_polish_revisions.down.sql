@@ -0,0 +1,2 @@
+-- 删除多轮修改记录
+DROP TABLE IF EXISTS polish_revisions;
@@ -0,0 +1,47 @@
+-- ============================================
+-- 多轮修改记录
+-- 版本: 012
+-- 说明: 用户看到润色结果后用自然语言指令继续修改，每轮保存为记录的一个修订，带有本轮的对比数据
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS polish_revisions (
+    id BIGSERIAL PRIMARY KEY,
+    record_id BIGINT NOT NULL,
+    revision INTEGER NOT NULL,
+
+    -- 本轮输入
+    instruction TEXT NOT NULL,
+    base_content TEXT NOT NULL,
+
+    -- 本轮输出
+    polished_content TEXT NOT NULL DEFAULT '',
+    polished_length INT NOT NULL DEFAULT 0,
+    comparison_data JSONB,
+    changes_count INT NOT NULL DEFAULT 0,
+
+    -- AI信息
+    provider VARCHAR(50) NOT NULL DEFAULT '',
+    model VARCHAR(100) NOT NULL DEFAULT '',
+    process_time_ms INT NOT NULL DEFAULT 0,
+
+    -- 状态
+    status VARCHAR(20) NOT NULL DEFAULT 'success',
+    error_message TEXT NOT NULL DEFAULT '',
+
+    -- 内容加密（指令、修改前文本、修改结果、对比数据）
+    encryption_key_id BIGINT NOT NULL DEFAULT 0,
+
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_polish_revisions_record FOREIGN KEY (record_id)
+        REFERENCES polish_records(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_record_revision ON polish_revisions(record_id, revision);
+
+COMMENT ON TABLE polish_revisions IS '多轮修改记录 - 每轮修改指令及其结果';
+COMMENT ON COLUMN polish_revisions.revision IS '记录内的修订号，从1开始递增';
+COMMENT ON COLUMN polish_revisions.instruction IS '用户的修改指令';
+COMMENT ON COLUMN polish_revisions.base_content IS '本轮修改前的文本（用户当时看到的最新文本）';
+COMMENT ON COLUMN polish_revisions.comparison_data IS '修改前文本与修改结果的对比数据JSON';
+COMMENT ON COLUMN polish_revisions.encryption_key_id IS '内容加密使用的数据密钥ID（0=明文）';
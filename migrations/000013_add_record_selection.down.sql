@@ -0,0 +1,4 @@
+-- 回滚选区润色
+
+ALTER TABLE polish_records DROP COLUMN IF EXISTS selection_end;
+ALTER TABLE polish_records DROP COLUMN IF EXISTS selection_start;
@@ -0,0 +1,11 @@
+-- ============================================
+-- 选区润色
+-- 版本: 013
+-- 说明: 只润色段落中的一段时记录该片段在原文中的位置，对比标注只限于该片段
+-- ============================================
+
+ALTER TABLE polish_records ADD COLUMN IF NOT EXISTS selection_start INTEGER;
+ALTER TABLE polish_records ADD COLUMN IF NOT EXISTS selection_end INTEGER;
+
+COMMENT ON COLUMN polish_records.selection_start IS '润色的原文片段起始位置（基于字符，NULL=润色整段）';
+COMMENT ON COLUMN polish_records.selection_end IS '润色的原文片段结束位置（不包含）';
@@ -0,0 +1,5 @@
+-- 回滚翻译润色模式与术语表
+
+DROP TABLE IF EXISTS glossary_terms;
+
+ALTER TABLE polish_records DROP COLUMN IF EXISTS source_language;
@@ -0,0 +1,30 @@
+-- ============================================
+-- 翻译润色模式与术语表
+-- 版本: 014
+-- 说明: 翻译记录的 mode 为 translate，language 为目标语言，source_language 为源语言；
+--       术语表按用户和语言对隔离，翻译时只使用同一语言对的术语，要求提供商使用固定译法
+-- ============================================
+
+ALTER TABLE polish_records ADD COLUMN IF NOT EXISTS source_language VARCHAR(10) NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN polish_records.source_language IS '翻译记录的源语言（润色记录为空）';
+
+CREATE TABLE IF NOT EXISTS glossary_terms (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    source_language VARCHAR(10) NOT NULL,
+    target_language VARCHAR(10) NOT NULL,
+    source_term VARCHAR(200) NOT NULL,
+    target_term VARCHAR(200) NOT NULL,
+    note VARCHAR(500) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_language_source_term ON glossary_terms(user_id, source_language, target_language, source_term);
+
+COMMENT ON TABLE glossary_terms IS '用户术语表（翻译时保持术语译法一致）';
+COMMENT ON COLUMN glossary_terms.source_language IS '源语言';
+COMMENT ON COLUMN glossary_terms.target_language IS '目标语言';
+COMMENT ON COLUMN glossary_terms.source_term IS '源语言术语（用户的同一语言对内唯一）';
+COMMENT ON COLUMN glossary_terms.target_term IS '固定译法';
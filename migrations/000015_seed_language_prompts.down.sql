@@ -0,0 +1,14 @@
+-- 回滚新增论文语言的初始 Prompt
+
+DELETE FROM polish_prompts
+WHERE version = 1
+  AND style = 'all'
+  AND language IN ('de', 'ja', 'es', 'all')
+  AND name IN (
+    'Conservative German', 'Balanced German', 'Aggressive German',
+    'Conservative Japanese', 'Balanced Japanese', 'Aggressive Japanese',
+    'Conservative Spanish', 'Balanced Spanish', 'Aggressive Spanish',
+    'Conservative Generic', 'Balanced Generic', 'Aggressive Generic'
+  );
+
+COMMENT ON COLUMN polish_prompts.language IS '语言: en / zh / all(通用)';
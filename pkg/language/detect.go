@@ -0,0 +1,112 @@
+package language
+
+import (
+	"strings"
+	"unicode"
+)
+
+// cjkWeight 一个汉字/假名/谚文相当的拉丁字母数（比较不同文字的字符数时使用）
+const cjkWeight = 3
+
+// latinStopwords 拉丁字母语言的高频虚词（按检测时的优先顺序排列，得分相同时取靠前的语言）
+var latinStopwords = []struct {
+	code  string
+	words map[string]bool
+}{
+	{"en", wordSet("the and of to is are that this with for we which be by on it was were these from have has")},
+	{"de", wordSet("der die das und ist nicht ein eine mit den dem des von zu wir wird werden auf sich für auch im sind dass")},
+	{"es", wordSet("el los las del que y es un una por con para se lo como más este esta son fue al")},
+	{"fr", wordSet("le les des du et est une dans pour qui sur pas nous sont avec ce cette au aux ont")},
+	{"pt", wordSet("os do da dos das em um uma não para com por é são foi mais pelo pela")},
+	{"it", wordSet("il gli della delle di che è per con sono non nel nella questo questa anche")},
+}
+
+// Detect 根据文字和高频虚词检测文本的语言，返回已知语言代码；线索不足时返回 false
+// 汉字/假名/谚文/西里尔字母按文字判断（含假名为日文），拉丁字母按虚词出现次数判断
+func Detect(text string) (string, bool) {
+	var han, kana, hangul, cyrillic, latin int
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			han++
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+			kana++
+		case unicode.Is(unicode.Hangul, r):
+			hangul++
+		case unicode.Is(unicode.Cyrillic, r):
+			cyrillic++
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		}
+	}
+
+	cjk := (han + kana) * cjkWeight
+	switch {
+	case cjk == 0 && hangul == 0 && cyrillic == 0 && latin == 0:
+		return "", false
+	case cjk >= hangul*cjkWeight && cjk >= cyrillic && cjk >= latin:
+		// 日文汉字夹杂假名，中文几乎不含假名
+		if kana*10 >= han+kana {
+			return "ja", true
+		}
+		return "zh", true
+	case hangul*cjkWeight >= cyrillic && hangul*cjkWeight >= latin:
+		return "ko", true
+	case cyrillic >= latin:
+		return "ru", true
+	}
+	return detectLatin(text)
+}
+
+// DetectOrDefault 检测文本的语言；检测不出或检测到的语言未开放时返回默认语言
+func DetectOrDefault(text string) string {
+	if code, ok := Detect(text); ok && IsSupported(code) {
+		return code
+	}
+	return Default()
+}
+
+// ContainsCJK 是否包含中日韩文字（汉字、假名、谚文）；这些语言不以空格分词
+func ContainsCJK(text string) bool {
+	for _, r := range text {
+		if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) {
+			return true
+		}
+	}
+	return false
+}
+
+// detectLatin 按高频虚词出现次数判断拉丁字母文本的语言
+func detectLatin(text string) (string, bool) {
+	scores := make([]int, len(latinStopwords))
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	for _, word := range words {
+		for i, lang := range latinStopwords {
+			if lang.words[word] {
+				scores[i]++
+			}
+		}
+	}
+
+	best := -1
+	for i, score := range scores {
+		if score > 0 && (best == -1 || score > scores[best]) {
+			best = i
+		}
+	}
+	if best == -1 {
+		return "", false
+	}
+	return latinStopwords[best].code, true
+}
+
+// wordSet 空格分隔的词转换为集合
+func wordSet(words string) map[string]bool {
+	set := make(map[string]bool)
+	for _, word := range strings.Fields(words) {
+		set[word] = true
+	}
+	return set
+}
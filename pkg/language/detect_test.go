@@ -0,0 +1,97 @@
+package language
+
+import "testing"
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		want   string
+		wantOK bool
+	}{
+		{"english", "We propose a novel method for the detection of anomalies in time series.", "en", true},
+		{"chinese", "本文提出了一种基于深度学习的时间序列异常检测方法。", "zh", true},
+		{"chinese with english terms", "我们使用BERT模型对文本进行编码，并在GLUE基准上评估。", "zh", true},
+		{"japanese", "本研究では、時系列データの異常検知のための新しい手法を提案する。", "ja", true},
+		{"korean", "본 연구에서는 시계열 데이터의 이상 탐지를 위한 새로운 방법을 제안한다.", "ko", true},
+		{"german", "Wir stellen eine neue Methode zur Erkennung von Anomalien in Zeitreihen vor, die auf tiefen Netzen basiert.", "de", true},
+		{"spanish", "Proponemos un método nuevo para la detección de anomalías en series temporales con redes profundas.", "es", true},
+		{"french", "Nous proposons une nouvelle méthode pour la détection des anomalies dans les séries temporelles.", "fr", true},
+		{"russian", "Мы предлагаем новый метод обнаружения аномалий во временных рядах.", "ru", true},
+		{"numbers only", "3.14 (2024) [12]", "", false},
+		{"no stopwords", "Transformer", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Detect(tt.text)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Detect() = %q, %v, want %q, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestDetectOrDefault(t *testing.T) {
+	defer func() {
+		if err := Configure(DefaultSupported, DefaultLanguage); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	if err := Configure([]string{"zh", "en"}, "zh"); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+
+	if got := DetectOrDefault("We propose a novel method."); got != "en" {
+		t.Errorf("DetectOrDefault(english) = %q, want en", got)
+	}
+	// 检测到的语言未开放时使用默认语言
+	if got := DetectOrDefault("Wir stellen eine neue Methode vor, die auf Netzen basiert."); got != "zh" {
+		t.Errorf("DetectOrDefault(german) = %q, want default zh", got)
+	}
+	if got := DetectOrDefault("42"); got != "zh" {
+		t.Errorf("DetectOrDefault(number) = %q, want default zh", got)
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	defer func() {
+		if err := Configure(DefaultSupported, DefaultLanguage); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	if err := Configure(nil, "en"); err == nil {
+		t.Error("Configure() with no languages should fail")
+	}
+	if err := Configure([]string{"en", "xx"}, "en"); err == nil {
+		t.Error("Configure() with an unknown language should fail")
+	}
+	if err := Configure([]string{"en", "zh"}, "de"); err == nil {
+		t.Error("Configure() with an unsupported default should fail")
+	}
+
+	if err := Configure([]string{"de", "en", "de"}, "de"); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+	if got := Supported(); len(got) != 2 || got[0] != "de" || got[1] != "en" {
+		t.Errorf("Supported() = %q, want [de en]", got)
+	}
+	if IsSupported("zh") || !IsSupported("de") || Default() != "de" {
+		t.Errorf("registry not updated: supported=%q default=%q", Supported(), Default())
+	}
+}
+
+func TestContainsCJK(t *testing.T) {
+	for text, want := range map[string]bool{
+		"transformer 模型": true,
+		"アテンション":         true,
+		"어텐션":            true,
+		"self-attention": false,
+		"Übersetzung":    false,
+	} {
+		if got := ContainsCJK(text); got != want {
+			t.Errorf("ContainsCJK(%q) = %v, want %v", text, got, want)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package language
+
+import (
+	"fmt"
+	"sort"
+	"sync/atomic"
+)
+
+// 论文语言
+// 已知语言（有名称，可以写 Prompt）是固定的；其中哪些语言对用户开放由配置 languages.supported 决定，
+// 启动和配置热更新时调用 Configure 生效
+
+// Language 已知语言
+type Language struct {
+	Code        string // ISO 639-1 代码
+	Name        string // 英文名称（用于英文 Prompt）
+	ChineseName string // 中文名称（用于中文 Prompt）
+}
+
+// known 已知语言
+var known = map[string]Language{
+	"en": {Code: "en", Name: "English", ChineseName: "英文"},
+	"zh": {Code: "zh", Name: "Chinese", ChineseName: "中文"},
+	"de": {Code: "de", Name: "German", ChineseName: "德文"},
+	"ja": {Code: "ja", Name: "Japanese", ChineseName: "日文"},
+	"es": {Code: "es", Name: "Spanish", ChineseName: "西班牙文"},
+	"fr": {Code: "fr", Name: "French", ChineseName: "法文"},
+	"it": {Code: "it", Name: "Italian", ChineseName: "意大利文"},
+	"pt": {Code: "pt", Name: "Portuguese", ChineseName: "葡萄牙文"},
+	"ko": {Code: "ko", Name: "Korean", ChineseName: "韩文"},
+	"ru": {Code: "ru", Name: "Russian", ChineseName: "俄文"},
+}
+
+// DefaultSupported 默认开放的语言
+var DefaultSupported = []string{"en", "zh", "de", "ja", "es"}
+
+// DefaultLanguage 默认语言（未指定且无法检测时使用）
+const DefaultLanguage = "en"
+
+// registry 当前开放的语言（配置热更新时整体原子替换）
+type registry struct {
+	codes       []string
+	set         map[string]bool
+	defaultCode string
+}
+
+var current atomic.Pointer[registry]
+
+func init() {
+	if err := Configure(DefaultSupported, DefaultLanguage); err != nil {
+		panic(err)
+	}
+}
+
+// Configure 设置开放的语言和默认语言
+func Configure(codes []string, defaultCode string) error {
+	if len(codes) == 0 {
+		return fmt.Errorf("at least one language must be supported")
+	}
+
+	r := &registry{codes: make([]string, 0, len(codes)), set: make(map[string]bool, len(codes)), defaultCode: defaultCode}
+	for _, code := range codes {
+		if !IsKnown(code) {
+			return fmt.Errorf("unknown language %q", code)
+		}
+		if !r.set[code] {
+			r.set[code] = true
+			r.codes = append(r.codes, code)
+		}
+	}
+	if !r.set[defaultCode] {
+		return fmt.Errorf("default language %q is not supported", defaultCode)
+	}
+
+	current.Store(r)
+	return nil
+}
+
+// Supported 返回开放的语言代码（按配置顺序）
+func Supported() []string {
+	codes := current.Load().codes
+	result := make([]string, len(codes))
+	copy(result, codes)
+	return result
+}
+
+// IsSupported 语言是否开放
+func IsSupported(code string) bool {
+	return current.Load().set[code]
+}
+
+// Default 返回默认语言
+func Default() string {
+	return current.Load().defaultCode
+}
+
+// IsKnown 是否为已知语言
+func IsKnown(code string) bool {
+	_, ok := known[code]
+	return ok
+}
+
+// Known 返回所有已知语言代码（按代码排序）
+func Known() []string {
+	codes := make([]string, 0, len(known))
+	for code := range known {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// Name 语言的英文名称，未知语言返回代码本身
+func Name(code string) string {
+	if lang, ok := known[code]; ok {
+		return lang.Name
+	}
+	return code
+}
+
+// ChineseName 语言的中文名称，未知语言返回代码本身
+func ChineseName(code string) string {
+	if lang, ok := known[code]; ok {
+		return lang.ChineseName
+	}
+	return code
+}
@@ -0,0 +1,38 @@
+package logger
+
+import (
+	"context"
+
+	"paper_ai/pkg/reqctx"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// FromContext 返回带有请求上下文字段（request_id、trace_id、user_id）的 logger
+// 用于替代在每次调用时手动添加这些字段
+// 启用链路追踪时还会附带 otel_trace_id（注意与业务 trace_id 区分），便于从日志跳转到链路
+func FromContext(ctx context.Context) *zap.Logger {
+	if base == nil {
+		return zap.NewNop()
+	}
+
+	fields := make([]zap.Field, 0, 4)
+	if requestID := reqctx.RequestID(ctx); requestID != "" {
+		fields = append(fields, zap.String("request_id", requestID))
+	}
+	if traceID := reqctx.TraceID(ctx); traceID != "" {
+		fields = append(fields, zap.String("trace_id", traceID))
+	}
+	if userID, ok := reqctx.UserID(ctx); ok {
+		fields = append(fields, zap.Int64("user_id", userID))
+	}
+	if spanCtx := trace.SpanContextFromContext(ctx); spanCtx.IsValid() {
+		fields = append(fields, zap.String("otel_trace_id", spanCtx.TraceID().String()))
+	}
+
+	if len(fields) == 0 {
+		return base
+	}
+	return base.With(fields...)
+}
@@ -7,7 +7,10 @@ import (
 	"go.uber.org/zap/zapcore"
 )
 
-var log *zap.Logger
+var (
+	base *zap.Logger // 直接使用的 logger（FromContext 返回）
+	log  *zap.Logger // 包级函数使用的 logger（跳过一层调用栈）
+)
 
 // Init 初始化日志
 func Init() error {
@@ -21,7 +24,25 @@ func Init() error {
 		zapcore.InfoLevel,
 	)
 
-	log = zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1))
+	base = zap.New(core, zap.AddCaller())
+	log = base.WithOptions(zap.AddCallerSkip(1))
+	return nil
+}
+
+// InitConsole 初始化面向终端的日志（命令行工具使用），输出到 stderr，低于 level 的日志不输出
+func InitConsole(level zapcore.Level) error {
+	config := zap.NewDevelopmentEncoderConfig()
+	config.EncodeTime = zapcore.ISO8601TimeEncoder
+	config.EncodeLevel = zapcore.CapitalLevelEncoder
+
+	core := zapcore.NewCore(
+		zapcore.NewConsoleEncoder(config),
+		zapcore.AddSync(os.Stderr),
+		level,
+	)
+
+	base = zap.New(core)
+	log = base
 	return nil
 }
 
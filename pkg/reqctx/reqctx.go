@@ -0,0 +1,81 @@
+package reqctx
+
+import (
+	"context"
+	"net/http"
+)
+
+// 请求上下文
+// 由中间件写入请求ID、用户ID，由服务层写入业务 TraceID，
+// 贯穿 handler → service → AI 提供商调用，用于日志关联和链路追踪
+
+// HeaderRequestID 请求ID的 HTTP 头
+const HeaderRequestID = "X-Request-ID"
+
+// HeaderTraceID 业务 TraceID 的 HTTP 头（传递给 AI 提供商）
+const HeaderTraceID = "X-Trace-ID"
+
+// contextKey 上下文键类型（避免与其他包的字符串键冲突）
+type contextKey int
+
+const (
+	requestIDKey contextKey = iota
+	traceIDKey
+	userIDKey
+)
+
+// WithRequestID 写入请求ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestID 读取请求ID（不存在时返回空字符串）
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
+
+// WithTraceID 写入业务 TraceID
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceIDKey, traceID)
+}
+
+// TraceID 读取业务 TraceID（不存在时返回空字符串）
+func TraceID(ctx context.Context) string {
+	traceID, _ := ctx.Value(traceIDKey).(string)
+	return traceID
+}
+
+// WithUserID 写入用户ID
+func WithUserID(ctx context.Context, userID int64) context.Context {
+	return context.WithValue(ctx, userIDKey, userID)
+}
+
+// UserID 读取用户ID
+func UserID(ctx context.Context) (int64, bool) {
+	userID, ok := ctx.Value(userIDKey).(int64)
+	return userID, ok
+}
+
+// InjectHeaders 将请求ID和 TraceID 写入外发请求的 HTTP 头
+func InjectHeaders(ctx context.Context, header http.Header) {
+	if requestID := RequestID(ctx); requestID != "" {
+		header.Set(HeaderRequestID, requestID)
+	}
+	if traceID := TraceID(ctx); traceID != "" {
+		header.Set(HeaderTraceID, traceID)
+	}
+}
+
+// ValidRequestID 判断客户端传入的请求ID是否可用（长度不超过 128，且只包含可打印 ASCII 字符）
+func ValidRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > 128 {
+		return false
+	}
+	for i := 0; i < len(requestID); i++ {
+		if requestID[i] < 0x21 || requestID[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,60 @@
+package reqctx
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestContextValues(t *testing.T) {
+	ctx := context.Background()
+	if RequestID(ctx) != "" || TraceID(ctx) != "" {
+		t.Error("空上下文应该返回空字符串")
+	}
+	if _, ok := UserID(ctx); ok {
+		t.Error("空上下文不应该有用户ID")
+	}
+
+	ctx = WithUserID(WithTraceID(WithRequestID(ctx, "req-1"), "1732701603456"), 42)
+	if got := RequestID(ctx); got != "req-1" {
+		t.Errorf("RequestID() = %q", got)
+	}
+	if got := TraceID(ctx); got != "1732701603456" {
+		t.Errorf("TraceID() = %q", got)
+	}
+	if got, ok := UserID(ctx); !ok || got != 42 {
+		t.Errorf("UserID() = %d, %v", got, ok)
+	}
+
+	// 字符串键不会读到类型化键的值
+	if ctx.Value("trace_id") != nil {
+		t.Error("字符串键不应该与类型化键冲突")
+	}
+
+	header := http.Header{}
+	InjectHeaders(ctx, header)
+	if header.Get(HeaderRequestID) != "req-1" || header.Get(HeaderTraceID) != "1732701603456" {
+		t.Errorf("InjectHeaders() = %v", header)
+	}
+}
+
+func TestValidRequestID(t *testing.T) {
+	tests := []struct {
+		id   string
+		want bool
+	}{
+		{"3f2b9c1e-8a4d-4c1b-9e2f-0a1b2c3d4e5f", true},
+		{"gateway.trace-01", true},
+		{"", false},
+		{"has space", false},
+		{"line\nbreak", false},
+		{strings.Repeat("a", 129), false},
+	}
+
+	for _, tt := range tests {
+		if got := ValidRequestID(tt.id); got != tt.want {
+			t.Errorf("ValidRequestID(%q) = %v, want %v", tt.id, got, tt.want)
+		}
+	}
+}